@@ -0,0 +1,216 @@
+// Package client provides an HTTP client for JSON:API servers,
+// which marshals and unmarshals tagged structs.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/max-waters/jsonapi/jsonapi"
+)
+
+// DefaultMaxResponseSize is the maximum size, in bytes, of a response
+// body read by a Client whose MaxResponseSize is zero.
+const DefaultMaxResponseSize = 10 << 20
+
+var (
+	ErrResponseTooLarge = fmt.Errorf("response body is too large")
+	ErrNotSlicePtr      = fmt.Errorf("not a slice pointer")
+)
+
+// ResponseErr is returned when the server responds with an error
+// status. It unwraps to the jsonapi.ErrorList of the response's error
+// document, if there is one, and so to its error objects, so errors.As
+// can be used to inspect them.
+type ResponseErr struct {
+	StatusCode int
+	Errors     jsonapi.ErrorList
+}
+
+func (e *ResponseErr) Error() string {
+	if len(e.Errors) == 0 {
+		return fmt.Sprintf("jsonapi: server responded with status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("jsonapi: server responded with status %d: %s", e.StatusCode, e.Errors.Error())
+}
+
+func (e *ResponseErr) Unwrap() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+	return e.Errors
+}
+
+// Client sends JSON:API requests to the server at BaseURL.
+// Paths passed to its methods are appended to BaseURL, and
+// may include a query string, eg one built with jsonapi.QueryBuilder.
+type Client struct {
+	BaseURL string
+	// HTTPClient sends requests, http.DefaultClient if nil.
+	HTTPClient *http.Client
+	// Header is added to every request.
+	Header http.Header
+	// Options are used to marshal and unmarshal documents.
+	Options []jsonapi.Option
+	// MaxResponseSize is the maximum size, in bytes, of response
+	// bodies, DefaultMaxResponseSize if zero. Larger responses fail
+	// with ErrResponseTooLarge.
+	MaxResponseSize int64
+}
+
+// NewClient returns a client for the server at baseURL.
+func NewClient(baseURL string) *Client {
+	return &Client{BaseURL: strings.TrimSuffix(baseURL, "/")}
+}
+
+// Get fetches a single resource and stores it in out, as described by
+// jsonapi.DeformatDocument. The response document is returned so that
+// its included resources, meta and links can be inspected.
+func (c *Client) Get(ctx context.Context, path string, out any) (*jsonapi.Document, error) {
+	return c.do(ctx, http.MethodGet, path, nil, out)
+}
+
+// List fetches a collection of resources and stores
+// them in out, which must be a pointer to a slice. If
+// the primary data isn't a collection, it fails with
+// jsonapi.ErrNotCollection.
+func (c *Client) List(ctx context.Context, path string, out any) (*jsonapi.Document, error) {
+	if v := reflect.ValueOf(out); v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Slice {
+		return nil, fmt.Errorf("jsonapi: %w", ErrNotSlicePtr)
+	}
+
+	d, err := c.send(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	if d == nil || d.Data == nil || !d.Data.Collection {
+		return d, fmt.Errorf("jsonapi: %w", jsonapi.ErrNotCollection)
+	}
+	return d, c.deformat(d, out)
+}
+
+// Create posts in as a new resource, and stores the created resource
+// returned by the server, if any, in out. If out is nil the response's
+// primary data is ignored.
+func (c *Client) Create(ctx context.Context, path string, in, out any) (*jsonapi.Document, error) {
+	return c.do(ctx, http.MethodPost, path, in, out)
+}
+
+// Update patches the resource at path with in, and stores the updated
+// resource returned by the server, if any, in out. If out is nil the
+// response's primary data is ignored.
+func (c *Client) Update(ctx context.Context, path string, in, out any) (*jsonapi.Document, error) {
+	return c.do(ctx, http.MethodPatch, path, in, out)
+}
+
+// Delete deletes the resource at path.
+func (c *Client) Delete(ctx context.Context, path string) error {
+	_, err := c.do(ctx, http.MethodDelete, path, nil, nil)
+	return err
+}
+
+// do sends a request whose primary data is in, if not nil, and
+// stores the response's primary data in out, if not nil.
+func (c *Client) do(ctx context.Context, method, path string, in, out any) (*jsonapi.Document, error) {
+	d, err := c.send(ctx, method, path, in)
+	if err != nil || d == nil || out == nil {
+		return d, err
+	}
+	return d, c.deformat(d, out)
+}
+
+// send sends a request whose primary data is in, if not nil, and
+// returns the response document, or nil if the response is empty.
+func (c *Client) send(ctx context.Context, method, path string, in any) (*jsonapi.Document, error) {
+	var body io.Reader
+	if in != nil {
+		data, err := jsonapi.MarshalDocument(in, c.Options...)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path, body)
+	if err != nil {
+		return nil, fmt.Errorf("jsonapi: creating request: %w", err)
+	}
+	for k, vs := range c.Header {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+	req.Header.Set("Accept", jsonapi.MediaType)
+	if in != nil {
+		req.Header.Set("Content-Type", jsonapi.MediaType)
+	}
+
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	limit := c.maxResponseSize()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("jsonapi: reading response: %w", err)
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("jsonapi: %w: larger than %d bytes", ErrResponseTooLarge, limit)
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return nil, responseErr(resp.StatusCode, data)
+	}
+
+	if resp.StatusCode == http.StatusNoContent || len(bytes.TrimSpace(data)) == 0 {
+		return nil, nil
+	}
+
+	d := &jsonapi.Document{}
+	if err := json.Unmarshal(data, d); err != nil {
+		return nil, fmt.Errorf("jsonapi: unmarshaling document: %w", err)
+	}
+	return d, nil
+}
+
+// deformat runs the decode hooks of c's options on the response
+// document d, and stores its primary data in out.
+func (c *Client) deformat(d *jsonapi.Document, out any) error {
+	if err := jsonapi.DecodeDocument(d, c.Options...); err != nil {
+		return err
+	}
+	return jsonapi.DeformatDocument(d, out, c.Options...)
+}
+
+func (c *Client) maxResponseSize() int64 {
+	if c.MaxResponseSize > 0 {
+		return c.MaxResponseSize
+	}
+	return DefaultMaxResponseSize
+}
+
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+// responseErr converts an error response to a *ResponseErr,
+// including the error objects if data is an error document.
+func responseErr(status int, data []byte) error {
+	e := &ResponseErr{StatusCode: status}
+	d := jsonapi.Document{}
+	if err := json.Unmarshal(data, &d); err == nil {
+		e.Errors = d.Errors
+	}
+	return e
+}
@@ -0,0 +1,185 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/max-waters/jsonapi/jsonapi"
+	"github.com/stretchr/testify/assert"
+)
+
+type article struct {
+	Id     int    `jsonapi:"id,articles,string"`
+	Title  string `jsonapi:"attr,title"`
+	Author int    `jsonapi:"rel,author,people,string"`
+}
+
+type person struct {
+	Id   int    `jsonapi:"id,people,string"`
+	Name string `jsonapi:"attr,name"`
+}
+
+const articleJson = `{"type": "articles", "id": "1", "attributes": {"title": "Hello"}, "relationships": {"author": {"data": {"type": "people", "id": "2"}}}}`
+
+type request struct {
+	Method      string
+	Path        string
+	Accept      string
+	ContentType string
+	Body        string
+}
+
+// newServer returns a server that records each request
+// and responds with the supplied status and body.
+func newServer(t *testing.T, status int, body string) (*httptest.Server, *request) {
+	got := &request{}
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		*got = request{r.Method, r.URL.RequestURI(), r.Header.Get("Accept"), r.Header.Get("Content-Type"), string(data)}
+		w.Header().Set("Content-Type", jsonapi.MediaType)
+		w.WriteHeader(status)
+		_, _ = io.WriteString(w, body)
+	}))
+	t.Cleanup(s.Close)
+	return s, got
+}
+
+func TestClient_Get(t *testing.T) {
+	s, req := newServer(t, http.StatusOK, `{
+		"data": `+articleJson+`,
+		"included": [{"type": "people", "id": "2", "attributes": {"name": "Bob"}}]
+	}`)
+
+	got := article{}
+	d, err := NewClient(s.URL+"/").Get(context.Background(), "/articles/1?include=author", &got)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, request{http.MethodGet, "/articles/1?include=author", jsonapi.MediaType, "", ""}, *req)
+	assert.Equal(t, article{Id: 1, Title: "Hello", Author: 2}, got)
+
+	people := []person{}
+	if err := jsonapi.DeformatIncluded(d, &people); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []person{{Id: 2, Name: "Bob"}}, people)
+}
+
+func TestClient_List(t *testing.T) {
+	s, req := newServer(t, http.StatusOK, `{"data": [`+articleJson+`], "meta": {"total": 1}}`)
+
+	got := []article{}
+	d, err := NewClient(s.URL).List(context.Background(), "/articles", &got)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, http.MethodGet, req.Method)
+	assert.Equal(t, []article{{Id: 1, Title: "Hello", Author: 2}}, got)
+	assert.JSONEq(t, "1", string(d.Meta["total"]))
+}
+
+func TestClient_List_Err(t *testing.T) {
+	s, req := newServer(t, http.StatusOK, `{"data": `+articleJson+`}`)
+	c := NewClient(s.URL)
+
+	_, err := c.List(context.Background(), "/articles", &article{})
+	assert.ErrorIs(t, err, ErrNotSlicePtr)
+	assert.Equal(t, request{}, *req, "request sent")
+
+	_, err = c.List(context.Background(), "/articles", &[]article{})
+	assert.ErrorIs(t, err, jsonapi.ErrNotCollection)
+
+	s, _ = newServer(t, http.StatusOK, `{"data": null}`)
+	_, err = NewClient(s.URL).List(context.Background(), "/articles", &[]article{})
+	assert.ErrorIs(t, err, jsonapi.ErrNotCollection)
+}
+
+func TestClient_Create(t *testing.T) {
+	s, req := newServer(t, http.StatusCreated, `{"data": `+articleJson+`}`)
+
+	got := article{}
+	_, err := NewClient(s.URL).Create(context.Background(), "/articles", &article{Title: "Hello", Author: 2}, &got)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, http.MethodPost, req.Method)
+	assert.Equal(t, jsonapi.MediaType, req.ContentType)
+	assert.JSONEq(t, `{"data": {"type": "articles", "id": "0", "attributes": {"title": "Hello"}, "relationships": {"author": {"data": {"type": "people", "id": "2"}}}}}`, req.Body)
+	assert.Equal(t, article{Id: 1, Title: "Hello", Author: 2}, got)
+}
+
+func TestClient_Update_NoContent(t *testing.T) {
+	s, req := newServer(t, http.StatusNoContent, "")
+
+	d, err := NewClient(s.URL).Update(context.Background(), "/articles/1", &article{Id: 1, Title: "Hello", Author: 2}, &article{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, http.MethodPatch, req.Method)
+	assert.Nil(t, d)
+}
+
+func TestClient_Delete(t *testing.T) {
+	s, req := newServer(t, http.StatusNoContent, "")
+
+	if err := NewClient(s.URL).Delete(context.Background(), "/articles/1"); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, request{http.MethodDelete, "/articles/1", jsonapi.MediaType, "", ""}, *req)
+}
+
+func TestClient_ErrorDocument(t *testing.T) {
+	s, _ := newServer(t, http.StatusNotFound, `{"errors": [{"status": "404", "title": "Not Found", "detail": "no such article"}]}`)
+
+	_, err := NewClient(s.URL).Get(context.Background(), "/articles/1", &article{})
+
+	var respErr *ResponseErr
+	if assert.ErrorAs(t, err, &respErr) {
+		assert.Equal(t, http.StatusNotFound, respErr.StatusCode)
+	}
+
+	var obj *jsonapi.ErrorObject
+	if assert.True(t, errors.As(err, &obj)) {
+		assert.Equal(t, "no such article", obj.Detail)
+	}
+
+	var list jsonapi.ErrorList
+	if assert.True(t, errors.As(err, &list)) {
+		assert.Equal(t, jsonapi.ErrorList{obj}, list)
+	}
+	assert.EqualError(t, err, "jsonapi: server responded with status 404: jsonapi: 404 Not Found: no such article")
+}
+
+func TestClient_ErrorStatus(t *testing.T) {
+	s, _ := newServer(t, http.StatusBadGateway, "bad gateway")
+
+	err := NewClient(s.URL).Delete(context.Background(), "/articles/1")
+	assert.Equal(t, &ResponseErr{StatusCode: http.StatusBadGateway}, err)
+	assert.EqualError(t, err, "jsonapi: server responded with status 502")
+}
+
+func TestClient_MaxResponseSize(t *testing.T) {
+	body := `{"data": ` + articleJson + `}`
+	s, _ := newServer(t, http.StatusOK, body)
+	c := NewClient(s.URL)
+
+	c.MaxResponseSize = int64(len(body))
+	if _, err := c.Get(context.Background(), "/articles/1", &article{}); err != nil {
+		t.Fatal(err)
+	}
+
+	c.MaxResponseSize--
+	_, err := c.Get(context.Background(), "/articles/1", &article{})
+	assert.ErrorIs(t, err, ErrResponseTooLarge)
+}
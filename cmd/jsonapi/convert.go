@@ -0,0 +1,219 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/max-waters/jsonapi/jsonapi"
+)
+
+// schema describes how the members of flat JSON objects map to
+// resources. Members that aren't the id, a relationship or meta
+// are attributes.
+type schema struct {
+	// Type is the resource type.
+	Type string `json:"type"`
+	// Id is the name of the member holding the id, "id" by default.
+	Id string `json:"id"`
+	// Relationships maps relationship names to their
+	// related resource type and the member holding their ids.
+	Relationships map[string]relSchema `json:"relationships"`
+	// Meta lists the members that are meta rather than attributes.
+	Meta []string `json:"meta"`
+}
+
+// relSchema describes a relationship. An array of ids is
+// a to-many relationship, and anything else is to-one.
+type relSchema struct {
+	Type string `json:"type"`
+	// Member is the name of the member holding the related
+	// resource's id, the relationship name by default.
+	Member string `json:"member"`
+}
+
+// runConvert converts a flat JSON object, or array
+// of objects, to a JSON:API document, given a schema.
+func runConvert(args []string, e *env) (int, error) {
+	flags := flag.NewFlagSet("convert", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	schemaFile := flags.String("schema", "", "the schema file")
+	if err := flags.Parse(args); err != nil {
+		return 0, fmt.Errorf("%w: %v", errUsage, err)
+	}
+	if *schemaFile == "" {
+		return 0, fmt.Errorf("%w: -schema is required", errUsage)
+	}
+
+	name, err := optionalArg(flags.Args())
+	if err != nil {
+		return 0, err
+	}
+
+	s, err := readSchema(*schemaFile)
+	if err != nil {
+		return 0, err
+	}
+
+	data, err := readInput(name, e)
+	if err != nil {
+		return 0, err
+	}
+
+	d, err := convert(data, s)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", displayName(name), err)
+	}
+	return exitOk, writeJson(d, e)
+}
+
+// readSchema reads and checks the named schema file.
+func readSchema(name string) (*schema, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	s := schema{}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	if s.Type == "" {
+		return nil, fmt.Errorf("%s: type is required", name)
+	}
+	if s.Id == "" {
+		s.Id = "id"
+	}
+	for rel, rs := range s.Relationships {
+		if rs.Type == "" {
+			return nil, fmt.Errorf("%s: relationship %s: type is required", name, rel)
+		}
+	}
+	return &s, nil
+}
+
+// convert converts the flat JSON object, or array of objects,
+// data to a document with resources described by s.
+func convert(data []byte, s *schema) (*jsonapi.Document, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '[' {
+		objs := []map[string]json.RawMessage{}
+		if err := json.Unmarshal(data, &objs); err != nil {
+			return nil, err
+		}
+		rs := make([]*jsonapi.Resource, len(objs))
+		for i, obj := range objs {
+			r, err := convertObject(obj, s)
+			if err != nil {
+				return nil, fmt.Errorf("[%d]: %w", i, err)
+			}
+			rs[i] = r
+		}
+		return &jsonapi.Document{Data: &jsonapi.PrimaryData{Collection: true, Resources: rs}}, nil
+	}
+
+	obj := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, err
+	}
+	r, err := convertObject(obj, s)
+	if err != nil {
+		return nil, err
+	}
+	return &jsonapi.Document{Data: &jsonapi.PrimaryData{Resource: r}}, nil
+}
+
+// convertObject converts a flat JSON object to a resource.
+func convertObject(obj map[string]json.RawMessage, s *schema) (*jsonapi.Resource, error) {
+	r := &jsonapi.Resource{}
+	r.Type = s.Type
+
+	if id, ok := obj[s.Id]; ok {
+		var err error
+		if r.Id, err = idString(id); err != nil {
+			return nil, err
+		}
+	}
+
+	claimed := map[string]bool{s.Id: true}
+	for rel, rs := range s.Relationships {
+		member := rs.Member
+		if member == "" {
+			member = rel
+		}
+		claimed[member] = true
+
+		value, ok := obj[member]
+		if !ok {
+			continue
+		}
+		if len(value) > 0 && value[0] == '[' {
+			ids := []json.RawMessage{}
+			if err := json.Unmarshal(value, &ids); err != nil {
+				return nil, fmt.Errorf("relationship %s: %w", rel, err)
+			}
+			l := &jsonapi.ToManyResourceLinkage{Data: make([]jsonapi.ResourceIdentifier, len(ids))}
+			for i, id := range ids {
+				id, err := idString(id)
+				if err != nil {
+					return nil, fmt.Errorf("relationship %s: %w", rel, err)
+				}
+				l.Data[i] = jsonapi.ResourceIdentifier{Type: rs.Type, Id: id}
+			}
+			if r.ToManyRelationships == nil {
+				r.ToManyRelationships = map[string]*jsonapi.ToManyResourceLinkage{}
+			}
+			r.ToManyRelationships[rel] = l
+			continue
+		}
+
+		id, err := idString(value)
+		if err != nil {
+			return nil, fmt.Errorf("relationship %s: %w", rel, err)
+		}
+		l := &jsonapi.ToOneResourceLinkage{Data: jsonapi.ResourceIdentifier{Type: rs.Type, Id: id}}
+		if r.ToOneRelationships == nil {
+			r.ToOneRelationships = map[string]*jsonapi.ToOneResourceLinkage{}
+		}
+		r.ToOneRelationships[rel] = l
+	}
+
+	for _, name := range s.Meta {
+		claimed[name] = true
+		if value, ok := obj[name]; ok {
+			if r.Meta == nil {
+				r.Meta = map[string]json.RawMessage{}
+			}
+			r.Meta[name] = value
+		}
+	}
+
+	for name, value := range obj {
+		if claimed[name] {
+			continue
+		}
+		if r.Attributes == nil {
+			r.Attributes = map[string]json.RawMessage{}
+		}
+		r.Attributes[name] = value
+	}
+
+	return r, nil
+}
+
+// idString returns the JSON id, quoting it if it is a number
+// or boolean, as JSON:API ids are strings. Objects and arrays
+// can't be ids.
+func idString(id json.RawMessage) (json.RawMessage, error) {
+	switch {
+	case len(id) == 0 || id[0] == '"' || bytes.Equal(id, jsonapi.NullJson):
+		return id, nil
+	case id[0] == '{' || id[0] == '[':
+		return nil, fmt.Errorf("invalid id %s", id)
+	}
+	q, _ := json.Marshal(string(id))
+	return q, nil
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvert(t *testing.T) {
+	type testCase struct {
+		Name     string
+		Json     string
+		Expected string
+	}
+
+	testCases := []testCase{
+		{
+			"object",
+			`{"id": 1, "title": "Hello", "authorId": 2, "tags": ["a", "b"], "views": 10}`,
+			`{"data": {
+				"type": "articles",
+				"id": "1",
+				"attributes": {"title": "Hello"},
+				"relationships": {
+					"author": {"data": {"type": "people", "id": "2"}},
+					"tags": {"data": [{"type": "tags", "id": "a"}, {"type": "tags", "id": "b"}]}
+				},
+				"meta": {"views": 10}
+			}}`,
+		},
+		{
+			"array",
+			`[{"id": "1"}, {"id": "2", "title": "World"}]`,
+			`{"data": [
+				{"type": "articles", "id": "1"},
+				{"type": "articles", "id": "2", "attributes": {"title": "World"}}
+			]}`,
+		},
+		{"no id", `{"title": "Hello"}`, `{"data": {"type": "articles", "attributes": {"title": "Hello"}}}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			stdout, stderr, status := runCmd(tc.Json, "convert", "-schema", "testdata/schema.json")
+			assert.JSONEq(t, tc.Expected, stdout)
+			assert.Empty(t, stderr)
+			assert.Equal(t, exitOk, status)
+		})
+	}
+}
+
+func TestConvert_Err(t *testing.T) {
+	_, stderr, status := runCmd(`[{"tags": 1}, {"tags": [{}]}]`, "convert", "-schema", "testdata/schema.json")
+	assert.Contains(t, stderr, "jsonapi convert: -: [1]: relationship tags:")
+	assert.Equal(t, exitFailed, status)
+
+	_, stderr, status = runCmd(`{}`, "convert", "-schema", "testdata/articles.json")
+	assert.Contains(t, stderr, "jsonapi convert: testdata/articles.json:")
+	assert.Equal(t, exitFailed, status)
+}
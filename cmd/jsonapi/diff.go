@@ -0,0 +1,158 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/max-waters/jsonapi/jsonapi"
+)
+
+// runDiff compares two documents, printing their differences,
+// and fails if there are any.
+func runDiff(args []string, e *env) (int, error) {
+	if len(args) != 2 {
+		return 0, fmt.Errorf("%w: two files are required", errUsage)
+	}
+
+	a, err := readDocument(args[0], e)
+	if err != nil {
+		return 0, err
+	}
+	b, err := readDocument(args[1], e)
+	if err != nil {
+		return 0, err
+	}
+
+	diffs := diff(a, b)
+	for _, d := range diffs {
+		fmt.Fprintln(e.stdout, d)
+	}
+	if len(diffs) > 0 {
+		return exitFailed, nil
+	}
+	return exitOk, nil
+}
+
+// diff returns the differences between the documents a and b:
+// resources that are only in a ("- type/id") or b ("+ type/id"),
+// and members that differ between them ("~ type/id member.name").
+// Members are compared as JSON values, regardless of formatting
+// and the order of object members.
+func diff(a, b *jsonapi.Document) []string {
+	var diffs []string
+
+	for _, m := range diffMembers(documentMembers(a), documentMembers(b)) {
+		diffs = append(diffs, "~ "+m)
+	}
+
+	as, bs := resourcesByKey(a), resourcesByKey(b)
+	for _, key := range sortedKeys(as) {
+		r, ok := bs[key]
+		if !ok {
+			diffs = append(diffs, "- "+key)
+			continue
+		}
+		for _, m := range diffMembers(resourceMembers(as[key]), resourceMembers(r)) {
+			diffs = append(diffs, "~ "+key+" "+m)
+		}
+	}
+	for _, key := range sortedKeys(bs) {
+		if _, ok := as[key]; !ok {
+			diffs = append(diffs, "+ "+key)
+		}
+	}
+
+	return diffs
+}
+
+// resourcesByKey returns the primary and included
+// resources of d, by their resourceKey.
+func resourcesByKey(d *jsonapi.Document) map[string]*jsonapi.Resource {
+	rs := map[string]*jsonapi.Resource{}
+	for _, r := range documentResources(d) {
+		if r != nil {
+			rs[resourceKey(r)] = r
+		}
+	}
+	return rs
+}
+
+// documentMembers returns the top-level members of d,
+// other than its resources, by their paths.
+func documentMembers(d *jsonapi.Document) map[string]any {
+	ms := map[string]any{}
+	addMembers(ms, "meta", d.Meta)
+	addMembers(ms, "links", d.Links)
+	if d.JsonApi != nil {
+		ms["jsonapi"] = d.JsonApi
+	}
+	if d.Errors != nil {
+		ms["errors"] = d.Errors
+	}
+	return ms
+}
+
+// resourceMembers returns the members of r by their paths.
+func resourceMembers(r *jsonapi.Resource) map[string]any {
+	ms := map[string]any{}
+	addMembers(ms, "attributes", r.Attributes)
+	addMembers(ms, "relationships", r.ToOneRelationships)
+	addMembers(ms, "relationships", r.ToManyRelationships)
+	addMembers(ms, "meta", r.Meta)
+	addMembers(ms, "links", r.Links)
+	return ms
+}
+
+func addMembers[V any](ms map[string]any, prefix string, m map[string]V) {
+	for k, v := range m {
+		ms[prefix+"."+k] = v
+	}
+}
+
+// diffMembers returns the sorted paths of the members
+// that are in only one of a and b, or differ between them.
+func diffMembers(a, b map[string]any) []string {
+	var paths []string
+	for path, v := range a {
+		if w, ok := b[path]; !ok || !jsonEqual(v, w) {
+			paths = append(paths, path)
+		}
+	}
+	for path := range b {
+		if _, ok := a[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// jsonEqual returns whether a and b marshal to the same JSON value.
+func jsonEqual(a, b any) bool {
+	return normalize(a) == normalize(b)
+}
+
+// normalize returns the JSON encoding of v, with object
+// members sorted, by decoding and re-encoding it.
+func normalize(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	var i any
+	if err := json.Unmarshal(data, &i); err != nil {
+		return string(data)
+	}
+	data, _ = json.Marshal(i)
+	return string(data)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	stdout, stderr, status := runCmd("", "diff", "testdata/articles.json", "testdata/articles_v2.json")
+	want := `~ articles/1 attributes.title
+- comments/5
++ people/3
+`
+	assert.Equal(t, want, stdout)
+	assert.Empty(t, stderr)
+	assert.Equal(t, exitFailed, status)
+}
+
+func TestDiff_Same(t *testing.T) {
+	// formatting and member order are ignored
+	in := `{"meta": {"total": 1}, "included": [
+		{"attributes": {"body": "First"}, "id": "5", "type": "comments"},
+		{"type": "people", "id": "2", "attributes": {"name": "Bob"}}
+	], "data": {"relationships": {"author": {"data": {"id": "2", "type": "people"}}}, "type": "articles", "id": "1", "attributes": {"title": "Hello"}}}`
+
+	stdout, stderr, status := runCmd(in, "diff", "-", "testdata/articles.json")
+	assert.Empty(t, stdout)
+	assert.Empty(t, stderr)
+	assert.Equal(t, exitOk, status)
+}
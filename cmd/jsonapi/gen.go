@@ -0,0 +1,742 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/max-waters/jsonapi/jsonapi"
+)
+
+// genType describes a struct type whose methods are generated.
+type genType struct {
+	Name string
+	// Type is the resource type.
+	Type string
+	Id   genMember
+	// Attrs, Rels and Meta hold the members of each
+	// kind, in the order their fields are declared.
+	Attrs []genMember
+	Rels  []genMember
+	Meta  []genMember
+}
+
+// genMember describes a field mapped to a member.
+type genMember struct {
+	// Name is the member name, and Field the Go field name.
+	Name  string
+	Field string
+	// OmitEmpty and Quote are true if the field has
+	// the omitempty and string options.
+	OmitEmpty bool
+	Quote     bool
+	// RelType is the related resource type of relationships,
+	// and ToMany is true for to-many relationships.
+	RelType string
+	ToMany  bool
+	// Pointer is the JSON pointer of the member,
+	// relative to the resource object.
+	Pointer string
+	// Codec is the encoding of the field, or of the elements of
+	// to-many relationships, if it can be generated, and otherwise
+	// nil, in which case the field is encoded by reflection at run
+	// time. SliceType is the field type of to-many relationships.
+	Codec     *genCodec
+	SliceType string
+}
+
+// genCodec describes the encoding of a predeclared
+// basic type, or of a pointer to one.
+type genCodec struct {
+	// Type is the name of the basic type, Kind its kind, eg
+	// uint8 for byte, and Local the type it is decoded into.
+	Type    string
+	Kind    reflect.Kind
+	Local   string
+	Pointer bool
+}
+
+// basicKinds are the kinds of the predeclared types
+// whose encoding is generated.
+var basicKinds = map[string]reflect.Kind{
+	"bool":    reflect.Bool,
+	"string":  reflect.String,
+	"int":     reflect.Int,
+	"int8":    reflect.Int8,
+	"int16":   reflect.Int16,
+	"int32":   reflect.Int32,
+	"rune":    reflect.Int32,
+	"int64":   reflect.Int64,
+	"uint":    reflect.Uint,
+	"uint8":   reflect.Uint8,
+	"byte":    reflect.Uint8,
+	"uint16":  reflect.Uint16,
+	"uint32":  reflect.Uint32,
+	"uint64":  reflect.Uint64,
+	"uintptr": reflect.Uintptr,
+	"float32": reflect.Float32,
+	"float64": reflect.Float64,
+}
+
+// codecOf returns the codec of the field type expr, or nil if it
+// isn't a predeclared basic type or a pointer to one. Other types
+// may implement json.Marshaler, encoding.TextMarshaler and so on,
+// which can't be known without type checking.
+func codecOf(expr ast.Expr, structs map[string]*ast.StructType) *genCodec {
+	c := &genCodec{}
+	if s, ok := expr.(*ast.StarExpr); ok {
+		c.Pointer, expr = true, s.X
+	}
+	ident, ok := expr.(*ast.Ident)
+	if !ok {
+		return nil
+	}
+	if _, ok := structs[ident.Name]; ok {
+		// nb shadows the predeclared type
+		return nil
+	}
+	if c.Kind, ok = basicKinds[ident.Name]; !ok {
+		return nil
+	}
+	c.Type = ident.Name
+
+	switch c.Kind {
+	case reflect.Bool, reflect.String:
+		c.Local = c.Type
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		c.Local = "int64"
+	case reflect.Float32, reflect.Float64:
+		c.Local = "float64"
+	default:
+		c.Local = "uint64"
+	}
+	return c
+}
+
+// quoted returns whether the member is encoded as a
+// string, ie has the string option and is a number.
+func (m genMember) quoted() bool {
+	switch m.Codec.Kind {
+	case reflect.Bool, reflect.String, reflect.Uintptr:
+		return false
+	}
+	return m.Quote
+}
+
+// conversion returns the conversion of the expression x
+// from the type from to the type to.
+func conversion(to, from, x string) string {
+	if to == from {
+		return x
+	}
+	return to + "(" + x + ")"
+}
+
+// marshalValue returns the statements that assign the
+// encoding of x, of the codec's basic type, to dst.
+func (m genMember) marshalValue(dst, x string) string {
+	c := m.Codec
+	if c.Kind == reflect.String || c.Kind == reflect.Float32 || c.Kind == reflect.Float64 {
+		// nb json escapes strings and formats floats
+		// differently to strconv
+		e := "b"
+		if m.quoted() {
+			e = `append(append([]byte{'"'}, b...), '"')`
+		}
+		return fmt.Sprintf("b, err := json.Marshal(%s)\nif err != nil {\nreturn nil, %s\n}\n%s = %s\n", x, m.MarshalErr(), dst, e)
+	}
+
+	b := "nil"
+	if m.quoted() {
+		b = `[]byte{'"'}`
+	}
+	var e string
+	switch c.Kind {
+	case reflect.Bool:
+		e = "strconv.AppendBool(" + b + ", " + x + ")"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		e = "strconv.AppendInt(" + b + ", " + conversion("int64", c.Type, x) + ", 10)"
+	default:
+		e = "strconv.AppendUint(" + b + ", " + conversion("uint64", c.Type, x) + ", 10)"
+	}
+	if m.quoted() {
+		e = "append(" + e + `, '"')`
+	}
+	return dst + " = " + e + "\n"
+}
+
+// MarshalCode returns the statements that assign the encoding of x,
+// the field or an element of a to-many relationship field, to dst.
+func (m genMember) MarshalCode(dst, x string) string {
+	if !m.Codec.Pointer {
+		return strings.TrimSuffix(m.marshalValue(dst, x), "\n")
+	}
+	return fmt.Sprintf("%s = jsonapi.NullJson\nif %s != nil {\n%s}", dst, x, m.marshalValue(dst, "*"+x))
+}
+
+// unmarshalValue returns the statements that decode
+// d into dst, of the codec's basic type.
+func (m genMember) unmarshalValue(dst string) string {
+	c := m.Codec
+	code := ""
+	if m.quoted() {
+		code = fmt.Sprintf("d, err := jsonapi.UnquoteMember(d, %q)\nif err != nil {\nreturn %s\n}\n", c.Kind, m.UnmarshalErr())
+	}
+	return code + fmt.Sprintf("var x %s\nif err := json.Unmarshal(d, &x); err != nil {\nreturn %s\n}\n%s = %s\n",
+		c.Local, m.UnmarshalErr(), dst, conversion(c.Type, c.Local, "x"))
+}
+
+// UnmarshalCode returns the statements that decode the
+// member data d into dst, the field or an element of a
+// to-many relationship field. Null is decoded into
+// pointers as nil.
+func (m genMember) UnmarshalCode(dst string) string {
+	if !m.Codec.Pointer {
+		return strings.TrimSuffix(m.unmarshalValue(dst), "\n")
+	}
+	return fmt.Sprintf("if bytes.Equal(d, jsonapi.NullJson) {\n%s = nil\n} else {\nif %s == nil {\n%s = new(%s)\n}\n%s}",
+		dst, dst, dst, m.Codec.Type, m.unmarshalValue("*"+dst))
+}
+
+// NotEmpty returns the condition under which the member
+// isn't empty, and so isn't omitted by the omitempty option.
+func (m genMember) NotEmpty() string {
+	x := "v." + m.Field
+	switch {
+	case m.Codec == nil:
+		return "!jsonapi.IsEmptyMember(" + x + ")"
+	case m.ToMany:
+		return "len(" + x + ") > 0"
+	}
+
+	zero := "0"
+	switch m.Codec.Kind {
+	case reflect.Bool:
+		zero = "false"
+	case reflect.String:
+		zero = `""`
+	}
+	if m.Codec.Pointer {
+		return x + " != nil && *" + x + " != " + zero
+	}
+	return x + " != " + zero
+}
+
+// MarshalErr returns the expression of the error
+// returned when the member can't be marshaled.
+func (m genMember) MarshalErr() string {
+	return fmt.Sprintf("fmt.Errorf(%s, &jsonapi.MarshalErr{Field: %q, Pointer: %q, Err: err})", m.ErrFormat("marshaling"), m.Name, m.Pointer)
+}
+
+// UnmarshalErr returns the expression of the error
+// returned when the member can't be unmarshaled.
+func (m genMember) UnmarshalErr() string {
+	return fmt.Sprintf("fmt.Errorf(%s, &jsonapi.UnmarshalErr{Field: %q, Pointer: %q, Err: err})", m.ErrFormat("unmarshaling"), m.Name, m.Pointer)
+}
+
+// ErrFormat returns the quoted format string of the
+// error wrapping the member's marshaling error, where
+// op is marshaling or unmarshaling.
+func (m genMember) ErrFormat(op string) string {
+	return strconv.Quote("jsonapi: " + op + " field " + strings.ReplaceAll(m.Name, "%", "%%") + ": %w")
+}
+
+// memberPointer returns the JSON pointer, relative to the
+// resource object, of the member called name of the tag type typ.
+func memberPointer(typ, name string) string {
+	name = strings.NewReplacer("~", "~0", "/", "~1").Replace(name)
+	switch typ {
+	case jsonapi.TagValueAttr:
+		return "/attributes/" + name
+	case jsonapi.TagValueRel:
+		return "/relationships/" + name
+	case jsonapi.TagValueMeta:
+		return "/meta/" + name
+	}
+	return "/" + name
+}
+
+// runGen generates MarshalJsonApiResource and UnmarshalJsonApiResource
+// methods for the named struct types of the Go package in a directory,
+// the current directory by default, eg with go:generate:
+//
+//	//go:generate go run github.com/max-waters/jsonapi/cmd/jsonapi gen -type Article,Comment
+func runGen(args []string, e *env) (int, error) {
+	flags := flag.NewFlagSet("gen", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	typeNames := flags.String("type", "", "comma-separated list of type names")
+	output := flags.String("output", "", "the output file")
+	if err := flags.Parse(args); err != nil {
+		return 0, fmt.Errorf("%w: %v", errUsage, err)
+	}
+	if *typeNames == "" {
+		return 0, fmt.Errorf("%w: -type is required", errUsage)
+	}
+	if flags.NArg() > 1 {
+		return 0, fmt.Errorf("%w: too many arguments", errUsage)
+	}
+
+	dir := "."
+	if flags.NArg() == 1 {
+		dir = flags.Arg(0)
+	}
+
+	names := strings.Split(*typeNames, ",")
+	pkg, structs, err := parsePackage(dir)
+	if err != nil {
+		return 0, err
+	}
+
+	src, err := generate("jsonapi gen "+strings.Join(args, " "), pkg, structs, names)
+	if err != nil {
+		return 0, err
+	}
+
+	if *output == "" {
+		*output = filepath.Join(dir, strings.ToLower(names[0])+"_jsonapi.go")
+	}
+	return exitOk, os.WriteFile(*output, src, 0o644)
+}
+
+// parsePackage parses the non-test Go files in dir, returning
+// the package name and its struct types by name.
+func parsePackage(dir string) (string, map[string]*ast.StructType, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", nil, err
+	}
+
+	fset := token.NewFileSet()
+	pkg := ""
+	structs := map[string]*ast.StructType{}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+
+		f, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.SkipObjectResolution)
+		if err != nil {
+			return "", nil, err
+		}
+		pkg = f.Name.Name
+
+		ast.Inspect(f, func(n ast.Node) bool {
+			if spec, ok := n.(*ast.TypeSpec); ok {
+				if st, ok := spec.Type.(*ast.StructType); ok {
+					structs[spec.Name.Name] = st
+				}
+			}
+			return true
+		})
+	}
+
+	if pkg == "" {
+		return "", nil, fmt.Errorf("%s: no Go files", dir)
+	}
+	return pkg, structs, nil
+}
+
+// generate returns the formatted source of the methods of the
+// named struct types, in a file of package pkg generated by cmd.
+func generate(cmd, pkg string, structs map[string]*ast.StructType, names []string) ([]byte, error) {
+	var types []*genType
+	for _, name := range names {
+		st, ok := structs[name]
+		if !ok {
+			return nil, fmt.Errorf("struct type %s not found", name)
+		}
+		t, err := parseStruct(name, st, structs)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		types = append(types, t)
+	}
+
+	buf := bytes.Buffer{}
+	err := genTemplate.Execute(&buf, map[string]any{"Command": cmd, "Package": pkg, "Imports": genImports(types), "Types": types})
+	if err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
+
+// genImports returns the standard library packages
+// imported by the generated methods of types.
+func genImports(types []*genType) []string {
+	bytesUsed, strconvUsed := false, false
+	for _, t := range types {
+		members := append([]genMember{t.Id}, t.Attrs...)
+		members = append(append(members, t.Rels...), t.Meta...)
+		for _, m := range members {
+			if m.Codec == nil {
+				continue
+			}
+			bytesUsed = bytesUsed || m.Codec.Pointer
+			switch m.Codec.Kind {
+			case reflect.String, reflect.Float32, reflect.Float64:
+			default:
+				strconvUsed = true
+			}
+		}
+	}
+
+	imports := []string{}
+	if bytesUsed {
+		imports = append(imports, "bytes")
+	}
+	imports = append(imports, "encoding/json", "fmt")
+	if strconvUsed {
+		imports = append(imports, "strconv")
+	}
+	return imports
+}
+
+// parseStruct reads the tags of the struct type st called
+// name. Tags and field types that generated code can't encode
+// exactly as the jsonapi package would are rejected.
+func parseStruct(name string, st *ast.StructType, structs map[string]*ast.StructType) (*genType, error) {
+	t := &genType{Name: name}
+	hasId := false
+	seen := map[string]bool{}
+
+	for _, f := range st.Fields.List {
+		tags := reflect.StructTag("")
+		if f.Tag != nil {
+			raw, err := strconv.Unquote(f.Tag.Value)
+			if err != nil {
+				return nil, err
+			}
+			tags = reflect.StructTag(raw)
+		}
+		value, tagged := tags.Lookup(jsonapi.TagKeyJsonApi)
+		typ, opts, _ := strings.Cut(value, ",")
+
+		if len(f.Names) == 0 {
+			if typ == jsonapi.TagValueIgnore {
+				continue
+			}
+			return nil, fmt.Errorf("embedded field %s is not supported", astString(f.Type))
+		}
+
+		if !tagged {
+			typ = jsonapi.TagValueAttr
+		}
+
+		for _, ident := range f.Names {
+			if !ident.IsExported() || typ == jsonapi.TagValueIgnore {
+				continue
+			}
+
+			m := genMember{Field: ident.Name}
+			fieldOpts := opts
+			if typ == jsonapi.TagValueId {
+				t.Type, fieldOpts, _ = strings.Cut(fieldOpts, ",")
+				m.Name = jsonapi.TagValueId
+			} else {
+				m.Name, fieldOpts, _ = strings.Cut(fieldOpts, ",")
+				if m.Name == "" {
+					m.Name, _, _ = strings.Cut(tags.Get("json"), ",")
+				}
+				if m.Name == "" {
+					m.Name = ident.Name
+				}
+			}
+			if typ == jsonapi.TagValueRel {
+				m.RelType, fieldOpts, _ = strings.Cut(fieldOpts, ",")
+			}
+
+			for _, opt := range strings.Split(fieldOpts, ",") {
+				switch opt {
+				case "":
+				case jsonapi.TagValueOmitEmpty:
+					m.OmitEmpty = true
+				case jsonapi.TagValueString:
+					m.Quote = true
+				default:
+					return nil, fmt.Errorf("field %s: option %q is not supported", ident.Name, opt)
+				}
+			}
+
+			if seen[typ+" "+m.Name] {
+				return nil, fmt.Errorf("field %s: %s %s is declared more than once", ident.Name, typ, m.Name)
+			}
+			seen[typ+" "+m.Name] = true
+
+			if specialType(f.Type) {
+				return nil, fmt.Errorf("field %s: type %s is not supported", ident.Name, astString(f.Type))
+			}
+
+			m.Pointer = memberPointer(typ, m.Name)
+			m.Codec = codecOf(f.Type, structs)
+			switch typ {
+			case jsonapi.TagValueId:
+				hasId = true
+				t.Id = m
+			case jsonapi.TagValueAttr:
+				t.Attrs = append(t.Attrs, m)
+			case jsonapi.TagValueMeta:
+				t.Meta = append(t.Meta, m)
+			case jsonapi.TagValueRel:
+				if m.RelType == "" {
+					return nil, fmt.Errorf("field %s: related resource type is required", ident.Name)
+				}
+				toMany, err := relKind(f.Type, structs)
+				if err != nil {
+					return nil, fmt.Errorf("field %s: %w", ident.Name, err)
+				}
+				m.ToMany = toMany
+				if toMany {
+					m.Codec = codecOf(f.Type.(*ast.ArrayType).Elt, structs)
+					m.SliceType = astString(f.Type)
+				}
+				t.Rels = append(t.Rels, m)
+			default:
+				return nil, fmt.Errorf("field %s: %q tags are not supported", ident.Name, typ)
+			}
+		}
+	}
+
+	if !hasId {
+		return nil, fmt.Errorf("no id field")
+	}
+	if t.Type == "" {
+		t.Type = jsonapi.TypeNamePlural(name)
+	}
+	return t, nil
+}
+
+// relKind returns whether the relationship field type expr is a
+// slice of ids, and so to-many, or else an id, or a pointer to one.
+// Related structs, whose ids are found by reflection, aren't supported.
+func relKind(expr ast.Expr, structs map[string]*ast.StructType) (bool, error) {
+	toMany := false
+	if a, ok := expr.(*ast.ArrayType); ok && a.Len == nil {
+		toMany, expr = true, a.Elt
+	}
+	if s, ok := expr.(*ast.StarExpr); ok {
+		expr = s.X
+	}
+
+	switch e := expr.(type) {
+	case *ast.Ident:
+		if _, ok := structs[e.Name]; !ok {
+			return toMany, nil
+		}
+	case *ast.SelectorExpr:
+		return toMany, nil
+	}
+	return false, fmt.Errorf("relationship type %s is not supported", astString(expr))
+}
+
+// specialType returns whether the field type expr, or the type it
+// points to, is one that the jsonapi package encodes specially, ie
+// time.Duration, as a string, or an sql nullable, eg sql.NullString
+// or sql.Null[T], as its value.
+func specialType(expr ast.Expr) bool {
+	if s, ok := expr.(*ast.StarExpr); ok {
+		expr = s.X
+	}
+	switch e := expr.(type) {
+	case *ast.IndexExpr:
+		expr = e.X
+	case *ast.IndexListExpr:
+		expr = e.X
+	}
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return false
+	}
+	return pkg.Name == "time" && sel.Sel.Name == "Duration" ||
+		pkg.Name == "sql" && strings.HasPrefix(sel.Sel.Name, "Null")
+}
+
+// astString returns the source of the expression expr.
+func astString(expr ast.Expr) string {
+	buf := bytes.Buffer{}
+	_ = format.Node(&buf, token.NewFileSet(), expr)
+	return buf.String()
+}
+
+var genTemplate = template.Must(template.New("gen").Parse(`// Code generated by "{{.Command}}"; DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+{{- range .Imports}}
+	{{printf "%q" .}}
+{{- end}}
+
+	"github.com/max-waters/jsonapi/jsonapi"
+)
+{{range .Types}}
+// MarshalJsonApiResource implements jsonapi.ResourceMarshaler.
+func (v {{.Name}}) MarshalJsonApiResource() ([]byte, error) {
+	r := jsonapi.Resource{
+		ResourceIdentifier:  jsonapi.ResourceIdentifier{Type: {{printf "%q" .Type}}, Meta: map[string]json.RawMessage{}},
+		Attributes:          map[string]json.RawMessage{},
+		ToOneRelationships:  map[string]*jsonapi.ToOneResourceLinkage{},
+		ToManyRelationships: map[string]*jsonapi.ToManyResourceLinkage{},
+	}
+{{with .Id}}
+	{{template "omitempty" .}}{
+{{- if .Codec}}
+		{{.MarshalCode "r.Id" (print "v." .Field)}}
+{{- else}}
+		m, err := jsonapi.MarshalMember(v.{{.Field}}, {{.Quote}})
+		if err != nil {
+			return nil, {{.MarshalErr}}
+		}
+		r.Id = m
+{{- end}}
+	}
+{{- end}}
+{{- range .Attrs}}
+	{{template "omitempty" .}}{
+{{- if .Codec}}
+		{{.MarshalCode (printf "r.Attributes[%q]" .Name) (print "v." .Field)}}
+{{- else}}
+		m, err := jsonapi.MarshalMember(v.{{.Field}}, {{.Quote}})
+		if err != nil {
+			return nil, {{.MarshalErr}}
+		}
+		r.Attributes[{{printf "%q" .Name}}] = m
+{{- end}}
+	}
+{{- end}}
+{{- range .Rels}}
+	{{template "omitempty" .}}{
+{{- if and .ToMany .Codec}}
+		ids := make([]jsonapi.ResourceIdentifier, len(v.{{.Field}}))
+		for i, x := range v.{{.Field}} {
+			ids[i].Type = {{printf "%q" .RelType}}
+			{{.MarshalCode "ids[i].Id" "x"}}
+		}
+		r.ToManyRelationships[{{printf "%q" .Name}}] = &jsonapi.ToManyResourceLinkage{Data: ids}
+{{- else if .ToMany}}
+		ids, err := jsonapi.MarshalIds({{printf "%q" .RelType}}, v.{{.Field}}, {{.Quote}})
+		if err != nil {
+			return nil, {{.MarshalErr}}
+		}
+		r.ToManyRelationships[{{printf "%q" .Name}}] = &jsonapi.ToManyResourceLinkage{Data: ids}
+{{- else if .Codec}}
+		l := &jsonapi.ToOneResourceLinkage{Data: jsonapi.ResourceIdentifier{Type: {{printf "%q" .RelType}}}}
+		{{.MarshalCode "l.Data.Id" (print "v." .Field)}}
+		r.ToOneRelationships[{{printf "%q" .Name}}] = l
+{{- else}}
+		id, err := jsonapi.MarshalMember(v.{{.Field}}, {{.Quote}})
+		if err != nil {
+			return nil, {{.MarshalErr}}
+		}
+		r.ToOneRelationships[{{printf "%q" .Name}}] = &jsonapi.ToOneResourceLinkage{
+			Data: jsonapi.ResourceIdentifier{Type: {{printf "%q" .RelType}}, Id: id},
+		}
+{{- end}}
+	}
+{{- end}}
+{{- range .Meta}}
+	{{template "omitempty" .}}{
+{{- if .Codec}}
+		{{.MarshalCode (printf "r.Meta[%q]" .Name) (print "v." .Field)}}
+{{- else}}
+		m, err := jsonapi.MarshalMember(v.{{.Field}}, {{.Quote}})
+		if err != nil {
+			return nil, {{.MarshalErr}}
+		}
+		r.Meta[{{printf "%q" .Name}}] = m
+{{- end}}
+	}
+{{- end}}
+
+	return json.Marshal(&r)
+}
+
+// UnmarshalJsonApiResource implements jsonapi.ResourceUnmarshaler.
+func (v *{{.Name}}) UnmarshalJsonApiResource(data []byte) error {
+	r := jsonapi.Resource{}
+	if err := json.Unmarshal(data, &r); err != nil {
+		return fmt.Errorf("jsonapi: unmarshaling resource: %w", err)
+	}
+{{with .Id}}
+{{- if .Codec}}
+	if d := r.Id; len(d) > 0 {
+		{{.UnmarshalCode (print "v." .Field)}}
+	}
+{{- else}}
+	if err := jsonapi.UnmarshalMember(r.Id, &v.{{.Field}}, {{.Quote}}); err != nil {
+		return {{.UnmarshalErr}}
+	}
+{{- end}}
+{{- end}}
+{{- range .Attrs}}
+	if d, ok := r.Attributes[{{printf "%q" .Name}}]; ok {
+{{- if .Codec}}
+		{{.UnmarshalCode (print "v." .Field)}}
+{{- else}}
+		if err := jsonapi.UnmarshalMember(d, &v.{{.Field}}, {{.Quote}}); err != nil {
+			return {{.UnmarshalErr}}
+		}
+{{- end}}
+	}
+{{- end}}
+{{- range .Rels}}
+{{- if and .ToMany .Codec}}
+	if l, ok := r.ToManyRelationships[{{printf "%q" .Name}}]; ok && len(l.Data) > 0 {
+		v.{{.Field}} = make({{.SliceType}}, len(l.Data))
+		for i, id := range l.Data {
+			if d := id.Id; len(d) > 0 {
+				{{.UnmarshalCode (print "v." .Field "[i]")}}
+			}
+		}
+	}
+{{- else if .ToMany}}
+	if l, ok := r.ToManyRelationships[{{printf "%q" .Name}}]; ok {
+		if err := jsonapi.UnmarshalIds(l.Data, &v.{{.Field}}, {{.Quote}}); err != nil {
+			return {{.UnmarshalErr}}
+		}
+	}
+{{- else if .Codec}}
+	if l, ok := r.ToOneRelationships[{{printf "%q" .Name}}]; ok && len(l.Data.Id) > 0 {
+		d := l.Data.Id
+		{{.UnmarshalCode (print "v." .Field)}}
+	}
+{{- else}}
+	if l, ok := r.ToOneRelationships[{{printf "%q" .Name}}]; ok {
+		if err := jsonapi.UnmarshalMember(l.Data.Id, &v.{{.Field}}, {{.Quote}}); err != nil {
+			return {{.UnmarshalErr}}
+		}
+	}
+{{- end}}
+{{- end}}
+{{- range .Meta}}
+	if d, ok := r.Meta[{{printf "%q" .Name}}]; ok {
+{{- if .Codec}}
+		{{.UnmarshalCode (print "v." .Field)}}
+{{- else}}
+		if err := jsonapi.UnmarshalMember(d, &v.{{.Field}}, {{.Quote}}); err != nil {
+			return {{.UnmarshalErr}}
+		}
+{{- end}}
+	}
+{{- end}}
+
+	return nil
+}
+{{end}}
+{{- define "omitempty"}}{{if .OmitEmpty}}if {{.NotEmpty}} {{end}}{{end}}
+`))
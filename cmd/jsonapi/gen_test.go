@@ -0,0 +1,125 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGen(t *testing.T) {
+	pkg, structs, err := parsePackage("testdata/gen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, err := generate("jsonapi gen -type Article,Person", pkg, structs, []string{"Article", "Person"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := os.ReadFile("testdata/gen/blog_jsonapi.go.golden")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, string(want), string(src))
+}
+
+func TestGen_Gentest(t *testing.T) {
+	pkg, structs, err := parsePackage("internal/gentest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, err := generate("jsonapi gen -type Article", pkg, structs, []string{"Article"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := os.ReadFile("internal/gentest/article_jsonapi.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, string(want), string(src), "run go generate ./cmd/jsonapi/internal/gentest")
+}
+
+func TestGen_Output(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "out.go")
+	stdout, stderr, status := runCmd("", "gen", "-type", "Person", "-output", output, "testdata/gen")
+	assert.Empty(t, stdout)
+	assert.Empty(t, stderr)
+	assert.Equal(t, exitOk, status)
+
+	src, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Contains(t, string(src), `// Code generated by "jsonapi gen -type Person -output `+output+` testdata/gen"; DO NOT EDIT.`)
+	assert.Contains(t, string(src), "func (v *Person) UnmarshalJsonApiResource(data []byte) error {")
+	assert.NotContains(t, string(src), "Article")
+}
+
+func TestCodecOf(t *testing.T) {
+	type testCase struct {
+		Name     string
+		Src      string
+		Expected *genCodec
+	}
+
+	testCases := []testCase{
+		{"String", "string", &genCodec{Type: "string", Kind: reflect.String, Local: "string"}},
+		{"Byte", "byte", &genCodec{Type: "byte", Kind: reflect.Uint8, Local: "uint64"}},
+		{"Pointer", "*float32", &genCodec{Type: "float32", Kind: reflect.Float32, Local: "float64", Pointer: true}},
+		{"PointerPointer", "**int", nil},
+		{"Selector", "time.Time", nil},
+		{"Named", "Status", nil},
+		{"Shadowed", "rune", nil},
+		{"Slice", "[]int", nil},
+	}
+
+	structs := map[string]*ast.StructType{"rune": {}}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			expr, err := parser.ParseExpr(tc.Src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tc.Expected, codecOf(expr, structs))
+		})
+	}
+}
+
+func TestGen_Err(t *testing.T) {
+	type testCase struct {
+		Name     string
+		Src      string
+		Expected string
+	}
+
+	testCases := []testCase{
+		{"NotFound", "type A struct{}", "struct type T not found"},
+		{"NoId", "type T struct{ Name string }", "T: no id field"},
+		{"Embedded", "type A struct{}\ntype T struct{ A }", "T: embedded field A is not supported"},
+		{"Option", "type T struct{ Id string `jsonapi:\"id\"`\nName string `jsonapi:\"attr,name,omitzero\"` }", `T: field Name: option "omitzero" is not supported`},
+		{"TagType", "type T struct{ Id string `jsonapi:\"id\"`\nSelf string `jsonapi:\"link,self\"` }", `T: field Self: "link" tags are not supported`},
+		{"Duplicate", "type T struct{ Id string `jsonapi:\"id\"`\nA, B string `jsonapi:\"attr,name\"` }", "T: field B: attr name is declared more than once"},
+		{"Duration", "type T struct{ Id string `jsonapi:\"id\"`\nTimeout *time.Duration `jsonapi:\"attr,timeout\"` }", "T: field Timeout: type *time.Duration is not supported"},
+		{"SqlNull", "type T struct{ Id sql.NullInt64 `jsonapi:\"id\"` }", "T: field Id: type sql.NullInt64 is not supported"},
+		{"RelType", "type T struct{ Id string `jsonapi:\"id\"`\nA string `jsonapi:\"rel,author\"` }", "T: field A: related resource type is required"},
+		{"RelStruct", "type P struct{ Id string `jsonapi:\"id\"` }\ntype T struct{ Id string `jsonapi:\"id\"`\nA *P `jsonapi:\"rel,author,people\"` }", "T: field A: relationship type P is not supported"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, "t.go"), []byte("package t\n\n"+tc.Src+"\n"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			stdout, stderr, status := runCmd("", "gen", "-type", "T", dir)
+			assert.Empty(t, stdout)
+			assert.Contains(t, stderr, "jsonapi gen: "+tc.Expected)
+			assert.Equal(t, exitFailed, status)
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/max-waters/jsonapi/jsonapi"
+)
+
+// runIncluded prints the included resources
+// of a document with a given type, as an array.
+func runIncluded(args []string, e *env) (int, error) {
+	flags := flag.NewFlagSet("included", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	typ := flags.String("type", "", "the type of the resources to extract")
+	if err := flags.Parse(args); err != nil {
+		return 0, fmt.Errorf("%w: %v", errUsage, err)
+	}
+	if *typ == "" {
+		return 0, fmt.Errorf("%w: -type is required", errUsage)
+	}
+
+	name, err := optionalArg(flags.Args())
+	if err != nil {
+		return 0, err
+	}
+
+	d, err := readDocument(name, e)
+	if err != nil {
+		return 0, err
+	}
+
+	rs := []*jsonapi.Resource{}
+	for _, r := range d.Included {
+		if r != nil && r.Type == *typ {
+			rs = append(rs, r)
+		}
+	}
+	return exitOk, writeJson(rs, e)
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIncluded(t *testing.T) {
+	stdout, stderr, status := runCmd("", "included", "-type", "people", "testdata/articles.json")
+	want := `[
+  {
+    "type": "people",
+    "id": "2",
+    "attributes": {
+      "name": "Bob"
+    }
+  }
+]
+`
+	assert.Equal(t, want, stdout)
+	assert.Empty(t, stderr)
+	assert.Equal(t, exitOk, status)
+
+	stdout, _, status = runCmd(`{"data": null}`, "included", "-type", "people")
+	assert.Equal(t, "[]\n", stdout)
+	assert.Equal(t, exitOk, status)
+}
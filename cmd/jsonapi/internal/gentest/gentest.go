@@ -0,0 +1,23 @@
+// Package gentest holds types whose methods are generated by the
+// jsonapi command's gen subcommand, to test and benchmark generated
+// code against the jsonapi package's encoding of tagged structs.
+package gentest
+
+import "time"
+
+//go:generate go run github.com/max-waters/jsonapi/cmd/jsonapi gen -type Article
+
+type Article struct {
+	Id        int64     `jsonapi:"id,articles,string"`
+	Title     string    `jsonapi:"attr,title"`
+	Body      string    `jsonapi:"attr,body,omitempty"`
+	Views     int64     `jsonapi:"attr,views,string"`
+	Rating    float32   `jsonapi:"attr,rating"`
+	Draft     bool      `jsonapi:"attr,draft"`
+	Published time.Time `jsonapi:"attr,published,omitempty"`
+	Author    string    `jsonapi:"rel,author,people"`
+	Editor    *string   `jsonapi:"rel,editor,people,omitempty"`
+	Tags      []uint    `jsonapi:"rel,tags,tags,string"`
+	Revision  int       `jsonapi:"meta,revision"`
+	Score     *float64  `jsonapi:"meta,score,string"`
+}
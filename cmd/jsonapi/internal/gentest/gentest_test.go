@@ -0,0 +1,171 @@
+package gentest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/max-waters/jsonapi/jsonapi"
+	"github.com/stretchr/testify/assert"
+)
+
+// tagged has Article's fields, but not its
+// generated methods, so it is encoded by reflection.
+type tagged Article
+
+func addrOf[T any](v T) *T {
+	return &v
+}
+
+var article = Article{
+	Id:        1,
+	Title:     "JSON:API <paints> my bikeshed!",
+	Views:     1024,
+	Rating:    4.2,
+	Draft:     true,
+	Published: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	Author:    "9",
+	Editor:    addrOf("10"),
+	Tags:      []uint{2, 3},
+	Revision:  -7,
+	Score:     addrOf(1e21),
+}
+
+func TestGenerated_Marshal(t *testing.T) {
+	type testCase struct {
+		Name    string
+		Article Article
+	}
+
+	testCases := []testCase{
+		{"Full", article},
+		{"Zero", Article{}},
+		{"EmptyEditor", Article{Editor: addrOf("")}},
+		{"EmptyTags", Article{Tags: []uint{}}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			expected, err := jsonapi.MarshalResource(tagged(tc.Article))
+			if err != nil {
+				t.Fatal(err)
+			}
+			actual, err := jsonapi.MarshalResource(tc.Article)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.JSONEq(t, string(expected), string(actual))
+		})
+	}
+}
+
+func TestGenerated_Unmarshal(t *testing.T) {
+	type testCase struct {
+		Name string
+		Json string
+	}
+
+	testCases := []testCase{
+		{"Full", `{
+			"type": "articles",
+			"id": "1",
+			"attributes": {"title": "a", "body": "b", "views": "3", "rating": 1.5, "draft": true, "published": "2024-01-02T03:04:05Z"},
+			"relationships": {
+				"author": {"data": {"type": "people", "id": "9"}},
+				"editor": {"data": {"type": "people", "id": "10"}},
+				"tags": {"data": [{"type": "tags", "id": "2"}, {"type": "tags", "id": "3"}]}
+			},
+			"meta": {"revision": 4, "score": "2.5"}
+		}`},
+		{"Nulls", `{
+			"type": "articles",
+			"id": "1",
+			"attributes": {"title": null, "rating": null},
+			"relationships": {"editor": {"data": null}}
+		}`},
+		{"NoLinkage", `{
+			"type": "articles",
+			"id": "1",
+			"relationships": {"tags": {"data": []}}
+		}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			expected := tagged{Editor: addrOf("x"), Score: addrOf(1.0)}
+			if err := jsonapi.UnmarshalResource([]byte(tc.Json), &expected); err != nil {
+				t.Fatal(err)
+			}
+			actual := Article{Editor: addrOf("x"), Score: addrOf(1.0)}
+			if err := jsonapi.UnmarshalResource([]byte(tc.Json), &actual); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, Article(expected), actual)
+		})
+	}
+}
+
+func TestGenerated_UnmarshalErr(t *testing.T) {
+	type testCase struct {
+		Name    string
+		Json    string
+		Pointer string
+	}
+
+	testCases := []testCase{
+		{"Unquoted", `{"type": "articles", "id": 1}`, "/id"},
+		{"Type", `{"type": "articles", "id": "1", "attributes": {"title": 1}}`, "/attributes/title"},
+		{"UnquotedPointer", `{"type": "articles", "id": "1", "meta": {"score": 2.5}}`, "/meta/score"},
+		{"Element", `{"type": "articles", "id": "1", "relationships": {"tags": {"data": [{"type": "tags", "id": "-1"}]}}}`, "/relationships/tags"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			err := jsonapi.UnmarshalResource([]byte(tc.Json), &Article{})
+			unmarshalErr := &jsonapi.UnmarshalErr{}
+			if assert.ErrorAs(t, err, &unmarshalErr) {
+				assert.Equal(t, tc.Pointer, unmarshalErr.Pointer)
+			}
+			assert.Error(t, jsonapi.UnmarshalResource([]byte(tc.Json), &tagged{}))
+		})
+	}
+}
+
+func BenchmarkMarshalResource(b *testing.B) {
+	b.Run("Generated", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := jsonapi.MarshalResource(article); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("Reflection", func(b *testing.B) {
+		v := tagged(article)
+		for i := 0; i < b.N; i++ {
+			if _, err := jsonapi.MarshalResource(v); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkUnmarshalResource(b *testing.B) {
+	data, err := jsonapi.MarshalResource(article)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.Run("Generated", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if err := jsonapi.UnmarshalResource(data, &Article{}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("Reflection", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if err := jsonapi.UnmarshalResource(data, &tagged{}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,187 @@
+// Command jsonapi validates, pretty-prints, converts and compares
+// JSON:API documents. It is built on the public API of the jsonapi
+// package, and so also serves as an integration test of it.
+//
+// Usage:
+//
+//	jsonapi validate [file]
+//	jsonapi fmt [file]
+//	jsonapi convert -schema schema.json [file]
+//	jsonapi included -type type [file]
+//	jsonapi diff file1 file2
+//	jsonapi gen -type types [-output file] [dir]
+//	jsonapi vet [dir...]
+//
+// Documents are read from the named file, or from standard
+// input if the file is "-" or omitted.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/max-waters/jsonapi/jsonapi"
+)
+
+const usage = `usage:
+  jsonapi validate [file]
+  jsonapi fmt [file]
+  jsonapi convert -schema schema.json [file]
+  jsonapi included -type type [file]
+  jsonapi diff file1 file2
+  jsonapi gen -type types [-output file] [dir]
+  jsonapi vet [dir...]
+`
+
+// exit statuses
+const (
+	exitOk     = 0
+	exitFailed = 1
+	exitUsage  = 2
+)
+
+// errUsage is returned by commands given invalid arguments.
+var errUsage = errors.New("invalid arguments")
+
+// command runs a subcommand with its arguments, returning
+// its exit status, or an error if it couldn't be run.
+type command func(args []string, env *env) (int, error)
+
+var commands = map[string]command{
+	"validate": runValidate,
+	"fmt":      runFmt,
+	"convert":  runConvert,
+	"included": runIncluded,
+	"diff":     runDiff,
+	"gen":      runGen,
+	"vet":      runVet,
+}
+
+// env holds the standard streams of a command.
+type env struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], &env{os.Stdin, os.Stdout, os.Stderr}))
+}
+
+// run runs the command named by args[0], and returns the exit status.
+func run(args []string, e *env) int {
+	if len(args) == 0 {
+		fmt.Fprint(e.stderr, usage)
+		return exitUsage
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(e.stderr, "jsonapi: unknown command %q\n%s", args[0], usage)
+		return exitUsage
+	}
+
+	status, err := cmd(args[1:], e)
+	switch {
+	case errors.Is(err, errUsage):
+		fmt.Fprintf(e.stderr, "jsonapi %s: %v\n%s", args[0], err, usage)
+		return exitUsage
+	case err != nil:
+		fmt.Fprintf(e.stderr, "jsonapi %s: %v\n", args[0], err)
+		return exitFailed
+	}
+	return status
+}
+
+// readInput reads the named file, or stdin if name is "-" or empty.
+func readInput(name string, e *env) ([]byte, error) {
+	if name == "" || name == "-" {
+		return io.ReadAll(e.stdin)
+	}
+	return os.ReadFile(name)
+}
+
+// readDocument reads and unmarshals the named document.
+func readDocument(name string, e *env) (*jsonapi.Document, error) {
+	data, err := readInput(name, e)
+	if err != nil {
+		return nil, err
+	}
+
+	d := jsonapi.Document{}
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, fmt.Errorf("%s: %w", displayName(name), err)
+	}
+	return &d, nil
+}
+
+// displayName returns the name of the input
+// file for messages, which is "-" for stdin.
+func displayName(name string) string {
+	if name == "" {
+		return "-"
+	}
+	return name
+}
+
+// writeJson writes v to e.stdout as indented JSON.
+func writeJson(v any, e *env) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	buf := bytes.Buffer{}
+	if err := json.Indent(&buf, data, "", "  "); err != nil {
+		return err
+	}
+	buf.WriteByte('\n')
+	_, err = buf.WriteTo(e.stdout)
+	return err
+}
+
+// optionalArg returns the single optional positional argument
+// of args, which is empty if there are none.
+func optionalArg(args []string) (string, error) {
+	switch len(args) {
+	case 0:
+		return "", nil
+	case 1:
+		return args[0], nil
+	default:
+		return "", fmt.Errorf("%w: too many files", errUsage)
+	}
+}
+
+// resourceKey returns the "type/id" key of r, or "type/lid"
+// if it has no id, with string ids unquoted.
+func resourceKey(r *jsonapi.Resource) string {
+	if len(r.Id) == 0 {
+		if r.Lid != "" {
+			return r.Type + "/" + r.Lid
+		}
+		return r.Type
+	}
+	id := ""
+	if err := json.Unmarshal(r.Id, &id); err != nil {
+		id = string(r.Id)
+	}
+	return r.Type + "/" + id
+}
+
+// documentResources returns the primary and included resources of d.
+func documentResources(d *jsonapi.Document) []*jsonapi.Resource {
+	var rs []*jsonapi.Resource
+	if d.Data != nil {
+		if d.Data.Collection {
+			rs = append(rs, d.Data.Resources...)
+		} else if d.Data.Resource != nil {
+			rs = append(rs, d.Data.Resource)
+		}
+	}
+	return append(rs, d.Included...)
+}
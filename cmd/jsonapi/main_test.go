@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// runCmd runs the command line args with the supplied stdin,
+// returning its stdout, stderr and exit status.
+func runCmd(stdin string, args ...string) (string, string, int) {
+	stdout, stderr := bytes.Buffer{}, bytes.Buffer{}
+	status := run(args, &env{strings.NewReader(stdin), &stdout, &stderr})
+	return stdout.String(), stderr.String(), status
+}
+
+func TestRun_Usage(t *testing.T) {
+	type testCase struct {
+		Args     []string
+		Expected string
+	}
+
+	testCases := []testCase{
+		{nil, "usage:"},
+		{[]string{"blah"}, `unknown command "blah"`},
+		{[]string{"fmt", "a.json", "b.json"}, "too many files"},
+		{[]string{"diff", "a.json"}, "two files are required"},
+		{[]string{"included"}, "-type is required"},
+		{[]string{"gen"}, "-type is required"},
+		{[]string{"convert", "-blah"}, "flag provided but not defined"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(strings.Join(tc.Args, " "), func(t *testing.T) {
+			stdout, stderr, status := runCmd("", tc.Args...)
+			assert.Empty(t, stdout)
+			assert.Contains(t, stderr, tc.Expected)
+			assert.Equal(t, exitUsage, status)
+		})
+	}
+}
+
+func TestRun_Err(t *testing.T) {
+	stdout, stderr, status := runCmd("", "fmt", "testdata/missing.json")
+	assert.Empty(t, stdout)
+	assert.Contains(t, stderr, "jsonapi fmt: open testdata/missing.json")
+	assert.Equal(t, exitFailed, status)
+
+	_, stderr, status = runCmd(`{"data": 1}`, "fmt")
+	assert.Contains(t, stderr, "jsonapi fmt: -: cannot unmarshal into primary data")
+	assert.Equal(t, exitFailed, status)
+}
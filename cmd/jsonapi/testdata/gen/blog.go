@@ -0,0 +1,24 @@
+package blog
+
+import "time"
+
+type Article struct {
+	Id        int       `jsonapi:"id,articles,string"`
+	Title     string    `jsonapi:"attr,title"`
+	Body      string    `jsonapi:"attr,body,omitempty"`
+	Views     int64     `jsonapi:"attr,views,string"`
+	Draft     bool      `json:"draft"`
+	Published time.Time `jsonapi:"attr,published,omitempty"`
+	Author    string    `jsonapi:"rel,author,people"`
+	Editor    *string   `jsonapi:"rel,editor,people,omitempty"`
+	Tags      []int     `jsonapi:"rel,tags,tags,string"`
+	Revision  int       `jsonapi:"meta,revision"`
+	Score     *float64  `jsonapi:"meta,score,string"`
+	Internal  string    `jsonapi:"-"`
+	notes     []string
+}
+
+type Person struct {
+	Id   string `jsonapi:"id"`
+	Name string `jsonapi:"attr,name"`
+}
@@ -0,0 +1,45 @@
+package models
+
+type Node interface {
+	ID() string
+}
+
+type Article struct {
+	ID       string         `jsonapi:"id,articles"`
+	Title    string         `jsonapi:"attr,title"`
+	Heading  string         `jsonapi:"attr,title"`
+	Kind     string         `jsonapi:"attr,type"`
+	Author   string         `jsonapi:"rel,author"`
+	Parent   Node           `jsonapi:"rel,parent"`
+	Children []any          `jsonapi:"rel,children"`
+	Editor   string         `jsonapi:"rel,title,people"`
+	Callback func()         `jsonapi:"attr,callback"`
+	Updates  chan int       `jsonapi:"meta,updates"`
+	Local    int            `jsonapi:"lid"`
+	Summary  string         `jsonapi:"attribute,summary"`
+	Views    int            `jsonapi:"meta,views"`
+	Count    int            `jsonapi:"meta,views"`
+	Secret   string         `jsonapi:"-"`
+	Extra    map[string]any `jsonapi:"attr-map"`
+	Body     string         `json:"body" jsonapi:"attr"`
+	Text     string         `jsonapi:"attr,body"`
+	Ignored  string
+	Reviewer *Person `jsonapi:"rel,reviewer"`
+}
+
+type Person struct {
+	ID   string `jsonapi:"id,people"`
+	Name string `jsonapi:"attr,name"`
+}
+
+type Post struct {
+	ID            string   `jsonapi:"id,posts"`
+	Comments      []string `jsonapi:"rel,comments,comments"`
+	CommentsTotal int      `jsonapi:"meta,total,rel=comments"`
+	Total         int      `jsonapi:"meta,total"`
+}
+
+type Result struct {
+	Article *Article `jsonapi:"union,articles"`
+	Person  *Person  `jsonapi:"union,people"`
+}
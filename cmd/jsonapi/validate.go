@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/max-waters/jsonapi/jsonapi"
+)
+
+// runValidate checks that a document is well formed, printing
+// any problems found, and fails if there are any.
+func runValidate(args []string, e *env) (int, error) {
+	name, err := optionalArg(args)
+	if err != nil {
+		return 0, err
+	}
+
+	d, err := readDocument(name, e)
+	if err != nil {
+		return 0, err
+	}
+
+	problems := validate(d)
+	for _, p := range problems {
+		fmt.Fprintf(e.stdout, "%s: %s\n", displayName(name), p)
+	}
+	if len(problems) > 0 {
+		return exitFailed, nil
+	}
+	return exitOk, nil
+}
+
+// validate returns the ways in which d breaks the
+// structural rules of the JSON:API specification.
+func validate(d *jsonapi.Document) []string {
+	var problems []string
+	if d.Data == nil && d.Errors == nil && d.Meta == nil {
+		problems = append(problems, "document must contain at least one of data, errors or meta")
+	}
+	if d.Data != nil && d.Errors != nil {
+		problems = append(problems, "document must not contain both data and errors")
+	}
+	if d.Data == nil && d.Included != nil {
+		problems = append(problems, "document must not contain included without data")
+	}
+
+	seen := map[string]bool{}
+	check := func(r *jsonapi.Resource, member string, needId bool) {
+		if r.Type == "" {
+			problems = append(problems, member+": resource must have a type")
+		}
+		if needId && len(r.Id) == 0 && r.Lid == "" {
+			problems = append(problems, member+": resource must have an id or lid")
+		}
+		if len(r.Id) == 0 && r.Lid == "" {
+			return
+		}
+		key := resourceKey(r)
+		if seen[key] {
+			problems = append(problems, member+": duplicate resource "+key)
+		}
+		seen[key] = true
+	}
+
+	if d.Data != nil {
+		if d.Data.Collection {
+			for i, r := range d.Data.Resources {
+				check(r, fmt.Sprintf("data[%d]", i), true)
+			}
+		} else if d.Data.Resource != nil {
+			// the primary data of a creation request may lack an id
+			check(d.Data.Resource, "data", false)
+		}
+	}
+	for i, r := range d.Included {
+		check(r, fmt.Sprintf("included[%d]", i), true)
+	}
+
+	return problems
+}
+
+// runFmt pretty-prints a document.
+func runFmt(args []string, e *env) (int, error) {
+	name, err := optionalArg(args)
+	if err != nil {
+		return 0, err
+	}
+
+	d, err := readDocument(name, e)
+	if err != nil {
+		return 0, err
+	}
+
+	return exitOk, writeJson(d, e)
+}
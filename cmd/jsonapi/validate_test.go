@@ -0,0 +1,68 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	type testCase struct {
+		Name     string
+		Json     string
+		Expected string
+	}
+
+	testCases := []testCase{
+		{"empty", `{}`, "-: document must contain at least one of data, errors or meta\n"},
+		{"data and errors", `{"data": null, "errors": []}`, "-: document must not contain both data and errors\n"},
+		{"included without data", `{"meta": {}, "included": []}`, "-: document must not contain included without data\n"},
+		{"no type", `{"data": [{"id": "1"}]}`, "-: data[0]: resource must have a type\n"},
+		{"no id", `{"data": {"type": "articles"}, "included": [{"type": "people"}]}`, "-: included[0]: resource must have an id or lid\n"},
+		{"duplicate", `{"data": {"type": "articles", "id": "1"}, "included": [{"type": "articles", "id": "1"}]}`, "-: included[0]: duplicate resource articles/1\n"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			stdout, stderr, status := runCmd(tc.Json, "validate")
+			assert.Equal(t, tc.Expected, stdout)
+			assert.Empty(t, stderr)
+			assert.Equal(t, exitFailed, status)
+		})
+	}
+}
+
+func TestValidate_Ok(t *testing.T) {
+	stdout, stderr, status := runCmd("", "validate", "testdata/articles.json")
+	assert.Empty(t, stdout)
+	assert.Empty(t, stderr)
+	assert.Equal(t, exitOk, status)
+}
+
+func TestValidate_NullResource(t *testing.T) {
+	stdout, stderr, status := runCmd(`{"data": [null]}`, "validate")
+	assert.Empty(t, stdout)
+	assert.Contains(t, stderr, "resource must not be null")
+	assert.Equal(t, exitFailed, status)
+}
+
+func TestFmt(t *testing.T) {
+	stdout, stderr, status := runCmd(`{"meta": {"total": 1}, "data": {"type": "articles", "id": "1", "attributes": {"title": "Hello", "body": "World"}}}`, "fmt")
+	want := `{
+  "data": {
+    "type": "articles",
+    "id": "1",
+    "attributes": {
+      "title": "Hello",
+      "body": "World"
+    }
+  },
+  "meta": {
+    "total": 1
+  }
+}
+`
+	assert.Equal(t, want, stdout)
+	assert.Empty(t, stderr)
+	assert.Equal(t, exitOk, status)
+}
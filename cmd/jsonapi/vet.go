@@ -0,0 +1,282 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/max-waters/jsonapi/jsonapi"
+)
+
+// vetProblem is a problem with a tag, at pos.
+type vetProblem struct {
+	pos token.Position
+	msg string
+}
+
+// runVet checks the jsonapi tags of the struct types of the Go
+// packages in directories, the current directory by default,
+// printing the problems that would otherwise only be found when
+// the structs are marshaled, and fails if there are any.
+func runVet(args []string, e *env) (int, error) {
+	dirs := args
+	if len(dirs) == 0 {
+		dirs = []string{"."}
+	}
+
+	status := exitOk
+	for _, dir := range dirs {
+		problems, err := vetDir(dir)
+		if err != nil {
+			return 0, err
+		}
+		for _, p := range problems {
+			fmt.Fprintf(e.stdout, "%s: %s\n", p.pos, p.msg)
+			status = exitFailed
+		}
+	}
+	return status, nil
+}
+
+// vetDir returns the problems with the tags of
+// the Go files in dir, including tests, in order.
+func vetDir(dir string) ([]vetProblem, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, filepath.Join(dir, entry.Name()), nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+
+	// interface types, and struct types whose id tags declare a
+	// type, whose relationships needn't declare a type
+	typed := map[string]bool{}
+	for _, f := range files {
+		ast.Inspect(f, func(n ast.Node) bool {
+			if spec, ok := n.(*ast.TypeSpec); ok {
+				switch t := spec.Type.(type) {
+				case *ast.InterfaceType:
+					typed[spec.Name.Name] = true
+				case *ast.StructType:
+					typed[spec.Name.Name] = declaresType(t)
+				}
+			}
+			return true
+		})
+	}
+
+	var problems []vetProblem
+	for _, f := range files {
+		ast.Inspect(f, func(n ast.Node) bool {
+			if st, ok := n.(*ast.StructType); ok {
+				problems = append(problems, vetStruct(fset, st, typed)...)
+			}
+			return true
+		})
+	}
+	return problems, nil
+}
+
+// vetStruct returns the problems with the jsonapi tags of the struct
+// type st, at the positions of their fields. typed holds the names of
+// the package's interface types, and of its struct types whose id tags
+// declare a type.
+func vetStruct(fset *token.FileSet, st *ast.StructType, typed map[string]bool) []vetProblem {
+	var problems []vetProblem
+	report := func(pos token.Pos, format string, args ...any) {
+		problems = append(problems, vetProblem{fset.Position(pos), fmt.Sprintf(format, args...)})
+	}
+
+	// the fields of attributes and relationships, which share
+	// a namespace, and of meta, by member name
+	members := map[string]string{}
+	meta := map[string]string{}
+	for _, f := range st.Fields.List {
+		if f.Tag == nil {
+			continue
+		}
+		raw, err := strconv.Unquote(f.Tag.Value)
+		if err != nil {
+			continue
+		}
+		tags := reflect.StructTag(raw)
+		value, ok := tags.Lookup(jsonapi.TagKeyJsonApi)
+		if !ok {
+			continue
+		}
+		typ, opts, _ := strings.Cut(value, ",")
+
+		for _, ident := range fieldNames(f) {
+			if !slices.Contains(jsonapi.TagTypes(), typ) {
+				report(f.Pos(), "field %s: unknown tag type %q", ident, typ)
+				continue
+			}
+			if typ == jsonapi.TagValueIgnore {
+				continue
+			}
+			if k := unsupportedKind(f.Type); k != "" {
+				report(f.Pos(), "field %s: unsupported %s type %s", ident, typ, k)
+			}
+
+			name, rest, _ := strings.Cut(opts, ",")
+			switch typ {
+			case jsonapi.TagValueLid:
+				if !isStringType(f.Type) {
+					report(f.Pos(), "field %s: lid must be a string", ident)
+				}
+			case jsonapi.TagValueRel:
+				if rscType, _, _ := strings.Cut(rest, ","); rscType == "" && !isTypedRel(f.Type, typed) {
+					report(f.Pos(), "field %s: rel tag requires a resource type", ident)
+				}
+			}
+
+			if typ != jsonapi.TagValueAttr && typ != jsonapi.TagValueRel && typ != jsonapi.TagValueMeta {
+				continue
+			}
+			if name == "" {
+				name, _, _ = strings.Cut(tags.Get("json"), ",")
+			}
+			if name == "" {
+				name = ident
+			}
+
+			seen := members
+			if typ == jsonapi.TagValueMeta {
+				seen = meta
+				if rel, ok := jsonapi.RelOption(rest); ok {
+					// relationship meta, which has
+					// a namespace per relationship
+					name = rel + "." + name
+				}
+			} else if name == "type" || name == "id" {
+				report(f.Pos(), "field %s: member name %q is reserved", ident, name)
+			}
+			if other, ok := seen[name]; ok {
+				report(f.Pos(), "field %s: member name %q is also used by field %s", ident, name, other)
+				continue
+			}
+			seen[name] = ident
+		}
+	}
+	return problems
+}
+
+// fieldNames returns the names of the field f,
+// or the name of its type if it's embedded.
+func fieldNames(f *ast.Field) []string {
+	if len(f.Names) == 0 {
+		return []string{astString(f.Type)}
+	}
+	names := make([]string, len(f.Names))
+	for i, ident := range f.Names {
+		names[i] = ident.Name
+	}
+	return names
+}
+
+// derefExpr returns the type expr points to, if it's a pointer type.
+func derefExpr(expr ast.Expr) ast.Expr {
+	for {
+		s, ok := expr.(*ast.StarExpr)
+		if !ok {
+			return expr
+		}
+		expr = s.X
+	}
+}
+
+// unsupportedKind returns the kind of the field type expr, or of the
+// type it points to, if the jsonapi package can't marshal it, or "".
+func unsupportedKind(expr ast.Expr) string {
+	switch e := derefExpr(expr).(type) {
+	case *ast.FuncType:
+		return "func"
+	case *ast.ChanType:
+		return "chan"
+	case *ast.Ident:
+		if e.Name == "complex64" || e.Name == "complex128" {
+			return e.Name
+		}
+	}
+	return ""
+}
+
+// isStringType returns whether the field type expr may be a string,
+// or a pointer to one, ie it isn't a different built-in type.
+func isStringType(expr ast.Expr) bool {
+	switch e := derefExpr(expr).(type) {
+	case *ast.Ident:
+		return e.Name == "string" || !isBuiltinType(e.Name)
+	case *ast.SelectorExpr:
+		return true
+	}
+	return false
+}
+
+// declaresType returns whether the struct type st has an
+// id tag that declares a resource type.
+func declaresType(st *ast.StructType) bool {
+	for _, f := range st.Fields.List {
+		if f.Tag == nil {
+			continue
+		}
+		raw, err := strconv.Unquote(f.Tag.Value)
+		if err != nil {
+			continue
+		}
+		value := reflect.StructTag(raw).Get(jsonapi.TagKeyJsonApi)
+		if typ, opts, _ := strings.Cut(value, ","); typ == jsonapi.TagValueId {
+			rscType, _, _ := strings.Cut(opts, ",")
+			return rscType != ""
+		}
+	}
+	return false
+}
+
+// isTypedRel returns whether the field type expr may be an interface,
+// or a pointer to or slice of them, as polymorphic relationships are,
+// or a related struct that declares its type, ie one named by typed.
+// Types of other packages may be.
+func isTypedRel(expr ast.Expr, typed map[string]bool) bool {
+	expr = derefExpr(expr)
+	if a, ok := expr.(*ast.ArrayType); ok {
+		expr = derefExpr(a.Elt)
+	}
+	switch e := expr.(type) {
+	case *ast.InterfaceType, *ast.SelectorExpr:
+		return true
+	case *ast.Ident:
+		return e.Name == "any" || typed[e.Name]
+	}
+	return false
+}
+
+// isBuiltinType returns whether name is that of a predeclared type.
+func isBuiltinType(name string) bool {
+	switch name {
+	case "bool", "string", "error", "any", "byte", "rune",
+		"int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64", "uintptr",
+		"float32", "float64", "complex64", "complex128":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVet(t *testing.T) {
+	stdout, stderr, status := runCmd("", "vet", "testdata/vet")
+	assert.Empty(t, stderr)
+	assert.Equal(t, exitFailed, status)
+
+	expected := []string{
+		`testdata/vet/models.go:10:2: field Heading: member name "title" is also used by field Title`,
+		`testdata/vet/models.go:11:2: field Kind: member name "type" is reserved`,
+		`testdata/vet/models.go:12:2: field Author: rel tag requires a resource type`,
+		`testdata/vet/models.go:15:2: field Editor: member name "title" is also used by field Title`,
+		`testdata/vet/models.go:16:2: field Callback: unsupported attr type func`,
+		`testdata/vet/models.go:17:2: field Updates: unsupported meta type chan`,
+		`testdata/vet/models.go:18:2: field Local: lid must be a string`,
+		`testdata/vet/models.go:19:2: field Summary: unknown tag type "attribute"`,
+		`testdata/vet/models.go:21:2: field Count: member name "views" is also used by field Views`,
+		`testdata/vet/models.go:25:2: field Text: member name "body" is also used by field Body`,
+	}
+	assert.Equal(t, strings.Join(expected, "\n")+"\n", stdout)
+}
+
+func TestVet_Ok(t *testing.T) {
+	stdout, stderr, status := runCmd("", "vet", "testdata/gen")
+	assert.Empty(t, stdout)
+	assert.Empty(t, stderr)
+	assert.Equal(t, exitOk, status)
+}
+
+func TestVet_Err(t *testing.T) {
+	_, stderr, status := runCmd("", "vet", "testdata/missing")
+	assert.Contains(t, stderr, "jsonapi vet: open testdata/missing")
+	assert.Equal(t, exitFailed, status)
+}
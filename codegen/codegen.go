@@ -0,0 +1,230 @@
+// Package codegen generates client types for other languages from
+// the JSON:API representation of tagged structs, as described by
+// jsonapi.TypeInfo, so that clients share the contract defined by
+// the Go types.
+package codegen
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+
+	"github.com/max-waters/jsonapi/jsonapi"
+)
+
+var (
+	timeType          = reflect.TypeFor[time.Time]()
+	durationType      = reflect.TypeFor[time.Duration]()
+	rawMessageType    = reflect.TypeFor[json.RawMessage]()
+	jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// Language generates client types in one language by executing a
+// text/template with the []*jsonapi.TypeInfo of the resources. The
+// template can be replaced, eg to add annotations or change the
+// output's structure, and use the language's functions, such as
+// "type", which returns the language's type for a Go type.
+type Language struct {
+	Name     string
+	Funcs    template.FuncMap
+	Template string
+}
+
+// Generate writes the client types for the resources described by
+// types to w. Every type must be named, as the generated types are
+// named after the Go types.
+func (l Language) Generate(w io.Writer, types []*jsonapi.TypeInfo) error {
+	for _, t := range types {
+		if t.GoType.Name() == "" {
+			return fmt.Errorf("codegen: cannot name anonymous struct type for resource type '%s'", t.Type)
+		}
+	}
+
+	tmpl, err := template.New(l.Name).Funcs(l.Funcs).Parse(l.Template)
+	if err != nil {
+		return fmt.Errorf("codegen: parsing %s template: %w", l.Name, err)
+	}
+	return tmpl.Execute(w, types)
+}
+
+// typeKind classifies Go types by their JSON encoding, which determines
+// their type in the generated languages.
+type typeKind int
+
+const (
+	kindUnknown typeKind = iota
+	kindBool
+	kindInt
+	kindInt64
+	kindFloat32
+	kindFloat64
+	kindString
+	kindList
+	kindMap
+	kindObject
+	kindNullable
+)
+
+// kindOf returns the kind of the JSON encoding of t, which is
+// a string if quoted is true and t is a number or bool. The format
+// is the time or duration format of a time.Time or time.Duration
+// member, and is empty for values encoded by encoding/json.
+func kindOf(t reflect.Type, quoted bool, format string) typeKind {
+	k := kindUnknown
+	switch {
+	case t.Kind() == reflect.Pointer:
+		return kindNullable
+	case t == timeType && (format == "unix" || format == "unixmilli"):
+		k = kindInt64
+	case t == timeType, t == durationType && format == "string":
+		return kindString
+	case t == durationType && (format == "seconds" || format == "milliseconds"):
+		k = kindFloat64
+	case t == rawMessageType, t.Implements(jsonMarshalerType):
+		return kindUnknown
+	case t.Implements(textMarshalerType):
+		return kindString
+	default:
+		switch t.Kind() {
+		case reflect.Bool:
+			k = kindBool
+		case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+			k = kindInt
+		case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			k = kindInt64
+		case reflect.Float32:
+			k = kindFloat32
+		case reflect.Float64:
+			k = kindFloat64
+		case reflect.String:
+			return kindString
+		case reflect.Slice:
+			if t.Elem().Kind() == reflect.Uint8 {
+				// base64 encoded
+				return kindString
+			}
+			return kindList
+		case reflect.Array:
+			return kindList
+		case reflect.Map:
+			return kindMap
+		case reflect.Struct:
+			return kindObject
+		}
+	}
+
+	if quoted && k != kindUnknown {
+		return kindString
+	}
+	return k
+}
+
+// camelCase returns name as an identifier for languages whose members
+// are camel case, eg "published-at" as "publishedAt".
+func camelCase(name string) string {
+	b := strings.Builder{}
+	upper := false
+	for _, r := range name {
+		if r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if b.Len() == 0 && unicode.IsDigit(r) {
+			b.WriteByte('_')
+		}
+		if upper && b.Len() > 0 {
+			r = unicode.ToUpper(r)
+		}
+		b.WriteRune(r)
+		upper = false
+	}
+	if b.Len() == 0 {
+		return "_"
+	}
+	return b.String()
+}
+
+// nullable returns typ with a trailing question mark, unless it has one.
+func nullable(typ string) string {
+	if strings.HasSuffix(typ, "?") {
+		return typ
+	}
+	return typ + "?"
+}
+
+// memberType returns the type of the JSON encoding of the member m,
+// which is a pointer to the value of a database/sql nullable, such
+// as sql.NullString, as it is encoded as its value or null.
+func memberType(m jsonapi.MemberInfo) reflect.Type {
+	t := m.GoType
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if isSqlNull(t) {
+		return reflect.PointerTo(t.Field(0).Type)
+	}
+	return m.GoType
+}
+
+// isSqlNull returns true if t is one of the database/sql package's
+// nullable types, eg sql.NullString or sql.Null[T].
+func isSqlNull(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct &&
+		t.PkgPath() == "database/sql" &&
+		strings.HasPrefix(t.Name(), "Null") &&
+		t.NumField() == 2 &&
+		t.Field(1).Name == "Valid" &&
+		t.Field(1).Type.Kind() == reflect.Bool
+}
+
+// jsonField is a member of the encoding/json encoding of a struct.
+type jsonField struct {
+	Name string
+	Type reflect.Type
+	// Optional and Quoted are true if the field has
+	// the omitempty and string options.
+	Optional bool
+	Quoted   bool
+}
+
+// jsonFields returns the members of the encoding/json
+// encoding of the struct type t, in field order.
+func jsonFields(t reflect.Type) []jsonField {
+	var fields []jsonField
+	var named [][]int // embedded structs encoded as named members
+	for _, f := range reflect.VisibleFields(t) {
+		if slices.ContainsFunc(named, func(idx []int) bool {
+			return len(f.Index) > len(idx) && slices.Equal(f.Index[:len(idx)], idx)
+		}) {
+			continue
+		}
+		if f.Anonymous && f.Tag.Get("json") != "" {
+			named = append(named, f.Index)
+		}
+		if !f.IsExported() || f.Anonymous && f.Tag.Get("json") == "" {
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" && opts == "" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields = append(fields, jsonField{
+			Name:     name,
+			Type:     f.Type,
+			Optional: strings.Contains(","+opts+",", ",omitempty,"),
+			Quoted:   strings.Contains(","+opts+",", ",string,"),
+		})
+	}
+	return fields
+}
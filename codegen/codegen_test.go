@@ -0,0 +1,131 @@
+package codegen
+
+import (
+	"bytes"
+	"database/sql"
+	"flag"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/max-waters/jsonapi/jsonapi"
+	"github.com/stretchr/testify/assert"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestLanguage_Generate(t *testing.T) {
+	lang := KotlinLanguage
+	lang.Template = `{{range .}}{{.GoType.Name}}:{{range .Attributes}} {{name .Name}}={{type .}}{{end}}
+{{end}}`
+
+	buf := bytes.Buffer{}
+	if err := lang.Generate(&buf, describeTypes(t)); err != nil {
+		t.Fatal(err)
+	}
+	want := "Article: extra=Map<String, JsonElement> publishedAt=String rating=Double tags=List<String> title=String\n" +
+		"Person: address=JsonObject? name=String\n"
+	assert.Equal(t, want, buf.String())
+
+	lang.Template = "{{"
+	assert.Error(t, lang.Generate(&bytes.Buffer{}, nil))
+}
+
+type Timing struct {
+	Id       string           `jsonapi:"id,timings"`
+	Elapsed  time.Duration    `jsonapi:"attr,elapsed"`
+	Seconds  time.Duration    `jsonapi:"attr,seconds,format=seconds"`
+	Nanos    *time.Duration   `jsonapi:"attr,nanos,format=nanoseconds,string"`
+	Started  time.Time        `jsonapi:"attr,started,format=unix"`
+	Day      time.Time        `jsonapi:"attr,day,format=date"`
+	Note     sql.NullString   `jsonapi:"attr,note"`
+	Count    *sql.NullInt32   `jsonapi:"attr,count"`
+	Weight   sql.Null[int64]  `jsonapi:"attr,weight,string"`
+	Interval []time.Duration  `jsonapi:"attr,interval"`
+	Ended    *time.Time       `jsonapi:"attr,ended,format=unixmilli"`
+	Labels   map[string]int64 `jsonapi:"attr,labels"`
+}
+
+func TestMemberType(t *testing.T) {
+	info, err := jsonapi.DescribeType(Timing{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"count":    "number | null",
+		"day":      "string",
+		"elapsed":  "string",
+		"ended":    "number | null",
+		"interval": "number[]",
+		"labels":   "Record<string, number>",
+		"nanos":    "string | null",
+		"note":     "string | null",
+		"seconds":  "number",
+		"started":  "number",
+		"weight":   "string | null",
+	}
+	got := map[string]string{}
+	for _, a := range info.Attributes {
+		got[a.Name] = tsType(a)
+	}
+	assert.Equal(t, want, got)
+
+	kinds := map[string]string{}
+	for _, a := range info.Attributes {
+		kinds[a.Name] = ktType(a)
+	}
+	assert.Equal(t, map[string]string{
+		"count":    "Int?",
+		"day":      "String",
+		"elapsed":  "String",
+		"ended":    "Long?",
+		"interval": "List<Long>",
+		"labels":   "Map<String, Long>",
+		"nanos":    "String?",
+		"note":     "String?",
+		"seconds":  "Double",
+		"started":  "Long",
+		"weight":   "String?",
+	}, kinds)
+
+	assert.Equal(t, schema{"type": "string", "format": "date"}, typeSchema(info.Attributes[1]))
+}
+
+func TestCamelCase(t *testing.T) {
+	for in, want := range map[string]string{
+		"title":        "title",
+		"published-at": "publishedAt",
+		"first name":   "firstName",
+		"snake_case":   "snake_case",
+		"2fa":          "_2fa",
+		"-":            "_",
+	} {
+		assert.Equal(t, want, camelCase(in), in)
+	}
+}
+
+func describeTypes(t *testing.T) []*jsonapi.TypeInfo {
+	var types []*jsonapi.TypeInfo
+	for _, a := range []any{Article{}, Person{}} {
+		info, err := jsonapi.DescribeType(a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		types = append(types, info)
+	}
+	return types
+}
+
+func assertGolden(t *testing.T, path string, got []byte) {
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, string(want), string(got))
+}
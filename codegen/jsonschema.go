@@ -0,0 +1,260 @@
+package codegen
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/max-waters/jsonapi/jsonapi"
+)
+
+// JsonSchemaDraft is the URI of the JSON Schema
+// dialect of the schemas written by JsonSchema.
+const JsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// schema is a JSON Schema object.
+type schema = map[string]any
+
+// JsonSchema writes a JSON Schema document describing the resources
+// described by types to w, eg for contract tests or client-side
+// validation. Its $defs hold a schema of each resource's object,
+// named after its Go type, eg "Article", and of its single-resource
+// and collection documents, eg "ArticleDocument" and
+// "ArticleCollectionDocument", as well as the common link, resource
+// identifier and error object schemas. As in the TypeScript types,
+// members without the omitempty option are required.
+func JsonSchema(w io.Writer, types []*jsonapi.TypeInfo) error {
+	defs := schema{
+		"Meta": schema{"type": "object"},
+		"Link": schema{"anyOf": []any{
+			schema{"type": "string"},
+			schemaObject(schema{
+				"href":        schema{"type": "string"},
+				"rel":         schema{"type": "string"},
+				"describedby": schemaRef("Link"),
+				"title":       schema{"type": "string"},
+				"type":        schema{"type": "string"},
+				"hreflang": schema{"anyOf": []any{
+					schema{"type": "string"},
+					schema{"type": "array", "items": schema{"type": "string"}},
+				}},
+				"meta": schemaRef("Meta"),
+			}, "href"),
+		}},
+		"Links": schema{
+			"type":                 "object",
+			"additionalProperties": schemaNullable(schemaRef("Link")),
+		},
+		"ResourceIdentifier": schemaObject(schema{
+			"type": schema{"type": "string"},
+			"id":   schema{"type": "string"},
+			"lid":  schema{"type": "string"},
+			"meta": schemaRef("Meta"),
+		}, "type"),
+		"ErrorObject": schemaObject(schema{
+			"id":     schema{"type": "string"},
+			"links":  schemaRef("Links"),
+			"status": schema{"type": "string"},
+			"code":   schema{"type": "string"},
+			"title":  schema{"type": "string"},
+			"detail": schema{"type": "string"},
+			"source": schemaObject(schema{
+				"pointer":   schema{"type": "string"},
+				"parameter": schema{"type": "string"},
+				"header":    schema{"type": "string"},
+			}),
+			"meta": schemaRef("Meta"),
+		}),
+	}
+
+	resources := []any{}
+	for _, t := range types {
+		name := t.GoType.Name()
+		if name == "" {
+			return fmt.Errorf("codegen: cannot name anonymous struct type for resource type '%s'", t.Type)
+		}
+		defs[name] = resourceSchema(t)
+		defs[name+"Document"] = documentSchema(schemaNullable(schemaRef(name)))
+		defs[name+"CollectionDocument"] = documentSchema(schema{"type": "array", "items": schemaRef(name)})
+		resources = append(resources, schemaRef(name))
+	}
+	// included resources of other types
+	resources = append(resources, schemaRef("ResourceIdentifier"))
+	defs["Resource"] = schema{"anyOf": resources}
+
+	data, err := json.MarshalIndent(schema{"$schema": JsonSchemaDraft, "$defs": defs}, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// resourceSchema returns the schema of the resource object of t.
+func resourceSchema(t *jsonapi.TypeInfo) schema {
+	props := schema{
+		"type":  schema{"const": t.Type},
+		"links": schemaRef("Links"),
+		"meta":  schemaRef("Meta"),
+	}
+	required := []string{"type"}
+	if t.Id != nil {
+		props["id"] = typeSchema(*t.Id)
+		if !t.Id.OmitEmpty {
+			required = append(required, "id")
+		}
+	}
+	if t.Lid {
+		props["lid"] = schema{"type": "string"}
+	}
+
+	attrs, attrsRequired := schema{}, []string{}
+	for _, a := range t.Attributes {
+		attrs[a.Name] = typeSchema(a)
+		if !a.OmitEmpty {
+			attrsRequired = append(attrsRequired, a.Name)
+		}
+	}
+	props["attributes"] = schemaObject(attrs, attrsRequired...)
+
+	rels, relsRequired := schema{}, []string{}
+	for _, r := range t.Relationships {
+		rels[r.Name] = relationshipSchema(r)
+		if !r.OmitEmpty {
+			relsRequired = append(relsRequired, r.Name)
+		}
+	}
+	props["relationships"] = schemaObject(rels, relsRequired...)
+
+	if len(t.Meta) > 0 {
+		props["meta"] = membersSchema(t.Meta, nil)
+	}
+	return schemaObject(props, required...)
+}
+
+// relationshipSchema returns the schema of the relationship object of r.
+func relationshipSchema(r jsonapi.RelationshipInfo) schema {
+	props := schema{"links": schemaRef("Links")}
+	if r.CountOnly {
+		props["meta"] = membersSchema(r.Meta, schema{"count": schema{"type": "integer"}}, "count")
+		return schemaObject(props, "meta")
+	}
+
+	data := schemaRef("ResourceIdentifier")
+	if r.Type != "" {
+		data["properties"] = schema{"type": schema{"const": r.Type}}
+	}
+	switch {
+	case r.ToMany:
+		data = schema{"type": "array", "items": data}
+	case r.GoType.Kind() == reflect.Pointer, r.GoType.Kind() == reflect.Interface:
+		data = schemaNullable(data)
+	}
+	props["data"] = data
+	props["meta"] = schemaRef("Meta")
+	if len(r.Meta) > 0 {
+		props["meta"] = membersSchema(r.Meta, nil)
+	}
+	return schemaObject(props)
+}
+
+// membersSchema returns the schema of an object with the members ms,
+// and the additional properties props, which are required.
+func membersSchema(ms []jsonapi.MemberInfo, props schema, required ...string) schema {
+	if props == nil {
+		props = schema{}
+	}
+	for _, m := range ms {
+		props[m.Name] = typeSchema(m)
+		if !m.OmitEmpty {
+			required = append(required, m.Name)
+		}
+	}
+	return schemaObject(props, required...)
+}
+
+// documentSchema returns the schema of a document with primary data data.
+func documentSchema(data schema) schema {
+	return schemaObject(schema{
+		"data":     data,
+		"errors":   schema{"type": "array", "items": schemaRef("ErrorObject")},
+		"meta":     schemaRef("Meta"),
+		"links":    schemaRef("Links"),
+		"jsonapi":  schemaObject(schema{"version": schema{"type": "string"}, "meta": schemaRef("Meta")}),
+		"included": schema{"type": "array", "items": schemaRef("Resource")},
+	})
+}
+
+// typeSchema returns the schema of the JSON encoding of the member m.
+func typeSchema(m jsonapi.MemberInfo) schema {
+	return typeSchemaSeen(memberType(m), m.String, m.Format, map[reflect.Type]bool{})
+}
+
+// typeSchemaSeen returns the schema of the JSON encoding of t, which
+// is a string if quoted is true and t is a number or bool. The format
+// is that of a time.Time or time.Duration member. See kindOf.
+func typeSchemaSeen(t reflect.Type, quoted bool, format string, seen map[reflect.Type]bool) schema {
+	if t == timeType {
+		switch format {
+		case "", "rfc3339", "rfc3339nano":
+			return schema{"type": "string", "format": "date-time"}
+		case "date":
+			return schema{"type": "string", "format": "date"}
+		}
+	}
+
+	switch kindOf(t, quoted, format) {
+	case kindNullable:
+		return schemaNullable(typeSchemaSeen(t.Elem(), quoted, format, seen))
+	case kindBool:
+		return schema{"type": "boolean"}
+	case kindInt, kindInt64:
+		return schema{"type": "integer"}
+	case kindFloat32, kindFloat64:
+		return schema{"type": "number"}
+	case kindString:
+		return schema{"type": "string"}
+	case kindList:
+		return schema{"type": "array", "items": typeSchemaSeen(t.Elem(), false, "", seen)}
+	case kindMap:
+		return schema{"type": "object", "additionalProperties": typeSchemaSeen(t.Elem(), false, "", seen)}
+	case kindObject:
+		if seen[t] {
+			return schema{}
+		}
+		seen[t] = true
+		defer delete(seen, t)
+
+		props, required := schema{}, []string{}
+		for _, f := range jsonFields(t) {
+			props[f.Name] = typeSchemaSeen(f.Type, f.Quoted, "", seen)
+			if !f.Optional {
+				required = append(required, f.Name)
+			}
+		}
+		return schemaObject(props, required...)
+	default:
+		return schema{}
+	}
+}
+
+// schemaObject returns the schema of an object with the properties
+// props, of which those named by required are required.
+func schemaObject(props schema, required ...string) schema {
+	s := schema{"type": "object", "properties": props}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	return s
+}
+
+// schemaRef returns a reference to the schema called name in $defs.
+func schemaRef(name string) schema {
+	return schema{"$ref": "#/$defs/" + name}
+}
+
+// schemaNullable returns the schema of s or null.
+func schemaNullable(s schema) schema {
+	return schema{"anyOf": []any{s, schema{"type": "null"}}}
+}
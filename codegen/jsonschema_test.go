@@ -0,0 +1,82 @@
+package codegen
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/max-waters/jsonapi/jsonapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJsonSchema(t *testing.T) {
+	buf := bytes.Buffer{}
+	if err := JsonSchema(&buf, describeTypes(t)); err != nil {
+		t.Fatal(err)
+	}
+	assertGolden(t, "testdata/types.schema.json", buf.Bytes())
+}
+
+func TestJsonSchema_Resource(t *testing.T) {
+	buf := bytes.Buffer{}
+	if err := JsonSchema(&buf, describeTypes(t)); err != nil {
+		t.Fatal(err)
+	}
+
+	var s struct {
+		Defs map[string]json.RawMessage `json:"$defs"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &s); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{
+		"type": "object",
+		"required": ["type", "id"],
+		"properties": {
+			"type": {"const": "people"},
+			"id": {"type": "string"},
+			"attributes": {
+				"type": "object",
+				"required": ["address", "name"],
+				"properties": {
+					"address": {"anyOf": [
+						{
+							"type": "object",
+							"required": ["street"],
+							"properties": {"street": {"type": "string"}, "postcode": {"type": "string"}}
+						},
+						{"type": "null"}
+					]},
+					"name": {"type": "string"}
+				}
+			},
+			"relationships": {"type": "object", "properties": {}},
+			"links": {"$ref": "#/$defs/Links"},
+			"meta": {"$ref": "#/$defs/Meta"}
+		}
+	}`
+	assert.JSONEq(t, expected, string(s.Defs["Person"]))
+	assert.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"data": {"type": "array", "items": {"$ref": "#/$defs/Person"}},
+			"errors": {"type": "array", "items": {"$ref": "#/$defs/ErrorObject"}},
+			"meta": {"$ref": "#/$defs/Meta"},
+			"links": {"$ref": "#/$defs/Links"},
+			"jsonapi": {"type": "object", "properties": {"version": {"type": "string"}, "meta": {"$ref": "#/$defs/Meta"}}},
+			"included": {"type": "array", "items": {"$ref": "#/$defs/Resource"}}
+		}
+	}`, string(s.Defs["PersonCollectionDocument"]))
+}
+
+func TestJsonSchema_Anonymous(t *testing.T) {
+	info, err := jsonapi.DescribeType(struct {
+		Id string `jsonapi:"id,things"`
+	}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = JsonSchema(&bytes.Buffer{}, []*jsonapi.TypeInfo{info})
+	assert.EqualError(t, err, "codegen: cannot name anonymous struct type for resource type 'things'")
+}
@@ -0,0 +1,211 @@
+package codegen
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"text/template"
+
+	"github.com/max-waters/jsonapi/jsonapi"
+)
+
+// Kotlin writes Kotlin data classes for the resources described by
+// types to w, for use with kotlinx.serialization: a class for each
+// resource, its attributes, its relationships and, if it has tagged
+// meta fields, its meta, aliases for its single-resource and collection
+// documents, and the common document and relationship classes.
+func Kotlin(w io.Writer, types []*jsonapi.TypeInfo) error {
+	return KotlinLanguage.Generate(w, types)
+}
+
+// KotlinLanguage generates Kotlin data classes.
+var KotlinLanguage = Language{
+	Name: "kotlin",
+	Funcs: template.FuncMap{
+		"quote":   ktQuote,
+		"name":    ktName,
+		"member":  ktMember,
+		"relType": ktRelType,
+		"type":    ktType,
+	},
+	Template: KotlinTemplate,
+}
+
+// KotlinTemplate is the default template of KotlinLanguage.
+const KotlinTemplate = `// Code generated by github.com/max-waters/jsonapi/codegen. DO NOT EDIT.
+
+import kotlinx.serialization.SerialName
+import kotlinx.serialization.Serializable
+import kotlinx.serialization.json.JsonElement
+import kotlinx.serialization.json.JsonObject
+
+@Serializable
+data class ResourceIdentifier(
+    val type: String,
+    val id: String? = null,
+    val lid: String? = null,
+    val meta: JsonObject? = null,
+)
+
+@Serializable
+data class ToOneRelationship(
+    val data: ResourceIdentifier? = null,
+    val links: JsonObject? = null,
+    val meta: JsonObject? = null,
+)
+
+@Serializable
+data class ToManyRelationship(
+    val data: List<ResourceIdentifier>? = null,
+    val links: JsonObject? = null,
+    val meta: JsonObject? = null,
+)
+
+@Serializable
+data class ErrorObject(
+    val id: String? = null,
+    val links: JsonObject? = null,
+    val status: String? = null,
+    val code: String? = null,
+    val title: String? = null,
+    val detail: String? = null,
+    val source: JsonObject? = null,
+    val meta: JsonObject? = null,
+)
+
+@Serializable
+data class Document<D>(
+    val data: D? = null,
+    val errors: List<ErrorObject>? = null,
+    val meta: JsonObject? = null,
+    val links: JsonObject? = null,
+    val jsonapi: JsonObject? = null,
+    val included: List<JsonObject>? = null,
+)
+{{range .}}{{$name := .GoType.Name}}
+@Serializable
+{{- if .Attributes}}
+data class {{$name}}Attributes(
+{{- range .Attributes}}
+    {{member .Name (type .) .OmitEmpty}},
+{{- end}}
+)
+{{- else}}
+class {{$name}}Attributes
+{{- end}}
+
+@Serializable
+{{- if .Relationships}}
+data class {{$name}}Relationships(
+{{- range .Relationships}}
+    {{member .Name (relType .) .OmitEmpty}},
+{{- end}}
+)
+{{- else}}
+class {{$name}}Relationships
+{{- end}}
+{{if .Meta}}
+@Serializable
+data class {{$name}}Meta(
+{{- range .Meta}}
+    {{member .Name (type .) .OmitEmpty}},
+{{- end}}
+)
+{{end}}
+@Serializable
+data class {{$name}}(
+    val type: String,
+{{- if .Id}}
+    {{member "id" (type .Id) .Id.OmitEmpty}},
+{{- end}}
+{{- if .Lid}}
+    val lid: String? = null,
+{{- end}}
+    val attributes: {{$name}}Attributes? = null,
+    val relationships: {{$name}}Relationships? = null,
+    val meta: {{if .Meta}}{{$name}}Meta{{else}}JsonObject{{end}}? = null,
+    val links: JsonObject? = null,
+)
+
+typealias {{$name}}Document = Document<{{$name}}>
+
+typealias {{$name}}CollectionDocument = Document<List<{{$name}}>>
+{{end}}`
+
+var ktKeywords = map[string]bool{
+	"as": true, "break": true, "class": true, "continue": true, "do": true, "else": true,
+	"false": true, "for": true, "fun": true, "if": true, "in": true, "interface": true,
+	"is": true, "null": true, "object": true, "package": true, "return": true, "super": true,
+	"this": true, "throw": true, "true": true, "try": true, "typealias": true, "typeof": true,
+	"val": true, "var": true, "when": true, "while": true,
+}
+
+// ktQuote returns s as a Kotlin string literal.
+func ktQuote(s string) string {
+	return strings.ReplaceAll(tsQuote(s), "$", `\$`)
+}
+
+// ktName returns the Kotlin property name of the member called name.
+func ktName(name string) string {
+	name = camelCase(name)
+	if ktKeywords[name] {
+		return "`" + name + "`"
+	}
+	return name
+}
+
+// ktMember returns the constructor parameter of the member called
+// name, which is nullable and defaults to null if optional.
+func ktMember(name, typ string, optional bool) string {
+	decl := "val " + ktName(name) + ": " + typ
+	if optional {
+		decl = "val " + ktName(name) + ": " + nullable(typ) + " = null"
+	}
+	if camelCase(name) != name {
+		decl = "@SerialName(" + ktQuote(name) + ") " + decl
+	}
+	return decl
+}
+
+// ktRelType returns the Kotlin type of the relationship r.
+func ktRelType(r jsonapi.RelationshipInfo) string {
+	if r.ToMany {
+		return "ToManyRelationship"
+	}
+	return "ToOneRelationship"
+}
+
+// ktType returns the Kotlin type of the JSON encoding of the member m.
+func ktType(m jsonapi.MemberInfo) string {
+	return ktTypeOf(memberType(m), m.String, m.Format)
+}
+
+// ktTypeOf returns the Kotlin type of the JSON encoding of t, which
+// is a string if quoted is true and t is a number or bool. The format
+// is that of a time.Time or time.Duration member. See kindOf.
+func ktTypeOf(t reflect.Type, quoted bool, format string) string {
+	switch kindOf(t, quoted, format) {
+	case kindNullable:
+		return nullable(ktTypeOf(t.Elem(), quoted, format))
+	case kindBool:
+		return "Boolean"
+	case kindInt:
+		return "Int"
+	case kindInt64:
+		return "Long"
+	case kindFloat32:
+		return "Float"
+	case kindFloat64:
+		return "Double"
+	case kindString:
+		return "String"
+	case kindList:
+		return "List<" + ktTypeOf(t.Elem(), false, "") + ">"
+	case kindMap:
+		return "Map<String, " + ktTypeOf(t.Elem(), false, "") + ">"
+	case kindObject:
+		return "JsonObject"
+	default:
+		return "JsonElement"
+	}
+}
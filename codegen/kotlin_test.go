@@ -0,0 +1,20 @@
+package codegen
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKotlin(t *testing.T) {
+	buf := bytes.Buffer{}
+	if err := Kotlin(&buf, describeTypes(t)); err != nil {
+		t.Fatal(err)
+	}
+	assertGolden(t, "testdata/types.kt", buf.Bytes())
+}
+
+func TestKotlinName(t *testing.T) {
+	assert.Equal(t, "`class`", ktName("class"))
+}
@@ -0,0 +1,259 @@
+package codegen
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"text/template"
+
+	"github.com/max-waters/jsonapi/jsonapi"
+)
+
+// Swift writes Swift Codable structs for the resources described by
+// types to w: a struct for each resource, its attributes, its
+// relationships and, if it has tagged meta fields, its meta, aliases
+// for its single-resource and collection documents, and the common
+// document, relationship and JSONValue types.
+func Swift(w io.Writer, types []*jsonapi.TypeInfo) error {
+	return SwiftLanguage.Generate(w, types)
+}
+
+// SwiftLanguage generates Swift Codable structs.
+var SwiftLanguage = Language{
+	Name: "swift",
+	Funcs: template.FuncMap{
+		"quote":   tsQuote,
+		"name":    swiftName,
+		"member":  swiftMember,
+		"keys":    swiftKeys,
+		"relType": swiftRelType,
+		"type":    swiftType,
+	},
+	Template: SwiftTemplate,
+}
+
+// SwiftTemplate is the default template of SwiftLanguage.
+const SwiftTemplate = `// Code generated by github.com/max-waters/jsonapi/codegen. DO NOT EDIT.
+
+import Foundation
+
+public enum JSONValue: Codable, Equatable {
+    case null
+    case bool(Bool)
+    case number(Double)
+    case string(String)
+    case array([JSONValue])
+    case object([String: JSONValue])
+
+    public init(from decoder: Decoder) throws {
+        let c = try decoder.singleValueContainer()
+        if c.decodeNil() {
+            self = .null
+        } else if let v = try? c.decode(Bool.self) {
+            self = .bool(v)
+        } else if let v = try? c.decode(Double.self) {
+            self = .number(v)
+        } else if let v = try? c.decode(String.self) {
+            self = .string(v)
+        } else if let v = try? c.decode([JSONValue].self) {
+            self = .array(v)
+        } else {
+            self = .object(try c.decode([String: JSONValue].self))
+        }
+    }
+
+    public func encode(to encoder: Encoder) throws {
+        var c = encoder.singleValueContainer()
+        switch self {
+        case .null: try c.encodeNil()
+        case .bool(let v): try c.encode(v)
+        case .number(let v): try c.encode(v)
+        case .string(let v): try c.encode(v)
+        case .array(let v): try c.encode(v)
+        case .object(let v): try c.encode(v)
+        }
+    }
+}
+
+public struct ResourceIdentifier: Codable {
+    public var type: String
+    public var id: String?
+    public var lid: String?
+    public var meta: [String: JSONValue]?
+}
+
+public struct ToOneRelationship: Codable {
+    public var data: ResourceIdentifier?
+    public var links: [String: JSONValue]?
+    public var meta: [String: JSONValue]?
+}
+
+public struct ToManyRelationship: Codable {
+    public var data: [ResourceIdentifier]?
+    public var links: [String: JSONValue]?
+    public var meta: [String: JSONValue]?
+}
+
+public struct ErrorObject: Codable {
+    public var id: String?
+    public var links: [String: JSONValue]?
+    public var status: String?
+    public var code: String?
+    public var title: String?
+    public var detail: String?
+    public var source: [String: JSONValue]?
+    public var meta: [String: JSONValue]?
+}
+
+public struct Document<D: Codable>: Codable {
+    public var data: D?
+    public var errors: [ErrorObject]?
+    public var meta: [String: JSONValue]?
+    public var links: [String: JSONValue]?
+    public var jsonapi: [String: JSONValue]?
+    public var included: [JSONValue]?
+}
+{{range .}}{{$name := .GoType.Name}}
+public struct {{$name}}Attributes: Codable {
+{{- range .Attributes}}
+    {{member .Name (type .) .OmitEmpty}}
+{{- end}}{{keys .Attributes}}
+}
+
+public struct {{$name}}Relationships: Codable {
+{{- range .Relationships}}
+    {{member .Name (relType .) .OmitEmpty}}
+{{- end}}{{keys .Relationships}}
+}
+{{if .Meta}}
+public struct {{$name}}Meta: Codable {
+{{- range .Meta}}
+    {{member .Name (type .) .OmitEmpty}}
+{{- end}}{{keys .Meta}}
+}
+{{end}}
+public struct {{$name}}: Codable {
+    public var type: String
+{{- if .Id}}
+    {{member "id" (type .Id) .Id.OmitEmpty}}
+{{- end}}
+{{- if .Lid}}
+    public var lid: String?
+{{- end}}
+    public var attributes: {{$name}}Attributes?
+    public var relationships: {{$name}}Relationships?
+    public var meta: {{if .Meta}}{{$name}}Meta{{else}}[String: JSONValue]{{end}}?
+    public var links: [String: JSONValue]?
+}
+
+public typealias {{$name}}Document = Document<{{$name}}>
+
+public typealias {{$name}}CollectionDocument = Document<[{{$name}}]>
+{{end}}`
+
+var swiftKeywords = map[string]bool{
+	"Any": true, "as": true, "associatedtype": true, "break": true, "case": true, "catch": true,
+	"class": true, "continue": true, "default": true, "defer": true, "deinit": true, "do": true,
+	"else": true, "enum": true, "extension": true, "fallthrough": true, "false": true, "for": true,
+	"func": true, "guard": true, "if": true, "import": true, "in": true, "init": true, "inout": true,
+	"internal": true, "is": true, "let": true, "nil": true, "operator": true, "private": true,
+	"protocol": true, "public": true, "repeat": true, "rethrows": true, "return": true, "self": true,
+	"Self": true, "static": true, "struct": true, "subscript": true, "super": true, "switch": true,
+	"throw": true, "throws": true, "true": true, "try": true, "typealias": true, "var": true,
+	"where": true, "while": true,
+}
+
+// swiftName returns the Swift property name of the member called name.
+func swiftName(name string) string {
+	name = camelCase(name)
+	if swiftKeywords[name] {
+		return "`" + name + "`"
+	}
+	return name
+}
+
+// swiftMember returns the declaration of the member called
+// name, which is optional if omitted when empty.
+func swiftMember(name, typ string, optional bool) string {
+	if optional {
+		typ = nullable(typ)
+	}
+	return "public var " + swiftName(name) + ": " + typ
+}
+
+// swiftKeys returns a CodingKeys enum for members, a slice of
+// jsonapi.MemberInfo or jsonapi.RelationshipInfo, if any of
+// their names are not valid Swift property names.
+func swiftKeys(members any) string {
+	var names []string
+	switch ms := members.(type) {
+	case []jsonapi.MemberInfo:
+		for _, m := range ms {
+			names = append(names, m.Name)
+		}
+	case []jsonapi.RelationshipInfo:
+		for _, m := range ms {
+			names = append(names, m.Name)
+		}
+	}
+
+	renamed := false
+	for _, name := range names {
+		renamed = renamed || camelCase(name) != name
+	}
+	if !renamed {
+		return ""
+	}
+
+	b := strings.Builder{}
+	b.WriteString("\n\n    enum CodingKeys: String, CodingKey {")
+	for _, name := range names {
+		b.WriteString("\n        case " + swiftName(name))
+		if camelCase(name) != name {
+			b.WriteString(" = " + tsQuote(name))
+		}
+	}
+	b.WriteString("\n    }")
+	return b.String()
+}
+
+// swiftRelType returns the Swift type of the relationship r.
+func swiftRelType(r jsonapi.RelationshipInfo) string {
+	if r.ToMany {
+		return "ToManyRelationship"
+	}
+	return "ToOneRelationship"
+}
+
+// swiftType returns the Swift type of the JSON encoding of the member m.
+func swiftType(m jsonapi.MemberInfo) string {
+	return swiftTypeOf(memberType(m), m.String, m.Format)
+}
+
+// swiftTypeOf returns the Swift type of the JSON encoding of t, which
+// is a string if quoted is true and t is a number or bool. The format
+// is that of a time.Time or time.Duration member. See kindOf.
+func swiftTypeOf(t reflect.Type, quoted bool, format string) string {
+	switch kindOf(t, quoted, format) {
+	case kindNullable:
+		return nullable(swiftTypeOf(t.Elem(), quoted, format))
+	case kindBool:
+		return "Bool"
+	case kindInt:
+		return "Int32"
+	case kindInt64:
+		return "Int"
+	case kindFloat32:
+		return "Float"
+	case kindFloat64:
+		return "Double"
+	case kindString:
+		return "String"
+	case kindList:
+		return "[" + swiftTypeOf(t.Elem(), false, "") + "]"
+	case kindMap:
+		return "[String: " + swiftTypeOf(t.Elem(), false, "") + "]"
+	default:
+		return "JSONValue"
+	}
+}
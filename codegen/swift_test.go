@@ -0,0 +1,20 @@
+package codegen
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSwift(t *testing.T) {
+	buf := bytes.Buffer{}
+	if err := Swift(&buf, describeTypes(t)); err != nil {
+		t.Fatal(err)
+	}
+	assertGolden(t, "testdata/types.swift", buf.Bytes())
+}
+
+func TestSwiftName(t *testing.T) {
+	assert.Equal(t, "`default`", swiftName("default"))
+}
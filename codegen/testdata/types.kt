@@ -0,0 +1,111 @@
+// Code generated by github.com/max-waters/jsonapi/codegen. DO NOT EDIT.
+
+import kotlinx.serialization.SerialName
+import kotlinx.serialization.Serializable
+import kotlinx.serialization.json.JsonElement
+import kotlinx.serialization.json.JsonObject
+
+@Serializable
+data class ResourceIdentifier(
+    val type: String,
+    val id: String? = null,
+    val lid: String? = null,
+    val meta: JsonObject? = null,
+)
+
+@Serializable
+data class ToOneRelationship(
+    val data: ResourceIdentifier? = null,
+    val links: JsonObject? = null,
+    val meta: JsonObject? = null,
+)
+
+@Serializable
+data class ToManyRelationship(
+    val data: List<ResourceIdentifier>? = null,
+    val links: JsonObject? = null,
+    val meta: JsonObject? = null,
+)
+
+@Serializable
+data class ErrorObject(
+    val id: String? = null,
+    val links: JsonObject? = null,
+    val status: String? = null,
+    val code: String? = null,
+    val title: String? = null,
+    val detail: String? = null,
+    val source: JsonObject? = null,
+    val meta: JsonObject? = null,
+)
+
+@Serializable
+data class Document<D>(
+    val data: D? = null,
+    val errors: List<ErrorObject>? = null,
+    val meta: JsonObject? = null,
+    val links: JsonObject? = null,
+    val jsonapi: JsonObject? = null,
+    val included: List<JsonObject>? = null,
+)
+
+@Serializable
+data class ArticleAttributes(
+    val extra: Map<String, JsonElement>? = null,
+    @SerialName("published-at") val publishedAt: String,
+    val rating: Double? = null,
+    val tags: List<String>,
+    val title: String,
+)
+
+@Serializable
+data class ArticleRelationships(
+    val author: ToOneRelationship,
+    val comments: ToManyRelationship,
+    val editor: ToOneRelationship,
+    val related: ToManyRelationship? = null,
+)
+
+@Serializable
+data class ArticleMeta(
+    val labels: Map<String, String>? = null,
+    val views: Long,
+)
+
+@Serializable
+data class Article(
+    val type: String,
+    val id: String,
+    val lid: String? = null,
+    val attributes: ArticleAttributes? = null,
+    val relationships: ArticleRelationships? = null,
+    val meta: ArticleMeta? = null,
+    val links: JsonObject? = null,
+)
+
+typealias ArticleDocument = Document<Article>
+
+typealias ArticleCollectionDocument = Document<List<Article>>
+
+@Serializable
+data class PersonAttributes(
+    val address: JsonObject?,
+    val name: String,
+)
+
+@Serializable
+class PersonRelationships
+
+@Serializable
+data class Person(
+    val type: String,
+    val id: String,
+    val attributes: PersonAttributes? = null,
+    val relationships: PersonRelationships? = null,
+    val meta: JsonObject? = null,
+    val links: JsonObject? = null,
+)
+
+typealias PersonDocument = Document<Person>
+
+typealias PersonCollectionDocument = Document<List<Person>>
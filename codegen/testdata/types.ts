@@ -0,0 +1,105 @@
+// Code generated by github.com/max-waters/jsonapi/codegen. DO NOT EDIT.
+
+export type Meta = Record<string, unknown>;
+
+export type Link =
+  | string
+  | {
+      href: string;
+      rel?: string;
+      describedby?: Link;
+      title?: string;
+      type?: string;
+      hreflang?: string | string[];
+      meta?: Meta;
+    };
+
+export type Links = Record<string, Link | null>;
+
+export interface ResourceIdentifier<T extends string = string> {
+  type: T;
+  id?: string;
+  lid?: string;
+  meta?: Meta;
+}
+
+export interface Relationship<D> {
+  data?: D;
+  links?: Links;
+  meta?: Meta;
+}
+
+export interface ErrorObject {
+  id?: string;
+  links?: Links;
+  status?: string;
+  code?: string;
+  title?: string;
+  detail?: string;
+  source?: { pointer?: string; parameter?: string; header?: string };
+  meta?: Meta;
+}
+
+export interface Document<D> {
+  data?: D;
+  errors?: ErrorObject[];
+  meta?: Meta;
+  links?: Links;
+  jsonapi?: { version?: string; meta?: Meta };
+  included?: Resource[];
+}
+
+export interface ArticleAttributes {
+  extra?: Record<string, unknown>;
+  "published-at": string;
+  rating?: number;
+  tags: string[];
+  title: string;
+}
+
+export interface ArticleRelationships {
+  author: Relationship<ResourceIdentifier<"people"> | null>;
+  comments: Relationship<never> & { meta: { count: number } };
+  editor: Relationship<ResourceIdentifier<"people">>;
+  related?: Relationship<ResourceIdentifier<"articles">[]>;
+}
+
+export interface Article {
+  type: "articles";
+  id: string;
+  lid?: string;
+  attributes?: ArticleAttributes;
+  relationships?: ArticleRelationships;
+  meta?: {
+    labels?: Record<string, string>;
+    views: number;
+  };
+  links?: Links;
+}
+
+export type ArticleDocument = Document<Article | null>;
+
+export type ArticleCollectionDocument = Document<Article[]>;
+
+export interface PersonAttributes {
+  address: { street: string; postcode?: string } | null;
+  name: string;
+}
+
+export interface PersonRelationships {
+}
+
+export interface Person {
+  type: "people";
+  id: string;
+  attributes?: PersonAttributes;
+  relationships?: PersonRelationships;
+  meta?: Meta;
+  links?: Links;
+}
+
+export type PersonDocument = Document<Person | null>;
+
+export type PersonCollectionDocument = Document<Person[]>;
+
+export type Resource = Article | Person | { type: string; id?: string; lid?: string };
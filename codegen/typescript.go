@@ -0,0 +1,259 @@
+package codegen
+
+import (
+	"encoding/json"
+	"io"
+	"reflect"
+	"strings"
+	"text/template"
+
+	"github.com/max-waters/jsonapi/jsonapi"
+)
+
+// TypeScript writes TypeScript declarations for the resources described
+// by types to w: an interface for each resource, its attributes and its
+// relationships, aliases for its single-resource and collection
+// documents, and the common document, link and error types.
+func TypeScript(w io.Writer, types []*jsonapi.TypeInfo) error {
+	return TypeScriptLanguage.Generate(w, types)
+}
+
+// TypeScriptLanguage generates TypeScript declarations.
+var TypeScriptLanguage = Language{
+	Name: "typescript",
+	Funcs: template.FuncMap{
+		"quote":   tsQuote,
+		"member":  tsMember,
+		"relType": tsRelType,
+		"type":    tsType,
+	},
+	Template: TypeScriptTemplate,
+}
+
+// TypeScriptTemplate is the default template of TypeScriptLanguage.
+const TypeScriptTemplate = `// Code generated by github.com/max-waters/jsonapi/codegen. DO NOT EDIT.
+
+export type Meta = Record<string, unknown>;
+
+export type Link =
+  | string
+  | {
+      href: string;
+      rel?: string;
+      describedby?: Link;
+      title?: string;
+      type?: string;
+      hreflang?: string | string[];
+      meta?: Meta;
+    };
+
+export type Links = Record<string, Link | null>;
+
+export interface ResourceIdentifier<T extends string = string> {
+  type: T;
+  id?: string;
+  lid?: string;
+  meta?: Meta;
+}
+
+export interface Relationship<D> {
+  data?: D;
+  links?: Links;
+  meta?: Meta;
+}
+
+export interface ErrorObject {
+  id?: string;
+  links?: Links;
+  status?: string;
+  code?: string;
+  title?: string;
+  detail?: string;
+  source?: { pointer?: string; parameter?: string; header?: string };
+  meta?: Meta;
+}
+
+export interface Document<D> {
+  data?: D;
+  errors?: ErrorObject[];
+  meta?: Meta;
+  links?: Links;
+  jsonapi?: { version?: string; meta?: Meta };
+  included?: Resource[];
+}
+{{range .}}{{$name := .GoType.Name}}
+export interface {{$name}}Attributes {
+{{- range .Attributes}}
+  {{member .Name .OmitEmpty}}: {{type .}};
+{{- end}}
+}
+
+export interface {{$name}}Relationships {
+{{- range .Relationships}}
+  {{member .Name .OmitEmpty}}: {{relType .}};
+{{- end}}
+}
+
+export interface {{$name}} {
+  type: {{quote .Type}};
+{{- if .Id}}
+  {{member "id" .Id.OmitEmpty}}: {{type .Id}};
+{{- end}}
+{{- if .Lid}}
+  lid?: string;
+{{- end}}
+  attributes?: {{$name}}Attributes;
+  relationships?: {{$name}}Relationships;
+{{- if .Meta}}
+  meta?: {
+{{- range .Meta}}
+    {{member .Name .OmitEmpty}}: {{type .}};
+{{- end}}
+  };
+{{- else}}
+  meta?: Meta;
+{{- end}}
+  links?: Links;
+}
+
+export type {{$name}}Document = Document<{{$name}} | null>;
+
+export type {{$name}}CollectionDocument = Document<{{$name}}[]>;
+{{end}}
+export type Resource ={{range .}} {{.GoType.Name}} |{{end}} { type: string; id?: string; lid?: string };
+`
+
+// tsQuote returns s as a TypeScript string literal.
+func tsQuote(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
+// tsMember returns the declaration of the member called name,
+// which is optional if omitted when empty.
+func tsMember(name string, optional bool) string {
+	if !isIdentifier(name) {
+		name = tsQuote(name)
+	}
+	if optional {
+		return name + "?"
+	}
+	return name
+}
+
+// tsRelType returns the TypeScript type of the relationship r.
+func tsRelType(r jsonapi.RelationshipInfo) string {
+	var meta []string
+	for _, m := range r.Meta {
+		meta = append(meta, tsMember(m.Name, m.OmitEmpty)+": "+tsType(m))
+	}
+	if r.CountOnly {
+		meta = append(meta, "count: number")
+		return "Relationship<never> & { meta: { " + strings.Join(meta, "; ") + " } }"
+	}
+
+	id := "ResourceIdentifier"
+	if r.Type != "" {
+		id += "<" + tsQuote(r.Type) + ">"
+	}
+	switch {
+	case r.ToMany:
+		id += "[]"
+	case r.GoType.Kind() == reflect.Pointer, r.GoType.Kind() == reflect.Interface:
+		id += " | null"
+	}
+
+	if len(meta) > 0 {
+		return "Relationship<" + id + "> & { meta?: { " + strings.Join(meta, "; ") + " } }"
+	}
+	return "Relationship<" + id + ">"
+}
+
+// tsType returns the TypeScript type of the JSON encoding of the member m.
+func tsType(m jsonapi.MemberInfo) string {
+	return tsTypeSeen(memberType(m), m.String, m.Format, map[reflect.Type]bool{})
+}
+
+// tsTypeSeen returns the TypeScript type of the JSON encoding of t,
+// which is a string if quoted is true and t is a number or bool. The
+// format is that of a time.Time or time.Duration member. See kindOf.
+func tsTypeSeen(t reflect.Type, quoted bool, format string, seen map[reflect.Type]bool) string {
+	switch {
+	case t.Kind() == reflect.Pointer:
+		return tsTypeSeen(t.Elem(), quoted, format, seen) + " | null"
+	case t == timeType, t == durationType && format != "":
+		if kindOf(t, quoted, format) == kindString {
+			return "string"
+		}
+		return "number"
+	case t == rawMessageType, t.Implements(jsonMarshalerType):
+		return "unknown"
+	case t.Implements(textMarshalerType):
+		return "string"
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		if quoted {
+			return "string"
+		}
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		if quoted {
+			return "string"
+		}
+		return "number"
+	case reflect.String:
+		return "string"
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 && t.Kind() == reflect.Slice {
+			// base64 encoded
+			return "string"
+		}
+		elem := tsTypeSeen(t.Elem(), false, "", seen)
+		if strings.Contains(elem, " | ") {
+			elem = "(" + elem + ")"
+		}
+		return elem + "[]"
+	case reflect.Map:
+		return "Record<string, " + tsTypeSeen(t.Elem(), false, "", seen) + ">"
+	case reflect.Struct:
+		if seen[t] {
+			return "unknown"
+		}
+		seen[t] = true
+		defer delete(seen, t)
+		return tsStruct(t, seen)
+	default:
+		return "unknown"
+	}
+}
+
+// tsStruct returns an object type for the encoding/json
+// encoding of the struct type t.
+func tsStruct(t reflect.Type, seen map[reflect.Type]bool) string {
+	var members []string
+	for _, f := range jsonFields(t) {
+		members = append(members, tsMember(f.Name, f.Optional)+": "+tsTypeSeen(f.Type, f.Quoted, "", seen))
+	}
+	if len(members) == 0 {
+		return "Record<string, never>"
+	}
+	return "{ " + strings.Join(members, "; ") + " }"
+}
+
+// isIdentifier returns whether s can be used as
+// an unquoted TypeScript property name.
+func isIdentifier(s string) bool {
+	for i, r := range s {
+		switch {
+		case r == '_' || r == '$' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z':
+		case i > 0 && r >= '0' && r <= '9':
+		default:
+			return false
+		}
+	}
+	return s != ""
+}
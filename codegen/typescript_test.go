@@ -0,0 +1,56 @@
+package codegen
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/max-waters/jsonapi/jsonapi"
+	"github.com/stretchr/testify/assert"
+)
+
+type Address struct {
+	Street   string `json:"street"`
+	Postcode string `json:"postcode,omitempty"`
+}
+
+type Person struct {
+	Id      string   `jsonapi:"id,people"`
+	Name    string   `jsonapi:"attr,name"`
+	Address *Address `jsonapi:"attr,address"`
+}
+
+type Article struct {
+	Id        int               `jsonapi:"id,articles,string"`
+	Lid       string            `jsonapi:"lid"`
+	Title     string            `jsonapi:"attr,title"`
+	Rating    float64           `jsonapi:"attr,rating,omitempty"`
+	Published time.Time         `jsonapi:"attr,published-at"`
+	Tags      []string          `jsonapi:"attr,tags"`
+	Extra     map[string]any    `jsonapi:"attr,extra,omitempty"`
+	Author    *string           `jsonapi:"rel,author,people"`
+	Editor    string            `jsonapi:"rel,editor,people"`
+	Comments  []int             `jsonapi:"rel,comments,comments,countonly"`
+	Related   []string          `jsonapi:"rel,related,articles,omitempty"`
+	Views     int               `jsonapi:"meta,views"`
+	Self      string            `jsonapi:"link,self"`
+	Labels    map[string]string `jsonapi:"meta,labels,omitempty"`
+}
+
+func TestTypeScript(t *testing.T) {
+	buf := bytes.Buffer{}
+	if err := TypeScript(&buf, describeTypes(t)); err != nil {
+		t.Fatal(err)
+	}
+	assertGolden(t, "testdata/types.ts", buf.Bytes())
+}
+
+func TestTypeScript_Anonymous(t *testing.T) {
+	info, err := jsonapi.DescribeType(struct {
+		Id string `jsonapi:"id,things"`
+	}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Error(t, TypeScript(&bytes.Buffer{}, []*jsonapi.TypeInfo{info}))
+}
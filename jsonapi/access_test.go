@@ -0,0 +1,72 @@
+package jsonapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type accessAccount struct {
+	Id        string `jsonapi:"id,accounts"`
+	Name      string `jsonapi:"attr,name"`
+	CreatedAt string `jsonapi:"attr,createdAt,readonly"`
+	Password  string `jsonapi:"attr,password,writeonly"`
+	Owner     string `jsonapi:"rel,owner,people,readonly"`
+	OwnerSelf string `jsonapi:"link,self,rel=owner"`
+	Key       string `jsonapi:"rel,key,keys,writeonly"`
+	KeyMeta   int    `jsonapi:"meta,count,rel=key"`
+	Version   int    `jsonapi:"meta,version,readonly"`
+}
+
+func TestMarshalResource_ReadWriteOnly(t *testing.T) {
+	in := accessAccount{"1", "Bob", "2024-01-01", "secret", "2", "/accounts/1/relationships/owner", "3", 1, 4}
+
+	got, err := MarshalResource(&in)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `
+	{
+		"type": "accounts",
+		"id": "1",
+		"attributes": {"name": "Bob", "createdAt": "2024-01-01"},
+		"relationships": {
+			"owner": {
+				"data": {"type": "people", "id": "2"},
+				"links": {"self": "/accounts/1/relationships/owner"}
+			}
+		},
+		"meta": {"version": 4}
+	}`
+	assert.Equal(t, fmtJson(t, []byte(want)), fmtJson(t, got))
+}
+
+func TestUnmarshalResource_ReadWriteOnly(t *testing.T) {
+	in := `
+	{
+		"type": "accounts",
+		"id": "1",
+		"attributes": {"name": "Bob", "createdAt": "2024-01-01", "password": "secret"},
+		"relationships": {
+			"owner": {"data": {"type": "people", "id": "2"}},
+			"key": {"data": {"type": "keys", "id": "3"}, "meta": {"count": 1}}
+		},
+		"meta": {"version": 4}
+	}`
+
+	got := accessAccount{}
+	if err := UnmarshalResource([]byte(in), &got, WithDisallowUnknownMembers()); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, accessAccount{Id: "1", Name: "Bob", Password: "secret", Key: "3", KeyMeta: 1}, got)
+}
+
+func TestMarshalResource_ReadWriteOnly_TagErr(t *testing.T) {
+	type tp struct {
+		Name string `jsonapi:"attr,name,readonly,writeonly"`
+	}
+
+	_, err := MarshalResource(&tp{})
+	assert.ErrorAs(t, err, addrOf(&TagErr{}))
+}
@@ -0,0 +1,244 @@
+package jsonapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// DecodeDocumentAliased decodes the JSON:API document data, as
+// json.Unmarshal does into a Document, except that the id, attributes,
+// meta and unknown members of its resources, and its own unknown and
+// extension members, are slices of data rather than copies. This avoids
+// copying most of a large document, eg one read with MapFile.
+//
+// The document aliases data, so data must not be modified, or unmapped,
+// while the document is in use, and the document must not be modified
+// in ways that would modify data, eg by appending to its members. Call
+// Detach to copy the aliased members so that the document no longer
+// refers to data.
+func DecodeDocumentAliased(data []byte) (*Document, error) {
+	if !json.Valid(data) {
+		// report the syntax error as json.Unmarshal does
+		var v any
+		return nil, fmt.Errorf("jsonapi: unmarshaling document: %w", json.Unmarshal(data, &v))
+	}
+
+	d := &Document{}
+	if err := d.unmarshal(bytes.TrimSpace(data), true); err != nil {
+		return nil, fmt.Errorf("jsonapi: unmarshaling document: %w", err)
+	}
+	return d, nil
+}
+
+// Detach copies the members of d's resources, and its own members,
+// that may alias the data it was decoded from, as described by
+// DecodeDocumentAliased, so that the data can be modified or released.
+func (d *Document) Detach() {
+	d.Unknown = detachMembers(d.Unknown)
+	d.ExtMembers = detachMembers(d.ExtMembers)
+	for _, r := range d.resources() {
+		if r != nil {
+			r.Detach()
+		}
+	}
+}
+
+// Detach copies the members of r that may alias the data it was
+// decoded from, as described by DecodeDocumentAliased.
+func (r *Resource) Detach() {
+	r.Id = slices.Clone(r.Id)
+	r.Meta = detachMembers(r.Meta)
+	r.Attributes = detachMembers(r.Attributes)
+	r.Unknown = detachMembers(r.Unknown)
+}
+
+// detachMembers copies the values of members in place.
+func detachMembers(members map[string]json.RawMessage) map[string]json.RawMessage {
+	for name, value := range members {
+		members[name] = slices.Clone(value)
+	}
+	return members
+}
+
+// ErrNullResource is returned when unmarshaling
+// a null element of an array of resource objects.
+var ErrNullResource = fmt.Errorf("resource must not be null")
+
+// unmarshalResources decodes the JSON array of resource objects
+// data, aliasing data if alias is true. Null elements are rejected,
+// as the rest of the package assumes that resources are non-nil.
+func unmarshalResources(data []byte, alias bool) ([]*Resource, error) {
+	var rs []*Resource
+	if !alias {
+		if err := json.Unmarshal(data, &rs); err != nil {
+			return nil, err
+		}
+		for i, r := range rs {
+			if r == nil {
+				return nil, fmt.Errorf("element %d: %w", i, ErrNullResource)
+			}
+		}
+		return rs, nil
+	}
+
+	elems, err := rawElements(data)
+	if err != nil || elems == nil {
+		return nil, err
+	}
+	rs = make([]*Resource, len(elems))
+	for i, elem := range elems {
+		if bytes.Equal(elem, NullJson) {
+			return nil, fmt.Errorf("element %d: %w", i, ErrNullResource)
+		}
+		rs[i] = &Resource{}
+		if err := rs[i].unmarshal(elem, true); err != nil {
+			return nil, err
+		}
+	}
+	return rs, nil
+}
+
+// rawMembers decodes the JSON object data into a map of its members.
+// If alias is true, data must be valid, and the values of the members
+// are slices of data, rather than copies. Null decodes to a nil map.
+func rawMembers(data []byte, alias bool) (map[string]json.RawMessage, error) {
+	var members map[string]json.RawMessage
+	if !alias {
+		err := json.Unmarshal(data, &members)
+		return members, err
+	}
+
+	if bytes.Equal(data, NullJson) {
+		return nil, nil
+	}
+	if data[0] != '{' {
+		return nil, fmt.Errorf("cannot unmarshal %s into object", jsonKind(data))
+	}
+
+	members = map[string]json.RawMessage{}
+	eachMember(data, func(name string, value json.RawMessage) {
+		members[name] = value
+	})
+	return members, nil
+}
+
+// eachMember calls f with the name and value of each member of the
+// valid JSON object data, in order, where the values are slices of data.
+func eachMember(data []byte, f func(name string, value json.RawMessage)) {
+	i := skipSpace(data, 1)
+	for data[i] != '}' {
+		end := skipString(data, i)
+		name := string(data[i+1 : end-1])
+		if bytes.IndexByte(data[i:end], '\\') >= 0 {
+			// nb data is valid, so this can't fail
+			_ = json.Unmarshal(data[i:end], &name)
+		}
+
+		// skip the colon
+		i = skipSpace(data, skipSpace(data, end)+1)
+		end = skipValue(data, i)
+		// limit the capacity, so that appending copies
+		f(name, data[i:end:end])
+		i = skipSpace(data, end)
+		if data[i] == ',' {
+			i = skipSpace(data, i+1)
+		}
+	}
+}
+
+// rawElements returns the elements of the JSON array data, which
+// must be valid, as slices of data. Null returns a nil slice.
+func rawElements(data []byte) ([]json.RawMessage, error) {
+	if bytes.Equal(data, NullJson) {
+		return nil, nil
+	}
+	if data[0] != '[' {
+		return nil, fmt.Errorf("cannot unmarshal %s into array", jsonKind(data))
+	}
+
+	elems := []json.RawMessage{}
+	i := skipSpace(data, 1)
+	for data[i] != ']' {
+		end := skipValue(data, i)
+		elems = append(elems, data[i:end:end])
+		i = skipSpace(data, end)
+		if data[i] == ',' {
+			i = skipSpace(data, i+1)
+		}
+	}
+	return elems, nil
+}
+
+// skipSpace returns the index of the first
+// non-whitespace byte of data at or after i.
+func skipSpace(data []byte, i int) int {
+	for i < len(data) && (data[i] == ' ' || data[i] == '\t' || data[i] == '\r' || data[i] == '\n') {
+		i++
+	}
+	return i
+}
+
+// skipString returns the index after the end of the
+// valid JSON string that starts at index i of data.
+func skipString(data []byte, i int) int {
+	for i++; i < len(data); i++ {
+		switch data[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+	return i
+}
+
+// skipValue returns the index after the end of the
+// valid JSON value that starts at index i of data.
+func skipValue(data []byte, i int) int {
+	switch data[i] {
+	case '"':
+		return skipString(data, i)
+	case '{', '[':
+		depth := 0
+		for ; i < len(data); i++ {
+			switch data[i] {
+			case '"':
+				i = skipString(data, i) - 1
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+				if depth == 0 {
+					return i + 1
+				}
+			}
+		}
+		return i
+	default:
+		// numbers and literals end at the first delimiter
+		for i < len(data) && strings.IndexByte(",}] \t\r\n", data[i]) < 0 {
+			i++
+		}
+		return i
+	}
+}
+
+// jsonKind returns the kind of the valid JSON value data, as
+// named by the errors of json.Unmarshal, eg "array".
+func jsonKind(data []byte) string {
+	switch data[0] {
+	case '{':
+		return "object"
+	case '[':
+		return "array"
+	case '"':
+		return "string"
+	case 't', 'f':
+		return "bool"
+	default:
+		return "number"
+	}
+}
@@ -0,0 +1,133 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const aliasDocumentJson = ` {
+	"data": [{
+		"type": "articles",
+		"id": "1",
+		"attributes": {"title": "Hello", "tags": ["a", "b"], "body": {"text": "[{\"}"}, "title2": null},
+		"relationships": {
+			"author": {"data": {"type": "people", "id": "2"}, "meta": {"since": 2020}},
+			"tags": {"data": []}
+		},
+		"meta": {"views": 1.5e3},
+		"links": {"self": "/articles/1"},
+		"extra": true
+	}],
+	"included": [{"type": "people", "id": 2, "attributes": {"name": "Bob"}}],
+	"meta": {"total": 1},
+	"ext:member": [1, 2],
+	"other": "x"
+} `
+
+func TestDecodeDocumentAliased(t *testing.T) {
+	want := Document{}
+	if err := json.Unmarshal([]byte(aliasDocumentJson), &want); err != nil {
+		t.Fatal(err)
+	}
+
+	data := []byte(aliasDocumentJson)
+	got, err := DecodeDocumentAliased(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, &want, got)
+
+	// the attributes alias data, until detached
+	r := got.Data.Resources[0]
+	copy(data[bytesIndex(t, data, "Hello"):], "Howdy")
+	assert.Equal(t, `"Howdy"`, string(r.Attributes["title"]))
+
+	got.Detach()
+	copy(data[bytesIndex(t, data, "Howdy"):], "Hello")
+	assert.Equal(t, `"Howdy"`, string(r.Attributes["title"]))
+	assert.Equal(t, `[1, 2]`, string(got.ExtMembers["ext:member"]))
+}
+
+func TestDecodeDocumentAliased_Append(t *testing.T) {
+	data := []byte(`{"data": {"type": "articles", "id": "1", "attributes": {"a": 1, "b": 2}}}`)
+	d, err := DecodeDocumentAliased(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_ = append(d.Data.Resource.Attributes["a"], '0')
+	assert.Equal(t, `2`, string(d.Data.Resource.Attributes["b"]))
+}
+
+func TestDecodeDocumentAliased_Err(t *testing.T) {
+	type testCase struct {
+		Data     string
+		Expected string
+	}
+
+	testCases := []testCase{
+		{`{"data": `, "jsonapi: unmarshaling document: unexpected end of JSON input"},
+		{`[]`, "jsonapi: unmarshaling document: cannot unmarshal array into object"},
+		{`{"data": 1}`, "jsonapi: unmarshaling document: cannot unmarshal into primary data"},
+		{`{"data": {"type": "articles", "attributes": []}}`, "jsonapi: unmarshaling document: cannot unmarshal array into object"},
+		{`{"included": {}}`, "jsonapi: unmarshaling document: cannot unmarshal object into array"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Data, func(t *testing.T) {
+			_, err := DecodeDocumentAliased([]byte(tc.Data))
+			assert.EqualError(t, err, tc.Expected)
+		})
+	}
+}
+
+func TestMapFile(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "doc.json")
+	if err := os.WriteFile(name, []byte(aliasDocumentJson), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	data, unmap, err := MapFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := DecodeDocumentAliased(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Detach()
+	if err := unmap(); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, `"Hello"`, string(d.Data.Resources[0].Attributes["title"]))
+	assert.Equal(t, `"Bob"`, string(d.Included[0].Attributes["name"]))
+
+	_, _, err = MapFile(filepath.Join(t.TempDir(), "missing.json"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	empty := filepath.Join(t.TempDir(), "empty.json")
+	if err := os.WriteFile(empty, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	data, unmap, err = MapFile(empty)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Empty(t, data)
+	assert.NoError(t, unmap())
+}
+
+// bytesIndex returns the index of s in data.
+func bytesIndex(t *testing.T, data []byte, s string) int {
+	for i := range len(data) - len(s) + 1 {
+		if string(data[i:i+len(s)]) == s {
+			return i
+		}
+	}
+	t.Fatalf("%s not found", s)
+	return -1
+}
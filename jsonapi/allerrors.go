@@ -0,0 +1,17 @@
+package jsonapi
+
+// WithAllErrors makes unmarshaling continue past fields that fail to
+// unmarshal, so that every failing field of a resource is reported at
+// once, eg to show API consumers all the mistakes in a request. The
+// error returned is the errors.Join of the errors of each field, in
+// the order of their kinds, then member names, followed by the
+// UnknownMembersErr, if any, of WithDisallowUnknownMembers. For a
+// collection, the errors of all its resources are joined, in the
+// order of the primary data. Fields that failed are left unchanged, or
+// partially set. Errors outside of the fields, eg those of tags,
+// still fail immediately.
+func WithAllErrors() Option {
+	return func(o *options) {
+		o.allErrors = true
+	}
+}
@@ -0,0 +1,101 @@
+package jsonapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type allErrorsArticle struct {
+	Id     string `jsonapi:"id,articles"`
+	Title  string `jsonapi:"attr,title"`
+	Views  int    `jsonapi:"attr,views"`
+	Body   string `jsonapi:"attr,body,notnull"`
+	Author int    `jsonapi:"rel,author,people"`
+}
+
+const allErrorsJson = `
+{
+	"type": "articles",
+	"id": "1",
+	"attributes": {"title": 1, "views": "many", "body": null, "extra": true},
+	"relationships": {"author": {"data": {"type": "people", "id": "x"}}}
+}`
+
+func TestWithAllErrors(t *testing.T) {
+	a := allErrorsArticle{}
+	err := UnmarshalResource([]byte(allErrorsJson), &a, WithAllErrors(), WithDisallowUnknownMembers())
+
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !assert.True(t, ok, "errors aren't joined") {
+		return
+	}
+	errs := joined.Unwrap()
+	if assert.Len(t, errs, 5) {
+		for i, name := range []string{"body", "title", "views", "author"} {
+			assert.ErrorContains(t, errs[i], "jsonapi: unmarshaling field "+name+":")
+		}
+		assert.ErrorIs(t, errs[0], ErrNullMember)
+		unknown := &UnknownMembersErr{}
+		if assert.ErrorAs(t, errs[4], &unknown) {
+			assert.Equal(t, []string{"attributes.extra"}, unknown.Members)
+		}
+	}
+	assert.Equal(t, "1", a.Id)
+
+	// without the option, the first error is returned
+	err = UnmarshalResource([]byte(allErrorsJson), &allErrorsArticle{}, WithDisallowUnknownMembers())
+	assert.ErrorIs(t, err, ErrNullMember)
+	assert.NotContains(t, err.Error(), "title")
+}
+
+func TestWithAllErrors_Valid(t *testing.T) {
+	data := `{"type": "articles", "id": "1", "attributes": {"title": "Hello", "body": "World"}}`
+	a := allErrorsArticle{}
+	if err := UnmarshalResource([]byte(data), &a, WithAllErrors()); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, allErrorsArticle{Id: "1", Title: "Hello", Body: "World"}, a)
+}
+
+func TestWithAllErrors_Document(t *testing.T) {
+	data := `{"data": ` + allErrorsJson + `}`
+	err := UnmarshalDocument([]byte(data), &allErrorsArticle{}, WithAllErrors())
+	var ue *UnmarshalErr
+	assert.ErrorAs(t, err, &ue)
+	assert.ErrorIs(t, err, ErrNullMember)
+}
+
+func TestWithAllErrors_Collection(t *testing.T) {
+	data := `{"data": [
+		{"type": "articles", "id": "1", "attributes": {"title": 1}},
+		{"type": "articles", "id": "2", "attributes": {"title": "Hello"}},
+		{"type": "articles", "id": "3", "attributes": {"views": "many"}}
+	]}`
+	as := []allErrorsArticle{}
+	err := UnmarshalDocument([]byte(data), &as, WithAllErrors())
+
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !assert.True(t, ok, "errors aren't joined") {
+		return
+	}
+	errs := joined.Unwrap()
+	if assert.Len(t, errs, 2) {
+		for i, pointer := range []string{"/data/0/attributes/title", "/data/2/attributes/views"} {
+			var ue *UnmarshalErr
+			if assert.ErrorAs(t, errs[i], &ue) {
+				assert.Equal(t, pointer, ue.Pointer)
+			}
+		}
+	}
+	if assert.Len(t, as, 3) {
+		assert.Equal(t, "Hello", as[1].Title)
+	}
+
+	// without the option, the first error is returned
+	err = UnmarshalDocument([]byte(data), &[]allErrorsArticle{})
+	var ue *UnmarshalErr
+	if assert.ErrorAs(t, err, &ue) {
+		assert.Equal(t, "/data/0/attributes/title", ue.Pointer)
+	}
+}
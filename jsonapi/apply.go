@@ -0,0 +1,39 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+var ErrNoResource = fmt.Errorf("primary data is not a resource")
+
+// ApplyResource stores the members present in r in the existing struct
+// pointed to by a, leaving the fields of absent members unchanged, to
+// implement PATCH requests. Null members, and relationships with empty
+// linkage, set their fields to their zero values.
+func ApplyResource(r *Resource, a any, opts ...Option) error {
+	opts = append(opts[:len(opts):len(opts)], func(o *options) {
+		o.apply = true
+	})
+	return DeformatResource(r, a, opts...)
+}
+
+// ApplyDocument applies the primary resource of the single-resource
+// document in data to the existing struct pointed to by a, as described
+// by ApplyResource.
+func ApplyDocument(data []byte, a any, opts ...Option) error {
+	d := Document{}
+	if err := json.Unmarshal(data, &d); err != nil {
+		return fmt.Errorf("jsonapi: unmarshaling document: %w", err)
+	}
+
+	switch {
+	case d.Data == nil:
+		return fmt.Errorf("jsonapi: %w", ErrNoResource)
+	case d.Data.Collection:
+		return fmt.Errorf("jsonapi: %w", ErrUnexpectedArray)
+	case d.Data.Resource == nil:
+		return fmt.Errorf("jsonapi: %w", ErrNoResource)
+	}
+	return ApplyResource(d.Data.Resource, a, opts...)
+}
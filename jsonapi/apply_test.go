@@ -0,0 +1,92 @@
+package jsonapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type applyArticle struct {
+	Id       string   `jsonapi:"id,articles"`
+	Title    string   `jsonapi:"attr,title"`
+	Subtitle *string  `jsonapi:"attr,subtitle"`
+	Body     string   `jsonapi:"attr,body"`
+	Author   *string  `jsonapi:"rel,author,people"`
+	Editor   string   `jsonapi:"rel,editor,people"`
+	Tags     []string `jsonapi:"rel,tags,tags"`
+	Views    int      `jsonapi:"meta,views"`
+}
+
+func existingApplyArticle() applyArticle {
+	return applyArticle{
+		Id:       "1",
+		Title:    "Hello",
+		Subtitle: addrOf("World"),
+		Body:     "Body",
+		Author:   addrOf("2"),
+		Editor:   "3",
+		Tags:     []string{"a", "b"},
+		Views:    10,
+	}
+}
+
+func TestApplyDocument(t *testing.T) {
+	in := `
+	{
+		"data": {
+			"type": "articles",
+			"id": "1",
+			"attributes": {"title": "Goodbye", "subtitle": null},
+			"relationships": {
+				"author": {"data": null},
+				"tags": {"data": []}
+			}
+		}
+	}`
+
+	got := existingApplyArticle()
+	if err := ApplyDocument([]byte(in), &got); err != nil {
+		t.Fatal(err)
+	}
+
+	want := existingApplyArticle()
+	want.Title = "Goodbye"
+	want.Subtitle = nil
+	want.Author = nil
+	want.Tags = nil
+	assert.Equal(t, want, got)
+}
+
+func TestApplyResource(t *testing.T) {
+	r := newResource()
+	r.Type = "articles"
+	r.Attributes["body"] = []byte(`"New"`)
+	r.ToManyRelationships["tags"] = &ToManyResourceLinkage{Data: []ResourceIdentifier{{Type: "tags", Id: []byte(`"c"`)}}}
+
+	got := existingApplyArticle()
+	if err := ApplyResource(&r, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	want := existingApplyArticle()
+	want.Body = "New"
+	want.Tags = []string{"c"}
+	assert.Equal(t, want, got)
+}
+
+func TestApplyDocument_NotResource(t *testing.T) {
+	a := existingApplyArticle()
+	assert.ErrorIs(t, ApplyDocument([]byte(`{"data": null}`), &a), ErrNoResource)
+	assert.ErrorIs(t, ApplyDocument([]byte(`{"meta": {}}`), &a), ErrNoResource)
+	assert.ErrorIs(t, ApplyDocument([]byte(`{"data": []}`), &a), ErrUnexpectedArray)
+	assert.Equal(t, existingApplyArticle(), a)
+}
+
+func TestUnmarshalResource_NullToOne(t *testing.T) {
+	got := applyArticle{}
+	in := `{"type": "articles", "id": "1", "relationships": {"author": {"data": null}, "editor": {"data": null}}}`
+	if err := UnmarshalResource([]byte(in), &got); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, applyArticle{Id: "1"}, got)
+}
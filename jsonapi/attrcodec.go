@@ -0,0 +1,93 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// AttrMarshaler is implemented by the types of attribute and meta
+// fields that marshal their own member values, given the options of
+// their field's tag, eg a money type whose tag declares its currency,
+// `jsonapi:"attr,price,currency=EUR"`. It takes precedence over the
+// format option, json.Marshaler and encoding.TextMarshaler.
+type AttrMarshaler interface {
+	MarshalJsonApiAttr(opts TagOptions) (json.RawMessage, error)
+}
+
+// AttrUnmarshaler is implemented by the types of attribute and meta
+// fields that unmarshal their own member values, given the options
+// of their field's tag. It takes precedence over the format option,
+// json.Unmarshaler and encoding.TextUnmarshaler, and is called with
+// null values, unless the field is a pointer, map, slice or interface,
+// which is set to nil instead.
+type AttrUnmarshaler interface {
+	UnmarshalJsonApiAttr(data json.RawMessage, opts TagOptions) error
+}
+
+// TagOptions describes the tag of the field of an AttrMarshaler
+// or AttrUnmarshaler.
+type TagOptions struct {
+	// Name is the name of the member.
+	Name string
+	// Options are the tag's options after its name, eg
+	// "string,currency=EUR", including those unknown to
+	// this package.
+	Options string
+}
+
+// Has returns whether opt is one of the options.
+func (t TagOptions) Has(opt string) bool {
+	return hasOpt(t.Options, opt)
+}
+
+// Value returns the value of the first "key=value" option.
+func (t TagOptions) Value(key string) (string, bool) {
+	return optValue(t.Options, key)
+}
+
+var (
+	attrMarshalerType   = reflect.TypeFor[AttrMarshaler]()
+	attrUnmarshalerType = reflect.TypeFor[AttrUnmarshaler]()
+)
+
+// tagOptions returns the TagOptions of the attribute or meta tag tg.
+func tagOptions(tg tag) TagOptions {
+	return TagOptions{Name: tg.name, Options: tg.opts}
+}
+
+// attrMarshaler returns v as an AttrMarshaler if it, or a pointer to
+// it, implements it, even if v isn't addressable.
+func attrMarshaler(v reflect.Value) (AttrMarshaler, bool) {
+	if !v.IsValid() {
+		return nil, false
+	}
+	t := v.Type()
+	if t.Kind() == reflect.Pointer && v.IsNil() {
+		return nil, false
+	}
+	if t.Implements(attrMarshalerType) {
+		return v.Interface().(AttrMarshaler), true
+	}
+	if reflect.PointerTo(t).Implements(attrMarshalerType) {
+		p := reflect.New(t)
+		p.Elem().Set(v)
+		return p.Interface().(AttrMarshaler), true
+	}
+	return nil, false
+}
+
+// attrUnmarshaler returns the AttrUnmarshaler that v, or the value
+// it points to, initialising nil pointers, implements, if any.
+func attrUnmarshaler(v reflect.Value) (AttrUnmarshaler, bool) {
+	if !reflect.PointerTo(derefType(v.Type())).Implements(attrUnmarshalerType) {
+		return nil, false
+	}
+	initValue(v)
+	for v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+	if !v.CanAddr() {
+		return nil, false
+	}
+	return v.Addr().Interface().(AttrUnmarshaler), true
+}
@@ -0,0 +1,152 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// money is an amount of cents, encoded as a decimal
+// string in the currency given by its tag.
+type money int64
+
+func (m money) MarshalJsonApiAttr(opts TagOptions) (json.RawMessage, error) {
+	currency, _ := opts.Value("currency")
+	return json.Marshal(fmt.Sprintf("%d.%02d %s", m/100, m%100, currency))
+}
+
+func (m *money) UnmarshalJsonApiAttr(data json.RawMessage, opts TagOptions) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	currency, _ := opts.Value("currency")
+	amount, ok := strings.CutSuffix(s, " "+currency)
+	if !ok {
+		return fmt.Errorf("%s: expected currency %s", opts.Name, currency)
+	}
+	var units, cents int64
+	if _, err := fmt.Sscanf(amount, "%d.%d", &units, &cents); err != nil {
+		return err
+	}
+	*m = money(units*100 + cents)
+	return nil
+}
+
+// status is an enum encoded as its upper case name,
+// or lower case with the lower option.
+type status int
+
+var statusNames = []string{"DRAFT", "PUBLISHED"}
+
+func (s *status) MarshalJsonApiAttr(opts TagOptions) (json.RawMessage, error) {
+	name := statusNames[*s]
+	if opts.Has("lower") {
+		name = strings.ToLower(name)
+	}
+	return json.Marshal(name)
+}
+
+func (s *status) UnmarshalJsonApiAttr(data json.RawMessage, opts TagOptions) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return err
+	}
+	for i, n := range statusNames {
+		if strings.EqualFold(n, name) {
+			*s = status(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown status %q", name)
+}
+
+type codecArticle struct {
+	Id       int     `jsonapi:"id,articles,string"`
+	Price    money   `jsonapi:"attr,price,currency=EUR"`
+	Discount *money  `jsonapi:"attr,discount,currency=USD"`
+	Status   status  `jsonapi:"attr,status"`
+	Previous *status `jsonapi:"meta,previous,lower"`
+}
+
+func TestAttrMarshaler(t *testing.T) {
+	type testCase struct {
+		Name     string
+		In       any
+		Expected string
+	}
+
+	discount, previous := money(50), status(0)
+	for _, tc := range []testCase{
+		{
+			Name:     "values",
+			In:       codecArticle{Id: 1, Price: 1999, Status: 1},
+			Expected: `{"type":"articles","id":"1","attributes":{"discount":null,"price":"19.99 EUR","status":"PUBLISHED"},"meta":{"previous":null}}`,
+		},
+		{
+			Name:     "pointers",
+			In:       &codecArticle{Id: 1, Price: 5, Discount: &discount, Previous: &previous},
+			Expected: `{"type":"articles","id":"1","attributes":{"discount":"0.50 USD","price":"0.05 EUR","status":"DRAFT"},"meta":{"previous":"draft"}}`,
+		},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			data, err := MarshalResource(tc.In)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.JSONEq(t, tc.Expected, string(data))
+		})
+	}
+}
+
+func TestAttrUnmarshaler(t *testing.T) {
+	data := `{"type":"articles","id":"1","attributes":{"discount":"0.50 USD","price":"19.99 EUR","status":"published"},"meta":{"previous":"draft"}}`
+	got := codecArticle{}
+	if err := UnmarshalResource([]byte(data), &got); err != nil {
+		t.Fatal(err)
+	}
+	discount, previous := money(50), status(0)
+	assert.Equal(t, codecArticle{Id: 1, Price: 1999, Discount: &discount, Status: 1, Previous: &previous}, got)
+
+	// null pointers are set to nil
+	got = codecArticle{Discount: &discount}
+	if err := UnmarshalResource([]byte(`{"type":"articles","id":"1","attributes":{"discount":null}}`), &got); err != nil {
+		t.Fatal(err)
+	}
+	assert.Nil(t, got.Discount)
+
+	err := UnmarshalResource([]byte(`{"type":"articles","id":"1","attributes":{"price":"1.00 USD"}}`), &codecArticle{})
+	var unmarshalErr *UnmarshalErr
+	if assert.ErrorAs(t, err, &unmarshalErr) {
+		assert.Equal(t, "price", unmarshalErr.Field)
+		assert.EqualError(t, unmarshalErr.Err, "price: expected currency EUR")
+	}
+}
+
+func TestTagOptions(t *testing.T) {
+	opts := TagOptions{Name: "price", Options: "string,currency=EUR,lower"}
+	assert.True(t, opts.Has("lower"))
+	assert.False(t, opts.Has("currency"))
+	currency, ok := opts.Value("currency")
+	assert.True(t, ok)
+	assert.Equal(t, "EUR", currency)
+	_, ok = opts.Value("precision")
+	assert.False(t, ok)
+}
+
+func TestRelOption(t *testing.T) {
+	rel, ok := RelOption("count,omitempty,rel=comments")
+	assert.True(t, ok)
+	assert.Equal(t, "comments", rel)
+	_, ok = RelOption("count,omitempty")
+	assert.False(t, ok)
+}
+
+func TestTagTypes(t *testing.T) {
+	assert.Contains(t, TagTypes(), TagValueAttr)
+	assert.Contains(t, TagTypes(), TagValueUnion)
+	assert.NotContains(t, TagTypes(), TagValueOmitEmpty)
+}
@@ -0,0 +1,94 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// parseCatchAllTag parses a catch-all tag, eg `jsonapi:"attr-map"`,
+// whose field must be a map with string keys.
+func parseCatchAllTag(f reflect.StructField, typ string) (tag, error) {
+	t := derefType(f.Type)
+	if t.Kind() != reflect.Map || t.Key().Kind() != reflect.String {
+		return tag{}, &TagErr{f.Name, fmt.Errorf("%s must be a map with string keys", typ)}
+	}
+
+	return tag{
+		typ:  typ,
+		name: typ,
+	}, nil
+}
+
+// marshalCatchAll adds the entries of the catch-all map field f of v
+// to members, except those whose keys are already present.
+func marshalCatchAll(v reflect.Value, members map[string]json.RawMessage, f field) error {
+	v, err := fieldByIndex(v, f.idxs)
+	if err != nil {
+		return err
+	}
+	v, err = derefValue(v)
+	if err != nil {
+		return err
+	}
+	if !v.IsValid() {
+		return nil
+	}
+
+	iter := v.MapRange()
+	for iter.Next() {
+		k := iter.Key().String()
+		if _, ok := members[k]; ok {
+			continue
+		}
+		j, err := json.Marshal(iter.Value().Interface())
+		if err != nil {
+			return &MarshalErr{Field: k, Err: err}
+		}
+		members[k] = j
+	}
+	return nil
+}
+
+// unmarshalCatchAll stores the members that are not claimed by other
+// fields in the catch-all map field f of v. The map is only allocated
+// if there are unclaimed members.
+func unmarshalCatchAll(v reflect.Value, members map[string]json.RawMessage, claimed map[string]bool, f field, o *options) error {
+	var m reflect.Value
+	for k, data := range members {
+		if claimed[k] {
+			continue
+		}
+		if !m.IsValid() {
+			fv, err := initFieldByIndex(v, f.idxs)
+			if err != nil {
+				return err
+			}
+			if m, err = derefValue(fv); err != nil {
+				return err
+			}
+			if m.IsNil() {
+				m.Set(reflect.MakeMap(m.Type()))
+			}
+		}
+
+		elem := reflect.New(m.Type().Elem())
+		if err := decodeJson(data, elem.Interface(), o.useNumber); err != nil {
+			return &UnmarshalErr{Field: k, Err: err}
+		}
+		m.SetMapIndex(reflect.ValueOf(k).Convert(m.Type().Key()), elem.Elem())
+	}
+	return nil
+}
+
+// claimedNames returns the names of the fields of type typ,
+// excluding those that belong to relationships.
+func claimedNames(fields []field, typ string) map[string]bool {
+	claimed := map[string]bool{}
+	for _, f := range fields {
+		if f.tag.typ == typ && f.tag.rel == "" {
+			claimed[f.tag.name] = true
+		}
+	}
+	return claimed
+}
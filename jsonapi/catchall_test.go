@@ -0,0 +1,132 @@
+package jsonapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type metaMapArticle struct {
+	Id      string         `jsonapi:"id,articles"`
+	Views   int            `jsonapi:"meta,views"`
+	Total   int            `jsonapi:"meta,total,rel=comments"`
+	Extra   map[string]any `jsonapi:"meta-map"`
+	Comment []string       `jsonapi:"rel,comments,comments"`
+}
+
+const metaMapArticleJson = `
+{
+	"type": "articles",
+	"id": "1",
+	"meta": {"views": 10, "source": "feed", "flags": [1, 2]},
+	"relationships": {"comments": {"data": [{"type": "comments", "id": "5"}], "meta": {"total": 3}}}
+}`
+
+var metaMapArticleValue = metaMapArticle{
+	Id:      "1",
+	Views:   10,
+	Total:   3,
+	Extra:   map[string]any{"source": "feed", "flags": []any{float64(1), float64(2)}},
+	Comment: []string{"5"},
+}
+
+func TestMarshalResource_MetaMap(t *testing.T) {
+	got, err := MarshalResource(metaMapArticleValue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, fmtJson(t, []byte(metaMapArticleJson)), fmtJson(t, got))
+
+	// tagged fields take precedence
+	in := metaMapArticleValue
+	in.Extra = map[string]any{"views": 99}
+	got, err = MarshalResource(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"type": "articles", "id": "1", "meta": {"views": 10}, "relationships": {"comments": {"data": [{"type": "comments", "id": "5"}], "meta": {"total": 3}}}}`
+	assert.Equal(t, fmtJson(t, []byte(want)), fmtJson(t, got))
+}
+
+func TestUnmarshalResource_MetaMap(t *testing.T) {
+	got := metaMapArticle{}
+	if err := UnmarshalResource([]byte(metaMapArticleJson), &got); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, metaMapArticleValue, got)
+
+	// the map is only allocated for unclaimed members
+	got = metaMapArticle{}
+	if err := UnmarshalResource([]byte(`{"type": "articles", "id": "1", "meta": {"views": 1}}`), &got); err != nil {
+		t.Fatal(err)
+	}
+	assert.Nil(t, got.Extra)
+}
+
+func TestUnmarshalResource_MetaMap_Typed(t *testing.T) {
+	type tp struct {
+		Extra *map[string]int `jsonapi:"meta-map"`
+	}
+
+	got := tp{}
+	if err := UnmarshalResource([]byte(`{"meta": {"a": 1, "b": 2}}`), &got); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, &map[string]int{"a": 1, "b": 2}, got.Extra)
+
+	err := UnmarshalResource([]byte(`{"meta": {"a": "x"}}`), &tp{})
+	assert.ErrorAs(t, err, addrOf(&UnmarshalErr{}))
+}
+
+func TestMarshalResource_MetaMap_TagErr(t *testing.T) {
+	_, err := MarshalResource(struct {
+		Extra map[int]any `jsonapi:"meta-map"`
+	}{})
+	assert.ErrorAs(t, err, addrOf(&TagErr{}))
+}
+
+type attrMapArticle struct {
+	Id    string         `jsonapi:"id,articles"`
+	Title string         `jsonapi:"attr,title"`
+	Body  string         `jsonapi:"attr,body,omitempty"`
+	Extra map[string]any `jsonapi:"attr-map"`
+}
+
+func TestResource_AttrMap_RoundTrip(t *testing.T) {
+	in := `
+	{
+		"type": "articles",
+		"id": "1",
+		"attributes": {"title": "Hello", "summary": "Hi", "rating": {"stars": 4}}
+	}`
+
+	got := attrMapArticle{}
+	if err := UnmarshalResource([]byte(in), &got); err != nil {
+		t.Fatal(err)
+	}
+	want := attrMapArticle{
+		Id:    "1",
+		Title: "Hello",
+		Extra: map[string]any{"summary": "Hi", "rating": map[string]any{"stars": float64(4)}},
+	}
+	assert.Equal(t, want, got)
+
+	out, err := MarshalResource(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, fmtJson(t, []byte(in)), fmtJson(t, out))
+}
+
+func TestMarshalResource_AttrMap_Precedence(t *testing.T) {
+	got, err := MarshalResource(attrMapArticle{
+		Id:    "1",
+		Title: "Hello",
+		Extra: map[string]any{"title": "Ignored", "body": "Extra"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"type": "articles", "id": "1", "attributes": {"title": "Hello", "body": "Extra"}}`
+	assert.Equal(t, fmtJson(t, []byte(want)), fmtJson(t, got))
+}
@@ -0,0 +1,121 @@
+package jsonapi
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// CheckType parses the tags of a, which must be a tagged struct, a
+// pointer to one, or the reflect.Type of either, and of the structs it
+// embeds and is related to, returning the errors.Join of every problem
+// found, or nil if there are none. Services can call it at startup or
+// in tests, to fail fast rather than on the first request that
+// marshals the type. The problems are those that marshaling and
+// unmarshaling would report, such as TagErrs and UnsupportedTypeErrs,
+// with opts applied as they would be, eg WithStrictMemberNames.
+func CheckType(a any, opts ...Option) error {
+	t, ok := a.(reflect.Type)
+	if !ok {
+		t = reflect.TypeOf(a)
+	}
+	if t == nil || derefType(t).Kind() != reflect.Struct {
+		return fmt.Errorf("jsonapi: %w", ErrNotStruct)
+	}
+
+	var errs []error
+	checkType(derefType(t), newOptions(opts), map[reflect.Type]bool{}, &errs)
+	return errors.Join(errs...)
+}
+
+// checkType appends the problems with the tags of the struct
+// type t, and of the structs related to it, to errs. seen holds
+// the types already checked.
+func checkType(t reflect.Type, o *options, seen map[reflect.Type]bool, errs *[]error) {
+	if seen[t] {
+		return
+	}
+	seen[t] = true
+
+	var related []reflect.Type
+	for _, err := range checkStruct(t, o, &related) {
+		*errs = append(*errs, fmt.Errorf("jsonapi: parsing tags of %s: %w", t, err))
+	}
+	for _, rt := range related {
+		checkType(rt, o, seen, errs)
+	}
+}
+
+// checkStruct returns the problems with the tags of the struct
+// type t, appending the struct types of its relationships to related.
+func checkStruct(t reflect.Type, o *options, related *[]reflect.Type) []error {
+	// parse each field's tag, as walkTags
+	// would stop at the first problem
+	var errs []error
+	checkFields(t, map[reflect.Type]bool{}, &errs, related)
+	if len(errs) > 0 {
+		return errs
+	}
+
+	v := reflect.New(t).Elem()
+	fields, err := parseTags(v)
+	if err != nil {
+		return []error{err}
+	}
+
+	typ := resourceType(v, fields, o)
+	if f, ok := fieldOfType(fields, TagValueId); ok && typ == "" {
+		errs = append(errs, &TagErr{f.structField, fmt.Errorf("required: type")})
+	}
+	if o = o.forType(typ); o.strictNames {
+		for _, f := range o.nameMembers(fields) {
+			if err := checkMemberNames([]field{f}); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errs
+}
+
+// checkFields appends the errors from parsing the tags of the
+// fields of the struct type t, and of the untagged structs it
+// embeds, to errs, and the struct types of its relationships to
+// related. embedded holds the types already walked.
+func checkFields(t reflect.Type, embedded map[reflect.Type]bool, errs *[]error, related *[]reflect.Type) {
+	if embedded[t] {
+		return
+	}
+	embedded[t] = true
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		typ, opts, ok := splitTypeAndOpts(f)
+		if !ok {
+			if f.Anonymous {
+				if ft := derefType(f.Type); ft.Kind() == reflect.Struct {
+					checkFields(ft, embedded, errs, related)
+				}
+				continue
+			}
+			typ = TagValueAttr
+		}
+		if !f.IsExported() && !f.Anonymous || typ == TagValueIgnore {
+			continue
+		}
+		tg, err := parseTag(f, typ, opts)
+		if err != nil {
+			*errs = append(*errs, err)
+			continue
+		}
+		if tg.typ != TagValueRel {
+			continue
+		}
+		rt := derefType(f.Type)
+		if !isToOne(reflect.Zero(rt)) {
+			rt = derefType(rt.Elem())
+		}
+		if rt.Kind() == reflect.Struct {
+			*related = append(*related, rt)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package jsonapi
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type checkTypeAuthor struct {
+	Id   string `jsonapi:"id,people"`
+	Lid  int    `jsonapi:"lid"`
+	Name string `jsonapi:"attr,name"`
+}
+
+type checkTypeBase struct {
+	Callback func() `jsonapi:"attr,callback"`
+}
+
+type checkTypeArticle struct {
+	checkTypeBase
+	Id        string             `jsonapi:"id,articles"`
+	Title     string             `jsonapi:"attr,title,format=date"`
+	Kind      string             `jsonapi:"attribute,kind"`
+	Author    *checkTypeAuthor   `jsonapi:"rel,author,people"`
+	Reviewers []*checkTypeAuthor `jsonapi:"rel,reviewers,people"`
+	Parent    *checkTypeArticle  `jsonapi:"rel,parent,articles"`
+	Editor    string             `jsonapi:"rel,editor"`
+}
+
+func TestCheckType(t *testing.T) {
+	err := CheckType(checkTypeArticle{})
+	assert.EqualError(t, err, ""+
+		"jsonapi: parsing tags of jsonapi.checkTypeArticle: unsupported type on field Callback': func\n"+
+		"jsonapi: parsing tags of jsonapi.checkTypeArticle: tag error on field 'Title': format requires a time.Time or time.Duration field, not string\n"+
+		"jsonapi: parsing tags of jsonapi.checkTypeArticle: tag error on field 'Kind': unknown tag type: attribute\n"+
+		"jsonapi: parsing tags of jsonapi.checkTypeArticle: tag error on field 'Editor': required: type\n"+
+		"jsonapi: parsing tags of jsonapi.checkTypeAuthor: tag error on field 'Lid': lid must be a string")
+
+	var tagErr *TagErr
+	assert.ErrorAs(t, err, &tagErr)
+	var typeErr *UnsupportedTypeErr
+	assert.ErrorAs(t, err, &typeErr)
+}
+
+func TestCheckType_Related(t *testing.T) {
+	type article struct {
+		Id      string             `jsonapi:"id,articles"`
+		Authors []*checkTypeAuthor `jsonapi:"rel,authors,people"`
+		Parent  *article           `jsonapi:"rel,parent,articles"`
+	}
+
+	err := CheckType(reflect.TypeFor[*article]())
+	assert.EqualError(t, err, "jsonapi: parsing tags of jsonapi.checkTypeAuthor: tag error on field 'Lid': lid must be a string")
+}
+
+func TestCheckType_Ok(t *testing.T) {
+	assert.NoError(t, CheckType(&docArticle{}))
+	assert.NoError(t, CheckType(reflect.TypeFor[docArticle]()))
+}
+
+func TestCheckType_Options(t *testing.T) {
+	type article struct {
+		Id       string `jsonapi:"id,articles"`
+		Title    string `jsonapi:"attr,the title!"`
+		Subtitle string `jsonapi:"attr,-sub"`
+	}
+
+	assert.NoError(t, CheckType(article{}))
+	err := CheckType(article{}, WithStrictMemberNames())
+	assert.ErrorIs(t, err, ErrInvalidMemberName)
+	assert.ErrorContains(t, err, `field 'Subtitle': invalid member name: "-sub"`)
+	assert.ErrorContains(t, err, `field 'Title': invalid member name: "the title!"`)
+}
+
+func TestCheckType_Structural(t *testing.T) {
+	type article struct {
+		Id  string `jsonapi:"id,articles"`
+		Key string `jsonapi:"id,articles"`
+	}
+	assert.EqualError(t, CheckType(article{}), "jsonapi: parsing tags of jsonapi.article: tag error on field 'Id': id tag also declared on field 'Key'")
+
+	assert.ErrorIs(t, CheckType(1), ErrNotStruct)
+	assert.ErrorIs(t, CheckType(nil), ErrNotStruct)
+}
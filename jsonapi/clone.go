@@ -0,0 +1,241 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+)
+
+// Clone returns a deep copy of the document, including its
+// resources, links and raw JSON members, so that it can be
+// modified without affecting d.
+func (d *Document) Clone() *Document {
+	if d == nil {
+		return nil
+	}
+	c := &Document{
+		Data:       d.Data.Clone(),
+		Meta:       cloneRawMap(d.Meta),
+		Links:      cloneLinks(d.Links),
+		JsonApi:    d.JsonApi.Clone(),
+		ExtMembers: cloneRawMap(d.ExtMembers),
+		Unknown:    cloneRawMap(d.Unknown),
+		metaOrder:  slices.Clone(d.metaOrder),
+	}
+	if d.Errors != nil {
+		c.Errors = make([]*ErrorObject, len(d.Errors))
+		for i, e := range d.Errors {
+			c.Errors[i] = e.Clone()
+		}
+	}
+	c.Included = cloneResources(d.Included)
+	return c
+}
+
+// Clone returns a deep copy of the primary data.
+func (p *PrimaryData) Clone() *PrimaryData {
+	if p == nil {
+		return nil
+	}
+	return &PrimaryData{
+		Resource:   p.Resource.Clone(),
+		Resources:  cloneResources(p.Resources),
+		Collection: p.Collection,
+	}
+}
+
+// Clone returns a deep copy of the resource.
+func (r *Resource) Clone() *Resource {
+	if r == nil {
+		return nil
+	}
+	c := &Resource{
+		ResourceIdentifier: r.ResourceIdentifier.Clone(),
+		Attributes:         cloneRawMap(r.Attributes),
+		Links:              cloneLinks(r.Links),
+		Unknown:            cloneRawMap(r.Unknown),
+		attrOrder:          slices.Clone(r.attrOrder),
+		relOrder:           slices.Clone(r.relOrder),
+		metaOrder:          slices.Clone(r.metaOrder),
+	}
+	if r.ToOneRelationships != nil {
+		c.ToOneRelationships = make(map[string]*ToOneResourceLinkage, len(r.ToOneRelationships))
+		for k, l := range r.ToOneRelationships {
+			c.ToOneRelationships[k] = l.Clone()
+		}
+	}
+	if r.ToManyRelationships != nil {
+		c.ToManyRelationships = make(map[string]*ToManyResourceLinkage, len(r.ToManyRelationships))
+		for k, l := range r.ToManyRelationships {
+			c.ToManyRelationships[k] = l.Clone()
+		}
+	}
+	return c
+}
+
+// Clone returns a deep copy of the resource identifier.
+func (id ResourceIdentifier) Clone() ResourceIdentifier {
+	id.Id = cloneRaw(id.Id)
+	id.Meta = cloneRawMap(id.Meta)
+	return id
+}
+
+// Clone returns a deep copy of the linkage.
+func (l *ToOneResourceLinkage) Clone() *ToOneResourceLinkage {
+	if l == nil {
+		return nil
+	}
+	return &ToOneResourceLinkage{
+		Links: cloneLinks(l.Links),
+		Meta:  cloneRawMap(l.Meta),
+		Data:  l.Data.Clone(),
+	}
+}
+
+// Clone returns a deep copy of the linkage.
+func (l *ToManyResourceLinkage) Clone() *ToManyResourceLinkage {
+	if l == nil {
+		return nil
+	}
+	c := &ToManyResourceLinkage{
+		Links: cloneLinks(l.Links),
+		Meta:  cloneRawMap(l.Meta),
+	}
+	if l.Data != nil {
+		c.Data = make([]ResourceIdentifier, len(l.Data))
+		for i, id := range l.Data {
+			c.Data[i] = id.Clone()
+		}
+	}
+	return c
+}
+
+// Clone returns a deep copy of the link.
+func (l *Link) Clone() *Link {
+	if l == nil {
+		return nil
+	}
+	c := *l
+	c.LinkObject.DescribedBy = l.LinkObject.DescribedBy.Clone()
+	if l.LinkObject.HrefLang != nil {
+		c.LinkObject.HrefLang = append([]string{}, l.LinkObject.HrefLang...)
+	}
+	if l.LinkObject.Meta != nil {
+		c.LinkObject.Meta = cloneAny(l.LinkObject.Meta).(map[string]interface{})
+	}
+	return &c
+}
+
+// Clone returns a deep copy of the jsonapi object.
+func (j *JsonApiObject) Clone() *JsonApiObject {
+	if j == nil {
+		return nil
+	}
+	return &JsonApiObject{
+		Version: j.Version,
+		Ext:     slices.Clone(j.Ext),
+		Profile: slices.Clone(j.Profile),
+		Meta:    cloneRawMap(j.Meta),
+	}
+}
+
+// Clone returns a deep copy of the error object.
+func (e *ErrorObject) Clone() *ErrorObject {
+	if e == nil {
+		return nil
+	}
+	c := *e
+	c.Links = cloneLinks(e.Links)
+	c.Meta = cloneRawMap(e.Meta)
+	if e.Source != nil {
+		src := *e.Source
+		c.Source = &src
+	}
+	return &c
+}
+
+func cloneResources(rs []*Resource) []*Resource {
+	if rs == nil {
+		return nil
+	}
+	c := make([]*Resource, len(rs))
+	for i, r := range rs {
+		c[i] = r.Clone()
+	}
+	return c
+}
+
+func cloneLinks(m map[string]*Link) map[string]*Link {
+	if m == nil {
+		return nil
+	}
+	c := make(map[string]*Link, len(m))
+	for k, l := range m {
+		c[k] = l.Clone()
+	}
+	return c
+}
+
+func cloneRawMap(m map[string]json.RawMessage) map[string]json.RawMessage {
+	if m == nil {
+		return nil
+	}
+	c := make(map[string]json.RawMessage, len(m))
+	for k, v := range m {
+		c[k] = cloneRaw(v)
+	}
+	return c
+}
+
+func cloneRaw(b json.RawMessage) json.RawMessage {
+	if b == nil {
+		return nil
+	}
+	return append(json.RawMessage{}, b...)
+}
+
+// cloneAny deep-copies the maps and slices of any type in v, eg
+// those produced by unmarshaling JSON into an interface value, and
+// raw JSON, []strings or map[string]strings set by middleware. The
+// values of pointers and structs are shared.
+func cloneAny(v any) any {
+	if v == nil {
+		return nil
+	}
+	return cloneValue(reflect.ValueOf(v)).Interface()
+}
+
+// cloneValue deep-copies the maps, slices and
+// interface values in v, as described by cloneAny.
+func cloneValue(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			c.SetMapIndex(iter.Key(), cloneValue(iter.Value()))
+		}
+		return c
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(cloneValue(v.Index(i)))
+		}
+		return c
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.New(v.Type()).Elem()
+		c.Set(cloneValue(v.Elem()))
+		return c
+	default:
+		return v
+	}
+}
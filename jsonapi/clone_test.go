@@ -0,0 +1,117 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocument_Clone(t *testing.T) {
+	in := `
+	{
+		"data": {
+			"type": "articles",
+			"id": "1",
+			"attributes": {"title": "Hello"},
+			"relationships": {
+				"author": {"data": {"type": "people", "id": "2"}},
+				"comments": {"data": [{"type": "comments", "id": "5", "meta": {"new": true}}]}
+			},
+			"links": {"self": {"href": "/articles/1", "meta": {"tags": ["a"]}}}
+		},
+		"included": [{"type": "people", "id": "2", "attributes": {"name": "Bob"}}],
+		"errors": [{"status": "404", "source": {"pointer": "/data"}}],
+		"meta": {"total": 1},
+		"jsonapi": {"version": "1.1"},
+		"debug": true
+	}`
+
+	d := Document{}
+	if err := json.Unmarshal([]byte(in), &d); err != nil {
+		t.Fatal(err)
+	}
+
+	c := d.Clone()
+	assert.Equal(t, &d, c)
+
+	// mutating the clone leaves the original untouched
+	r := c.Data.Resource
+	r.Attributes["title"][1] = 'J'
+	r.Id[1] = '9'
+	r.ToOneRelationships["author"].Data.Type = "admins"
+	r.ToManyRelationships["comments"].Data[0].Meta["new"][0] = 'f'
+	r.Links["self"].LinkObject.Meta["tags"].([]any)[0] = "b"
+	c.Included[0].Attributes["name"] = json.RawMessage(`"Alice"`)
+	c.Errors[0].Source.Pointer = "/errors"
+	c.Meta["total"][0] = '2'
+	c.JsonApi.Version = "1.0"
+	c.Unknown["debug"] = json.RawMessage(`false`)
+
+	got, err := json.Marshal(&d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, fmtJson(t, []byte(in)), fmtJson(t, got))
+}
+
+func TestLink_Clone_Meta(t *testing.T) {
+	// meta set by middleware rather than unmarshaled
+	l := &Link{LinkObject: LinkObject{Href: "/articles/1", Meta: map[string]interface{}{
+		"raw":    json.RawMessage(`{"a":1}`),
+		"tags":   []string{"a"},
+		"labels": map[string]string{"k": "v"},
+		"nested": []any{map[string][]int{"n": {1}}},
+	}}}
+
+	c := l.Clone()
+	assert.Equal(t, l, c)
+
+	c.LinkObject.Meta["raw"].(json.RawMessage)[6] = '2'
+	c.LinkObject.Meta["tags"].([]string)[0] = "b"
+	c.LinkObject.Meta["labels"].(map[string]string)["k"] = "w"
+	c.LinkObject.Meta["nested"].([]any)[0].(map[string][]int)["n"][0] = 2
+
+	assert.Equal(t, json.RawMessage(`{"a":1}`), l.LinkObject.Meta["raw"])
+	assert.Equal(t, []string{"a"}, l.LinkObject.Meta["tags"])
+	assert.Equal(t, map[string]string{"k": "v"}, l.LinkObject.Meta["labels"])
+	assert.Equal(t, []any{map[string][]int{"n": {1}}}, l.LinkObject.Meta["nested"])
+}
+
+func TestClone_Nil(t *testing.T) {
+	assert.Nil(t, (*Document)(nil).Clone())
+	assert.Nil(t, (*Resource)(nil).Clone())
+	assert.Nil(t, (*ToOneResourceLinkage)(nil).Clone())
+	assert.Nil(t, (*ToManyResourceLinkage)(nil).Clone())
+	assert.Nil(t, (*Link)(nil).Clone())
+
+	// nil maps and slices stay nil
+	assert.Equal(t, &Resource{}, (&Resource{}).Clone())
+	assert.Equal(t, &Document{}, (&Document{}).Clone())
+}
+
+func TestResource_Clone_Redaction(t *testing.T) {
+	r := &Resource{
+		ResourceIdentifier: ResourceIdentifier{Type: "people", Id: json.RawMessage(`"1"`)},
+		Attributes: map[string]json.RawMessage{
+			"name":  json.RawMessage(`"Bob"`),
+			"email": json.RawMessage(`"bob@example.com"`),
+		},
+		Links: map[string]*Link{
+			"self": {LinkObject: LinkObject{
+				Href: "/people/1",
+				Meta: map[string]any{"token": json.RawMessage(`"secret"`)},
+			}},
+		},
+	}
+
+	// middleware redacts a copy, eg for logging
+	c := r.Clone()
+	delete(c.Attributes, "email")
+	c.Attributes["name"][1] = '*'
+	c.Links["self"].LinkObject.Meta["token"].(json.RawMessage)[1] = '*'
+
+	assert.Equal(t, json.RawMessage(`"Bob"`), r.Attributes["name"])
+	assert.Equal(t, json.RawMessage(`"bob@example.com"`), r.Attributes["email"])
+	assert.Equal(t, json.RawMessage(`"secret"`), r.Links["self"].LinkObject.Meta["token"])
+}
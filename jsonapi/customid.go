@@ -0,0 +1,83 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// IdMarshaler is implemented by structs that produce their own resource
+// ids, eg from composite keys or opaque encodings of their fields,
+// decoupling the id on the wire from the fields that represent it. The
+// id returned by JsonApiId overrides the value of the id field, if any,
+// and is omitted if empty. It is also used to identify related structs
+// in relationships.
+type IdMarshaler interface {
+	JsonApiId() (string, error)
+}
+
+// IdUnmarshaler is implemented by structs that parse their own resource
+// ids. SetJsonApiId is called with the id of the resource being
+// unmarshaled, if any, once its members have been unmarshaled. The id
+// field, if any, is still set too.
+type IdUnmarshaler interface {
+	SetJsonApiId(id string) error
+}
+
+var (
+	idMarshalerType   = reflect.TypeFor[IdMarshaler]()
+	idUnmarshalerType = reflect.TypeFor[IdUnmarshaler]()
+)
+
+// idMarshaler returns the struct value v as an IdMarshaler if it,
+// or a pointer to it, implements it, even if v isn't addressable.
+func idMarshaler(v reflect.Value) (IdMarshaler, bool) {
+	t := v.Type()
+	switch {
+	case t.Implements(idMarshalerType):
+		return v.Interface().(IdMarshaler), true
+	case v.CanAddr() && v.Addr().Type().Implements(idMarshalerType):
+		return v.Addr().Interface().(IdMarshaler), true
+	case reflect.PointerTo(t).Implements(idMarshalerType):
+		p := reflect.New(t)
+		p.Elem().Set(v)
+		return p.Interface().(IdMarshaler), true
+	}
+	return nil, false
+}
+
+// marshalCustomId sets the id of r to that returned by the struct
+// value v, if it implements IdMarshaler.
+func marshalCustomId(v reflect.Value, r *Resource) error {
+	im, ok := idMarshaler(v)
+	if !ok {
+		return nil
+	}
+	id, err := im.JsonApiId()
+	if err != nil {
+		return &MarshalErr{Field: TagValueId, Pointer: "/id", Err: err}
+	}
+	r.Id = nil
+	if id != "" {
+		r.Id, _ = json.Marshal(id)
+	}
+	return nil
+}
+
+// unmarshalCustomId calls the SetJsonApiId method of the struct value
+// v, if it implements IdUnmarshaler and r has an id, with the id as a
+// string, or its JSON text if it isn't one, eg a number.
+func unmarshalCustomId(v reflect.Value, r *Resource) error {
+	if len(r.Id) == 0 || !v.CanAddr() || !v.Addr().Type().Implements(idUnmarshalerType) {
+		return nil
+	}
+	id := string(r.Id)
+	if r.Id[0] == '"' {
+		if err := json.Unmarshal(r.Id, &id); err != nil {
+			return &UnmarshalErr{Field: TagValueId, Pointer: "/id", Err: err}
+		}
+	}
+	if err := v.Addr().Interface().(IdUnmarshaler).SetJsonApiId(id); err != nil {
+		return &UnmarshalErr{Field: TagValueId, Pointer: "/id", Err: err}
+	}
+	return nil
+}
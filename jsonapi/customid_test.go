@@ -0,0 +1,108 @@
+package jsonapi
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// orderLine is identified by its order and line numbers.
+type orderLine struct {
+	Order    int    `jsonapi:"-"`
+	Line     int    `jsonapi:"-"`
+	Quantity int    `jsonapi:"attr,quantity"`
+	Product  string `jsonapi:"rel,product,products"`
+}
+
+func (l orderLine) JsonApiType() string {
+	return "order-lines"
+}
+
+func (l orderLine) JsonApiId() (string, error) {
+	if l.Order == 0 {
+		return "", nil
+	}
+	if l.Line < 1 {
+		return "", errors.New("invalid line")
+	}
+	return fmt.Sprintf("%d-%d", l.Order, l.Line), nil
+}
+
+func (l *orderLine) SetJsonApiId(id string) error {
+	if _, err := fmt.Sscanf(id, "%d-%d", &l.Order, &l.Line); err != nil {
+		return fmt.Errorf("invalid order line id %q", id)
+	}
+	return nil
+}
+
+type shipment struct {
+	Id    int         `jsonapi:"id,shipments,string"`
+	Lines []orderLine `jsonapi:"rel,lines,order-lines"`
+}
+
+func TestIdMarshaler(t *testing.T) {
+	type testCase struct {
+		Name     string
+		In       any
+		Expected string
+	}
+
+	for _, tc := range []testCase{
+		{
+			Name:     "composite id",
+			In:       orderLine{Order: 7, Line: 2, Quantity: 3, Product: "p1"},
+			Expected: `{"type":"order-lines","id":"7-2","attributes":{"quantity":3},"relationships":{"product":{"data":{"type":"products","id":"p1"}}}}`,
+		},
+		{
+			Name:     "empty id",
+			In:       &orderLine{Quantity: 3, Product: "p1"},
+			Expected: `{"type":"order-lines","attributes":{"quantity":3},"relationships":{"product":{"data":{"type":"products","id":"p1"}}}}`,
+		},
+		{
+			Name:     "related structs",
+			In:       shipment{Id: 1, Lines: []orderLine{{Order: 7, Line: 1}, {Order: 7, Line: 2}}},
+			Expected: `{"type":"shipments","id":"1","relationships":{"lines":{"data":[{"type":"order-lines","id":"7-1"},{"type":"order-lines","id":"7-2"}]}}}`,
+		},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			data, err := MarshalResource(tc.In)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.JSONEq(t, tc.Expected, string(data))
+		})
+	}
+
+	_, err := MarshalResource(orderLine{Order: 7})
+	var marshalErr *MarshalErr
+	if assert.ErrorAs(t, err, &marshalErr) {
+		assert.Equal(t, "/id", marshalErr.Pointer)
+		assert.EqualError(t, marshalErr.Err, "invalid line")
+	}
+}
+
+func TestIdUnmarshaler(t *testing.T) {
+	got := orderLine{}
+	data := `{"type":"order-lines","id":"7-2","attributes":{"quantity":3},"relationships":{"product":{"data":{"type":"products","id":"p1"}}}}`
+	if err := UnmarshalResource([]byte(data), &got); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, orderLine{Order: 7, Line: 2, Quantity: 3, Product: "p1"}, got)
+
+	// related structs are hydrated from their identifiers
+	s := shipment{}
+	data = `{"type":"shipments","id":"1","relationships":{"lines":{"data":[{"type":"order-lines","id":"7-1"},{"type":"order-lines","id":"7-2"}]}}}`
+	if err := UnmarshalResource([]byte(data), &s); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, shipment{Id: 1, Lines: []orderLine{{Order: 7, Line: 1}, {Order: 7, Line: 2}}}, s)
+
+	err := UnmarshalResource([]byte(`{"type":"order-lines","id":"7"}`), &orderLine{})
+	var unmarshalErr *UnmarshalErr
+	if assert.ErrorAs(t, err, &unmarshalErr) {
+		assert.Equal(t, "/id", unmarshalErr.Pointer)
+		assert.EqualError(t, unmarshalErr.Err, `invalid order line id "7"`)
+	}
+}
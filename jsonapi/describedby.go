@@ -0,0 +1,57 @@
+package jsonapi
+
+import "strings"
+
+// LinkDescribedBy is the name of the link to a description of a
+// document or link target, eg a JSON Schema.
+const LinkDescribedBy = "describedby"
+
+// WithDescribedBy adds a top-level describedby link to documents built
+// by FormatDocument and MarshalDocument whose primary data are resources
+// of a single type, eg to the endpoint serving the type's JSON Schema.
+// The link is tmpl with {type} replaced by the resource type, eg
+// "/schemas/{type}". Documents with null or empty primary data, or
+// resources of several types, have no describedby link.
+func WithDescribedBy(tmpl string) Option {
+	return func(o *options) {
+		o.describedBy = tmpl
+	}
+}
+
+// addDescribedBy adds the describedby link to d,
+// unless its primary data has no single type.
+func addDescribedBy(d *Document, o *options) {
+	if o.describedBy == "" || d.Data == nil {
+		return
+	}
+
+	typ := primaryType(d.Data)
+	if typ == "" {
+		return
+	}
+
+	if d.Links == nil {
+		d.Links = map[string]*Link{}
+	}
+	d.Links[LinkDescribedBy] = &Link{LinkString: strings.ReplaceAll(o.describedBy, "{type}", typ)}
+}
+
+// primaryType returns the type of the resources in p,
+// or "" if it has none, or resources of several types.
+func primaryType(p *PrimaryData) string {
+	if !p.Collection {
+		if p.Resource == nil {
+			return ""
+		}
+		return p.Resource.Type
+	}
+
+	typ := ""
+	for i, r := range p.Resources {
+		if i > 0 && r.Type != typ {
+			return ""
+		}
+		typ = r.Type
+	}
+	return typ
+}
@@ -0,0 +1,63 @@
+package jsonapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithDescribedBy(t *testing.T) {
+	opt := WithDescribedBy("/schemas/{type}")
+
+	for name, a := range map[string]any{"resource": docArticleValue, "collection": docArticlesValue} {
+		d, err := FormatDocument(a, opt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, map[string]*Link{"describedby": {LinkString: "/schemas/articles"}}, d.Links, name)
+	}
+
+	for name, a := range map[string]any{"null": (*docArticle)(nil), "empty": []docArticle{}, "none": docArticleValue} {
+		var opts []Option
+		if name != "none" {
+			opts = append(opts, opt)
+		}
+		d, err := FormatDocument(a, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Empty(t, d.Links, name)
+	}
+}
+
+func TestWithDescribedBy_Encoder(t *testing.T) {
+	buf := bytes.Buffer{}
+	if err := NewEncoder(&buf, WithDescribedBy("https://example.com/schemas/{type}.json")).Encode(docArticleValue); err != nil {
+		t.Fatal(err)
+	}
+
+	d := Document{}
+	if err := json.Unmarshal(buf.Bytes(), &d); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "https://example.com/schemas/articles.json", d.Links[LinkDescribedBy].LinkString)
+}
+
+func TestPrimaryType_Mixed(t *testing.T) {
+	p := &PrimaryData{Collection: true, Resources: []*Resource{
+		{ResourceIdentifier: ResourceIdentifier{Type: "articles"}},
+		{ResourceIdentifier: ResourceIdentifier{Type: "people"}},
+	}}
+	assert.Equal(t, "", primaryType(p))
+}
+
+func TestLinkObject_DescribedBy(t *testing.T) {
+	l := &Link{LinkObject: LinkObject{Href: "/articles/1", DescribedBy: &Link{LinkString: "/schemas/articles"}}}
+	data, err := json.Marshal(l)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.JSONEq(t, `{"href":"/articles/1","describedby":"/schemas/articles"}`, string(data))
+}
@@ -0,0 +1,121 @@
+package jsonapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"slices"
+)
+
+// ResourceDiff names the members that differ between two
+// resources, as reported by DiffResources and DiffResource.
+// Each list is sorted.
+type ResourceDiff struct {
+	Attributes    []string
+	Relationships []string
+	Meta          []string
+}
+
+// IsEmpty returns true if no members differ.
+func (d ResourceDiff) IsEmpty() bool {
+	return len(d.Attributes) == 0 && len(d.Relationships) == 0 && len(d.Meta) == 0
+}
+
+// DiffResources reports the attributes, relationships and meta that
+// differ between a and b, eg for audit logs, including members that
+// only one of them has. Values are compared as JSON, ignoring
+// whitespace and the order of object members, and relationships by
+// their linkage, ignoring their links and meta.
+func DiffResources(a, b *Resource) ResourceDiff {
+	return diffResources(a, b, false)
+}
+
+// DiffResource reports the members present in the incoming resource
+// r that differ from those of the struct a, as formatted by
+// FormatResource with opts, eg so that an update is skipped if
+// nothing would change. Members absent from r aren't compared, and
+// members present in r but omitted from a, eg by omitempty, differ.
+func DiffResource(r *Resource, a any, opts ...Option) (ResourceDiff, error) {
+	current, err := FormatResource(a, opts...)
+	if err != nil {
+		return ResourceDiff{}, err
+	}
+	return diffResources(current, r, true), nil
+}
+
+// diffResources compares the members of a and b, or, if
+// partial is true, only the members that b has.
+func diffResources(a, b *Resource, partial bool) ResourceDiff {
+	d := ResourceDiff{
+		Attributes: diffRawMaps(a.Attributes, b.Attributes, partial),
+		Meta:       diffRawMaps(a.Meta, b.Meta, partial),
+	}
+
+	names := relationshipNames(b)
+	if !partial {
+		names = append(names, relationshipNames(a)...)
+		slices.Sort(names)
+		names = slices.Compact(names)
+	}
+	for _, name := range names {
+		if !slices.Equal(linkageKeys(a, name), linkageKeys(b, name)) {
+			d.Relationships = append(d.Relationships, name)
+		}
+	}
+	return d
+}
+
+// diffRawMaps returns the sorted names of the members of a and b
+// that differ, or, if partial is true, of those that b has.
+func diffRawMaps(a, b map[string]json.RawMessage, partial bool) []string {
+	var names []string
+	for _, name := range sortedKeys(b) {
+		if va, ok := a[name]; !ok || !jsonEqual(va, b[name]) {
+			names = append(names, name)
+		}
+	}
+	if !partial {
+		for name := range a {
+			if _, ok := b[name]; !ok {
+				names = append(names, name)
+			}
+		}
+		slices.Sort(names)
+	}
+	return names
+}
+
+// linkageKeys returns the identifier keys of the linkage of r's
+// relationship name, with "" for null to-one linkage, prefixed by
+// "one" or "many" so that to-one and to-many relationships differ.
+// It returns nil if r doesn't have the relationship.
+func linkageKeys(r *Resource, name string) []string {
+	if rel, ok := r.ToOneRelationships[name]; ok {
+		if isNullIdentifier(rel.Data) {
+			return []string{"one", ""}
+		}
+		return []string{"one", identifierKey(rel.Data)}
+	}
+	if rel, ok := r.ToManyRelationships[name]; ok {
+		keys := []string{"many"}
+		for _, id := range rel.Data {
+			keys = append(keys, identifierKey(id))
+		}
+		return keys
+	}
+	return nil
+}
+
+// jsonEqual returns whether the raw JSON values a and b are
+// equal, ignoring whitespace and the order of object members.
+// Numbers are compared by their text.
+func jsonEqual(a, b json.RawMessage) bool {
+	if bytes.Equal(a, b) {
+		return true
+	}
+	var va, vb any
+	if decodeJson(a, &va, true) != nil || decodeJson(b, &vb, true) != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
@@ -0,0 +1,109 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffResources(t *testing.T) {
+	a := `{
+		"type": "articles",
+		"id": "1",
+		"attributes": {"title": "Hello", "body": {"text": "Hi", "lang": "en"}, "draft": true},
+		"relationships": {
+			"author": {"data": {"type": "people", "id": "2"}},
+			"editor": {"data": null},
+			"comments": {"data": [{"type": "comments", "id": "5"}]},
+			"tags": {"data": [], "links": {"self": "/articles/1/relationships/tags"}}
+		},
+		"meta": {"views": 10}
+	}`
+	b := `{
+		"type": "articles",
+		"id": "1",
+		"attributes": {"title": "Hello!", "body": {"lang": "en", "text": "Hi"}, "wordCount": 1},
+		"relationships": {
+			"author": {"data": {"type": "people", "id": "2"}},
+			"editor": {"data": {"type": "people", "id": "3"}},
+			"comments": {"data": [{"type": "comments", "id": "5"}, {"type": "comments", "id": "6"}]},
+			"tags": {"data": []}
+		},
+		"meta": {"views": 10}
+	}`
+
+	ra, rb := Resource{}, Resource{}
+	if err := json.Unmarshal([]byte(a), &ra); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(b), &rb); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := ResourceDiff{
+		Attributes:    []string{"draft", "title", "wordCount"},
+		Relationships: []string{"comments", "editor"},
+	}
+	assert.Equal(t, expected, DiffResources(&ra, &rb))
+	assert.Equal(t, expected, DiffResources(&rb, &ra))
+	assert.False(t, DiffResources(&ra, &rb).IsEmpty())
+	assert.True(t, DiffResources(&ra, ra.Clone()).IsEmpty())
+}
+
+func TestDiffResources_RelationshipKind(t *testing.T) {
+	a := &Resource{ToOneRelationships: map[string]*ToOneResourceLinkage{"author": {}}}
+	b := &Resource{ToManyRelationships: map[string]*ToManyResourceLinkage{"author": {Data: []ResourceIdentifier{}}}}
+	assert.Equal(t, []string{"author"}, DiffResources(a, b).Relationships)
+}
+
+func TestDiffResource(t *testing.T) {
+	type article struct {
+		Id       int    `jsonapi:"id,articles,string"`
+		Title    string `jsonapi:"attr,title"`
+		Author   int    `jsonapi:"rel,author,people,string"`
+		Comments []int  `jsonapi:"rel,comments,comments,string"`
+	}
+	current := article{Id: 1, Title: "Hello", Author: 2, Comments: []int{5}}
+
+	type testCase struct {
+		name     string
+		json     string
+		expected ResourceDiff
+	}
+
+	testCases := []testCase{
+		{
+			name:     "unchanged",
+			json:     `{"type": "articles", "id": "1", "attributes": {"title": "Hello"}}`,
+			expected: ResourceDiff{},
+		},
+		{
+			name:     "attribute",
+			json:     `{"type": "articles", "id": "1", "attributes": {"title": "Bye"}}`,
+			expected: ResourceDiff{Attributes: []string{"title"}},
+		},
+		{
+			name: "relationships",
+			json: `{"type": "articles", "id": "1", "relationships": {
+				"author": {"data": {"type": "people", "id": "2"}},
+				"comments": {"data": []}
+			}}`,
+			expected: ResourceDiff{Relationships: []string{"comments"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := Resource{}
+			if err := json.Unmarshal([]byte(tc.json), &r); err != nil {
+				t.Fatal(err)
+			}
+			d, err := DiffResource(&r, current)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tc.expected, d)
+		})
+	}
+}
@@ -0,0 +1,681 @@
+package jsonapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// MediaType is the JSON:API media type.
+const MediaType = "application/vnd.api+json"
+
+var (
+	ErrNotCollection   = fmt.Errorf("primary data is not a collection")
+	ErrUnexpectedArray = fmt.Errorf("primary data is a collection")
+)
+
+// Document represents a top-level JSON:API document.
+type Document struct {
+	// Data is the primary data. If nil, the "data"
+	// member is omitted.
+	Data     *PrimaryData
+	Errors   []*ErrorObject
+	Meta     map[string]json.RawMessage
+	Links    map[string]*Link
+	JsonApi  *JsonApiObject
+	Included []*Resource
+	// ExtMembers holds extension members, whose names are
+	// namespaced by their extension, eg "atomic:operations".
+	ExtMembers map[string]json.RawMessage
+	// Unknown holds other members that are not defined by the
+	// JSON:API specification, which are kept when unmarshaling
+	// so that they can be re-emitted verbatim, eg by proxies.
+	Unknown map[string]json.RawMessage
+
+	// the order of the meta members when unmarshaled,
+	// which is kept when marshaling
+	metaOrder []string
+}
+
+// PrimaryData is the primary data of a document: either a single
+// resource, which may be nil, or a collection of resources.
+type PrimaryData struct {
+	Resource   *Resource
+	Resources  []*Resource
+	Collection bool
+}
+
+// JsonApiObject describes the server's implementation, and
+// the URIs of the extensions and profiles applied to a document.
+type JsonApiObject struct {
+	Version string                     `json:"version,omitempty"`
+	Ext     []string                   `json:"ext,omitempty"`
+	Profile []string                   `json:"profile,omitempty"`
+	Meta    map[string]json.RawMessage `json:"meta,omitempty"`
+}
+
+// ErrorObject is a JSON:API error object. It implements
+// error, and so can be returned from handlers directly.
+type ErrorObject struct {
+	Id     string                     `json:"id,omitempty"`
+	Links  map[string]*Link           `json:"links,omitempty"`
+	Status string                     `json:"status,omitempty"`
+	Code   string                     `json:"code,omitempty"`
+	Title  string                     `json:"title,omitempty"`
+	Detail string                     `json:"detail,omitempty"`
+	Source *ErrorSource               `json:"source,omitempty"`
+	Meta   map[string]json.RawMessage `json:"meta,omitempty"`
+}
+
+// ErrorSource identifies the part of a request that caused an error.
+type ErrorSource struct {
+	Pointer   string `json:"pointer,omitempty"`
+	Parameter string `json:"parameter,omitempty"`
+	Header    string `json:"header,omitempty"`
+}
+
+func (e *ErrorObject) Error() string {
+	msg := e.Title
+	if e.Detail != "" {
+		if msg != "" {
+			msg += ": "
+		}
+		msg += e.Detail
+	}
+	if msg == "" {
+		msg = "error"
+	}
+	if e.Status != "" {
+		msg = e.Status + " " + msg
+	}
+	return "jsonapi: " + msg
+}
+
+// StatusCode returns the error's HTTP status code,
+// or zero if its status is not a valid integer.
+func (e *ErrorObject) StatusCode() int {
+	i, err := strconv.Atoi(e.Status)
+	if err != nil {
+		return 0
+	}
+	return i
+}
+
+func (d *Document) MarshalJSON() ([]byte, error) {
+	type alias struct {
+		Data     *PrimaryData     `json:"data,omitempty"`
+		Errors   []*ErrorObject   `json:"errors,omitempty"`
+		Meta     any              `json:"meta,omitempty"`
+		Links    map[string]*Link `json:"links,omitempty"`
+		JsonApi  *JsonApiObject   `json:"jsonapi,omitempty"`
+		Included []*Resource      `json:"included,omitempty"`
+	}
+	a := alias{
+		Data:     d.Data,
+		Errors:   d.Errors,
+		Links:    d.Links,
+		JsonApi:  d.JsonApi,
+		Included: d.Included,
+	}
+
+	// unmarshaled meta is kept in its original order
+	var err error
+	switch {
+	case len(d.Meta) == 0:
+	case d.metaOrder != nil:
+		if a.Meta, err = marshalOrdered(d.metaOrder, d.Meta); err != nil {
+			return nil, err
+		}
+	default:
+		a.Meta = d.Meta
+	}
+
+	data, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+
+	if data, err = appendMembers(data, d.ExtMembers); err != nil {
+		return nil, err
+	}
+
+	return appendMembers(data, d.Unknown)
+}
+
+func (d *Document) UnmarshalJSON(data []byte) error {
+	return d.unmarshal(data, false)
+}
+
+// unmarshal decodes the document data into d. If alias is true,
+// data must be valid, and the values of the id, attributes, meta
+// and unknown members of its resources, and its own unknown and
+// extension members, are slices of data rather than copies.
+func (d *Document) unmarshal(data []byte, alias bool) error {
+	members, err := rawMembers(data, alias)
+	if err != nil {
+		return err
+	}
+
+	*d = Document{}
+	for name, value := range members {
+		var err error
+		switch name {
+		case "data":
+			d.Data = &PrimaryData{}
+			err = d.Data.unmarshal(value, alias)
+		case "errors":
+			err = json.Unmarshal(value, &d.Errors)
+		case "meta":
+			err = json.Unmarshal(value, &d.Meta)
+			d.metaOrder = objectKeys(value)
+		case "links":
+			err = json.Unmarshal(value, &d.Links)
+		case "jsonapi":
+			err = json.Unmarshal(value, &d.JsonApi)
+		case "included":
+			d.Included, err = unmarshalResources(value, alias)
+		default:
+			if isExtMember(name) {
+				if d.ExtMembers == nil {
+					d.ExtMembers = map[string]json.RawMessage{}
+				}
+				d.ExtMembers[name] = value
+			} else {
+				if d.Unknown == nil {
+					d.Unknown = map[string]json.RawMessage{}
+				}
+				d.Unknown[name] = value
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	internDocument(d)
+	return nil
+}
+
+func (p *PrimaryData) MarshalJSON() ([]byte, error) {
+	if p.Collection {
+		if p.Resources == nil {
+			return []byte("[]"), nil
+		}
+		return json.Marshal(p.Resources)
+	}
+	if p.Resource == nil {
+		return NullJson, nil
+	}
+	return json.Marshal(p.Resource)
+}
+
+func (p *PrimaryData) UnmarshalJSON(data []byte) error {
+	return p.unmarshal(data, false)
+}
+
+// unmarshal decodes the primary data data into p, aliasing
+// data if alias is true, as for Document.unmarshal.
+func (p *PrimaryData) unmarshal(data []byte, alias bool) error {
+	*p = PrimaryData{}
+	switch data[0] {
+	case '[':
+		p.Collection = true
+		rs, err := unmarshalResources(data, alias)
+		if rs == nil {
+			rs = []*Resource{}
+		}
+		p.Resources = rs
+		return err
+	case '{':
+		p.Resource = &Resource{}
+		return p.Resource.unmarshal(data, alias)
+	case 'n':
+		return nil
+	default:
+		return fmt.Errorf("cannot unmarshal into primary data")
+	}
+}
+
+// FormatDocument converts a to a Document. Structs, and types implementing
+// ResourceMarshaler, are converted to single-resource documents, and slices
+// and arrays of these are converted to collection documents. A nil pointer
+// is converted to a document with null primary data.
+func FormatDocument(a any, opts ...Option) (*Document, error) {
+	d, err := formatDocument(a, newOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+	keepDocument(d)
+	return d, nil
+}
+
+func formatDocument(a any, o *options) (*Document, error) {
+	data, err := formatPrimaryData(a, o)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &Document{Data: data}
+	addDescribedBy(d, o)
+	addAppliedMediaParams(d, o)
+
+	for _, inc := range o.included {
+		if err := o.ctxErr(0); err != nil {
+			return nil, err
+		}
+		rs, err := formatIncluded(inc, o)
+		if err != nil {
+			return nil, err
+		}
+		d.Included = append(d.Included, rs...)
+	}
+	d.Included = pruneExcluded(d, o.excluded)
+
+	if o.omitIncludedLinks {
+		for _, r := range d.Included {
+			omitLinks(r)
+		}
+	}
+
+	if o.dependencyOrder {
+		d.SortIncluded()
+	}
+
+	if err := o.ctxErr(0); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// formatPrimaryData converts a to primary data, as described
+// by FormatDocument.
+func formatPrimaryData(a any, o *options) (*PrimaryData, error) {
+	if isNil(reflect.ValueOf(a)) {
+		return &PrimaryData{}, nil
+	}
+
+	v, err := derefInput(reflect.ValueOf(a), resourceMarshalerType)
+	if err != nil {
+		return nil, fmt.Errorf("jsonapi: dereferencing input: %w", err)
+	}
+
+	if isCollection(v) {
+		rs := make([]*Resource, v.Len())
+		for i := range rs {
+			if err := o.ctxErr(i); err != nil {
+				return nil, err
+			}
+			if rs[i], err = formatValue(v.Index(i), o); err != nil {
+				return nil, err
+			}
+			if err := o.progressCheckpoint(i + 1); err != nil {
+				return nil, err
+			}
+		}
+		return &PrimaryData{Resources: rs, Collection: true}, nil
+	}
+
+	r, err := formatValue(v, o)
+	if err != nil {
+		return nil, err
+	}
+	return &PrimaryData{Resource: r}, nil
+}
+
+// resources returns the resources in d's
+// primary data followed by its included resources.
+func (d *Document) resources() []*Resource {
+	var rs []*Resource
+	if d.Data != nil {
+		if d.Data.Resource != nil {
+			rs = append(rs, d.Data.Resource)
+		}
+		rs = append(rs, d.Data.Resources...)
+	}
+	return append(rs, d.Included...)
+}
+
+// formatIncluded converts a, which may be a single resource
+// or a collection, to a list of included resources.
+func formatIncluded(a any, o *options) ([]*Resource, error) {
+	data, err := formatPrimaryData(a, o)
+	if err != nil {
+		return nil, err
+	}
+	if data.Collection {
+		return data.Resources, nil
+	}
+	if data.Resource == nil {
+		return nil, nil
+	}
+	return []*Resource{data.Resource}, nil
+}
+
+// MarshalDocument returns the JSON:API document encoding of a,
+// as described by FormatDocument.
+func MarshalDocument(a any, opts ...Option) ([]byte, error) {
+	o := newOptions(opts)
+	ic := o.startCall("MarshalDocument", Marshaling, a)
+	data, err := marshalDocument(a, o, ic)
+	ic.end(len(data), err)
+	return data, err
+}
+
+func marshalDocument(a any, o *options, ic *instrumentedCall) ([]byte, error) {
+	d, err := formatDocument(a, o)
+	if err != nil {
+		return nil, err
+	}
+	ic.countDocument(d)
+
+	if err := encodeHooks(d, o); err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(d)
+	if err != nil {
+		return nil, fmt.Errorf("jsonapi: marshaling document: %w", err)
+	}
+
+	reportStats(d, len(data), o)
+	err = reportProgress(d, len(data), o)
+	releaseDocument(d)
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// DeformatDocument stores the primary data of d in the value pointed to by
+// a, which must be a pointer to a struct (or ResourceUnmarshaler) for single
+// resource documents, or a pointer to a slice of these for collections.
+// Null primary data leaves a unchanged.
+func DeformatDocument(d *Document, a any, opts ...Option) error {
+	return deformatDocument(d, a, newOptions(opts))
+}
+
+func deformatDocument(d *Document, a any, o *options) error {
+	v := reflect.ValueOf(a)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return ErrNotStructPtr
+	}
+
+	if d.Data == nil || (!d.Data.Collection && d.Data.Resource == nil) {
+		return nil
+	}
+
+	o.indexIncluded(d.Included)
+
+	initValue(v)
+	v, err := derefInput(v, resourceUnmarshalerType)
+	if err != nil {
+		return fmt.Errorf("jsonapi: dereferencing input: %w", err)
+	}
+
+	if v.Kind() == reflect.Slice && !v.Type().Implements(resourceUnmarshalerType) {
+		rs := d.Data.Resources
+		if !d.Data.Collection {
+			if !o.singleAsCollection {
+				return fmt.Errorf("jsonapi: %w", ErrNotCollection)
+			}
+			rs = []*Resource{d.Data.Resource}
+		}
+		rs, err := dedupeResources(rs, o.duplicates)
+		if err != nil {
+			return fmt.Errorf("jsonapi: %w", err)
+		}
+		s := reflect.MakeSlice(v.Type(), len(rs), len(rs))
+		var errs []error
+		for i, r := range rs {
+			elem := s.Index(i)
+			initValue(elem)
+			if err := deformatValue(r, elem, o); err != nil {
+				if !d.Data.Collection {
+					err = prefixPointers(err, "/data")
+				} else {
+					err = prefixPointers(err, dataPointer(d.Data.Resources, r))
+				}
+				if !o.allErrors {
+					return err
+				}
+				if joined, ok := err.(interface{ Unwrap() []error }); ok {
+					errs = append(errs, joined.Unwrap()...)
+				} else {
+					errs = append(errs, err)
+				}
+			}
+			if err := o.progressCheckpoint(i + 1); err != nil {
+				return err
+			}
+		}
+		v.Set(s)
+		return errors.Join(errs...)
+	}
+
+	if d.Data.Collection {
+		return fmt.Errorf("jsonapi: %w", ErrUnexpectedArray)
+	}
+
+	if err := deformatValue(d.Data.Resource, v, o); err != nil {
+		return prefixPointers(err, "/data")
+	}
+	return nil
+}
+
+// UnmarshalDocument parses the JSON:API document data and stores its
+// primary data in the value pointed to by a, as described by DeformatDocument.
+func UnmarshalDocument(data []byte, a any, opts ...Option) error {
+	o := newOptions(opts)
+	ic := o.startCall("UnmarshalDocument", Unmarshaling, a)
+	err := unmarshalDocument(data, a, o, ic)
+	ic.end(len(data), err)
+	return err
+}
+
+func unmarshalDocument(data []byte, a any, o *options, ic *instrumentedCall) error {
+	d := Document{}
+	if err := json.Unmarshal(data, &d); err != nil {
+		return fmt.Errorf("jsonapi: unmarshaling document: %w", err)
+	}
+	ic.countDocument(&d)
+
+	if err := decodeHooks(&d, o); err != nil {
+		return err
+	}
+
+	if err := deformatDocument(&d, a, o); err != nil {
+		return err
+	}
+
+	reportStats(&d, len(data), o)
+	return reportProgress(&d, len(data), o)
+}
+
+// DeformatIncluded stores the included resources of d whose type is
+// that declared by the id tag of a's element type in the slice pointed
+// to by a, whose elements must be structs or pointers to structs.
+// Included resources of other types are ignored.
+func DeformatIncluded(d *Document, a any, opts ...Option) error {
+	o := newOptions(opts)
+
+	v := reflect.ValueOf(a)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("jsonapi: %w", ErrNotCollection)
+	}
+
+	st := derefType(v.Elem().Type().Elem())
+	if st.Kind() != reflect.Struct {
+		return fmt.Errorf("jsonapi: %w", ErrNotStruct)
+	}
+
+	sv := reflect.New(st).Elem()
+	fields, err := parseTags(sv)
+	if err != nil {
+		return err
+	}
+	typ := resourceType(sv, fields, o)
+
+	s := reflect.MakeSlice(v.Elem().Type(), 0, len(d.Included))
+	for _, r := range d.Included {
+		if r.Type != typ {
+			continue
+		}
+		elem := reflect.New(s.Type().Elem()).Elem()
+		initValue(elem)
+		if err := deformatValue(r, elem, o); err != nil {
+			return err
+		}
+		s = reflect.Append(s, elem)
+	}
+	v.Elem().Set(s)
+
+	return nil
+}
+
+// formatValue converts v, which must be a struct, implement
+// ResourceMarshaler or be a map with a schema, to a Resource.
+func formatValue(v reflect.Value, o *options) (*Resource, error) {
+	if isNil(v) {
+		return nil, fmt.Errorf("jsonapi: %w", ErrNotStruct)
+	}
+
+	v, err := derefInput(v, resourceMarshalerType)
+	if err != nil {
+		return nil, fmt.Errorf("jsonapi: dereferencing input: %w", err)
+	}
+
+	if v.Type().Implements(resourceMarshalerType) {
+		data, err := v.Interface().(ResourceMarshaler).MarshalJsonApiResource()
+		if err != nil {
+			return nil, err
+		}
+		r := &Resource{}
+		if err := json.Unmarshal(data, r); err != nil {
+			return nil, fmt.Errorf("jsonapi: unmarshaling resource: %w", err)
+		}
+		return r, nil
+	}
+
+	if v.Kind() == reflect.Map && o.mapSchema != "" {
+		return formatMap(v, o)
+	}
+
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("jsonapi: %w", ErrNotStruct)
+	}
+
+	members, err := parseUnion(v.Type())
+	if err != nil {
+		return nil, fmt.Errorf("jsonapi: parsing tags: %w", err)
+	}
+	if members != nil {
+		return formatUnion(v, members, o)
+	}
+
+	return formatStruct(v, o)
+}
+
+// deformatValue stores r in v, which must be an addressable
+// struct or implement ResourceUnmarshaler.
+func deformatValue(r *Resource, v reflect.Value, o *options) error {
+	if v.Kind() != reflect.Pointer && v.CanAddr() {
+		v = v.Addr()
+	}
+
+	v, err := derefInput(v, resourceUnmarshalerType)
+	if err != nil {
+		return fmt.Errorf("jsonapi: dereferencing input: %w", err)
+	}
+
+	if v.Type().Implements(resourceUnmarshalerType) {
+		data, err := json.Marshal(r)
+		if err != nil {
+			return fmt.Errorf("jsonapi: marshaling resource: %w", err)
+		}
+		return v.Interface().(ResourceUnmarshaler).UnmarshalJsonApiResource(data)
+	}
+
+	if v.Kind() != reflect.Struct {
+		return ErrNotStructPtr
+	}
+
+	members, err := parseUnion(v.Type())
+	if err != nil {
+		return fmt.Errorf("jsonapi: parsing tags: %w", err)
+	}
+	if members != nil {
+		return deformatUnion(r, v, members, o)
+	}
+
+	return deformatStruct(r, v, o)
+}
+
+// isCollection returns true iff v is a slice or array that
+// represents a collection of resources, rather than a resource
+// itself.
+func isCollection(v reflect.Value) bool {
+	if v.Type().Implements(resourceMarshalerType) {
+		return false
+	}
+	return v.Kind() == reflect.Slice || v.Kind() == reflect.Array
+}
+
+// isNil returns true iff v is invalid, or following its
+// pointers and interfaces leads to a nil value.
+func isNil(v reflect.Value) bool {
+	v, err := derefValue(v)
+	return err == nil && !v.IsValid()
+}
+
+// isExtMember returns true iff name is the name of
+// an extension member, ie "namespace:member".
+func isExtMember(name string) bool {
+	ns, member, ok := strings.Cut(name, ":")
+	return ok && ns != "" && member != ""
+}
+
+// appendMembers adds the members, in name order,
+// to the marshaled json object obj.
+func appendMembers(obj []byte, members map[string]json.RawMessage) ([]byte, error) {
+	if len(members) == 0 {
+		return obj, nil
+	}
+
+	buf := bytes.NewBuffer(obj[:len(obj)-1])
+	sep := len(obj) > 2
+
+	names := make([]string, 0, len(members))
+	for name := range members {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		if sep {
+			buf.WriteByte(',')
+		}
+		sep = true
+
+		key, err := json.Marshal(name)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+
+		value := members[name]
+		if len(value) == 0 {
+			value = NullJson
+		}
+		buf.Write(value)
+	}
+
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,311 @@
+package jsonapi
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type docArticle struct {
+	Id     int    `jsonapi:"id,articles,string"`
+	Title  string `jsonapi:"attr,title"`
+	Author int    `jsonapi:"rel,author,people,string"`
+}
+
+const docArticleJson = `
+{
+	"data": {
+		"type": "articles",
+		"id": "1",
+		"attributes": {"title": "Hello"},
+		"relationships": {"author": {"data": {"type": "people", "id": "2"}}}
+	}
+}`
+
+const docArticlesJson = `
+{
+	"data": [
+		{
+			"type": "articles",
+			"id": "1",
+			"attributes": {"title": "Hello"},
+			"relationships": {"author": {"data": {"type": "people", "id": "2"}}}
+		},
+		{
+			"type": "articles",
+			"id": "3",
+			"attributes": {"title": "World"},
+			"relationships": {"author": {"data": {"type": "people", "id": "4"}}}
+		}
+	]
+}`
+
+var docArticleValue = docArticle{Id: 1, Title: "Hello", Author: 2}
+
+var docArticlesValue = []docArticle{
+	{Id: 1, Title: "Hello", Author: 2},
+	{Id: 3, Title: "World", Author: 4},
+}
+
+func TestMarshalDocument(t *testing.T) {
+	type testCase struct {
+		Name     string
+		In       any
+		Expected string
+	}
+
+	var nilArticle *docArticle
+
+	testCases := []testCase{
+		{"struct", docArticleValue, docArticleJson},
+		{"struct ptr", &docArticleValue, docArticleJson},
+		{"slice", docArticlesValue, docArticlesJson},
+		{"slice ptr", &docArticlesValue, docArticlesJson},
+		{"slice of ptrs", []*docArticle{&docArticlesValue[0], &docArticlesValue[1]}, docArticlesJson},
+		{"array", [2]docArticle{docArticlesValue[0], docArticlesValue[1]}, docArticlesJson},
+		{"empty slice", []docArticle{}, `{"data": []}`},
+		{"nil slice", []docArticle(nil), `{"data": []}`},
+		{"nil ptr", nilArticle, `{"data": null}`},
+		{"nil", nil, `{"data": null}`},
+		{"resource marshaler", &aliasMarshalUnmarshalerValue, `{"data": ` + aliasMarshalUnmarshalerJson + `}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			got, err := MarshalDocument(tc.In)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, fmtJson(t, []byte(tc.Expected)), fmtJson(t, got))
+		})
+	}
+}
+
+func TestMarshalDocument_Err(t *testing.T) {
+	for _, in := range []any{0, []int{1}, []*docArticle{nil}} {
+		t.Run("", func(t *testing.T) {
+			data, err := MarshalDocument(in)
+			assert.Empty(t, data)
+			assert.ErrorIs(t, err, ErrNotStruct)
+		})
+	}
+}
+
+func TestMarshalDocument_Context(t *testing.T) {
+	articles := make([]docArticle, 1000)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if _, err := MarshalDocument(articles, WithContext(ctx)); err != nil {
+		t.Fatal(err)
+	}
+
+	cancel()
+	data, err := MarshalDocument(articles, WithContext(ctx))
+	assert.Empty(t, data)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	_, err = MarshalDocument(&docArticleValue, WithContext(ctx), WithIncluded(articles))
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestUnmarshalDocument(t *testing.T) {
+	gotOne := docArticle{}
+	if err := UnmarshalDocument([]byte(docArticleJson), &gotOne); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, docArticleValue, gotOne)
+
+	gotMany := []docArticle{}
+	if err := UnmarshalDocument([]byte(docArticlesJson), &gotMany); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, docArticlesValue, gotMany)
+
+	gotPtrs := []*docArticle{}
+	if err := UnmarshalDocument([]byte(docArticlesJson), &gotPtrs); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []*docArticle{&docArticlesValue[0], &docArticlesValue[1]}, gotPtrs)
+
+	var gotPtr *docArticle
+	if err := UnmarshalDocument([]byte(docArticleJson), &gotPtr); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, &docArticleValue, gotPtr)
+
+	gotAlias := aliasMarshalUnmarshaler{}
+	if err := UnmarshalDocument([]byte(`{"data": `+aliasMarshalUnmarshalerJson+`}`), &gotAlias); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, aliasMarshalUnmarshalerValue, gotAlias)
+}
+
+func TestUnmarshalDocument_Null(t *testing.T) {
+	for _, in := range []string{`{"data": null}`, `{"meta": {"count": 1}}`} {
+		t.Run(in, func(t *testing.T) {
+			got := docArticleValue
+			if err := UnmarshalDocument([]byte(in), &got); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, docArticleValue, got)
+		})
+	}
+}
+
+func TestUnmarshalDocument_Err(t *testing.T) {
+	type testCase struct {
+		Json     string
+		In       any
+		Expected error
+	}
+
+	testCases := []testCase{
+		{docArticlesJson, &docArticle{}, ErrUnexpectedArray},
+		{docArticleJson, &[]docArticle{}, ErrNotCollection},
+		{docArticleJson, docArticle{}, ErrNotStructPtr},
+		{docArticleJson, addrOf(0), ErrNotStructPtr},
+		{`{"data": [null]}`, &[]docArticle{}, ErrNullResource},
+		{`{"data": [], "included": [null]}`, &[]docArticle{}, ErrNullResource},
+	}
+
+	for _, tc := range testCases {
+		t.Run("", func(t *testing.T) {
+			err := UnmarshalDocument([]byte(tc.Json), tc.In)
+			assert.ErrorIs(t, err, tc.Expected)
+		})
+	}
+}
+
+func TestDocument_UnmarshalJSON_NullResource(t *testing.T) {
+	for _, in := range []string{`{"data": [{"type": "articles", "id": "1"}, null]}`, `{"data": null, "included": [null]}`} {
+		t.Run(in, func(t *testing.T) {
+			err := json.Unmarshal([]byte(in), &Document{})
+			assert.ErrorIs(t, err, ErrNullResource)
+
+			_, err = DecodeDocumentAliased([]byte(in))
+			assert.ErrorIs(t, err, ErrNullResource)
+		})
+	}
+}
+
+func TestDocument_MarshalUnmarshal(t *testing.T) {
+	in := `
+	{
+		"data": {"type": "articles", "id": "1"},
+		"included": [{"type": "people", "id": "2", "attributes": {"name": "Bob"}}],
+		"meta": {"total": 1},
+		"links": {"self": "/articles/1"},
+		"jsonapi": {"version": "1.1"}
+	}`
+
+	d := Document{}
+	if err := json.Unmarshal([]byte(in), &d); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "1.1", d.JsonApi.Version)
+	assert.Equal(t, "people", d.Included[0].Type)
+	assert.Equal(t, "articles", d.Data.Resource.Type)
+
+	got, err := json.Marshal(&d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, fmtJson(t, []byte(in)), fmtJson(t, got))
+}
+
+func TestDocument_Errors(t *testing.T) {
+	in := `{"errors": [{"status": "404", "title": "Not Found", "source": {"parameter": "id"}}]}`
+
+	d := Document{}
+	if err := json.Unmarshal([]byte(in), &d); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Nil(t, d.Data)
+	assert.Equal(t, 404, d.Errors[0].StatusCode())
+	assert.Equal(t, "jsonapi: 404 Not Found", d.Errors[0].Error())
+
+	got, err := json.Marshal(&d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, fmtJson(t, []byte(in)), fmtJson(t, got))
+}
+
+func TestDeformatIncluded(t *testing.T) {
+	type person struct {
+		Id   int    `jsonapi:"id,people,string"`
+		Name string `jsonapi:"attr,name"`
+	}
+
+	in := `
+	{
+		"data": {"type": "articles", "id": "1"},
+		"included": [
+			{"type": "people", "id": "2", "attributes": {"name": "Bob"}},
+			{"type": "comments", "id": "5"},
+			{"type": "people", "id": "3", "attributes": {"name": "Alice"}}
+		]
+	}`
+
+	d := Document{}
+	if err := json.Unmarshal([]byte(in), &d); err != nil {
+		t.Fatal(err)
+	}
+
+	got := []person{}
+	if err := DeformatIncluded(&d, &got); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []person{{2, "Bob"}, {3, "Alice"}}, got)
+
+	gotPtrs := []*person{}
+	if err := DeformatIncluded(&d, &gotPtrs); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []*person{{2, "Bob"}, {3, "Alice"}}, gotPtrs)
+
+	assert.ErrorIs(t, DeformatIncluded(&d, &person{}), ErrNotCollection)
+	assert.ErrorIs(t, DeformatIncluded(&d, &[]int{}), ErrNotStruct)
+}
+
+func TestDocument_Unknown(t *testing.T) {
+	in := `
+	{
+		"data": {
+			"type": "articles",
+			"id": "1",
+			"attributes": {"title": "Hello"},
+			"version": 2,
+			"audit:by": "bob"
+		},
+		"included": [{"type": "people", "id": "2", "cache": {"ttl": 60}}],
+		"version": 2,
+		"debug": {"trace": "abc"}
+	}`
+
+	d := Document{}
+	if err := json.Unmarshal([]byte(in), &d); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, map[string]json.RawMessage{"version": json.RawMessage(`2`), "debug": json.RawMessage(`{"trace": "abc"}`)}, d.Unknown)
+	assert.Equal(t, map[string]json.RawMessage{"version": json.RawMessage(`2`), "audit:by": json.RawMessage(`"bob"`)}, d.Data.Resource.Unknown)
+	assert.Equal(t, map[string]json.RawMessage{"cache": json.RawMessage(`{"ttl": 60}`)}, d.Included[0].Unknown)
+
+	got, err := json.Marshal(&d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, fmtJson(t, []byte(in)), fmtJson(t, got))
+
+	// unknown members are ignored when unmarshaling into structs
+	article := docArticle{}
+	if err := UnmarshalDocument([]byte(in), &article); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, docArticle{Id: 1, Title: "Hello"}, article)
+}
@@ -0,0 +1,108 @@
+package jsonapi
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+)
+
+var ErrDuplicateResource = fmt.Errorf("duplicate resource")
+
+// DuplicatePolicy defines how resources that appear more than once,
+// with the same type and id, in a collection's primary data are
+// handled when unmarshaling.
+type DuplicatePolicy int
+
+const (
+	// DuplicateAllow unmarshals every resource, including duplicates.
+	DuplicateAllow DuplicatePolicy = iota
+	// DuplicateError fails with ErrDuplicateResource.
+	DuplicateError
+	// DuplicateKeepFirst unmarshals the first of the duplicates.
+	DuplicateKeepFirst
+	// DuplicateKeepLast unmarshals the last of the duplicates,
+	// in the position of the first.
+	DuplicateKeepLast
+	// DuplicateMerge unmarshals a single resource, in the position
+	// of the first, whose members are those of all the duplicates,
+	// with later duplicates' members overriding earlier ones'.
+	DuplicateMerge
+)
+
+// WithDuplicates sets how duplicate resources in the primary
+// data of collection documents are handled when unmarshaling.
+func WithDuplicates(p DuplicatePolicy) Option {
+	return func(o *options) {
+		o.duplicates = p
+	}
+}
+
+// dedupeResources returns rs with duplicate resources handled according
+// to p. Resources without an id or local id are never duplicates. rs,
+// and the resources it contains, are not modified.
+func dedupeResources(rs []*Resource, p DuplicatePolicy) ([]*Resource, error) {
+	if p == DuplicateAllow {
+		return rs, nil
+	}
+
+	deduped := make([]*Resource, 0, len(rs))
+	idxs := map[string]int{}
+	merged := map[int]bool{}
+	for _, r := range rs {
+		if r == nil || (len(r.Id) == 0 || bytes.Equal(r.Id, NullJson)) && r.Lid == "" {
+			deduped = append(deduped, r)
+			continue
+		}
+
+		key := identifierKey(r.ResourceIdentifier)
+		i, ok := idxs[key]
+		if !ok {
+			idxs[key] = len(deduped)
+			deduped = append(deduped, r)
+			continue
+		}
+
+		switch p {
+		case DuplicateError:
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateResource, identifierNode(r.ResourceIdentifier))
+		case DuplicateKeepLast:
+			deduped[i] = r
+		case DuplicateMerge:
+			// merge into a copy, so the original is unchanged
+			if !merged[i] {
+				deduped[i] = deduped[i].Clone()
+				merged[i] = true
+			}
+			mergeResource(deduped[i], r)
+		}
+	}
+	return deduped, nil
+}
+
+// mergeResource copies the members of src into dst,
+// replacing any that dst already has.
+func mergeResource(dst, src *Resource) {
+	dst.Meta = mergeMap(dst.Meta, src.Meta)
+	dst.Attributes = mergeMap(dst.Attributes, src.Attributes)
+	dst.Links = mergeMap(dst.Links, src.Links)
+	dst.Unknown = mergeMap(dst.Unknown, src.Unknown)
+	for name, rel := range src.ToOneRelationships {
+		delete(dst.ToManyRelationships, name)
+		dst.ToOneRelationships = mergeMap(dst.ToOneRelationships, map[string]*ToOneResourceLinkage{name: rel})
+	}
+	for name, rel := range src.ToManyRelationships {
+		delete(dst.ToOneRelationships, name)
+		dst.ToManyRelationships = mergeMap(dst.ToManyRelationships, map[string]*ToManyResourceLinkage{name: rel})
+	}
+}
+
+func mergeMap[V any](dst, src map[string]V) map[string]V {
+	if len(src) == 0 {
+		return dst
+	}
+	if dst == nil {
+		dst = make(map[string]V, len(src))
+	}
+	maps.Copy(dst, src)
+	return dst
+}
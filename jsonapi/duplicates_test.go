@@ -0,0 +1,78 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnmarshalDocument_Duplicates(t *testing.T) {
+	type article struct {
+		Id     int    `jsonapi:"id,articles,string"`
+		Title  string `jsonapi:"attr,title"`
+		Body   string `jsonapi:"attr,body"`
+		Author int    `jsonapi:"rel,author,people,string"`
+	}
+
+	in := `
+	{
+		"data": [
+			{"type": "articles", "id": "1", "attributes": {"title": "First", "body": "Hello"}},
+			{"type": "articles", "attributes": {"title": "New"}},
+			{"type": "articles", "id": "2", "attributes": {"title": "Other"}},
+			{"type": "articles", "id": "1", "attributes": {"title": "Last"}, "relationships": {"author": {"data": {"type": "people", "id": "9"}}}},
+			{"type": "articles", "attributes": {"title": "New"}}
+		]
+	}`
+
+	type testCase struct {
+		Name     string
+		Policy   DuplicatePolicy
+		Expected []article
+	}
+
+	testCases := []testCase{
+		{"allow", DuplicateAllow, []article{{1, "First", "Hello", 0}, {0, "New", "", 0}, {2, "Other", "", 0}, {1, "Last", "", 9}, {0, "New", "", 0}}},
+		{"keep first", DuplicateKeepFirst, []article{{1, "First", "Hello", 0}, {0, "New", "", 0}, {2, "Other", "", 0}, {0, "New", "", 0}}},
+		{"keep last", DuplicateKeepLast, []article{{1, "Last", "", 9}, {0, "New", "", 0}, {2, "Other", "", 0}, {0, "New", "", 0}}},
+		{"merge", DuplicateMerge, []article{{1, "Last", "Hello", 9}, {0, "New", "", 0}, {2, "Other", "", 0}, {0, "New", "", 0}}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			got := []article{}
+			if err := UnmarshalDocument([]byte(in), &got, WithDuplicates(tc.Policy)); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tc.Expected, got)
+		})
+	}
+
+	err := UnmarshalDocument([]byte(in), &[]article{}, WithDuplicates(DuplicateError))
+	assert.ErrorIs(t, err, ErrDuplicateResource)
+	assert.EqualError(t, err, "jsonapi: duplicate resource: articles/1")
+}
+
+func TestDeformatDocument_DuplicatesUnchanged(t *testing.T) {
+	type a struct {
+		Id string `jsonapi:"id,a"`
+		X  int    `jsonapi:"attr,x"`
+		Y  int    `jsonapi:"attr,y"`
+	}
+
+	in := `{"data": [{"type": "a", "id": "1", "attributes": {"x": 1}}, {"type": "a", "id": "1", "attributes": {"y": 2}}]}`
+
+	d := Document{}
+	if err := json.Unmarshal([]byte(in), &d); err != nil {
+		t.Fatal(err)
+	}
+	orig := d.Clone()
+
+	got := []a{}
+	if err := DeformatDocument(&d, &got, WithDuplicates(DuplicateMerge)); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []a{{"1", 1, 2}}, got)
+	assert.Equal(t, orig, &d)
+}
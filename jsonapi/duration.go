@@ -0,0 +1,101 @@
+package jsonapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+var durationType = reflect.TypeFor[time.Duration]()
+
+// durationFormats are the values of the format option of duration
+// fields. See TagValueFormat.
+var durationFormats = []string{"string", "seconds", "milliseconds", "nanoseconds"}
+
+// durationUnit returns the duration of one unit of the numeric format.
+func durationUnit(format string) time.Duration {
+	switch format {
+	case "seconds":
+		return time.Second
+	case "milliseconds":
+		return time.Millisecond
+	}
+	return time.Nanosecond
+}
+
+// marshalDuration marshals the time.Duration value v, if valid, in
+// format, quoting numeric formats if quote is true.
+func marshalDuration(v reflect.Value, format string, quote bool) (json.RawMessage, error) {
+	if !v.IsValid() {
+		return NullJson, nil
+	}
+	d := time.Duration(v.Int())
+
+	if format == "string" {
+		return json.Marshal(d.String())
+	}
+
+	var j []byte
+	if unit := durationUnit(format); unit == time.Nanosecond {
+		j = strconv.AppendInt(nil, int64(d), 10)
+	} else {
+		j = strconv.AppendFloat(nil, float64(d)/float64(unit), 'f', -1, 64)
+	}
+	if quote {
+		j = strconv.AppendQuote(nil, string(j))
+	}
+	return j, nil
+}
+
+// unmarshalDuration unmarshals data, in format, into the time.Duration
+// value v, or the value it points to, expecting numeric formats to be
+// quoted if quote is true. Null is ignored.
+func unmarshalDuration(data json.RawMessage, v reflect.Value, format string, quote bool) error {
+	if len(data) == 0 || bytes.Equal(data, NullJson) {
+		return nil
+	}
+	for v.Kind() == reflect.Pointer {
+		initValue(v)
+		v = v.Elem()
+	}
+
+	var d time.Duration
+	if format == "string" {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		var err error
+		if d, err = time.ParseDuration(s); err != nil {
+			return err
+		}
+	} else {
+		if quote {
+			if len(data) < 2 || data[0] != '"' {
+				return fmt.Errorf("cannot unmarshal %s into quoted %s duration", data, format)
+			}
+			data = data[1 : len(data)-1]
+		}
+		unit := durationUnit(format)
+		if unit == time.Nanosecond {
+			n, err := strconv.ParseInt(string(data), 10, 64)
+			if err != nil {
+				return fmt.Errorf("cannot unmarshal %s into %s duration", data, format)
+			}
+			d = time.Duration(n)
+		} else {
+			n, err := strconv.ParseFloat(string(data), 64)
+			if err != nil || math.IsNaN(n) || math.Abs(n*float64(unit)) >= math.MaxInt64 {
+				return fmt.Errorf("cannot unmarshal %s into %s duration", data, format)
+			}
+			d = time.Duration(math.Round(n * float64(unit)))
+		}
+	}
+
+	v.SetInt(int64(d))
+	return nil
+}
@@ -0,0 +1,103 @@
+package jsonapi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type durationJob struct {
+	Id      string         `jsonapi:"id,jobs"`
+	Timeout time.Duration  `jsonapi:"attr,timeout"`
+	Seconds time.Duration  `jsonapi:"attr,seconds,format=seconds"`
+	Millis  *time.Duration `jsonapi:"attr,millis,format=milliseconds,string"`
+	Nanos   time.Duration  `jsonapi:"attr,nanos,format=nanoseconds"`
+	Missing *time.Duration `jsonapi:"attr,missing"`
+	Elapsed time.Duration  `jsonapi:"meta,elapsed"`
+}
+
+func TestDuration(t *testing.T) {
+	d := 90*time.Minute + 1500*time.Millisecond
+	j := durationJob{
+		Id:      "1",
+		Timeout: d,
+		Seconds: d,
+		Millis:  &d,
+		Nanos:   d,
+		Elapsed: 250 * time.Millisecond,
+	}
+
+	data, err := MarshalResource(j)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{
+		"type": "jobs",
+		"id": "1",
+		"attributes": {
+			"timeout": "1h30m1.5s",
+			"seconds": 5401.5,
+			"millis": "5401500",
+			"nanos": 5401500000000,
+			"missing": null
+		},
+		"meta": {"elapsed": "250ms"}
+	}`
+	assert.Equal(t, fmtJson(t, []byte(expected)), fmtJson(t, data))
+
+	got := durationJob{}
+	if err := UnmarshalResource(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, j, got)
+}
+
+func TestDuration_Unmarshal(t *testing.T) {
+	type testCase struct {
+		name     string
+		json     string
+		expected durationJob
+		err      string
+	}
+
+	testCases := []testCase{
+		{name: "string", json: `{"timeout": "1m"}`, expected: durationJob{Timeout: time.Minute}},
+		{name: "seconds", json: `{"seconds": 2}`, expected: durationJob{Seconds: 2 * time.Second}},
+		{name: "fractional seconds", json: `{"seconds": 0.25}`, expected: durationJob{Seconds: 250 * time.Millisecond}},
+		{name: "null", json: `{"timeout": null}`, expected: durationJob{}},
+		{name: "invalid string", json: `{"timeout": "soon"}`, err: `time: invalid duration "soon"`},
+		{name: "nanoseconds as string", json: `{"timeout": 60000000000}`, err: "cannot unmarshal number"},
+		{name: "string as seconds", json: `{"seconds": "2"}`, err: `cannot unmarshal "2" into seconds duration`},
+		{name: "fractional nanoseconds", json: `{"nanos": 1.5}`, err: "cannot unmarshal 1.5 into nanoseconds duration"},
+		{name: "overflow", json: `{"seconds": 1e300}`, err: "cannot unmarshal 1e300 into seconds duration"},
+		{name: "unquoted", json: `{"millis": 1}`, err: "cannot unmarshal 1 into quoted milliseconds duration"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data := `{"type": "jobs", "id": "1", "attributes": ` + tc.json + `}`
+			got := durationJob{}
+			err := UnmarshalResource([]byte(data), &got)
+			if tc.err != "" {
+				var ue *UnmarshalErr
+				assert.ErrorAs(t, err, &ue)
+				assert.ErrorContains(t, err, tc.err)
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			tc.expected.Id = "1"
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func TestDuration_TagErr(t *testing.T) {
+	_, err := MarshalResource(struct {
+		Id      string        `jsonapi:"id,jobs"`
+		Timeout time.Duration `jsonapi:"attr,timeout,format=hours"`
+	}{})
+	assert.ErrorContains(t, err, `tag error on field 'Timeout': unknown duration format "hours"`)
+}
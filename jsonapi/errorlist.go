@@ -0,0 +1,97 @@
+package jsonapi
+
+import (
+	"errors"
+	"slices"
+	"strings"
+)
+
+// ErrorList is a list of error objects, eg those of an error document.
+// It implements error, and unwraps to its error objects, so that
+// errors.As and errors.Is can be used to inspect them.
+type ErrorList []*ErrorObject
+
+func (l ErrorList) Error() string {
+	if len(l) == 0 {
+		return "jsonapi: no errors"
+	}
+	msgs := make([]string, len(l))
+	for i, obj := range l {
+		msgs[i] = obj.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (l ErrorList) Unwrap() []error {
+	errs := make([]error, len(l))
+	for i, obj := range l {
+		errs[i] = obj
+	}
+	return errs
+}
+
+// ErrorMapper converts an error to an error object,
+// or returns nil if it does not apply to err.
+type ErrorMapper func(err error) *ErrorObject
+
+// RegisterErrorMapper registers m, which is used by NewErrorList,
+// after those previously registered, to convert errors that are
+// not error objects.
+func (r *Registry) RegisterErrorMapper(m ErrorMapper) {
+	r.update(func(s *RegistrySnapshot) {
+		s.errorMappers = append(slices.Clone(s.errorMappers), m)
+	})
+}
+
+// RegisterErrorMapper registers m with DefaultRegistry.
+func RegisterErrorMapper(m ErrorMapper) {
+	DefaultRegistry.RegisterErrorMapper(m)
+}
+
+// MapError returns the first *ErrorObject in err's tree, or else the
+// error object returned by the first registered ErrorMapper that
+// applies to err, or nil if there is none.
+func (s *RegistrySnapshot) MapError(err error) *ErrorObject {
+	var obj *ErrorObject
+	if errors.As(err, &obj) {
+		return obj
+	}
+	for _, m := range s.errorMappers {
+		if obj := m(err); obj != nil {
+			return obj
+		}
+	}
+	return nil
+}
+
+// ErrorList converts err, and each of its joined errors, to error
+// objects with MapError. The objects of an ErrorList in err's tree
+// are returned as they are. Errors that cannot be converted are reported
+// as generic 500 Internal Server Error objects, so as not to leak
+// their details. A nil err returns a nil list.
+func (s *RegistrySnapshot) ErrorList(err error) ErrorList {
+	if err == nil {
+		return nil
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		var l ErrorList
+		for _, e := range joined.Unwrap() {
+			l = append(l, s.ErrorList(e)...)
+		}
+		return l
+	}
+	var list ErrorList
+	if errors.As(err, &list) {
+		return slices.Clone(list)
+	}
+	if obj := s.MapError(err); obj != nil {
+		return ErrorList{obj}
+	}
+	return ErrorList{{Status: "500", Title: "Internal Server Error"}}
+}
+
+// NewErrorList converts err to error objects with the
+// error mappers registered with DefaultRegistry.
+func NewErrorList(err error) ErrorList {
+	return DefaultRegistry.Snapshot().ErrorList(err)
+}
@@ -0,0 +1,54 @@
+package jsonapi
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorList(t *testing.T) {
+	notFound := &ErrorObject{Status: "404", Title: "Not Found", Detail: "no such article"}
+	conflict := &ErrorObject{Status: "409", Title: "Conflict"}
+	var err error = fmt.Errorf("saving: %w", ErrorList{notFound, conflict})
+
+	assert.EqualError(t, err, "saving: jsonapi: 404 Not Found: no such article; jsonapi: 409 Conflict")
+	assert.ErrorIs(t, err, conflict)
+
+	var obj *ErrorObject
+	if assert.ErrorAs(t, err, &obj) {
+		assert.Same(t, notFound, obj)
+	}
+	var list ErrorList
+	if assert.ErrorAs(t, err, &list) {
+		assert.Equal(t, ErrorList{notFound, conflict}, list)
+	}
+
+	assert.EqualError(t, ErrorList{}, "jsonapi: no errors")
+}
+
+func TestNewErrorList(t *testing.T) {
+	notFound := &ErrorObject{Status: "404", Title: "Not Found"}
+	r := NewRegistry()
+	r.RegisterErrorMapper(func(err error) *ErrorObject {
+		if errors.Is(err, fs.ErrNotExist) {
+			return notFound
+		}
+		return nil
+	})
+	conflict := &ErrorObject{Status: "409", Title: "Conflict"}
+	s := r.Snapshot()
+
+	assert.Nil(t, s.ErrorList(nil))
+	assert.Equal(t, ErrorList{notFound}, s.ErrorList(fmt.Errorf("opening: %w", fs.ErrNotExist)))
+	assert.Equal(t, ErrorList{conflict}, s.ErrorList(fmt.Errorf("saving: %w", conflict)))
+	assert.Equal(t, ErrorList{notFound, conflict}, s.ErrorList(fmt.Errorf("saving: %w", ErrorList{notFound, conflict})))
+	assert.Equal(t, ErrorList{notFound, conflict, {Status: "500", Title: "Internal Server Error"}},
+		s.ErrorList(errors.Join(fs.ErrNotExist, conflict, errors.New("database is down"))))
+
+	// the default registry has no mappers
+	assert.Equal(t, ErrorList{{Status: "500", Title: "Internal Server Error"}}, NewErrorList(fs.ErrNotExist))
+	assert.Nil(t, s.MapError(errors.New("database is down")))
+}
@@ -0,0 +1,156 @@
+package jsonapi
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const (
+	// media type parameters
+	MediaTypeParamExt     = "ext"
+	MediaTypeParamProfile = "profile"
+)
+
+var ErrUnsupportedExt = fmt.Errorf("unsupported extension")
+
+// Extension is a JSON:API extension implemented outside of this package,
+// eg the Atomic Operations extension. Extensions are registered with a
+// Registry, and may implement any of the hook interfaces DocumentEncoder
+// and DocumentDecoder to take part in marshaling and unmarshaling.
+type Extension interface {
+	// URI uniquely identifies the extension, and is the value
+	// used in the "ext" media type parameter.
+	URI() string
+}
+
+// DocumentEncoder is implemented by extensions that modify documents,
+// eg by adding extension members, before they are marshaled by
+// MarshalDocument. The document's resources are reused once it is
+// marshaled, so EncodeDocument must not retain them.
+type DocumentEncoder interface {
+	EncodeDocument(d *Document) error
+}
+
+// DocumentDecoder is implemented by extensions that validate or modify
+// documents, eg by interpreting extension members, after they are
+// unmarshaled by UnmarshalDocument, and before the primary data is
+// stored in the target value.
+type DocumentDecoder interface {
+	DecodeDocument(d *Document) error
+}
+
+// RegisterExtension registers ext, replacing any extension
+// previously registered with the same URI.
+func (r *Registry) RegisterExtension(ext Extension) {
+	r.update(func(s *RegistrySnapshot) {
+		s.extensions = slices.DeleteFunc(slices.Clone(s.extensions), func(e Extension) bool {
+			return e.URI() == ext.URI()
+		})
+		s.extensions = append(s.extensions, ext)
+	})
+}
+
+// RegisterExtension registers ext with DefaultRegistry.
+func RegisterExtension(ext Extension) {
+	DefaultRegistry.RegisterExtension(ext)
+}
+
+// Extensions returns the registered extensions,
+// in order of registration.
+func (s *RegistrySnapshot) Extensions() []Extension {
+	return slices.Clone(s.extensions)
+}
+
+// Extension returns the extension registered with the supplied URI.
+func (s *RegistrySnapshot) Extension(uri string) (Extension, bool) {
+	for _, ext := range s.extensions {
+		if ext.URI() == uri {
+			return ext, true
+		}
+	}
+	return nil, false
+}
+
+// MediaType returns the JSON:API media type, with "ext" and
+// "profile" parameters listing the URIs of the registered
+// extensions and profiles, as formatted by FormatMediaType.
+func (s *RegistrySnapshot) MediaType() string {
+	ext := make([]string, len(s.extensions))
+	for i, e := range s.extensions {
+		ext[i] = e.URI()
+	}
+	profiles := make([]string, len(s.profiles))
+	for i, p := range s.profiles {
+		profiles[i] = p.URI()
+	}
+	return FormatMediaType(ext, profiles)
+}
+
+// SupportsExt returns nil if every URI in the space-separated value
+// of an "ext" media type parameter is a registered extension, or
+// ErrUnsupportedExt otherwise.
+func (s *RegistrySnapshot) SupportsExt(ext string) error {
+	for _, uri := range strings.Fields(ext) {
+		if _, ok := s.Extension(uri); !ok {
+			return fmt.Errorf("%w: %s", ErrUnsupportedExt, uri)
+		}
+	}
+	return nil
+}
+
+// EncodeDocument runs the DocumentEncoder hooks of the registered
+// extensions, and then those of the profiles, on d, as MarshalDocument
+// does. It's for documents that are marshaled separately, eg after
+// FormatDocument and inspecting their primary data.
+func EncodeDocument(d *Document, opts ...Option) error {
+	return encodeHooks(d, newOptions(opts))
+}
+
+// encodeHooks runs the DocumentEncoder hooks of the
+// registered extensions, and then those of the profiles.
+func encodeHooks(d *Document, o *options) error {
+	for _, ext := range o.snapshot.extensions {
+		if enc, ok := ext.(DocumentEncoder); ok {
+			if err := enc.EncodeDocument(d); err != nil {
+				return fmt.Errorf("jsonapi: extension %s: %w", ext.URI(), err)
+			}
+		}
+	}
+	for _, p := range o.snapshot.profiles {
+		if enc, ok := p.(DocumentEncoder); ok {
+			if err := enc.EncodeDocument(d); err != nil {
+				return fmt.Errorf("jsonapi: profile %s: %w", p.URI(), err)
+			}
+		}
+	}
+	return nil
+}
+
+// DecodeDocument runs the DocumentDecoder hooks of the registered
+// extensions, and then those of the profiles, on d, as UnmarshalDocument
+// does. It's for documents that are unmarshaled separately, eg to be
+// inspected before DeformatDocument stores their primary data.
+func DecodeDocument(d *Document, opts ...Option) error {
+	return decodeHooks(d, newOptions(opts))
+}
+
+// decodeHooks runs the DocumentDecoder hooks of the
+// registered extensions, and then those of the profiles.
+func decodeHooks(d *Document, o *options) error {
+	for _, ext := range o.snapshot.extensions {
+		if dec, ok := ext.(DocumentDecoder); ok {
+			if err := dec.DecodeDocument(d); err != nil {
+				return fmt.Errorf("jsonapi: extension %s: %w", ext.URI(), err)
+			}
+		}
+	}
+	for _, p := range o.snapshot.profiles {
+		if dec, ok := p.(DocumentDecoder); ok {
+			if err := dec.DecodeDocument(d); err != nil {
+				return fmt.Errorf("jsonapi: profile %s: %w", p.URI(), err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type versionExt struct {
+	decoded string
+}
+
+func (*versionExt) URI() string {
+	return "https://example.com/ext/version"
+}
+
+func (*versionExt) EncodeDocument(d *Document) error {
+	d.ExtMembers = map[string]json.RawMessage{"version:id": json.RawMessage(`"42"`)}
+	return nil
+}
+
+func (e *versionExt) DecodeDocument(d *Document) error {
+	raw, ok := d.ExtMembers["version:id"]
+	if !ok {
+		return fmt.Errorf("missing version:id")
+	}
+	return json.Unmarshal(raw, &e.decoded)
+}
+
+type otherExt struct{}
+
+func (otherExt) URI() string {
+	return "https://example.com/ext/other"
+}
+
+func TestRegistry_RegisterExtension(t *testing.T) {
+	reg := NewRegistry()
+	assert.Equal(t, MediaType, reg.Snapshot().MediaType())
+
+	ext := &versionExt{}
+	reg.RegisterExtension(ext)
+	reg.RegisterExtension(otherExt{})
+	reg.RegisterExtension(ext)
+
+	s := reg.Snapshot()
+	assert.Equal(t, []Extension{otherExt{}, ext}, s.Extensions())
+	assert.Equal(t, `application/vnd.api+json; ext="https://example.com/ext/other https://example.com/ext/version"`, s.MediaType())
+
+	got, ok := s.Extension("https://example.com/ext/version")
+	assert.True(t, ok)
+	assert.Equal(t, ext, got)
+
+	assert.NoError(t, s.SupportsExt("https://example.com/ext/version https://example.com/ext/other"))
+	assert.ErrorIs(t, s.SupportsExt("https://example.com/ext/version https://example.com/ext/unknown"), ErrUnsupportedExt)
+}
+
+func TestExtension_Hooks(t *testing.T) {
+	ext := &versionExt{}
+	reg := NewRegistry()
+	reg.RegisterExtension(ext)
+
+	got, err := MarshalDocument(docArticleValue, WithRegistry(reg))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"version:id": "42", ` + docArticleJson[3:]
+	assert.Equal(t, fmtJson(t, []byte(want)), fmtJson(t, got))
+
+	out := docArticle{}
+	if err := UnmarshalDocument(got, &out, WithRegistry(reg)); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, docArticleValue, out)
+	assert.Equal(t, "42", ext.decoded)
+
+	err = UnmarshalDocument([]byte(docArticleJson), &out, WithRegistry(reg))
+	assert.ErrorContains(t, err, "https://example.com/ext/version: missing version:id")
+}
+
+func TestEncodeDocument(t *testing.T) {
+	reg := NewRegistry()
+	reg.RegisterExtension(&versionExt{})
+
+	d := Document{}
+	if err := EncodeDocument(&d, WithRegistry(reg)); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, map[string]json.RawMessage{"version:id": json.RawMessage(`"42"`)}, d.ExtMembers)
+}
+
+func TestDecodeDocument(t *testing.T) {
+	ext := &versionExt{}
+	reg := NewRegistry()
+	reg.RegisterExtension(ext)
+
+	d := Document{ExtMembers: map[string]json.RawMessage{"version:id": json.RawMessage(`"42"`)}}
+	if err := DecodeDocument(&d, WithRegistry(reg)); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "42", ext.decoded)
+
+	err := DecodeDocument(&Document{}, WithRegistry(reg))
+	assert.ErrorContains(t, err, "https://example.com/ext/version: missing version:id")
+}
+
+func TestDocument_ExtMembers(t *testing.T) {
+	in := `{"data": null, "atomic:operations": [{"op": "remove"}], "unknown": 1}`
+
+	d := Document{}
+	if err := json.Unmarshal([]byte(in), &d); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, map[string]json.RawMessage{"atomic:operations": json.RawMessage(`[{"op": "remove"}]`)}, d.ExtMembers)
+	assert.Equal(t, map[string]json.RawMessage{"unknown": json.RawMessage(`1`)}, d.Unknown)
+
+	got, err := json.Marshal(&d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, fmtJson(t, []byte(in)), fmtJson(t, got))
+}
@@ -0,0 +1,56 @@
+package jsonapi
+
+import (
+	"reflect"
+	"slices"
+	"sync"
+)
+
+// fieldCache holds the parsed fields of struct types,
+// as a map[reflect.Type][]field.
+var fieldCache sync.Map
+
+// parseTags retrieves all attributes, relationships, etc from the
+// input value, as described by walkTags. The fields of types whose
+// fields don't depend on their values, ie that don't embed pointers
+// or interfaces, are cached, so their tags are only parsed once. The
+// returned slice is shared, and must not be modified.
+func parseTags(v reflect.Value) ([]field, error) {
+	t := v.Type()
+	if fields, ok := fieldCache.Load(t); ok {
+		return fields.([]field), nil
+	}
+
+	fields, err := walkTags(v)
+	if err != nil {
+		return nil, err
+	}
+
+	if !embedsIndirect(t) {
+		// clip, so that appending to the fields copies them
+		fields = slices.Clip(fields)
+		fieldCache.Store(t, fields)
+	}
+	return fields, nil
+}
+
+// embedsIndirect returns whether the struct type t, or any struct
+// it embeds, has an untagged embedded pointer or interface field,
+// whose tags walkTags finds through its value.
+func embedsIndirect(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if _, _, ok := splitTypeAndOpts(f); ok || !f.Anonymous {
+			continue
+		}
+		switch f.Type.Kind() {
+		case reflect.Pointer, reflect.Interface:
+			return true
+		case reflect.Struct:
+			if embedsIndirect(f.Type) {
+				return true
+			}
+		}
+	}
+	return false
+}
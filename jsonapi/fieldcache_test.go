@@ -0,0 +1,91 @@
+package jsonapi
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type cacheBase struct {
+	Id string `jsonapi:"id,articles"`
+}
+
+type cacheArticle struct {
+	cacheBase
+	Title string `jsonapi:"attr,title"`
+}
+
+type cachePtrArticle struct {
+	*cacheBase
+	Title string `jsonapi:"attr,title"`
+}
+
+func TestParseTags_Cached(t *testing.T) {
+	v := reflect.ValueOf(cacheArticle{})
+	first, err := parseTags(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := parseTags(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Len(t, second, 2)
+	assert.Same(t, &first[0], &second[0])
+	assert.Equal(t, len(second), cap(second))
+
+	allocs := testing.AllocsPerRun(10, func() {
+		_, _ = parseTags(v)
+	})
+	assert.Zero(t, allocs)
+}
+
+func TestParseTags_NotCachedWithEmbeddedPointer(t *testing.T) {
+	assert.True(t, embedsIndirect(reflect.TypeFor[cachePtrArticle]()))
+	assert.False(t, embedsIndirect(reflect.TypeFor[cacheArticle]()))
+
+	for _, a := range []cachePtrArticle{{}, {cacheBase: &cacheBase{Id: "1"}}} {
+		fields, err := parseTags(reflect.ValueOf(a))
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Len(t, fields, 2)
+	}
+	_, ok := fieldCache.Load(reflect.TypeFor[cachePtrArticle]())
+	assert.False(t, ok)
+}
+
+func TestParseTags_ErrorsNotCached(t *testing.T) {
+	type invalid struct {
+		Id  string `jsonapi:"id,articles"`
+		Id2 string `jsonapi:"id,articles"`
+	}
+
+	for range 2 {
+		_, err := parseTags(reflect.ValueOf(invalid{}))
+		assert.ErrorAs(t, err, addrOf(&TagErr{}))
+	}
+}
+
+func TestParseTags_Concurrent(t *testing.T) {
+	type concurrentArticle struct {
+		cacheBase
+		Body string `jsonapi:"attr,body"`
+	}
+
+	wg := sync.WaitGroup{}
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, err := MarshalResource(concurrentArticle{cacheBase{"1"}, "Hello"})
+			if assert.NoError(t, err) {
+				assert.JSONEq(t, `{"type":"articles","id":"1","attributes":{"body":"Hello"}}`, string(got))
+			}
+		}()
+	}
+	wg.Wait()
+}
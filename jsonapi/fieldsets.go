@@ -0,0 +1,39 @@
+package jsonapi
+
+import "slices"
+
+// WithFields restricts the attributes and relationships marshaled for
+// each resource type to its sparse fieldset, as requested by a query's
+// fields parameters, eg WithFields(q.Fields). Resources of types without
+// a fieldset are unaffected, and an empty fieldset omits every attribute
+// and relationship.
+func WithFields(fields map[string][]string) Option {
+	return func(o *options) {
+		o.fieldsets = fields
+	}
+}
+
+// applyFieldset removes the attributes and relationships of r
+// that aren't in the sparse fieldset of its type, if it has one.
+func (o *options) applyFieldset(r *Resource) {
+	fieldset, ok := o.fieldsets[r.Type]
+	if !ok {
+		return
+	}
+
+	excluded := func(name string) bool {
+		return !slices.Contains(fieldset, name)
+	}
+	deleteKeys(r.Attributes, excluded)
+	deleteKeys(r.ToOneRelationships, excluded)
+	deleteKeys(r.ToManyRelationships, excluded)
+}
+
+// deleteKeys deletes the entries of m whose keys satisfy del.
+func deleteKeys[V any](m map[string]V, del func(string) bool) {
+	for k := range m {
+		if del(k) {
+			delete(m, k)
+		}
+	}
+}
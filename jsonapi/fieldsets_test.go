@@ -0,0 +1,57 @@
+package jsonapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fieldsetPerson struct {
+	Id    string `jsonapi:"id,people"`
+	Name  string `jsonapi:"attr,name"`
+	Email string `jsonapi:"attr,email"`
+}
+
+type fieldsetArticle struct {
+	Id       string   `jsonapi:"id,articles"`
+	Title    string   `jsonapi:"attr,title"`
+	Body     string   `jsonapi:"attr,body"`
+	Author   string   `jsonapi:"rel,author,people"`
+	Tags     []string `jsonapi:"rel,tags,tags"`
+	Views    int      `jsonapi:"meta,views"`
+	TagsSelf string   `jsonapi:"link,self,rel=tags"`
+}
+
+func TestMarshalDocument_WithFields(t *testing.T) {
+	in := fieldsetArticle{"1", "Hello", "World", "2", []string{"a"}, 3, "/articles/1/relationships/tags"}
+	person := fieldsetPerson{"2", "Bob", "bob@example.com"}
+
+	got, err := MarshalDocument(&in, WithIncluded(person), WithFields(map[string][]string{
+		"articles": {"title", "author"},
+		"people":   {},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// meta isn't affected, and nor are types without fieldsets
+	want := `
+	{
+		"data": {
+			"type": "articles",
+			"id": "1",
+			"attributes": {"title": "Hello"},
+			"relationships": {"author": {"data": {"type": "people", "id": "2"}}},
+			"meta": {"views": 3}
+		},
+		"included": [{"type": "people", "id": "2"}]
+	}`
+	assert.Equal(t, fmtJson(t, []byte(want)), fmtJson(t, got))
+
+	got, err = MarshalResource(&person, WithFields(map[string][]string{"articles": {}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = `{"type": "people", "id": "2", "attributes": {"name": "Bob", "email": "bob@example.com"}}`
+	assert.Equal(t, fmtJson(t, []byte(want)), fmtJson(t, got))
+}
@@ -0,0 +1,166 @@
+package jsonapi
+
+import (
+	"cmp"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+const (
+	// query parameter families
+	QueryParamFilter = "filter"
+	// filter operators
+	FilterOpEq    = "eq"
+	FilterOpNe    = "ne"
+	FilterOpLt    = "lt"
+	FilterOpLe    = "le"
+	FilterOpGt    = "gt"
+	FilterOpGe    = "ge"
+	FilterOpIn    = "in"
+	FilterOpNin   = "nin"
+	FilterOpLike  = "like"
+	FilterOpIsNil = "null"
+)
+
+var ErrUnknownOperator = fmt.Errorf("unknown filter operator")
+
+// FilterExpr is a node in a parsed filter expression tree,
+// either a *FilterAnd or a *FilterCondition.
+type FilterExpr interface {
+	filterExpr()
+}
+
+// FilterAnd matches when all of its operands match.
+type FilterAnd struct {
+	Exprs []FilterExpr
+}
+
+// FilterCondition compares the value of a field with the supplied
+// values using the named operator. Field is a dot-separated path,
+// eg "author.name".
+type FilterCondition struct {
+	Field    string
+	Operator string
+	Values   []string
+}
+
+func (*FilterAnd) filterExpr()       {}
+func (*FilterCondition) filterExpr() {}
+
+// FilterOperator describes an operator accepted by ParseFilter.
+type FilterOperator struct {
+	// Multi splits comma-separated values into multiple values.
+	Multi bool
+	// Validate optionally checks the operator's values.
+	Validate func(values []string) error
+}
+
+// FilterOperators is a set of operators keyed by name,
+// as they appear in filter[field][operator] parameters.
+type FilterOperators map[string]FilterOperator
+
+// DefaultFilterOperators is the operator set used when none is supplied.
+var DefaultFilterOperators = FilterOperators{
+	FilterOpEq:    {},
+	FilterOpNe:    {},
+	FilterOpLt:    {},
+	FilterOpLe:    {},
+	FilterOpGt:    {},
+	FilterOpGe:    {},
+	FilterOpIn:    {Multi: true},
+	FilterOpNin:   {Multi: true},
+	FilterOpLike:  {},
+	FilterOpIsNil: {Validate: validateBool},
+}
+
+// ParseFilter converts the filter[...] parameters in q into an expression
+// tree. Parameters of the form filter[field][operator]=value compare the field
+// with the supplied operator, and filter[field]=value is shorthand for the
+// "eq" operator. All conditions are combined into a single *FilterAnd, sorted
+// by field then operator. If there are no filter parameters, nil is returned.
+// If ops is nil, DefaultFilterOperators is used.
+func ParseFilter(q url.Values, ops FilterOperators) (FilterExpr, error) {
+	if ops == nil {
+		ops = DefaultFilterOperators
+	}
+
+	var conds []FilterExpr
+	for key, values := range q {
+		member, ok := familyMember(key, QueryParamFilter)
+		if !ok {
+			continue
+		}
+
+		fieldName, opName, hasOp := strings.Cut(member, "][")
+		if !hasOp {
+			opName = FilterOpEq
+		}
+		if fieldName == "" || strings.ContainsAny(opName, "[]") {
+			return nil, &QueryErr{key, ErrInvalidValue}
+		}
+
+		op, ok := ops[opName]
+		if !ok {
+			return nil, &QueryErr{key, ErrUnknownOperator}
+		}
+
+		for _, value := range values {
+			vals := []string{value}
+			if op.Multi {
+				vals = strings.Split(value, ",")
+			}
+			if op.Validate != nil {
+				if err := op.Validate(vals); err != nil {
+					return nil, &QueryErr{key, err}
+				}
+			}
+			conds = append(conds, &FilterCondition{
+				Field:    fieldName,
+				Operator: opName,
+				Values:   vals,
+			})
+		}
+	}
+
+	if len(conds) == 0 {
+		return nil, nil
+	}
+
+	slices.SortStableFunc(conds, func(a, b FilterExpr) int {
+		ca, cb := a.(*FilterCondition), b.(*FilterCondition)
+		if c := cmp.Compare(ca.Field, cb.Field); c != 0 {
+			return c
+		}
+		return cmp.Compare(ca.Operator, cb.Operator)
+	})
+
+	return &FilterAnd{Exprs: conds}, nil
+}
+
+// addFilterValues adds the query parameters representing
+// the filter expression e to values.
+func addFilterValues(values url.Values, e FilterExpr) {
+	switch e := e.(type) {
+	case *FilterAnd:
+		for _, expr := range e.Exprs {
+			addFilterValues(values, expr)
+		}
+	case *FilterCondition:
+		key := QueryParamFilter + "[" + e.Field + "]"
+		if e.Operator != FilterOpEq {
+			key += "[" + e.Operator + "]"
+		}
+		values.Add(key, strings.Join(e.Values, ","))
+	}
+}
+
+func validateBool(values []string) error {
+	for _, v := range values {
+		if v != "true" && v != "false" {
+			return ErrInvalidValue
+		}
+	}
+	return nil
+}
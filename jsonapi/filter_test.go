@@ -0,0 +1,119 @@
+package jsonapi
+
+import (
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFilter(t *testing.T) {
+	type testCase struct {
+		Query string
+		Exp   FilterExpr
+	}
+
+	testCases := []testCase{
+		{"", nil},
+		{"sort=title", nil},
+		{
+			"filter[title]=hello",
+			&FilterAnd{Exprs: []FilterExpr{
+				&FilterCondition{Field: "title", Operator: FilterOpEq, Values: []string{"hello"}},
+			}},
+		},
+		{
+			"filter[title]=a,b&filter[tags][in]=a,b&filter[author.age][ge]=18&filter[author.age][lt]=65",
+			&FilterAnd{Exprs: []FilterExpr{
+				&FilterCondition{Field: "author.age", Operator: FilterOpGe, Values: []string{"18"}},
+				&FilterCondition{Field: "author.age", Operator: FilterOpLt, Values: []string{"65"}},
+				&FilterCondition{Field: "tags", Operator: FilterOpIn, Values: []string{"a", "b"}},
+				&FilterCondition{Field: "title", Operator: FilterOpEq, Values: []string{"a,b"}},
+			}},
+		},
+		{
+			"filter[deleted][null]=true&filter[deleted][null]=false",
+			&FilterAnd{Exprs: []FilterExpr{
+				&FilterCondition{Field: "deleted", Operator: FilterOpIsNil, Values: []string{"true"}},
+				&FilterCondition{Field: "deleted", Operator: FilterOpIsNil, Values: []string{"false"}},
+			}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Query, func(t *testing.T) {
+			q, err := url.ParseQuery(tc.Query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := ParseFilter(q, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tc.Exp, got)
+		})
+	}
+}
+
+func TestParseFilter_CustomOperators(t *testing.T) {
+	errNotNumeric := fmt.Errorf("not numeric")
+	ops := FilterOperators{
+		"between": {
+			Multi: true,
+			Validate: func(values []string) error {
+				if len(values) != 2 {
+					return errNotNumeric
+				}
+				return nil
+			},
+		},
+	}
+
+	q := url.Values{"filter[age][between]": {"18,65"}}
+	got, err := ParseFilter(q, ops)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := &FilterAnd{Exprs: []FilterExpr{
+		&FilterCondition{Field: "age", Operator: "between", Values: []string{"18", "65"}},
+	}}
+	assert.Equal(t, exp, got)
+
+	// validation failure
+	_, err = ParseFilter(url.Values{"filter[age][between]": {"18"}}, ops)
+	assert.ErrorIs(t, err, errNotNumeric)
+
+	// eq is not in the operator set
+	_, err = ParseFilter(url.Values{"filter[age]": {"18"}}, ops)
+	assert.ErrorIs(t, err, ErrUnknownOperator)
+}
+
+func TestParseFilter_Err(t *testing.T) {
+	type testCase struct {
+		Query  string
+		ExpErr error
+	}
+
+	testCases := []testCase{
+		{"filter[title][foo]=x", ErrUnknownOperator},
+		{"filter[]=x", ErrInvalidValue},
+		{"filter[a][b][c]=x", ErrInvalidValue},
+		{"filter[a][null]=x", ErrInvalidValue},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Query, func(t *testing.T) {
+			q, err := url.ParseQuery(tc.Query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = ParseFilter(q, nil)
+			assert.ErrorAs(t, err, addrOf(&QueryErr{}))
+			assert.ErrorIs(t, err, tc.ExpErr)
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package jsonapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// The functions in this file are called by the MarshalJsonApiResource
+// and UnmarshalJsonApiResource methods generated by the jsonapi
+// command's gen subcommand, so that generated code encodes members
+// exactly as tagged fields are encoded. Generated code encodes fields
+// of predeclared types itself, and calls the reflection-based helpers
+// only for fields of other types. They are not intended to be called
+// directly.
+
+// MarshalMember returns the encoding of a, the value of a field
+// mapped to an id, attribute, meta member or related resource id.
+// If quote is true, numbers are encoded as strings, as by the string
+// tag option.
+func MarshalMember(a any, quote bool) (json.RawMessage, error) {
+	v, err := derefValue(reflect.ValueOf(a))
+	if err != nil {
+		return nil, err
+	}
+	return marshalJson(v, quote)
+}
+
+// UnmarshalMember stores the member data in the value pointed to by a,
+// allocating pointers as necessary. Null is stored in pointers, maps,
+// slices and interfaces as nil. If quote is true, numbers are decoded
+// from strings, as by the string tag option.
+func UnmarshalMember(data json.RawMessage, a any, quote bool) error {
+	v := reflect.ValueOf(a)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return fmt.Errorf("cannot unmarshal member into non-pointer %T", a)
+	}
+	v = v.Elem()
+
+	if bytes.Equal(data, NullJson) && isNullable(v.Type()) {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+
+	initValue(v)
+	return unmarshalJson(data, v, quote)
+}
+
+// UnquoteMember returns the member data of a number field of kind
+// kind, eg int64, with the string tag option, without its quotes.
+func UnquoteMember(data json.RawMessage, kind string) (json.RawMessage, error) {
+	if len(data) < 2 || data[0] != '"' {
+		return nil, fmt.Errorf("cannot unmarshal %s into quoted %s", data, kind)
+	}
+	return data[1 : len(data)-1], nil
+}
+
+// IsEmptyMember returns whether a, the value of a field,
+// is empty, and so omitted by the omitempty tag option.
+func IsEmptyMember(a any) bool {
+	v, err := derefValue(reflect.ValueOf(a))
+	return err == nil && isEmpty(v)
+}
+
+// MarshalIds returns the linkage of a to-many relationship
+// to resources of type typ with the ids in s.
+func MarshalIds[T any](typ string, s []T, quote bool) ([]ResourceIdentifier, error) {
+	ids := make([]ResourceIdentifier, len(s))
+	for i := range s {
+		id, err := MarshalMember(s[i], quote)
+		if err != nil {
+			return nil, err
+		}
+		ids[i] = ResourceIdentifier{Type: typ, Id: id}
+	}
+	return ids, nil
+}
+
+// UnmarshalIds stores the ids of the linkage of a to-many
+// relationship in the slice pointed to by s. Empty linkage
+// leaves s unchanged.
+func UnmarshalIds[T any](ids []ResourceIdentifier, s *[]T, quote bool) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	*s = make([]T, len(ids))
+	for i, id := range ids {
+		if err := UnmarshalMember(id.Id, &(*s)[i], quote); err != nil {
+			return err
+		}
+	}
+	return nil
+}
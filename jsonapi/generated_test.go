@@ -0,0 +1,105 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshalMember(t *testing.T) {
+	type testCase struct {
+		Name     string
+		Value    any
+		Quote    bool
+		Expected string
+	}
+
+	testCases := []testCase{
+		{"Int", 1, false, `1`},
+		{"QuotedInt", 1, true, `"1"`},
+		{"QuotedString", "a", true, `"a"`},
+		{"Pointer", addrOf(2.5), true, `"2.5"`},
+		{"NilPointer", (*int)(nil), true, `null`},
+		{"Slice", []string{"a"}, false, `["a"]`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			m, err := MarshalMember(tc.Value, tc.Quote)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tc.Expected, string(m))
+		})
+	}
+}
+
+func TestUnmarshalMember(t *testing.T) {
+	i := 0
+	if err := UnmarshalMember(json.RawMessage(`"7"`), &i, true); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 7, i)
+
+	p := addrOf(1)
+	if err := UnmarshalMember(json.RawMessage(`null`), &p, false); err != nil {
+		t.Fatal(err)
+	}
+	assert.Nil(t, p)
+
+	if err := UnmarshalMember(json.RawMessage(`3`), &p, false); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, addrOf(3), p)
+
+	err := UnmarshalMember(json.RawMessage(`3`), i, false)
+	assert.EqualError(t, err, "cannot unmarshal member into non-pointer int")
+
+	err = UnmarshalMember(json.RawMessage(`3`), &i, true)
+	assert.EqualError(t, err, "cannot unmarshal 3 into quoted int")
+}
+
+func TestUnquoteMember(t *testing.T) {
+	m, err := UnquoteMember(json.RawMessage(`"7"`), "int")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, `7`, string(m))
+
+	_, err = UnquoteMember(json.RawMessage(`7`), "int")
+	assert.EqualError(t, err, "cannot unmarshal 7 into quoted int")
+
+	_, err = UnquoteMember(json.RawMessage(`"`), "float64")
+	assert.EqualError(t, err, `cannot unmarshal " into quoted float64`)
+}
+
+func TestIsEmptyMember(t *testing.T) {
+	assert.True(t, IsEmptyMember(0))
+	assert.True(t, IsEmptyMember((*string)(nil)))
+	assert.True(t, IsEmptyMember([]int{}))
+	assert.True(t, IsEmptyMember(addrOf("")))
+	assert.False(t, IsEmptyMember("a"))
+}
+
+func TestMarshalIds(t *testing.T) {
+	ids, err := MarshalIds("tags", []int{1, 2}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []ResourceIdentifier{
+		{Type: "tags", Id: json.RawMessage(`"1"`)},
+		{Type: "tags", Id: json.RawMessage(`"2"`)},
+	}, ids)
+
+	s := []int{9}
+	if err := UnmarshalIds(ids, &s, true); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []int{1, 2}, s)
+
+	if err := UnmarshalIds(nil, &s, true); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []int{1, 2}, s)
+}
@@ -0,0 +1,201 @@
+package jsonapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Graph is a directed graph of resources, or resource types,
+// connected by their relationships. It is intended for debugging,
+// eg to visualise include fan-out and cycles.
+type Graph struct {
+	Nodes []GraphNode
+	Edges []GraphEdge
+}
+
+// GraphNode is a resource, identified by its type and id,
+// or a resource type, in which case Id is empty.
+type GraphNode struct {
+	Type string
+	Id   string
+}
+
+func (n GraphNode) String() string {
+	if n.Id == "" {
+		return n.Type
+	}
+	return n.Type + "/" + n.Id
+}
+
+// GraphEdge is a relationship called Name from one node to another.
+type GraphEdge struct {
+	From GraphNode
+	To   GraphNode
+	Name string
+}
+
+// DocumentGraph returns the relationship graph of the resources in d's
+// primary data and included resources. Related resources that are not
+// in the document are also included as nodes.
+func DocumentGraph(d *Document) *Graph {
+	g := &Graph{}
+	seen := map[GraphNode]bool{}
+
+	rs := d.resources()
+
+	for _, r := range rs {
+		g.addNode(identifierNode(r.ResourceIdentifier), seen)
+	}
+
+	for _, r := range rs {
+		from := identifierNode(r.ResourceIdentifier)
+		for _, name := range relationshipNames(r) {
+			var ids []ResourceIdentifier
+			if rel, ok := r.ToOneRelationships[name]; ok {
+				ids = append(ids, rel.Data)
+			}
+			if rel, ok := r.ToManyRelationships[name]; ok {
+				ids = append(ids, rel.Data...)
+			}
+			for _, id := range ids {
+				if isNullIdentifier(id) {
+					continue
+				}
+				to := identifierNode(id)
+				g.addNode(to, seen)
+				g.Edges = append(g.Edges, GraphEdge{From: from, To: to, Name: name})
+			}
+		}
+	}
+
+	return g
+}
+
+// TypeGraph returns the relationship graph of the resource types
+// declared by the tags of the supplied structs, or pointers to structs.
+// Each relationship is an edge from the struct's resource type to the
+// type declared by the relationship's tag.
+func TypeGraph(a ...any) (*Graph, error) {
+	g := &Graph{}
+	o := newOptions(nil)
+	seen := map[GraphNode]bool{}
+
+	for _, x := range a {
+		v, err := derefValue(reflect.ValueOf(x))
+		if err != nil {
+			return nil, fmt.Errorf("jsonapi: dereferencing input: %w", err)
+		}
+		if !v.IsValid() || v.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("jsonapi: %w", ErrNotStruct)
+		}
+
+		fields, err := parseTags(v)
+		if err != nil {
+			return nil, fmt.Errorf("jsonapi: parsing tags: %w", err)
+		}
+
+		from := GraphNode{Type: resourceType(v, fields, o)}
+		g.addNode(from, seen)
+
+		var edges []GraphEdge
+		for _, f := range fields {
+			// polymorphic relationships have no declared type
+			if f.tag.typ == TagValueRel && f.tag.rscType != "" {
+				to := GraphNode{Type: f.tag.rscType}
+				edges = append(edges, GraphEdge{From: from, To: to, Name: f.tag.name})
+			}
+		}
+		slices.SortStableFunc(edges, func(a, b GraphEdge) int {
+			return strings.Compare(a.Name, b.Name)
+		})
+		for _, e := range edges {
+			g.addNode(e.To, seen)
+		}
+		g.Edges = append(g.Edges, edges...)
+	}
+
+	return g, nil
+}
+
+// DOT renders g in the Graphviz DOT language.
+func (g *Graph) DOT() string {
+	sb := strings.Builder{}
+	sb.WriteString("digraph jsonapi {\n")
+	for _, n := range g.Nodes {
+		fmt.Fprintf(&sb, "\t%s;\n", strconv.Quote(n.String()))
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&sb, "\t%s -> %s [label=%s];\n",
+			strconv.Quote(e.From.String()), strconv.Quote(e.To.String()), strconv.Quote(e.Name))
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// Mermaid renders g as a Mermaid flowchart.
+func (g *Graph) Mermaid() string {
+	ids := make(map[GraphNode]string, len(g.Nodes))
+	sb := strings.Builder{}
+	sb.WriteString("graph LR\n")
+	for i, n := range g.Nodes {
+		ids[n] = "n" + strconv.Itoa(i)
+		fmt.Fprintf(&sb, "\t%s[\"%s\"]\n", ids[n], mermaidEscape(n.String()))
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&sb, "\t%s -->|\"%s\"| %s\n", ids[e.From], mermaidEscape(e.Name), ids[e.To])
+	}
+	return sb.String()
+}
+
+// addNode adds n to g, if it has not already been seen.
+func (g *Graph) addNode(n GraphNode, seen map[GraphNode]bool) {
+	if !seen[n] {
+		seen[n] = true
+		g.Nodes = append(g.Nodes, n)
+	}
+}
+
+// identifierNode returns the node identified by id, with
+// string ids unquoted and other ids left as raw json. Resources
+// with only a local id are identified by it.
+func identifierNode(id ResourceIdentifier) GraphNode {
+	if len(id.Id) == 0 && id.Lid != "" {
+		return GraphNode{Type: id.Type, Id: id.Lid}
+	}
+
+	var s string
+	if err := json.Unmarshal(id.Id, &s); err != nil {
+		s = string(id.Id)
+	}
+	return GraphNode{Type: id.Type, Id: s}
+}
+
+// isNullIdentifier returns whether id, from resource
+// linkage, identifies no resource, eg null to-one linkage.
+func isNullIdentifier(id ResourceIdentifier) bool {
+	return (len(id.Id) == 0 && id.Lid == "") || bytes.Equal(id.Id, NullJson)
+}
+
+// relationshipNames returns the names of r's relationships, sorted.
+func relationshipNames(r *Resource) []string {
+	names := make([]string, 0, len(r.ToOneRelationships)+len(r.ToManyRelationships))
+	for name := range r.ToOneRelationships {
+		names = append(names, name)
+	}
+	for name := range r.ToManyRelationships {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// mermaidEscape escapes the double quotes in s,
+// which would otherwise terminate a mermaid label.
+func mermaidEscape(s string) string {
+	return strings.ReplaceAll(s, `"`, "#quot;")
+}
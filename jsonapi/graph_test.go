@@ -0,0 +1,100 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const graphDocJson = `
+{
+	"data": {
+		"type": "articles", "id": "1",
+		"relationships": {
+			"author": {"data": {"type": "people", "id": "2"}},
+			"comments": {"data": [{"type": "comments", "id": "3"}]}
+		}
+	},
+	"included": [
+		{"type": "people", "id": "2", "relationships": {"publisher": {"data": {"type": "publishers", "id": 4}}}},
+		{"type": "comments", "id": "3", "relationships": {"article": {"data": {"type": "articles", "id": "1"}}}}
+	]
+}`
+
+func TestDocumentGraph(t *testing.T) {
+	d := Document{}
+	if err := json.Unmarshal([]byte(graphDocJson), &d); err != nil {
+		t.Fatal(err)
+	}
+
+	g := DocumentGraph(&d)
+
+	article := GraphNode{"articles", "1"}
+	person := GraphNode{"people", "2"}
+	comment := GraphNode{"comments", "3"}
+	publisher := GraphNode{"publishers", "4"}
+
+	assert.Equal(t, []GraphNode{article, person, comment, publisher}, g.Nodes)
+	assert.Equal(t, []GraphEdge{
+		{article, person, "author"},
+		{article, comment, "comments"},
+		{person, publisher, "publisher"},
+		{comment, article, "article"},
+	}, g.Edges)
+
+	wantDot := `digraph jsonapi {
+	"articles/1";
+	"people/2";
+	"comments/3";
+	"publishers/4";
+	"articles/1" -> "people/2" [label="author"];
+	"articles/1" -> "comments/3" [label="comments"];
+	"people/2" -> "publishers/4" [label="publisher"];
+	"comments/3" -> "articles/1" [label="article"];
+}
+`
+	assert.Equal(t, wantDot, g.DOT())
+
+	wantMermaid := `graph LR
+	n0["articles/1"]
+	n1["people/2"]
+	n2["comments/3"]
+	n3["publishers/4"]
+	n0 -->|"author"| n1
+	n0 -->|"comments"| n2
+	n1 -->|"publisher"| n3
+	n2 -->|"article"| n0
+`
+	assert.Equal(t, wantMermaid, g.Mermaid())
+}
+
+func TestTypeGraph(t *testing.T) {
+	type article struct {
+		Id       string   `jsonapi:"id,articles"`
+		Comments []string `jsonapi:"rel,comments,comments"`
+		Author   string   `jsonapi:"rel,author,people"`
+	}
+	type person struct {
+		Id        string `jsonapi:"id,people"`
+		Publisher string `jsonapi:"rel,publisher,publishers"`
+	}
+
+	g, err := TypeGraph(article{}, &person{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	articles, people := GraphNode{Type: "articles"}, GraphNode{Type: "people"}
+	comments, publishers := GraphNode{Type: "comments"}, GraphNode{Type: "publishers"}
+
+	assert.Equal(t, []GraphNode{articles, people, comments, publishers}, g.Nodes)
+	assert.Equal(t, []GraphEdge{
+		{articles, people, "author"},
+		{articles, comments, "comments"},
+		{people, publishers, "publisher"},
+	}, g.Edges)
+
+	_, err = TypeGraph(0)
+	assert.ErrorIs(t, err, ErrNotStruct)
+}
@@ -0,0 +1,154 @@
+package jsonapi
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// TagValueBackRef is the relationship tag option, eg `backref=article`,
+// that names the relationship of the related structs that refers back
+// to the struct being unmarshaled.
+const TagValueBackRef = "backref"
+
+// resourceStructs caches the results of isResourceStruct.
+var resourceStructs sync.Map // map[reflect.Type]bool
+
+// isResourceStruct returns true if t, after following pointers, is a
+// struct with an id tag, or that implements IdMarshaler, and so a
+// related resource rather than an id.
+func isResourceStruct(t reflect.Type) bool {
+	t = derefType(t)
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	if ok, found := resourceStructs.Load(t); found {
+		return ok.(bool)
+	}
+
+	ok := reflect.PointerTo(t).Implements(idMarshalerType)
+	if fields, err := parseTags(reflect.New(t).Elem()); err == nil && !ok {
+		_, ok = fieldOfType(fields, TagValueId)
+	}
+	resourceStructs.Store(t, ok)
+	return ok
+}
+
+// declaredType returns the resource type declared by the id tag of the
+// struct type t, or of the structs embedded in it, if any, so that the
+// relationships of related structs needn't repeat it.
+func declaredType(t reflect.Type) string {
+	return declaredTypeSeen(t, map[reflect.Type]bool{})
+}
+
+func declaredTypeSeen(t reflect.Type, seen map[reflect.Type]bool) string {
+	if t.Kind() != reflect.Struct || seen[t] {
+		return ""
+	}
+	seen[t] = true
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		typ, opts, ok := splitTypeAndOpts(f)
+		switch {
+		case ok && typ == TagValueId:
+			rscType, _ := splitFirstAndOpts(opts)
+			return rscType
+		case !ok && f.Anonymous:
+			if rscType := declaredTypeSeen(derefType(f.Type), seen); rscType != "" {
+				return rscType
+			}
+		}
+	}
+	return ""
+}
+
+// structId returns the id field of the tagged struct v, and whether
+// it has the string option, or the id returned by v's JsonApiId
+// method if it implements IdMarshaler.
+func structId(v reflect.Value) (reflect.Value, bool, error) {
+	if im, ok := idMarshaler(v); ok {
+		id, err := im.JsonApiId()
+		return reflect.ValueOf(id), false, err
+	}
+	fields, err := parseTags(v)
+	if err != nil {
+		return reflect.Value{}, false, err
+	}
+	idField, ok := fieldOfType(fields, TagValueId)
+	if !ok {
+		return reflect.Value{}, false, fmt.Errorf("%s has no id", v.Type())
+	}
+	id, err := fieldByIndex(v, idField.idxs)
+	if err != nil {
+		return reflect.Value{}, false, err
+	}
+	if id, err = derefValue(id); err != nil {
+		return reflect.Value{}, false, err
+	}
+	return id, idField.tag.quote, nil
+}
+
+// indexIncluded makes the included resources available
+// for hydrating related structs when unmarshaling.
+func (o *options) indexIncluded(included []*Resource) {
+	if len(included) == 0 {
+		return
+	}
+	o.includedIndex = make(map[string]*Resource, len(included))
+	for _, r := range included {
+		o.includedIndex[identifierKey(r.ResourceIdentifier)] = r
+	}
+	o.hydrating = map[string]bool{}
+}
+
+// hydrate stores the related resource identified by id in the struct
+// sv, using its included resource, if any, and otherwise just its
+// identifier. If the relationship field f has the backref option, the
+// named relationship field of sv is set to the struct parent.
+func hydrate(parent, sv reflect.Value, id ResourceIdentifier, f field, o *options) error {
+	r := &Resource{ResourceIdentifier: id}
+
+	// nb included resources in a cycle are hydrated
+	// from their identifiers once already in progress
+	k := identifierKey(id)
+	if inc, ok := o.includedIndex[k]; ok && !o.hydrating[k] {
+		o.hydrating[k] = true
+		defer delete(o.hydrating, k)
+		r = inc
+	}
+
+	if err := deformatStruct(r, sv, o); err != nil {
+		return err
+	}
+
+	if f.tag.backref != "" {
+		return setBackRef(parent, sv, f.tag.backref, o)
+	}
+	return nil
+}
+
+// setBackRef sets the relationship field called name of the struct
+// child to a pointer to the struct parent.
+func setBackRef(parent, child reflect.Value, name string, o *options) error {
+	fields, err := parseTags(child)
+	if err != nil {
+		return err
+	}
+	fields = o.forType(resourceType(child, fields, o)).nameMembers(fields)
+
+	for _, f := range fields {
+		if f.tag.typ != TagValueRel || f.tag.name != name {
+			continue
+		}
+		fv, err := initFieldByIndex(child, f.idxs)
+		if err != nil {
+			return err
+		}
+		if !parent.CanAddr() || !parent.Addr().Type().AssignableTo(fv.Type()) {
+			return fmt.Errorf("backref %s: cannot assign %s to %s", name, reflect.PointerTo(parent.Type()), fv.Type())
+		}
+		fv.Set(parent.Addr())
+		return nil
+	}
+	return fmt.Errorf("backref %s: %s has no such relationship", name, child.Type())
+}
@@ -0,0 +1,208 @@
+package jsonapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type hydArticle struct {
+	Id       string        `jsonapi:"id,articles"`
+	Title    string        `jsonapi:"attr,title"`
+	Author   *hydPerson    `jsonapi:"rel,author,people"`
+	Comments []*hydComment `jsonapi:"rel,comments,comments,backref=article"`
+}
+
+type hydComment struct {
+	Id      string      `jsonapi:"id,comments"`
+	Body    string      `jsonapi:"attr,body"`
+	Article *hydArticle `jsonapi:"rel,article,articles"`
+	Author  hydPerson   `jsonapi:"rel,author,people"`
+}
+
+type hydPerson struct {
+	Id   string `jsonapi:"id,people"`
+	Name string `jsonapi:"attr,name"`
+}
+
+const hydArticleJson = `
+{
+	"data": {
+		"type": "articles",
+		"id": "1",
+		"attributes": {"title": "Hello"},
+		"relationships": {
+			"author": {"data": {"type": "people", "id": "9"}},
+			"comments": {"data": [{"type": "comments", "id": "5"}, {"type": "comments", "id": "6"}]}
+		}
+	},
+	"included": [
+		{
+			"type": "comments",
+			"id": "5",
+			"attributes": {"body": "First"},
+			"relationships": {
+				"article": {"data": {"type": "articles", "id": "1"}},
+				"author": {"data": {"type": "people", "id": "9"}}
+			}
+		},
+		{"type": "people", "id": "9", "attributes": {"name": "Ann"}}
+	]
+}`
+
+func TestMarshalDocument_RelatedStructs(t *testing.T) {
+	a := &hydArticle{Id: "1", Title: "Hello", Author: &hydPerson{Id: "9", Name: "Ann"}}
+	a.Comments = []*hydComment{
+		{Id: "5", Body: "First", Article: a, Author: *a.Author},
+		{Id: "6"},
+	}
+
+	got, err := MarshalDocument(a, WithIncluded(a.Comments[0], a.Author))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, fmtJson(t, []byte(hydArticleJson)), fmtJson(t, got))
+}
+
+func TestUnmarshalDocument_Hydrate(t *testing.T) {
+	got := hydArticle{}
+	if err := UnmarshalDocument([]byte(hydArticleJson), &got); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "Hello", got.Title)
+	assert.Equal(t, &hydPerson{Id: "9", Name: "Ann"}, got.Author)
+	if assert.Len(t, got.Comments, 2) {
+		// hydrated from the included resource
+		assert.Equal(t, "First", got.Comments[0].Body)
+		assert.Equal(t, hydPerson{Id: "9", Name: "Ann"}, got.Comments[0].Author)
+		// only identified
+		assert.Equal(t, "6", got.Comments[1].Id)
+		assert.Equal(t, "", got.Comments[1].Body)
+
+		// back references
+		assert.Same(t, &got, got.Comments[0].Article)
+		assert.Same(t, &got, got.Comments[1].Article)
+	}
+}
+
+func TestUnmarshalDocument_Hydrate_Collection(t *testing.T) {
+	in := `
+	{
+		"data": [
+			{"type": "articles", "id": "1", "relationships": {"comments": {"data": [{"type": "comments", "id": "5"}]}}},
+			{"type": "articles", "id": "2", "relationships": {"comments": {"data": [{"type": "comments", "id": "6"}]}}}
+		],
+		"included": [
+			{"type": "comments", "id": "5", "attributes": {"body": "First"}},
+			{"type": "comments", "id": "6", "attributes": {"body": "Second"}}
+		]
+	}`
+
+	got := []hydArticle{}
+	if err := UnmarshalDocument([]byte(in), &got); err != nil {
+		t.Fatal(err)
+	}
+	if assert.Len(t, got, 2) {
+		assert.Equal(t, "Second", got[1].Comments[0].Body)
+		assert.Same(t, &got[0], got[0].Comments[0].Article)
+		assert.Same(t, &got[1], got[1].Comments[0].Article)
+	}
+}
+
+func TestUnmarshalDocument_Hydrate_Cycle(t *testing.T) {
+	type node struct {
+		Id   string `jsonapi:"id,nodes"`
+		Name string `jsonapi:"attr,name"`
+		Next *node  `jsonapi:"rel,next,nodes"`
+	}
+
+	in := `
+	{
+		"data": {"type": "nodes", "id": "1", "relationships": {"next": {"data": {"type": "nodes", "id": "2"}}}},
+		"included": [
+			{"type": "nodes", "id": "2", "attributes": {"name": "b"}, "relationships": {"next": {"data": {"type": "nodes", "id": "3"}}}},
+			{"type": "nodes", "id": "3", "attributes": {"name": "c"}, "relationships": {"next": {"data": {"type": "nodes", "id": "2"}}}}
+		]
+	}`
+
+	got := node{}
+	if err := UnmarshalDocument([]byte(in), &got); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "b", got.Next.Name)
+	assert.Equal(t, "c", got.Next.Next.Name)
+	// the cycle ends with an identified struct
+	assert.Equal(t, &node{Id: "2"}, got.Next.Next.Next)
+}
+
+func TestParseRelTag_BackRef(t *testing.T) {
+	_, err := MarshalResource(struct {
+		Id       string   `jsonapi:"id,articles"`
+		Comments []string `jsonapi:"rel,comments,comments,backref=article"`
+	}{})
+	assert.ErrorAs(t, err, addrOf(&TagErr{}))
+
+	type comment struct {
+		Id string `jsonapi:"id,comments"`
+	}
+	type article struct {
+		Id       string     `jsonapi:"id,articles"`
+		Comments []*comment `jsonapi:"rel,comments,comments,backref=article"`
+	}
+	in := `{"type": "articles", "id": "1", "relationships": {"comments": {"data": [{"type": "comments", "id": "5"}]}}}`
+	err = UnmarshalResource([]byte(in), &article{})
+	assert.ErrorAs(t, err, addrOf(&UnmarshalErr{}))
+}
+
+type derivedEditor struct {
+	hydPerson
+	Role string `jsonapi:"attr,role"`
+}
+
+type derivedArticle struct {
+	Id      string          `jsonapi:"id,articles"`
+	Author  *hydPerson      `jsonapi:"rel,author"`
+	Editors []derivedEditor `jsonapi:"rel,editors"`
+}
+
+func TestParseRelTag_DeclaredType(t *testing.T) {
+	in := derivedArticle{
+		Id:      "1",
+		Author:  &hydPerson{Id: "9", Name: "Ann"},
+		Editors: []derivedEditor{{hydPerson: hydPerson{Id: "10"}, Role: "copy"}},
+	}
+	data, err := MarshalResource(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{
+		"type": "articles",
+		"id": "1",
+		"relationships": {
+			"author": {"data": {"type": "people", "id": "9"}},
+			"editors": {"data": [{"type": "people", "id": "10"}]}
+		}
+	}`
+	assert.JSONEq(t, expected, string(data))
+
+	got := derivedArticle{}
+	if err := UnmarshalResource(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, derivedArticle{
+		Id:      "1",
+		Author:  &hydPerson{Id: "9"},
+		Editors: []derivedEditor{{hydPerson: hydPerson{Id: "10"}}},
+	}, got)
+
+	// the related struct's id tag must declare a type
+	type person struct {
+		Id string `jsonapi:"id"`
+	}
+	_, err = MarshalResource(struct {
+		Id     string `jsonapi:"id,articles"`
+		Author person `jsonapi:"rel,author"`
+	}{})
+	assert.ErrorAs(t, err, addrOf(&TagErr{}))
+}
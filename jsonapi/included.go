@@ -0,0 +1,162 @@
+package jsonapi
+
+import "slices"
+
+// WithOmitIncludedLinks omits the links of included resources, and
+// of their relationships, from documents built by FormatDocument
+// and MarshalDocument, to reduce their size where clients don't
+// follow them. Relationships left with neither linkage nor meta
+// are omitted. The links of the primary data are unaffected.
+func WithOmitIncludedLinks() Option {
+	return func(o *options) {
+		o.omitIncludedLinks = true
+	}
+}
+
+// omitLinks removes the links of r and its relationships.
+func omitLinks(r *Resource) {
+	r.Links = nil
+	for _, rel := range r.ToOneRelationships {
+		rel.Links = nil
+	}
+	for name, rel := range r.ToManyRelationships {
+		rel.Links = nil
+		if rel.Data == nil && len(rel.Meta) == 0 {
+			delete(r.ToManyRelationships, name)
+		}
+	}
+}
+
+// SortIncluded sorts d's included resources so that every resource
+// appears after the included resources that it references through its
+// relationships, allowing them to be resolved in a single pass.
+// Resources are otherwise kept in their original order. Resources whose
+// references form a cycle cannot be ordered, and are kept together in
+// their original order, after the resources that any of them references
+// outside the cycle.
+func (d *Document) SortIncluded() {
+	byKey := make(map[string]*Resource, len(d.Included))
+	for _, r := range d.Included {
+		byKey[identifierKey(r.ResourceIdentifier)] = r
+	}
+	deps := func(r *Resource) []*Resource {
+		var rs []*Resource
+		for _, id := range references(r) {
+			if dep, ok := byKey[identifierKey(id)]; ok {
+				rs = append(rs, dep)
+			}
+		}
+		return rs
+	}
+	cycles := referenceCycles(d.Included, deps)
+
+	sorted := make([]*Resource, 0, len(d.Included))
+	visited := make(map[*Resource]bool, len(d.Included))
+
+	var visit func(r *Resource)
+	visit = func(r *Resource) {
+		members := cycles[r]
+		if visited[members[0]] {
+			return
+		}
+		visited[members[0]] = true
+		for _, m := range members {
+			for _, dep := range deps(m) {
+				visit(dep)
+			}
+		}
+		sorted = append(sorted, members...)
+	}
+
+	for _, r := range d.Included {
+		visit(r)
+	}
+	d.Included = sorted
+}
+
+// referenceCycles returns the strongly connected components of the
+// references between rs, as found by Tarjan's algorithm, mapping each
+// resource to the members of its component in their order in rs.
+// Resources that aren't in a cycle form a component of their own.
+func referenceCycles(rs []*Resource, deps func(r *Resource) []*Resource) map[*Resource][]*Resource {
+	pos := make(map[*Resource]int, len(rs))
+	for i, r := range rs {
+		pos[r] = i
+	}
+
+	var (
+		next    int
+		index   = make(map[*Resource]int, len(rs))
+		low     = make(map[*Resource]int, len(rs))
+		onStack = make(map[*Resource]bool, len(rs))
+		stack   []*Resource
+		comps   = make(map[*Resource][]*Resource, len(rs))
+	)
+
+	var connect func(r *Resource)
+	connect = func(r *Resource) {
+		index[r], low[r] = next, next
+		next++
+		stack = append(stack, r)
+		onStack[r] = true
+
+		for _, dep := range deps(r) {
+			if _, ok := index[dep]; !ok {
+				connect(dep)
+				low[r] = min(low[r], low[dep])
+			} else if onStack[dep] {
+				low[r] = min(low[r], index[dep])
+			}
+		}
+
+		if low[r] != index[r] {
+			return
+		}
+		var members []*Resource
+		for {
+			m := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[m] = false
+			members = append(members, m)
+			if m == r {
+				break
+			}
+		}
+		slices.SortFunc(members, func(a, b *Resource) int { return pos[a] - pos[b] })
+		for _, m := range members {
+			comps[m] = members
+		}
+	}
+
+	for _, r := range rs {
+		if _, ok := index[r]; !ok {
+			connect(r)
+		}
+	}
+	return comps
+}
+
+// references returns the resource identifiers of r's
+// relationships, ordered by relationship name.
+func references(r *Resource) []ResourceIdentifier {
+	var ids []ResourceIdentifier
+	for _, name := range relationshipNames(r) {
+		if rel, ok := r.ToOneRelationships[name]; ok {
+			ids = append(ids, rel.Data)
+		}
+		if rel, ok := r.ToManyRelationships[name]; ok {
+			ids = append(ids, rel.Data...)
+		}
+	}
+	return ids
+}
+
+// identifierKey returns a key that uniquely identifies
+// the resource with identifier id, by its id or else its
+// local id.
+func identifierKey(id ResourceIdentifier) string {
+	if len(id.Id) == 0 && id.Lid != "" {
+		return id.Type + "\x00lid\x00" + id.Lid
+	}
+	return id.Type + "\x00" + string(id.Id)
+}
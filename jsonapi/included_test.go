@@ -0,0 +1,180 @@
+package jsonapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type includedPublisher struct {
+	Id   string `jsonapi:"id,publishers"`
+	Name string `jsonapi:"attr,name"`
+}
+
+type includedAuthor struct {
+	Id        string `jsonapi:"id,people"`
+	Publisher string `jsonapi:"rel,publisher,publishers"`
+}
+
+type includedArticle struct {
+	Id     string `jsonapi:"id,articles"`
+	Author string `jsonapi:"rel,author,people"`
+}
+
+func TestMarshalDocument_Included(t *testing.T) {
+	got, err := MarshalDocument(
+		&includedArticle{Id: "1", Author: "2"},
+		WithIncluded(&includedAuthor{Id: "2", Publisher: "3"}, []includedPublisher{{Id: "3", Name: "Penguin"}}, (*includedAuthor)(nil)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `
+	{
+		"data": {"type": "articles", "id": "1", "relationships": {"author": {"data": {"type": "people", "id": "2"}}}},
+		"included": [
+			{"type": "people", "id": "2", "relationships": {"publisher": {"data": {"type": "publishers", "id": "3"}}}},
+			{"type": "publishers", "id": "3", "attributes": {"name": "Penguin"}}
+		]
+	}`
+	assert.Equal(t, fmtJson(t, []byte(want)), fmtJson(t, got))
+}
+
+func TestMarshalDocument_IncludedDependencyOrder(t *testing.T) {
+	got, err := MarshalDocument(
+		&includedArticle{Id: "1", Author: "2"},
+		WithIncluded(&includedAuthor{Id: "2", Publisher: "3"}, &includedPublisher{Id: "3", Name: "Penguin"}),
+		WithIncludedDependencyOrder(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `
+	{
+		"data": {"type": "articles", "id": "1", "relationships": {"author": {"data": {"type": "people", "id": "2"}}}},
+		"included": [
+			{"type": "publishers", "id": "3", "attributes": {"name": "Penguin"}},
+			{"type": "people", "id": "2", "relationships": {"publisher": {"data": {"type": "publishers", "id": "3"}}}}
+		]
+	}`
+	assert.Equal(t, fmtJson(t, []byte(want)), fmtJson(t, got))
+}
+
+func TestDocument_SortIncluded(t *testing.T) {
+	rsc := func(typ, id string, refs ...string) *Resource {
+		r := newResource()
+		r.Type = typ
+		r.Id = []byte(`"` + id + `"`)
+		ids := make([]ResourceIdentifier, len(refs))
+		for i, ref := range refs {
+			ids[i] = ResourceIdentifier{Type: "t", Id: []byte(`"` + ref + `"`)}
+		}
+		r.ToManyRelationships["refs"] = &ToManyResourceLinkage{Data: ids}
+		return &r
+	}
+
+	type testCase struct {
+		Name     string
+		In       []*Resource
+		Expected []string
+	}
+
+	testCases := []testCase{
+		{"independent", []*Resource{rsc("t", "a"), rsc("t", "b")}, []string{"a", "b"}},
+		{"chain", []*Resource{rsc("t", "a", "b"), rsc("t", "b", "c"), rsc("t", "c")}, []string{"c", "b", "a"}},
+		{"diamond", []*Resource{rsc("t", "a", "b", "c"), rsc("t", "b", "d"), rsc("t", "c", "d"), rsc("t", "d")}, []string{"d", "b", "c", "a"}},
+		{"cycle", []*Resource{rsc("t", "a", "b"), rsc("t", "b", "a")}, []string{"a", "b"}},
+		{"cycle reversed", []*Resource{rsc("t", "b", "a"), rsc("t", "a", "b")}, []string{"b", "a"}},
+		{"cycle with dependency", []*Resource{rsc("t", "a", "b"), rsc("t", "b", "a", "c"), rsc("t", "c")}, []string{"c", "a", "b"}},
+		{"cycle dependent", []*Resource{rsc("t", "x", "a"), rsc("t", "a", "b"), rsc("t", "b", "c", "a"), rsc("t", "c", "b")}, []string{"a", "b", "c", "x"}},
+		{"self", []*Resource{rsc("t", "a", "a")}, []string{"a"}},
+		{"not included", []*Resource{rsc("t", "a", "x"), rsc("u", "b")}, []string{"a", "b"}},
+		{"other type", []*Resource{rsc("t", "a", "b"), rsc("u", "b")}, []string{"a", "b"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			d := Document{Included: tc.In}
+			d.SortIncluded()
+
+			got := make([]string, len(d.Included))
+			for i, r := range d.Included {
+				got[i] = string(r.Id[1 : len(r.Id)-1])
+			}
+			assert.Equal(t, tc.Expected, got)
+		})
+	}
+}
+
+type includedLinkedAuthor struct {
+	Id        string `jsonapi:"id,people"`
+	Self      string `jsonapi:"link,self"`
+	Publisher string `jsonapi:"rel,publisher,publishers,related=/people/{id}/publisher"`
+	Books     []int  `jsonapi:"rel,books,books,countonly"`
+	BooksSelf string `jsonapi:"link,self,rel=books"`
+	Followers string `jsonapi:"link,related,rel=followers"`
+}
+
+type includedLinkedArticle struct {
+	Id     string `jsonapi:"id,articles"`
+	Self   string `jsonapi:"link,self"`
+	Author string `jsonapi:"rel,author,people,related=/articles/{id}/author"`
+}
+
+func TestMarshalDocument_IncludedLinks(t *testing.T) {
+	article := &includedLinkedArticle{Id: "1", Self: "/articles/1", Author: "2"}
+	author := &includedLinkedAuthor{Id: "2", Self: "/people/2", Publisher: "3", Books: []int{4, 5}, BooksSelf: "/people/2/relationships/books"}
+
+	got, err := MarshalDocument(article, WithIncluded(author))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `
+	{
+		"data": {
+			"type": "articles", "id": "1",
+			"relationships": {"author": {"data": {"type": "people", "id": "2"}, "links": {"related": "/articles/1/author"}}},
+			"links": {"self": "/articles/1"}
+		},
+		"included": [{
+			"type": "people", "id": "2",
+			"relationships": {
+				"publisher": {"data": {"type": "publishers", "id": "3"}, "links": {"related": "/people/2/publisher"}},
+				"books": {"meta": {"count": 2}, "links": {"self": "/people/2/relationships/books"}}
+			},
+			"links": {"self": "/people/2"}
+		}]
+	}`
+	assert.Equal(t, fmtJson(t, []byte(want)), fmtJson(t, got))
+
+	got, err = MarshalDocument(article, WithIncluded(author, &includedLinkedAuthor{Id: "3", Followers: "/people/3/followers"}), WithOmitIncludedLinks())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want = `
+	{
+		"data": {
+			"type": "articles", "id": "1",
+			"relationships": {"author": {"data": {"type": "people", "id": "2"}, "links": {"related": "/articles/1/author"}}},
+			"links": {"self": "/articles/1"}
+		},
+		"included": [{
+			"type": "people", "id": "2",
+			"relationships": {
+				"publisher": {"data": {"type": "publishers", "id": "3"}},
+				"books": {"meta": {"count": 2}}
+			}
+		}, {
+			"type": "people", "id": "3",
+			"relationships": {
+				"publisher": {"data": {"type": "publishers", "id": ""}},
+				"books": {"meta": {"count": 0}}
+			}
+		}]
+	}`
+	assert.Equal(t, fmtJson(t, []byte(want)), fmtJson(t, got))
+}
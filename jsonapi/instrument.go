@@ -0,0 +1,137 @@
+package jsonapi
+
+import (
+	"reflect"
+	"time"
+)
+
+// Direction is whether a Call marshaled or unmarshaled.
+type Direction int
+
+const (
+	// Marshaling is the direction of MarshalDocument and MarshalResource.
+	Marshaling Direction = iota
+	// Unmarshaling is the direction of UnmarshalDocument and UnmarshalResource.
+	Unmarshaling
+)
+
+// String returns "marshal" or "unmarshal", for use as a metric label.
+func (d Direction) String() string {
+	if d == Unmarshaling {
+		return "unmarshal"
+	}
+	return "marshal"
+}
+
+// Call describes a single call of MarshalDocument, UnmarshalDocument,
+// MarshalResource or UnmarshalResource, as reported to the function
+// set by WithInstrumentation.
+type Call struct {
+	// Func is the name of the function called, eg "MarshalDocument".
+	Func string
+	// Direction is whether the call marshaled or unmarshaled.
+	Direction Direction
+	// Type is the name of the Go type marshaled or unmarshaled into,
+	// with pointers and slices removed, eg "models.Article", or ""
+	// for nil values.
+	Type string
+	// Duration is the time taken by the call.
+	Duration time.Duration
+	// Bytes is the size of the encoded document or resource, which
+	// is 0 for failed calls that marshal.
+	Bytes int
+	// Resources is the number of resources in the primary data.
+	Resources int
+	// Included is the number of included resources.
+	Included int
+	// Attributes, Relationships and Meta are the number of members of
+	// the primary and included resources.
+	Attributes    int
+	Relationships int
+	Meta          int
+	// Err is the error returned by the call, if any.
+	Err error
+}
+
+// WithInstrumentation sets a function that is called with the Call
+// of each MarshalDocument, UnmarshalDocument, MarshalResource and
+// UnmarshalResource, whether it succeeds or fails, for recording
+// metrics without wrapping every call site. The counts of failed
+// calls are those of the document or resource, if any, formatted or
+// parsed before the failure. It's called on the goroutine of the call.
+func WithInstrumentation(f func(Call)) Option {
+	return func(o *options) {
+		o.instrument = f
+	}
+}
+
+// instrumentedCall records a Call. Its methods do nothing
+// when it's nil, which it is without WithInstrumentation.
+type instrumentedCall struct {
+	f     func(Call)
+	c     Call
+	start time.Time
+}
+
+// startCall returns an instrumentedCall recording a call of
+// fn with a, or nil if there is no instrumentation function.
+func (o *options) startCall(fn string, d Direction, a any) *instrumentedCall {
+	if o.instrument == nil {
+		return nil
+	}
+	c := Call{Func: fn, Direction: d}
+	if t := reflect.TypeOf(a); t != nil {
+		for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice {
+			t = t.Elem()
+		}
+		c.Type = t.String()
+	}
+	return &instrumentedCall{f: o.instrument, c: c, start: time.Now()}
+}
+
+// countDocument records the counts of d's resources and members.
+func (ic *instrumentedCall) countDocument(d *Document) {
+	if ic == nil {
+		return
+	}
+	ic.c.Resources = documentStats(d, 0).Resources
+	ic.c.Included = len(d.Included)
+	if d.Data != nil {
+		for _, r := range d.Data.Resources {
+			ic.countMembers(r)
+		}
+		if d.Data.Resource != nil {
+			ic.countMembers(d.Data.Resource)
+		}
+	}
+	for _, r := range d.Included {
+		ic.countMembers(r)
+	}
+}
+
+// countResource records the counts of r's members.
+func (ic *instrumentedCall) countResource(r *Resource) {
+	if ic == nil {
+		return
+	}
+	ic.c.Resources = 1
+	ic.countMembers(r)
+}
+
+func (ic *instrumentedCall) countMembers(r *Resource) {
+	ic.c.Attributes += len(r.Attributes)
+	ic.c.Relationships += len(r.ToOneRelationships) + len(r.ToManyRelationships)
+	ic.c.Meta += len(r.Meta)
+}
+
+// end calls the instrumentation function with the Call,
+// whose encoding is n bytes and whose error is err.
+func (ic *instrumentedCall) end(n int, err error) {
+	if ic == nil {
+		return
+	}
+	ic.c.Duration = time.Since(ic.start)
+	ic.c.Bytes = n
+	ic.c.Err = err
+	ic.f(ic.c)
+}
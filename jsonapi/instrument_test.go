@@ -0,0 +1,124 @@
+package jsonapi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithInstrumentation(t *testing.T) {
+	var got []Call
+	collect := WithInstrumentation(func(c Call) {
+		assert.GreaterOrEqual(t, c.Duration, time.Duration(0))
+		c.Duration = 0
+		got = append(got, c)
+	})
+
+	data, err := MarshalDocument(docArticlesValue, collect, WithIncluded(&docArticleValue))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []Call{{
+		Func:          "MarshalDocument",
+		Direction:     Marshaling,
+		Type:          "jsonapi.docArticle",
+		Bytes:         len(data),
+		Resources:     2,
+		Included:      1,
+		Attributes:    3,
+		Relationships: 3,
+	}}, got)
+
+	got = nil
+	if err := UnmarshalDocument([]byte(docArticleJson), &docArticle{}, collect); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []Call{{
+		Func:          "UnmarshalDocument",
+		Direction:     Unmarshaling,
+		Type:          "jsonapi.docArticle",
+		Bytes:         len(docArticleJson),
+		Resources:     1,
+		Attributes:    1,
+		Relationships: 1,
+	}}, got)
+
+	got = nil
+	data, err = MarshalResource(&docArticleValue, collect)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []Call{{
+		Func:          "MarshalResource",
+		Direction:     Marshaling,
+		Type:          "jsonapi.docArticle",
+		Bytes:         len(data),
+		Resources:     1,
+		Attributes:    1,
+		Relationships: 1,
+	}}, got)
+
+	got = nil
+	if err := UnmarshalResource(data, &docArticle{}, collect); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []Call{{
+		Func:          "UnmarshalResource",
+		Direction:     Unmarshaling,
+		Type:          "jsonapi.docArticle",
+		Bytes:         len(data),
+		Resources:     1,
+		Attributes:    1,
+		Relationships: 1,
+	}}, got)
+}
+
+func TestWithInstrumentation_Errors(t *testing.T) {
+	var got []Call
+	collect := WithInstrumentation(func(c Call) {
+		c.Duration = 0
+		got = append(got, c)
+	})
+
+	// failed calls are reported with their error and the counts
+	// of the document parsed before the failure
+	err := UnmarshalDocument([]byte(docArticlesJson), &docArticle{}, collect)
+	assert.ErrorIs(t, err, ErrUnexpectedArray)
+	assert.Equal(t, []Call{{
+		Func:          "UnmarshalDocument",
+		Direction:     Unmarshaling,
+		Type:          "jsonapi.docArticle",
+		Bytes:         len(docArticlesJson),
+		Resources:     2,
+		Attributes:    2,
+		Relationships: 2,
+		Err:           err,
+	}}, got)
+
+	got = nil
+	_, err = MarshalDocument(42, collect)
+	assert.Error(t, err)
+	assert.Equal(t, []Call{{
+		Func:      "MarshalDocument",
+		Direction: Marshaling,
+		Type:      "int",
+		Err:       err,
+	}}, got)
+
+	got = nil
+	err = UnmarshalResource([]byte(`{`), &docArticle{}, collect)
+	assert.Error(t, err)
+	assert.Equal(t, []Call{{
+		Func:      "UnmarshalResource",
+		Direction: Unmarshaling,
+		Type:      "jsonapi.docArticle",
+		Bytes:     1,
+		Err:       err,
+	}}, got)
+}
+
+func TestDirection_String(t *testing.T) {
+	assert.Equal(t, "marshal", Marshaling.String())
+	assert.Equal(t, "unmarshal", Unmarshaling.String())
+}
@@ -0,0 +1,89 @@
+package jsonapi
+
+// Resource types and member names are repeated in every resource of
+// a collection, so they are interned when unmarshaling a document, so
+// that large documents share a single copy of each rather than holding
+// one per resource. The table only lives for the duration of a single
+// document's decoding, so untrusted input can't grow it beyond the size
+// of that input; long strings are unlikely to repeat, and are used as
+// they are.
+const maxInternedLen = 64
+
+// interner is a string table for a single decode call.
+type interner map[string]string
+
+// intern returns the interned copy of s.
+func (in interner) intern(s string) string {
+	if len(s) > maxInternedLen {
+		return s
+	}
+	if v, ok := in[s]; ok {
+		return v
+	}
+	in[s] = s
+	return s
+}
+
+// internKeys returns a copy of m with interned keys.
+func internKeys[V any](in interner, m map[string]V) map[string]V {
+	if m == nil {
+		return nil
+	}
+	c := make(map[string]V, len(m))
+	for k, v := range m {
+		c[in.intern(k)] = v
+	}
+	return c
+}
+
+// internSlice interns the elements of s in place.
+func (in interner) internSlice(s []string) {
+	for i := range s {
+		s[i] = in.intern(s[i])
+	}
+}
+
+// internIdentifier interns the type and meta member names of id.
+func (in interner) internIdentifier(id *ResourceIdentifier) {
+	id.Type = in.intern(id.Type)
+	id.Meta = internKeys(in, id.Meta)
+}
+
+// internResource interns the type, member names and
+// relationship linkage types of r.
+func (in interner) internResource(r *Resource) {
+	if r == nil {
+		return
+	}
+	in.internIdentifier(&r.ResourceIdentifier)
+	r.Attributes = internKeys(in, r.Attributes)
+	in.internSlice(r.attrOrder)
+	in.internSlice(r.relOrder)
+	in.internSlice(r.metaOrder)
+
+	r.ToOneRelationships = internKeys(in, r.ToOneRelationships)
+	for _, rel := range r.ToOneRelationships {
+		in.internIdentifier(&rel.Data)
+	}
+	r.ToManyRelationships = internKeys(in, r.ToManyRelationships)
+	for _, rel := range r.ToManyRelationships {
+		for i := range rel.Data {
+			in.internIdentifier(&rel.Data[i])
+		}
+	}
+}
+
+// internDocument interns the resources of d,
+// sharing a single table between them.
+func internDocument(d *Document) {
+	in := interner{}
+	if d.Data != nil {
+		in.internResource(d.Data.Resource)
+		for _, r := range d.Data.Resources {
+			in.internResource(r)
+		}
+	}
+	for _, r := range d.Included {
+		in.internResource(r)
+	}
+}
@@ -0,0 +1,70 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocument_UnmarshalJSON_Interned(t *testing.T) {
+	in := `{"data": [
+		{"type": "articles", "id": "1", "attributes": {"title": "a"}, "relationships": {"author": {"data": {"type": "people", "id": "2"}}}},
+		{"type": "articles", "id": "3", "attributes": {"title": "b"}, "relationships": {"author": {"data": {"type": "people", "id": "4"}}}}
+	], "included": [
+		{"type": "people", "id": "2"}
+	]}`
+
+	d := &Document{}
+	if err := json.Unmarshal([]byte(in), d); err != nil {
+		t.Fatal(err)
+	}
+	rs := d.Data.Resources
+
+	same := func(a, b string) bool {
+		return unsafe.StringData(a) == unsafe.StringData(b)
+	}
+	assert.True(t, same(rs[0].Type, rs[1].Type))
+	assert.True(t, same(rs[0].ToOneRelationships["author"].Data.Type, rs[1].ToOneRelationships["author"].Data.Type))
+	assert.True(t, same(rs[0].ToOneRelationships["author"].Data.Type, d.Included[0].Type))
+	for k0 := range rs[0].Attributes {
+		for k1 := range rs[1].Attributes {
+			assert.True(t, same(k0, k1))
+		}
+	}
+	assert.True(t, same(rs[0].attrOrder[0], rs[1].attrOrder[0]))
+
+}
+
+func TestInternDocument_PerCall(t *testing.T) {
+	doc := func() *Document {
+		// nb build separate copies of each type
+		return &Document{Data: &PrimaryData{Collection: true, Resources: []*Resource{
+			{ResourceIdentifier: ResourceIdentifier{Type: string([]byte("articles"))}},
+			{ResourceIdentifier: ResourceIdentifier{Type: string([]byte("articles"))}},
+		}}}
+	}
+	d1, d2 := doc(), doc()
+	internDocument(d1)
+	internDocument(d2)
+
+	same := func(a, b string) bool {
+		return unsafe.StringData(a) == unsafe.StringData(b)
+	}
+	assert.True(t, same(d1.Data.Resources[0].Type, d1.Data.Resources[1].Type))
+	assert.True(t, same(d2.Data.Resources[0].Type, d2.Data.Resources[1].Type))
+	assert.False(t, same(d1.Data.Resources[0].Type, d2.Data.Resources[0].Type))
+}
+
+func TestIntern_Long(t *testing.T) {
+	b := make([]byte, maxInternedLen+1)
+	for i := range b {
+		b[i] = 'a'
+	}
+	s1, s2 := string(b), string(b)
+	in := interner{}
+	assert.Equal(t, s1, in.intern(s1))
+	assert.False(t, unsafe.StringData(in.intern(s1)) == unsafe.StringData(in.intern(s2)))
+	assert.Empty(t, in)
+}
@@ -1,6 +1,7 @@
 package jsonapi
 
 import (
+	"bytes"
 	"cmp"
 	"encoding/json"
 	"errors"
@@ -9,6 +10,7 @@ import (
 
 	"reflect"
 	"slices"
+	"strconv"
 	"strings"
 )
 
@@ -19,12 +21,26 @@ const (
 	// tag values
 	TagValueIgnore = "-"
 	TagValueId     = "id"
+	TagValueLid    = "lid"
 	TagValueAttr   = "attr"
 	TagValueRel    = "rel"
 	TagValueMeta   = "meta"
+	TagValueLink   = "link"
+	// catch-all tag values
+	TagValueAttrMap = "attr-map"
+	TagValueMetaMap = "meta-map"
 	// options
 	TagValueOmitEmpty = "omitempty"
+	TagValueOmitZero  = "omitzero"
+	TagValueReadOnly  = "readonly"
+	TagValueWriteOnly = "writeonly"
+	TagValueNotNull   = "notnull"
 	TagValueString    = "string"
+	TagValueEmpty     = "empty"
+	TagValueCountOnly = "countonly"
+	TagValueRelOpt    = "rel"
+	TagValueSelf      = "self"
+	TagValueRelated   = "related"
 )
 
 var NullJson = json.RawMessage([]byte("null"))
@@ -38,24 +54,43 @@ func (e *TagErr) Error() string {
 	return "tag error on field '" + e.Field + "': " + e.Err.Error()
 }
 
+func (e *TagErr) Unwrap() error {
+	return e.Err
+}
+
 type UnmarshalErr struct {
 	Field string
-	Err   error
+	// Pointer is the JSON pointer of the member, if known, eg
+	// "/data/attributes/age" when unmarshaling a document, or
+	// "/attributes/age" when unmarshaling a resource.
+	Pointer string
+	Err     error
 }
 
 func (e *UnmarshalErr) Error() string {
 	return "unmarshal error on field '" + e.Field + "': " + e.Err.Error()
 }
 
+func (e *UnmarshalErr) Unwrap() error {
+	return e.Err
+}
+
 type MarshalErr struct {
 	Field string
-	Err   error
+	// Pointer is the JSON pointer of the member, if known,
+	// relative to the resource object, eg "/attributes/age".
+	Pointer string
+	Err     error
 }
 
 func (e *MarshalErr) Error() string {
 	return "marshal error on field '" + e.Field + "': " + e.Err.Error()
 }
 
+func (e *MarshalErr) Unwrap() error {
+	return e.Err
+}
+
 type UnsupportedTypeErr struct {
 	Field string
 	Kind  reflect.Kind
@@ -66,9 +101,11 @@ func (e *UnsupportedTypeErr) Error() string {
 }
 
 var (
-	ErrNotStructPtr = fmt.Errorf("not a struct pointer")
-	ErrNotStruct    = fmt.Errorf("not a struct")
-	ErrSelfRefPtr   = fmt.Errorf("self-referential pointer")
+	ErrNotStructPtr     = fmt.Errorf("not a struct pointer")
+	ErrNotStruct        = fmt.Errorf("not a struct")
+	ErrSelfRefPtr       = fmt.Errorf("self-referential pointer")
+	ErrUnregisteredType = fmt.Errorf("unregistered type")
+	ErrMissingHref      = fmt.Errorf("link object has no href")
 )
 
 type ResourceUnmarshaler interface {
@@ -79,49 +116,126 @@ type ResourceMarshaler interface {
 	MarshalJsonApiResource() ([]byte, error)
 }
 
+// ResourceTyper is implemented by structs that decide their resource
+// type at runtime, eg generated or wrapped models. The type returned
+// by JsonApiType overrides the type declared by the id tag.
+type ResourceTyper interface {
+	JsonApiType() string
+}
+
 var (
 	resourceMarshalerType   = reflect.TypeFor[ResourceMarshaler]()
 	resourceUnmarshalerType = reflect.TypeFor[ResourceUnmarshaler]()
+	resourceTyperType       = reflect.TypeFor[ResourceTyper]()
 )
 
+// Counter is implemented by relationship field types that can report
+// the number of related resources, for use with the countonly tag option.
+type Counter interface {
+	Count() int
+}
+
+var counterType = reflect.TypeFor[Counter]()
+
 type ResourceIdentifier struct {
 	Type string                     `json:"type,omitempty"`
 	Id   json.RawMessage            `json:"id,omitempty"`
+	Lid  string                     `json:"lid,omitempty"`
 	Meta map[string]json.RawMessage `json:"meta,omitempty"`
 }
 
 type LinkObject struct {
 	Href        string                 `json:"href"`
-	DescribedBy *Link                  `json:"described_by,omitempty"`
+	DescribedBy *Link                  `json:"describedby,omitempty"`
 	Title       string                 `json:"title,omitempty"`
 	Type        string                 `json:"type,omitempty"`
 	HrefLang    []string               `json:"hreflang,omitempty"`
 	Meta        map[string]interface{} `json:"meta,omitempty"`
 }
 
+// Link is a link, which is exactly one of a string, a link object or
+// null. A Link with a LinkString is encoded as a string, a Link with a
+// LinkObject as an object, and a Null or zero Link as null.
 type Link struct {
 	LinkString string
 	LinkObject LinkObject
+	// Null is true for null links, eg links that
+	// aren't available for a resource.
+	Null bool
+}
+
+// IsZero returns whether l is nil or the zero Link,
+// with no string or link object and not null.
+func (l *Link) IsZero() bool {
+	return l == nil || (!l.Null && l.LinkString == "" && l.LinkObject.isZero())
+}
+
+// Href returns the link's URI, which is
+// "" for null and zero links.
+func (l *Link) Href() string {
+	switch {
+	case l == nil || l.Null:
+		return ""
+	case l.LinkString != "":
+		return l.LinkString
+	default:
+		return l.LinkObject.Href
+	}
 }
 
 func (l *Link) MarshalJSON() ([]byte, error) {
-	if l.LinkString != "" {
+	switch {
+	case l.IsZero() || l.Null:
+		return NullJson, nil
+	case l.LinkString != "":
 		return json.Marshal(l.LinkString)
+	case l.LinkObject.Href == "":
+		return nil, ErrMissingHref
 	}
 	return json.Marshal(l.LinkObject)
 }
 
 func (l *Link) UnmarshalJSON(data []byte) error {
+	*l = Link{}
 	switch data[0] {
 	case '"':
 		return json.Unmarshal(data, &l.LinkString)
 	case '{':
-		return json.Unmarshal(data, &l.LinkObject)
+		// hreflang may be a single language tag
+		type alias LinkObject
+		obj := struct {
+			*alias
+			HrefLang json.RawMessage `json:"hreflang"`
+		}{alias: (*alias)(&l.LinkObject)}
+		if err := json.Unmarshal(data, &obj); err != nil {
+			return err
+		}
+		if len(obj.HrefLang) > 0 && obj.HrefLang[0] == '"' {
+			obj.HrefLang = append(append([]byte{'['}, obj.HrefLang...), ']')
+		}
+		if len(obj.HrefLang) > 0 {
+			if err := json.Unmarshal(obj.HrefLang, &l.LinkObject.HrefLang); err != nil {
+				return err
+			}
+		}
+		if l.LinkObject.Href == "" {
+			return ErrMissingHref
+		}
+		return nil
+	case 'n':
+		l.Null = true
+		return nil
 	default:
 		return fmt.Errorf("cannot unmarshal into link data")
 	}
 }
 
+// isZero returns whether every member of l is empty.
+func (l *LinkObject) isZero() bool {
+	return l.Href == "" && l.DescribedBy == nil && l.Title == "" && l.Type == "" &&
+		len(l.HrefLang) == 0 && len(l.Meta) == 0
+}
+
 type ToOneResourceLinkage struct {
 	Links map[string]*Link           `json:"links,omitempty"`
 	Meta  map[string]json.RawMessage `json:"meta,omitempty"`
@@ -134,12 +248,37 @@ type ToManyResourceLinkage struct {
 	Data  []ResourceIdentifier       `json:"data"`
 }
 
+// MarshalJSON omits the data member if Data is nil,
+// eg for relationships that only contain meta or links.
+func (l *ToManyResourceLinkage) MarshalJSON() ([]byte, error) {
+	type alias ToManyResourceLinkage
+	if l.Data != nil {
+		return json.Marshal((*alias)(l))
+	}
+	return json.Marshal(struct {
+		Links map[string]*Link           `json:"links,omitempty"`
+		Meta  map[string]json.RawMessage `json:"meta,omitempty"`
+	}{l.Links, l.Meta})
+}
+
 type Resource struct {
 	ResourceIdentifier
 	Attributes          map[string]json.RawMessage
 	ToOneRelationships  map[string]*ToOneResourceLinkage
 	ToManyRelationships map[string]*ToManyResourceLinkage
 	Links               map[string]*Link
+	// Unknown holds members that are not defined by the JSON:API
+	// specification, which are kept when unmarshaling so they can
+	// be re-emitted verbatim.
+	Unknown map[string]json.RawMessage
+
+	// the order of the attributes, relationships and meta
+	// members when unmarshaled, which is kept when marshaling
+	attrOrder []string
+	relOrder  []string
+	metaOrder []string
+	// whether the resource was acquired from the pool
+	pooled bool
 }
 
 func newResource() Resource {
@@ -156,54 +295,129 @@ func newResource() Resource {
 func (r *Resource) MarshalJSON() ([]byte, error) {
 	type alias struct {
 		ResourceIdentifier
-		Attributes    map[string]json.RawMessage `json:"attributes,omitempty"`
-		Relationships map[string]any             `json:"relationships,omitempty"`
-		Links         map[string]*Link           `json:"links,omitempty"`
+		Meta          any              `json:"meta,omitempty"`
+		Attributes    any              `json:"attributes,omitempty"`
+		Relationships any              `json:"relationships,omitempty"`
+		Links         map[string]*Link `json:"links,omitempty"`
 	}
 	a := alias{
 		ResourceIdentifier: r.ResourceIdentifier,
-		Attributes:         r.Attributes,
-		Relationships:      make(map[string]any, len(r.ToOneRelationships)+len(r.ToManyRelationships)),
 		Links:              r.Links,
 	}
 
+	rels := make(map[string]any, len(r.ToOneRelationships)+len(r.ToManyRelationships))
 	for k, v := range r.ToOneRelationships {
-		a.Relationships[k] = v
+		rels[k] = v
 	}
 	for k, v := range r.ToManyRelationships {
-		a.Relationships[k] = v
+		rels[k] = v
 	}
 
-	return json.Marshal(a)
+	// unmarshaled members are kept in their original order
+	var err error
+	switch {
+	case len(r.Meta) == 0:
+	case r.metaOrder != nil:
+		if a.Meta, err = marshalOrdered(r.metaOrder, r.Meta); err != nil {
+			return nil, err
+		}
+	default:
+		a.Meta = r.Meta
+	}
+	switch {
+	case len(r.Attributes) == 0:
+	case r.attrOrder != nil:
+		if a.Attributes, err = marshalOrdered(r.attrOrder, r.Attributes); err != nil {
+			return nil, err
+		}
+	default:
+		a.Attributes = r.Attributes
+	}
+	switch {
+	case len(rels) == 0:
+	case r.relOrder != nil:
+		if a.Relationships, err = marshalOrdered(r.relOrder, rels); err != nil {
+			return nil, err
+		}
+	default:
+		a.Relationships = rels
+	}
+
+	data, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+
+	return appendMembers(data, r.Unknown)
 }
 
 func (r *Resource) UnmarshalJSON(data []byte) error {
+	return r.unmarshal(data, false)
+}
+
+// unmarshal decodes the resource object data into r. If alias is true,
+// data must be valid, and the values of r's id, attributes, meta and
+// unknown members are slices of data rather than copies.
+func (r *Resource) unmarshal(data []byte, alias bool) error {
 	type relAlias struct {
 		Meta  map[string]json.RawMessage `json:"meta"`
 		Data  json.RawMessage            `json:"data"`
 		Links map[string]*Link           `json:"links"`
 	}
 
-	type alias struct {
-		ResourceIdentifier
-		Attributes    map[string]json.RawMessage `json:"attributes"`
-		Relationships map[string]relAlias        `json:"relationships"`
-		Links         map[string]*Link           `json:"links"`
+	members, err := rawMembers(data, alias)
+	if err != nil {
+		return err
 	}
 
-	a := alias{}
+	*r = Resource{
+		ToOneRelationships:  map[string]*ToOneResourceLinkage{},
+		ToManyRelationships: map[string]*ToManyResourceLinkage{},
+	}
 
-	if err := json.Unmarshal(data, &a); err != nil {
-		return err
+	var rels map[string]relAlias
+	for name, value := range members {
+		var err error
+		switch name {
+		case "type":
+			err = json.Unmarshal(value, &r.Type)
+		case "id":
+			r.Id = value
+		case "lid":
+			err = json.Unmarshal(value, &r.Lid)
+		case "meta":
+			r.Meta, err = rawMembers(value, alias)
+			r.metaOrder = objectKeys(value)
+		case "attributes":
+			r.Attributes, err = rawMembers(value, alias)
+			r.attrOrder = objectKeys(value)
+		case "relationships":
+			err = json.Unmarshal(value, &rels)
+			r.relOrder = objectKeys(value)
+		case "links":
+			err = json.Unmarshal(value, &r.Links)
+		default:
+			if r.Unknown == nil {
+				r.Unknown = map[string]json.RawMessage{}
+			}
+			r.Unknown[name] = value
+		}
+		if err != nil {
+			return err
+		}
 	}
 
-	r.ResourceIdentifier = a.ResourceIdentifier
-	r.Attributes = a.Attributes
-	r.Links = a.Links
-	r.ToOneRelationships = map[string]*ToOneResourceLinkage{}
-	r.ToManyRelationships = map[string]*ToManyResourceLinkage{}
+	for name, rel := range rels {
+		if len(rel.Data) == 0 {
+			// no linkage, so the relationship's
+			// cardinality is unknown
+			r.ToManyRelationships[name] = &ToManyResourceLinkage{
+				Meta:  rel.Meta,
+				Links: rel.Links,
+			}
+			continue
+		}
 
-	for name, rel := range a.Relationships {
 		switch rel.Data[0] {
 		case '[':
 			ids := []ResourceIdentifier{}
@@ -215,6 +429,13 @@ func (r *Resource) UnmarshalJSON(data []byte) error {
 				Data:  ids,
 				Links: rel.Links,
 			}
+		case 'n':
+			// null linkage, as for empty to-one relationships
+			r.ToOneRelationships[name] = &ToOneResourceLinkage{
+				Meta:  rel.Meta,
+				Data:  ResourceIdentifier{Id: NullJson},
+				Links: rel.Links,
+			}
 		case '{':
 			id := ResourceIdentifier{}
 			if err := json.Unmarshal(rel.Data, &id); err != nil {
@@ -233,7 +454,7 @@ func (r *Resource) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
-func FormatResource(a any) (*Resource, error) {
+func FormatResource(a any, opts ...Option) (*Resource, error) {
 	v, err := derefValue(reflect.ValueOf(a))
 	if err != nil {
 		return nil, fmt.Errorf("jsonapi: dereferencing input: %w", err)
@@ -243,22 +464,91 @@ func FormatResource(a any) (*Resource, error) {
 		return nil, fmt.Errorf("jsonapi: %w", ErrNotStruct)
 	}
 
+	r, err := formatStruct(v, newOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+	r.pooled = false
+	return r, nil
+}
+
+// formatStruct converts the struct value v to a Resource.
+func formatStruct(v reflect.Value, o *options) (*Resource, error) {
 	fields, err := parseTags(v)
 	if err != nil {
 		return nil, fmt.Errorf("jsonapi: parsing tags: %w", err)
 	}
 
-	r := newResource()
+	typ := resourceType(v, fields, o)
+	if f, ok := fieldOfType(fields, TagValueId); ok && typ == "" {
+		return nil, fmt.Errorf("jsonapi: parsing tags: %w", &TagErr{f.structField, fmt.Errorf("required: type")})
+	}
+	o = o.forType(typ)
+	fields = o.nameMembers(fields)
+	if o.strictNames {
+		if err := checkMemberNames(fields); err != nil {
+			return nil, fmt.Errorf("jsonapi: parsing tags: %w", err)
+		}
+	}
+	excluded := o.excludedRels(v, typ)
+	writeOnly := accessRels(fields, false)
+
+	r := acquireResource()
+	r.Type = typ
+	var relFields []field
 	for _, f := range fields {
-		if err := marshalField(v, &r, f); err != nil {
-			return nil, fmt.Errorf("jsonapi: marshaling field "+f.tag.name+": %w", err)
+		if excluded[f.tag.rel] {
+			continue
+		}
+		if f.tag.typ == TagValueRel && excluded[f.tag.name] {
+			if err := excludeRel(v, f, o); err != nil {
+				return nil, fmt.Errorf("jsonapi: marshaling field "+f.tag.name+": %w", setFieldPointer(err, f))
+			}
+			continue
+		}
+		// fields that belong to relationships are marshaled
+		// once the relationships themselves have been
+		if f.tag.writeOnly || writeOnly[f.tag.rel] {
+			continue
+		}
+		if f.tag.rel != "" {
+			relFields = append(relFields, f)
+			continue
+		}
+		if err := marshalField(v, r, f, o); err != nil {
+			return nil, fmt.Errorf("jsonapi: marshaling field "+f.tag.name+": %w", setFieldPointer(err, f))
 		}
 	}
+	for _, f := range relFields {
+		if err := marshalField(v, r, f, o); err != nil {
+			return nil, fmt.Errorf("jsonapi: marshaling field "+f.tag.name+": %w", setFieldPointer(err, f))
+		}
+	}
+	if err := marshalCustomId(v, r); err != nil {
+		return nil, fmt.Errorf("jsonapi: marshaling id: %w", err)
+	}
+	if o.stringIds {
+		if err := marshalStringIds(r); err != nil {
+			return nil, fmt.Errorf("jsonapi: %w", err)
+		}
+	}
+
+	o.applyFieldset(r)
+	if o.linkBuilder != nil {
+		o.linkBuilder.Apply(r)
+	}
+	return r, nil
+}
 
-	return &r, nil
+func MarshalResource(a any, opts ...Option) ([]byte, error) {
+	o := newOptions(opts)
+	ic := o.startCall("MarshalResource", Marshaling, a)
+	data, err := marshalResource(a, o, ic)
+	ic.end(len(data), err)
+	return data, err
 }
 
-func MarshalResource(a any) ([]byte, error) {
+func marshalResource(a any, o *options, ic *instrumentedCall) ([]byte, error) {
 	v := reflect.ValueOf(a)
 
 	v, err := derefInput(v, resourceMarshalerType)
@@ -274,19 +564,14 @@ func MarshalResource(a any) ([]byte, error) {
 		return nil, fmt.Errorf("jsonapi: %w", ErrNotStruct)
 	}
 
-	fields, err := parseTags(v)
+	r, err := formatStruct(v, o)
 	if err != nil {
-		return nil, fmt.Errorf("jsonapi: parsing tags: %w", err)
-	}
-
-	r := newResource()
-	for _, f := range fields {
-		if err := marshalField(v, &r, f); err != nil {
-			return nil, fmt.Errorf("jsonapi: marshaling field "+f.tag.name+": %w", err)
-		}
+		return nil, err
 	}
+	ic.countResource(r)
 
-	data, err := json.Marshal(&r)
+	data, err := json.Marshal(r)
+	releaseResource(r)
 	if err != nil {
 		return nil, fmt.Errorf("jsonapi: marshaling resource: %w", err)
 	}
@@ -294,21 +579,40 @@ func MarshalResource(a any) ([]byte, error) {
 	return data, nil
 }
 
-func marshalField(v reflect.Value, r *Resource, f field) error {
+func marshalField(v reflect.Value, r *Resource, f field, o *options) error {
 	switch f.tag.typ {
 	case TagValueId:
-		return marshalId(v, r, f)
+		return marshalId(v, r, f, o)
+	case TagValueLid:
+		return marshalLid(v, r, f)
 	case TagValueAttr:
-		return marshalAttr(v, r, f)
+		return marshalAttr(v, r, f, o)
 	case TagValueRel:
-		return marshalRel(v, r, f)
+		if err := marshalRel(v, r, f, o); err != nil {
+			return err
+		}
+		marshalRelLinkTemplates(r, f)
+		return nil
 	case TagValueMeta:
-		return marshalMeta(v, r, f)
+		return marshalMeta(v, r, f, o)
+	case TagValueLink:
+		return marshalLink(v, r, f)
+	case TagValueAttrMap:
+		// tagged attribute fields are marshaled first, as fields
+		// are sorted by type, and so take precedence
+		return marshalCatchAll(v, r.Attributes, f)
+	case TagValueMetaMap:
+		// tagged meta fields are marshaled first, as fields are
+		// sorted by type, and so take precedence
+		return marshalCatchAll(v, r.Meta, f)
+	case TagValuePresence:
+		// only recorded when unmarshaling
+		return nil
 	}
 	return errors.New("unknown tag type " + f.tag.typ)
 }
 
-func DeformatResource(r *Resource, a any) error {
+func DeformatResource(r *Resource, a any, opts ...Option) error {
 	v := reflect.ValueOf(a)
 
 	if v.Kind() != reflect.Pointer {
@@ -324,21 +628,97 @@ func DeformatResource(r *Resource, a any) error {
 		return ErrNotStructPtr
 	}
 
+	return deformatStruct(r, v, newOptions(opts))
+}
+
+// deformatStruct stores the contents of r in the struct value v.
+func deformatStruct(r *Resource, v reflect.Value, o *options) error {
 	fields, err := parseTags(v)
 	if err != nil {
 		return fmt.Errorf("jsonapi: parsing tags: %w", err)
 	}
 
+	o = o.forType(r.Type)
+	fields = o.nameMembers(fields)
+	if o.strictNames {
+		if err := checkMemberNames(fields); err != nil {
+			return fmt.Errorf("jsonapi: parsing tags: %w", err)
+		}
+		if err := checkResourceMemberNames(r); err != nil {
+			return fmt.Errorf("jsonapi: %w", err)
+		}
+	}
+	r = renameMembers(r, o.renames)
+	if o.normalizeIds {
+		r = normalizeLinkage(r)
+	}
+	if o.stringIds {
+		if err := checkStringIds(r); err != nil {
+			return fmt.Errorf("jsonapi: %w", err)
+		}
+		fields = quoteIds(fields)
+	}
+
+	// the errors of fields, with WithAllErrors
+	var errs []error
+
+	readOnly := accessRels(fields, true)
 	for _, f := range fields {
-		if err := unmarshalField(v, r, f); err != nil {
-			return fmt.Errorf("jsonapi: unmarshaling field "+f.tag.name+": %w", err)
+		if f.tag.readOnly || readOnly[f.tag.rel] {
+			continue
+		}
+		var err error
+		switch {
+		case f.tag.notNull && isNullMember(r, f):
+			err = &UnmarshalErr{Field: f.tag.name, Err: ErrNullMember}
+		case f.tag.typ == TagValueAttrMap:
+			err = unmarshalCatchAll(v, r.Attributes, claimedNames(fields, TagValueAttr), f, o)
+		case f.tag.typ == TagValueMetaMap:
+			err = unmarshalCatchAll(v, r.Meta, claimedNames(fields, TagValueMeta), f, o)
+		case f.tag.typ == TagValuePresence:
+			err = unmarshalPresence(v, r, f)
+		default:
+			err = unmarshalField(v, r, f, o)
+		}
+		if err != nil {
+			err = fmt.Errorf("jsonapi: unmarshaling field "+f.tag.name+": %w", setFieldPointer(err, f))
+			if !o.allErrors {
+				return err
+			}
+			errs = append(errs, err)
 		}
 	}
 
-	return nil
+	if err := unmarshalCustomId(v, r); err != nil {
+		err = fmt.Errorf("jsonapi: unmarshaling id: %w", err)
+		if !o.allErrors {
+			return err
+		}
+		errs = append(errs, err)
+	}
+
+	if o.disallowUnknown {
+		if unknown := unknownMembers(r, fields); len(unknown) > 0 {
+			err := fmt.Errorf("jsonapi: %w", &UnknownMembersErr{unknown})
+			if !o.allErrors {
+				return err
+			}
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func UnmarshalResource(data []byte, a any, opts ...Option) error {
+	o := newOptions(opts)
+	ic := o.startCall("UnmarshalResource", Unmarshaling, a)
+	err := unmarshalResource(data, a, o, ic)
+	ic.end(len(data), err)
+	return err
 }
 
-func UnmarshalResource(data []byte, a any) error {
+func unmarshalResource(data []byte, a any, o *options, ic *instrumentedCall) error {
 	v := reflect.ValueOf(a)
 
 	if v.Kind() != reflect.Pointer {
@@ -358,39 +738,34 @@ func UnmarshalResource(data []byte, a any) error {
 		return ErrNotStructPtr
 	}
 
-	r := newResource()
+	r := Resource{}
 	if err := json.Unmarshal(data, &r); err != nil {
 		return fmt.Errorf("jsonapi: unmarshaling resource: %w", err)
 	}
+	ic.countResource(&r)
 
-	fields, err := parseTags(v)
-	if err != nil {
-		return fmt.Errorf("jsonapi: parsing tags: %w", err)
-	}
-
-	for _, f := range fields {
-		if err := unmarshalField(v, &r, f); err != nil {
-			return fmt.Errorf("jsonapi: unmarshaling field "+f.tag.name+": %w", err)
-		}
-	}
-	return nil
+	return deformatStruct(&r, v, o)
 }
 
-func unmarshalField(v reflect.Value, r *Resource, f field) error {
+func unmarshalField(v reflect.Value, r *Resource, f field, o *options) error {
 	switch f.tag.typ {
 	case TagValueId:
 		return unmarshalId(v, r, f)
+	case TagValueLid:
+		return unmarshalLid(v, r, f)
 	case TagValueAttr:
-		return unmarshalAttr(v, r, f)
+		return unmarshalAttr(v, r, f, o)
 	case TagValueRel:
-		return unmarshalRel(v, r, f)
+		return unmarshalRel(v, r, f, o)
 	case TagValueMeta:
-		return unmarshalMeta(v, r, f)
+		return unmarshalMeta(v, r, f, o)
+	case TagValueLink:
+		return unmarshalLink(v, r, f)
 	}
 	return nil
 }
 
-// parseTags retrieves all attributes, relationships,
+// walkTags retrieves all attributes, relationships,
 // etc from the input value.
 //   - performs a breadth-first search over the value
 //     rooted at v
@@ -400,7 +775,7 @@ func unmarshalField(v reflect.Value, r *Resource, f field) error {
 //   - modelled on the equivalent function in the
 //     encoding/json package to reduce heap allocs
 //     (see issue #1)
-func parseTags(v reflect.Value) ([]field, error) {
+func walkTags(v reflect.Value) ([]field, error) {
 	// every element in the queue represents a
 	// struct, either a type or a value
 	type structElem struct {
@@ -507,8 +882,9 @@ func parseTags(v reflect.Value) ([]field, error) {
 				}
 
 				fld := field{
-					tag:  tag,
-					idxs: fIdxs,
+					tag:         tag,
+					idxs:        fIdxs,
+					structField: f.Name,
 				}
 
 				fields = append(fields, fld)
@@ -554,6 +930,14 @@ func parseTags(v reflect.Value) ([]field, error) {
 				}
 			}
 
+			// multiple ids are only allowed if one
+			// overrides the others from a shallower depth
+			if typ == TagValueId && nName > 1 && len(fields[j].idxs) == len(fields[j+1].idxs) {
+				names := []string{fields[j].structField, fields[j+1].structField}
+				slices.Sort(names)
+				return nil, &TagErr{names[0], fmt.Errorf("id tag also declared on field '%s'", names[1])}
+			}
+
 			// if there are multiple with the same name and type,
 			// get the dominant field
 			field, ok := getDominantField(fields[j : j+nName])
@@ -568,6 +952,24 @@ func parseTags(v reflect.Value) ([]field, error) {
 	return fields[:nFiltered], nil
 }
 
+// resourceType returns the resource type of the struct value v, which
+// is that returned by its JsonApiType method if it implements
+// ResourceTyper, or otherwise that declared by the id field, if any,
+// or derived from the struct's name if the id field doesn't declare one.
+func resourceType(v reflect.Value, fields []field, o *options) string {
+	switch {
+	case v.Type().Implements(resourceTyperType):
+		return v.Interface().(ResourceTyper).JsonApiType()
+	case v.CanAddr() && v.Addr().Type().Implements(resourceTyperType):
+		return v.Addr().Interface().(ResourceTyper).JsonApiType()
+	}
+	f, ok := fieldOfType(fields, TagValueId)
+	if ok && f.tag.rscType == "" && v.Type().Name() != "" {
+		return o.typeNamer(v.Type().Name())
+	}
+	return f.tag.rscType
+}
+
 // getDominantField returns the highest precedence
 // field from the supplied list, with (zero, false)
 // indicating a that no dominant tag can be determined.
@@ -602,12 +1004,20 @@ func parseTag(f reflect.StructField, typ string, opts string) (tag, error) {
 	switch typ {
 	case TagValueId:
 		return parseIdTag(f, opts)
+	case TagValueLid:
+		return parseLidTag(f)
 	case TagValueAttr:
 		return parseAttrTag(f, opts)
 	case TagValueMeta:
 		return parseMetaTag(f, opts)
 	case TagValueRel:
 		return parseRelTag(f, opts)
+	case TagValueLink:
+		return parseLinkTag(f, opts)
+	case TagValueAttrMap, TagValueMetaMap:
+		return parseCatchAllTag(f, typ)
+	case TagValuePresence:
+		return parsePresenceTag(f)
 	default:
 		return tag{}, &TagErr{f.Name, errors.New("unknown tag type: " + typ)}
 	}
@@ -623,6 +1033,8 @@ type field struct {
 	// idxs represents this and all ancestor fields' indexes
 	// within their parent structs
 	idxs []int
+	// the declared name of the struct field
+	structField string
 }
 
 // tag represents a jsonapi struct tag
@@ -642,14 +1054,42 @@ type tag struct {
 	quote bool
 	// whether the "omitempty" flag was specified
 	omitempty bool
+	// whether the "omitzero" flag was specified
+	omitzero bool
+	// whether the "readonly" or "writeonly" flags were specified
+	readOnly  bool
+	writeOnly bool
+	// whether the "notnull" flag was specified
+	notNull bool
+	// the value of the "empty" option, if specified
+	empty EmptyPolicy
+	// the value of the "format" option of time fields, if specified,
+	// and of duration fields, which default to "string"
+	timeFormat     string
+	durationFormat string
+	// whether the "countonly" flag was specified
+	countOnly bool
+	// whether the "lid" flag was specified
+	lid bool
+	// for links and meta that belong to a relationship, the
+	// relationship's name, as specified by the "rel" option,
+	// and the member's name within the relationship
+	rel       string
+	relMember string
+	// the link templates of a relationship, by link name
+	links map[string]string
+	// the relationship of related structs that refers back,
+	// as specified by the "backref" option
+	backref string
+	// the options of attribute and meta tags after their
+	// name, for AttrMarshalers and AttrUnmarshalers
+	opts string
 }
 
 // parseIdTag parses an id tag, eg `jsonapi:"id,name,type,opt1,opt2..."`
 func parseIdTag(f reflect.StructField, opts string) (tag, error) {
+	// if the type is empty, it is derived from the struct's name
 	rscType, opts := splitFirstAndOpts(opts)
-	if rscType == "" {
-		return tag{}, &TagErr{f.Name, fmt.Errorf("required: type")}
-	}
 
 	omitempty, quote := optFlags(opts)
 
@@ -657,30 +1097,29 @@ func parseIdTag(f reflect.StructField, opts string) (tag, error) {
 		typ:       TagValueId,
 		rscType:   rscType,
 		omitempty: omitempty,
+		omitzero:  hasOpt(opts, TagValueOmitZero),
 		quote:     quote,
 	}, nil
 }
 
-func marshalId(v reflect.Value, r *Resource, f field) error {
-	r.Type = f.tag.rscType
-
-	v, err := fieldByIndex(v, f.idxs)
+func marshalId(v reflect.Value, r *Resource, f field, o *options) error {
+	fv, err := fieldByIndex(v, f.idxs)
 	if err != nil {
 		return err
 	}
 
-	v, err = derefValue(v)
+	v, err = derefValue(fv)
 	if err != nil {
 		return err
 	}
 
-	if f.tag.omitempty && isEmpty(v) {
+	if o.omitEmpty(f) && isEmpty(v) || o.omitZero(f) && isZero(fv) {
 		return nil
 	}
 
 	j, err := marshalJson(v, f.tag.quote)
 	if err != nil {
-		return &MarshalErr{f.tag.name, err}
+		return &MarshalErr{Field: f.tag.name, Err: err}
 	}
 
 	r.ResourceIdentifier.Id = j
@@ -698,43 +1137,138 @@ func unmarshalId(v reflect.Value, r *Resource, f field) error {
 	}
 
 	if err := unmarshalJson(r.ResourceIdentifier.Id, v, f.tag.quote); err != nil {
-		return &UnmarshalErr{f.tag.name, err}
+		return &UnmarshalErr{Field: f.tag.name, Err: err}
 	}
 	return nil
 }
 
+// parseLidTag parses a local id tag, ie `jsonapi:"lid"`.
+// The field must be a string, or a pointer to one.
+func parseLidTag(f reflect.StructField) (tag, error) {
+	if derefType(f.Type).Kind() != reflect.String {
+		return tag{}, &TagErr{f.Name, fmt.Errorf("lid must be a string")}
+	}
+
+	return tag{
+		typ:  TagValueLid,
+		name: TagValueLid,
+	}, nil
+}
+
+func marshalLid(v reflect.Value, r *Resource, f field) error {
+	v, err := fieldByIndex(v, f.idxs)
+	if err != nil {
+		return err
+	}
+
+	v, err = derefValue(v)
+	if err != nil {
+		return err
+	}
+
+	if v.IsValid() {
+		r.Lid = v.String()
+	}
+	return nil
+}
+
+func unmarshalLid(v reflect.Value, r *Resource, f field) error {
+	if r.Lid == "" {
+		return nil
+	}
+
+	v, err := initFieldByIndex(v, f.idxs)
+	if err != nil {
+		return err
+	}
+
+	v, err = derefValue(v)
+	if err != nil {
+		return err
+	}
+
+	v.SetString(r.Lid)
+	return nil
+}
+
 // parseAttrTag parses an attribute tag, eg `jsonapi:"attr,name,opt1,opt2..."`
 func parseAttrTag(f reflect.StructField, opts string) (tag, error) {
 	name, namePrec, opts := splitNameAndOpts(f, opts)
 	omitempty, quote := optFlags(opts)
 
-	return tag{
+	empty := emptyUnset
+	if value, ok := optValue(opts, TagValueEmpty); ok {
+		var err error
+		if empty, err = parseEmptyPolicy(value); err != nil {
+			return tag{}, &TagErr{f.Name, err}
+		}
+	}
+
+	tg := tag{
 		typ:       TagValueAttr,
 		name:      name,
 		namePrec:  namePrec,
 		omitempty: omitempty,
+		omitzero:  hasOpt(opts, TagValueOmitZero),
+		notNull:   hasOpt(opts, TagValueNotNull),
 		quote:     quote,
-	}, nil
+		empty:     empty,
+		opts:      opts,
+	}
+	if err := parseAccessOpts(f, &tg, opts); err != nil {
+		return tag{}, err
+	}
+	if err := parseFormatOpt(f, &tg, opts); err != nil {
+		return tag{}, err
+	}
+	return tg, nil
 }
 
-func marshalAttr(v reflect.Value, r *Resource, f field) error {
-	v, err := fieldByIndex(v, f.idxs)
+func marshalAttr(v reflect.Value, r *Resource, f field, o *options) error {
+	fv, err := fieldByIndex(v, f.idxs)
 	if err != nil {
 		return err
 	}
 
-	v, err = derefValue(v)
+	v, err = derefValue(fv)
 	if err != nil {
 		return err
 	}
 
-	if f.tag.omitempty && isEmpty(v) {
+	if o.omitEmpty(f) && isEmpty(v) || o.omitZero(f) && isZero(fv) || isAbsent(v) {
 		return nil
 	}
 
-	j, err := marshalJson(v, f.tag.quote)
+	empty := f.tag.empty
+	if empty == emptyUnset {
+		empty = o.emptyCollections
+	}
+	if o.alwaysInclude[f.tag.name] && empty == EmptyOmit {
+		empty = EmptyDefault
+	}
+	if empty != EmptyDefault && isEmptyCollection(v, fv.Type()) {
+		switch empty {
+		case EmptyOmit:
+			return nil
+		case EmptyAsNull:
+			if f.tag.empty != EmptyAsNull && o.omitNull(f) {
+				return nil
+			}
+			r.Attributes[f.tag.name] = NullJson
+			return nil
+		case EmptyAsCollection:
+			r.Attributes[f.tag.name] = emptyCollectionJson(fv.Type())
+			return nil
+		}
+	}
+
+	j, err := marshalMemberValue(v, f.tag)
 	if err != nil {
-		return &MarshalErr{f.tag.name, err}
+		return &MarshalErr{Field: f.tag.name, Err: err}
+	}
+
+	if o.omitNull(f) && bytes.Equal(j, NullJson) {
+		return nil
 	}
 
 	r.Attributes[f.tag.name] = j
@@ -742,18 +1276,21 @@ func marshalAttr(v reflect.Value, r *Resource, f field) error {
 	return nil
 }
 
-func unmarshalAttr(v reflect.Value, r *Resource, f field) error {
+func unmarshalAttr(v reflect.Value, r *Resource, f field, o *options) error {
 	if len(r.Attributes[f.tag.name]) == 0 {
 		return nil
 	}
+	if bytes.Equal(r.Attributes[f.tag.name], NullJson) && isNullable(v.Type().FieldByIndex(f.idxs).Type) {
+		return zeroField(v, f.idxs)
+	}
 
 	v, err := initFieldByIndex(v, f.idxs)
 	if err != nil {
 		return err
 	}
 
-	if err := unmarshalJson(r.Attributes[f.tag.name], v, f.tag.quote); err != nil {
-		return &UnmarshalErr{f.tag.name, err}
+	if err := unmarshalMemberValue(r.Attributes[f.tag.name], v, f.tag, o); err != nil {
+		return &UnmarshalErr{Field: f.tag.name, Err: err}
 	}
 	return nil
 }
@@ -763,37 +1300,95 @@ func parseRelTag(f reflect.StructField, opts string) (tag, error) {
 	name, namePrec, opts := splitNameAndOpts(f, opts)
 	rscType, opts := splitFirstAndOpts(opts)
 	if rscType == "" {
+		// related structs declare their own type
+		rscType = declaredType(relElemType(f.Type))
+	}
+	// polymorphic relationships declared on interface fields
+	// take their type from the registry instead
+	if rscType == "" && derefType(f.Type).Kind() != reflect.Interface {
 		return tag{}, &TagErr{f.Name, fmt.Errorf("required: type")}
 	}
 
 	omitempty, quote := optFlags(opts)
 
-	return tag{
+	lid := hasOpt(opts, TagValueLid)
+	if lid {
+		t := derefType(f.Type)
+		if !isToOne(reflect.Zero(t)) {
+			t = derefType(t.Elem())
+		}
+		if t.Kind() != reflect.String {
+			return tag{}, &TagErr{f.Name, fmt.Errorf("lid must be a string")}
+		}
+	}
+
+	backref, _ := optValue(opts, TagValueBackRef)
+	if backref != "" {
+		if !isResourceStruct(relElemType(f.Type)) {
+			return tag{}, &TagErr{f.Name, fmt.Errorf("backref requires related structs")}
+		}
+	}
+
+	var links map[string]string
+	for _, l := range []string{TagValueSelf, TagValueRelated} {
+		if tmpl, ok := optValue(opts, l); ok {
+			if links == nil {
+				links = map[string]string{}
+			}
+			links[l] = tmpl
+		}
+	}
+
+	tg := tag{
 		typ:       TagValueRel,
 		name:      name,
 		namePrec:  namePrec,
 		rscType:   rscType,
 		omitempty: omitempty,
+		omitzero:  hasOpt(opts, TagValueOmitZero),
+		notNull:   hasOpt(opts, TagValueNotNull),
 		quote:     quote,
-	}, nil
+		countOnly: hasOpt(opts, TagValueCountOnly),
+		lid:       lid,
+		links:     links,
+		backref:   backref,
+	}
+	if err := parseAccessOpts(f, &tg, opts); err != nil {
+		return tag{}, err
+	}
+	return tg, nil
 }
 
-func marshalRel(v reflect.Value, r *Resource, f field) error {
-	v, err := fieldByIndex(v, f.idxs)
+func marshalRel(v reflect.Value, r *Resource, f field, o *options) error {
+	fv, err := fieldByIndex(v, f.idxs)
 	if err != nil {
 		return err
 	}
 
-	v, err = derefValue(v)
+	v, err = derefValue(fv)
 	if err != nil {
 		return err
 	}
 
-	if f.tag.omitempty && isEmpty(v) {
+	if f.tag.countOnly {
+		return marshalRelCount(fv, v, r, f, o)
+	}
+
+	if o.omitEmpty(f) && isEmpty(v) || o.omitZero(f) && isZero(fv) {
 		return nil
 	}
 
-	if isToOne(v) {
+	if f.tag.rscType == "" {
+		return marshalPolymorphicRel(v, r, f, o)
+	}
+
+	// a nil pointer's cardinality is determined by its type
+	cv := v
+	if !cv.IsValid() {
+		cv = reflect.Zero(derefType(fv.Type()))
+	}
+
+	if isToOne(cv) {
 		return marshalToOneRel(v, r, f)
 	}
 
@@ -801,100 +1396,338 @@ func marshalRel(v reflect.Value, r *Resource, f field) error {
 }
 
 func marshalToOneRel(v reflect.Value, r *Resource, f field) error {
-	j, err := marshalJson(v, f.tag.quote)
+	id, err := relIdentifier(v, f)
 	if err != nil {
-		return &MarshalErr{f.tag.name, err}
+		return err
 	}
 
 	r.ToOneRelationships[f.tag.name] = &ToOneResourceLinkage{
-		Data: ResourceIdentifier{
-			Type: f.tag.rscType,
-			Id:   j,
-		},
+		Data: id,
 	}
 	return nil
 }
 
 func marshalToManyRel(v reflect.Value, r *Resource, f field) error {
+	n := 0
+	if v.IsValid() {
+		n = v.Len()
+	}
+
 	r.ToManyRelationships[f.tag.name] = &ToManyResourceLinkage{
-		Data: make([]ResourceIdentifier, v.Len()),
+		Data: make([]ResourceIdentifier, n),
 	}
 
-	for i := 0; i < v.Len(); i++ {
+	for i := 0; i < n; i++ {
 		vi, err := derefValue(v.Index(i))
 		if err != nil {
 			return err
 		}
 
-		j, err := marshalJson(vi, f.tag.quote)
+		id, err := relIdentifier(vi, f)
 		if err != nil {
-			return &MarshalErr{f.tag.name, err}
+			return err
 		}
 
-		r.ToManyRelationships[f.tag.name].Data[i] = ResourceIdentifier{
-			Type: f.tag.rscType,
-			Id:   j,
+		r.ToManyRelationships[f.tag.name].Data[i] = id
+	}
+
+	return nil
+}
+
+// marshalRelCount marshals a relationship as its number of related
+// resources, in the "count" member of its meta object, without any
+// resource linkage. The count is taken from the Counter interface
+// if the field implements it, or otherwise from the dereferenced
+// value v's length.
+func marshalRelCount(fv, v reflect.Value, r *Resource, f field, o *options) error {
+	var n int
+	switch {
+	case counter(fv) != nil:
+		n = counter(fv).Count()
+	case !v.IsValid():
+		n = 0
+	case counter(v) != nil:
+		n = counter(v).Count()
+	case v.Kind() == reflect.Slice, v.Kind() == reflect.Array, v.Kind() == reflect.Map:
+		n = v.Len()
+	default:
+		return &MarshalErr{Field: f.tag.name, Err: fmt.Errorf("cannot count %s", v.Kind())}
+	}
+
+	if o.omitEmpty(f) && n == 0 {
+		return nil
+	}
+
+	r.ToManyRelationships[f.tag.name] = &ToManyResourceLinkage{
+		Meta: map[string]json.RawMessage{
+			"count": json.RawMessage(strconv.Itoa(n)),
+		},
+	}
+	return nil
+}
+
+// counter returns v, or its address, as a Counter,
+// or nil if neither implements Counter.
+func counter(v reflect.Value) Counter {
+	if !v.IsValid() {
+		return nil
+	}
+	if v.Kind() == reflect.Pointer && v.IsNil() {
+		return nil
+	}
+	if v.Type().Implements(counterType) {
+		return v.Interface().(Counter)
+	}
+	if v.CanAddr() && v.Addr().Type().Implements(counterType) {
+		return v.Addr().Interface().(Counter)
+	}
+	return nil
+}
+
+// relElemType returns the type of the related resources of a
+// relationship field of type t, following pointers.
+func relElemType(t reflect.Type) reflect.Type {
+	t = derefType(t)
+	if !isToOne(reflect.Zero(t)) {
+		t = derefType(t.Elem())
+	}
+	return t
+}
+
+// relIdentifier returns the identifier of the related resource whose
+// id, or local id if the lid option was specified, is v.
+func relIdentifier(v reflect.Value, f field) (ResourceIdentifier, error) {
+	if f.tag.lid && v.IsValid() {
+		return ResourceIdentifier{Type: f.tag.rscType, Lid: v.String()}, nil
+	}
+
+	quote := f.tag.quote
+	if v.IsValid() && isResourceStruct(v.Type()) {
+		// related structs are identified by their ids
+		var err error
+		if v, quote, err = structId(v); err != nil {
+			return ResourceIdentifier{}, &MarshalErr{Field: f.tag.name, Err: err}
 		}
 	}
 
+	j, err := marshalJson(v, quote)
+	if err != nil {
+		return ResourceIdentifier{}, &MarshalErr{Field: f.tag.name, Err: err}
+	}
+	return ResourceIdentifier{Type: f.tag.rscType, Id: j}, nil
+}
+
+// relId returns the raw id of the related resource, or its
+// local id encoded as json if the lid option was specified.
+func relId(id ResourceIdentifier, f field) json.RawMessage {
+	if !f.tag.lid {
+		return id.Id
+	}
+	if id.Lid == "" {
+		return nil
+	}
+	j, _ := json.Marshal(id.Lid)
+	return j
+}
+
+// marshalPolymorphicRel marshals a to-one relationship declared on an
+// interface field, whose concrete value v is either a tagged struct,
+// whose id is used, or an id. In both cases the resource type is that
+// registered for v's type, or else the struct's resource type.
+func marshalPolymorphicRel(v reflect.Value, r *Resource, f field, o *options) error {
+	if !v.IsValid() {
+		r.ToOneRelationships[f.tag.name] = &ToOneResourceLinkage{
+			Data: ResourceIdentifier{Id: NullJson},
+		}
+		return nil
+	}
+
+	typ, registered := o.snapshot.TypeName(v.Type())
+
+	id, quote := v, f.tag.quote
+	if v.Kind() == reflect.Struct {
+		fields, err := parseTags(v)
+		if err != nil {
+			return err
+		}
+		if !registered {
+			typ, registered = resourceType(v, fields, o), true
+		}
+		if id, quote, err = structId(v); err != nil {
+			return &MarshalErr{Field: f.tag.name, Err: err}
+		}
+	}
+
+	if !registered {
+		return &MarshalErr{Field: f.tag.name, Err: fmt.Errorf("%w: %s", ErrUnregisteredType, v.Type())}
+	}
+
+	j, err := marshalJson(id, quote)
+	if err != nil {
+		return &MarshalErr{Field: f.tag.name, Err: err}
+	}
+
+	r.ToOneRelationships[f.tag.name] = &ToOneResourceLinkage{
+		Data: ResourceIdentifier{Type: typ, Id: j},
+	}
+	return nil
+}
+
+// unmarshalPolymorphicRel unmarshals a to-one relationship declared
+// on an interface field into a new value of the type registered for
+// the linkage's resource type. If the registered type is a struct, the
+// linkage's id is unmarshaled into its id field.
+func unmarshalPolymorphicRel(v reflect.Value, r *Resource, f field, o *options) error {
+	rel, ok := r.ToOneRelationships[f.tag.name]
+	if !ok || len(rel.Data.Id) == 0 {
+		return nil
+	}
+	if bytes.Equal(rel.Data.Id, NullJson) {
+		return zeroField(v, f.idxs)
+	}
+
+	t, ok := o.snapshot.Type(rel.Data.Type)
+	if !ok {
+		return &UnmarshalErr{Field: f.tag.name, Err: fmt.Errorf("%w: %s", ErrUnregisteredType, rel.Data.Type)}
+	}
+
+	fv, err := initFieldByIndex(v, f.idxs[:len(f.idxs)-1])
+	if err != nil {
+		return err
+	}
+	if fv, err = derefValue(fv); err != nil {
+		return err
+	}
+	fv = fv.Field(f.idxs[len(f.idxs)-1])
+	for fv.Kind() == reflect.Pointer {
+		initValue(fv)
+		fv = fv.Elem()
+	}
+
+	ptr := reflect.New(derefType(t))
+	if ptr.Elem().Kind() == reflect.Struct {
+		if err := hydrate(v, ptr.Elem(), rel.Data, f, o); err != nil {
+			return &UnmarshalErr{Field: f.tag.name, Err: err}
+		}
+	} else if err := unmarshalJson(rel.Data.Id, ptr, o.quoteRelId(f)); err != nil {
+		return &UnmarshalErr{Field: f.tag.name, Err: err}
+	}
+
+	rv := ptr.Elem()
+	if t.Kind() == reflect.Pointer {
+		rv = ptr
+	}
+	if !rv.Type().AssignableTo(fv.Type()) {
+		return &UnmarshalErr{Field: f.tag.name, Err: fmt.Errorf("%s does not implement %s", t, fv.Type())}
+	}
+	fv.Set(rv)
 	return nil
 }
 
-func unmarshalRel(v reflect.Value, r *Resource, f field) error {
+// fieldOfType returns the first field with the tag type typ.
+func fieldOfType(fields []field, typ string) (field, bool) {
+	for _, f := range fields {
+		if f.tag.typ == typ {
+			return f, true
+		}
+	}
+	return field{}, false
+}
+
+func unmarshalRel(v reflect.Value, r *Resource, f field, o *options) error {
+	if f.tag.countOnly {
+		return nil
+	}
+
+	if f.tag.rscType == "" {
+		return unmarshalPolymorphicRel(v, r, f, o)
+	}
+
 	fv, err := fieldByIndex(v, f.idxs)
 	if err != nil {
 		return err
 	}
 
 	if isToOne(fv) {
-		return unmarshalToOneRel(v, r, f)
+		return unmarshalToOneRel(v, r, f, o)
 	}
-	return unmarshalToManyRel(v, r, f)
+	return unmarshalToManyRel(v, r, f, o)
 }
 
-func unmarshalToOneRel(v reflect.Value, r *Resource, f field) error {
+func unmarshalToOneRel(v reflect.Value, r *Resource, f field, o *options) error {
 	rel, ok := r.ToOneRelationships[f.tag.name]
 	if !ok {
 		return nil
 	}
 
-	if len(rel.Data.Id) == 0 {
+	id := relId(rel.Data, f)
+	if len(id) == 0 {
 		return nil
 	}
+	if bytes.Equal(id, NullJson) {
+		return zeroField(v, f.idxs)
+	}
 
-	v, err := initFieldByIndex(v, f.idxs)
+	fv, err := initFieldByIndex(v, f.idxs)
 	if err != nil {
 		return err
 	}
 
-	if err := unmarshalJson(rel.Data.Id, v, f.tag.quote); err != nil {
-		return &UnmarshalErr{f.tag.name, err}
+	if isResourceStruct(fv.Type()) {
+		if fv, err = derefValue(fv); err != nil {
+			return err
+		}
+		if err := hydrate(v, fv, rel.Data, f, o); err != nil {
+			return &UnmarshalErr{Field: f.tag.name, Err: err}
+		}
+		return nil
+	}
+
+	if err := unmarshalJson(id, fv, o.quoteRelId(f)); err != nil {
+		return &UnmarshalErr{Field: f.tag.name, Err: err}
 	}
 	return nil
 }
 
-func unmarshalToManyRel(v reflect.Value, r *Resource, f field) error {
-	rels, ok := r.ToManyRelationships[f.tag.name]
+func unmarshalToManyRel(v reflect.Value, r *Resource, f field, o *options) error {
+	rels, ok, err := toManyLinkage(r, f, o)
 	if !ok {
-		return nil
+		return err
 	}
 
 	if len(rels.Data) == 0 {
+		// empty linkage clears the relationship when applying
+		if o.apply && rels.Data != nil {
+			return zeroField(v, f.idxs)
+		}
 		return nil
 	}
 
-	v, err := initFieldByIndex(v, f.idxs)
+	fv, err := initFieldByIndex(v, f.idxs)
 	if err != nil {
 		return err
 	}
 
-	v.Grow(len(rels.Data) - v.Cap())
-	v.SetLen(len(rels.Data))
+	// nb the slice may already have elements, when applying
+	fv.SetLen(0)
+	fv.Grow(len(rels.Data))
+	fv.SetLen(len(rels.Data))
+	hydrated := isResourceStruct(fv.Type().Elem())
 	for i, rel := range rels.Data {
-		elem := v.Index(i)
+		elem := fv.Index(i)
 		initValue(elem)
-		if err := unmarshalJson(rel.Id, elem, f.tag.quote); err != nil {
-			return &UnmarshalErr{f.tag.name, err}
+		if hydrated {
+			sv, err := derefValue(elem)
+			if err != nil {
+				return err
+			}
+			if err := hydrate(v, sv, rel, f, o); err != nil {
+				return &UnmarshalErr{Field: f.tag.name, Err: err}
+			}
+			continue
+		}
+		if err := unmarshalJson(relId(rel, f), elem, o.quoteRelId(f)); err != nil {
+			return &UnmarshalErr{Field: f.tag.name, Err: err}
 		}
 	}
 
@@ -913,40 +1746,138 @@ func parseMetaTag(f reflect.StructField, opts string) (tag, error) {
 	name, namePrec, opts := splitNameAndOpts(f, opts)
 	omitempty, quote := optFlags(opts)
 
-	return tag{
+	tg := tag{
 		typ:       TagValueMeta,
 		name:      name,
 		namePrec:  namePrec,
 		omitempty: omitempty,
+		omitzero:  hasOpt(opts, TagValueOmitZero),
+		notNull:   hasOpt(opts, TagValueNotNull),
 		quote:     quote,
-	}, nil
+		opts:      opts,
+	}
+	if err := parseRelOpt(f, &tg, opts); err != nil {
+		return tag{}, err
+	}
+	if err := parseAccessOpts(f, &tg, opts); err != nil {
+		return tag{}, err
+	}
+	if err := parseFormatOpt(f, &tg, opts); err != nil {
+		return tag{}, err
+	}
+	return tg, nil
 }
 
-func marshalMeta(v reflect.Value, r *Resource, f field) error {
-	v, err := fieldByIndex(v, f.idxs)
+// parseAccessOpts sets whether the attribute, relationship or meta
+// tag tg is read-only or write-only from the readonly and writeonly
+// options, which can't both be present.
+func parseAccessOpts(f reflect.StructField, tg *tag, opts string) error {
+	tg.readOnly = hasOpt(opts, TagValueReadOnly)
+	tg.writeOnly = hasOpt(opts, TagValueWriteOnly)
+	if tg.readOnly && tg.writeOnly {
+		return &TagErr{f.Name, fmt.Errorf("readonly and writeonly are exclusive")}
+	}
+	return nil
+}
+
+// accessRels returns the names of the read-only relationships
+// in fields if readOnly is true, or write-only ones otherwise,
+// whose links and meta fields are likewise read or write-only.
+func accessRels(fields []field, readOnly bool) map[string]bool {
+	var rels map[string]bool
+	for _, f := range fields {
+		if f.tag.typ == TagValueRel && (readOnly && f.tag.readOnly || !readOnly && f.tag.writeOnly) {
+			if rels == nil {
+				rels = map[string]bool{}
+			}
+			rels[f.tag.name] = true
+		}
+	}
+	return rels
+}
+
+// TagTypes returns the tag types, the first value of a jsonapi tag,
+// eg "attr" in `jsonapi:"attr,title"`, for tools that check tags.
+func TagTypes() []string {
+	return []string{
+		TagValueIgnore,
+		TagValueId,
+		TagValueLid,
+		TagValueAttr,
+		TagValueRel,
+		TagValueMeta,
+		TagValueLink,
+		TagValueAttrMap,
+		TagValueMetaMap,
+		TagValuePresence,
+		TagValueUnion,
+	}
+}
+
+// RelOption returns the value of the rel option in the options opts
+// of a link or meta tag, eg "comments" in "count,rel=comments", and
+// whether it's present.
+func RelOption(opts string) (string, bool) {
+	return optValue(opts, TagValueRelOpt)
+}
+
+// parseRelOpt sets the relationship of the link or meta tag tg from
+// the rel option, eg `jsonapi:"meta,count,rel=comments"`, if present.
+func parseRelOpt(f reflect.StructField, tg *tag, opts string) error {
+	rel, ok := optValue(opts, TagValueRelOpt)
+	if !ok {
+		return nil
+	}
+	if rel == "" {
+		return &TagErr{f.Name, fmt.Errorf("required: rel")}
+	}
+
+	// relationship names can't contain '.', so this
+	// doesn't clash with the resource's own members
+	tg.relMember = tg.name
+	tg.name = rel + "." + tg.name
+	tg.rel = rel
+	return nil
+}
+
+func marshalMeta(v reflect.Value, r *Resource, f field, o *options) error {
+	fv, err := fieldByIndex(v, f.idxs)
 	if err != nil {
 		return err
 	}
-	v, err = derefValue(v)
+	v, err = derefValue(fv)
 	if err != nil {
 		return err
 	}
 
-	if f.tag.omitempty && isEmpty(v) {
+	if o.omitEmpty(f) && isEmpty(v) || o.omitZero(f) && isZero(fv) || isAbsent(v) {
 		return nil
 	}
 
-	j, err := marshalJson(v, f.tag.quote)
+	j, err := marshalMemberValue(v, f.tag)
 	if err != nil {
-		return &MarshalErr{f.tag.name, err}
+		return &MarshalErr{Field: f.tag.name, Err: err}
+	}
+
+	if f.tag.rel != "" {
+		_, meta := relMembers(r, f.tag.rel)
+		if *meta == nil {
+			*meta = map[string]json.RawMessage{}
+		}
+		(*meta)[f.tag.relMember] = j
+		return nil
 	}
 
 	r.Meta[f.tag.name] = j
 	return nil
 }
 
-func unmarshalMeta(v reflect.Value, r *Resource, f field) error {
-	if len(r.Meta[f.tag.name]) == 0 {
+func unmarshalMeta(v reflect.Value, r *Resource, f field, o *options) error {
+	data := r.Meta[f.tag.name]
+	if f.tag.rel != "" {
+		data = relMeta(r, f.tag.rel)[f.tag.relMember]
+	}
+	if len(data) == 0 {
 		return nil
 	}
 
@@ -955,8 +1886,8 @@ func unmarshalMeta(v reflect.Value, r *Resource, f field) error {
 		return err
 	}
 
-	if err := unmarshalJson(r.Meta[f.tag.name], v, f.tag.quote); err != nil {
-		return &UnmarshalErr{f.tag.name, err}
+	if err := unmarshalMemberValue(data, v, f.tag, o); err != nil {
+		return &UnmarshalErr{Field: f.tag.name, Err: err}
 	}
 	return nil
 }
@@ -1022,11 +1953,42 @@ func optFlags(opts string) (bool, bool) {
 	return omitempty, quote
 }
 
+// hasOpt returns true iff the flag opt is found in opts.
+func hasOpt(opts string, opt string) bool {
+	for opts != "" {
+		var o string
+		o, opts, _ = strings.Cut(opts, ",")
+		if o == opt {
+			return true
+		}
+	}
+	return false
+}
+
+// optValue returns the value of the first "key=value"
+// option found in opts.
+func optValue(opts string, key string) (string, bool) {
+	for opts != "" {
+		opt, rest, _ := strings.Cut(opts, ",")
+		if k, value, ok := strings.Cut(opt, "="); ok && k == key {
+			return value, true
+		}
+		opts = rest
+	}
+	return "", false
+}
+
 // marshalJson marshals the value represented by v to raw json.
 func marshalJson(v reflect.Value, quote bool) (json.RawMessage, error) {
 	if !v.IsValid() {
 		return NullJson, nil
 	}
+	if sv, ok := sqlNullValue(v); ok {
+		return marshalJson(sv, quote)
+	}
+	if tm, ok := textMarshaler(v); ok {
+		return marshalText(tm)
+	}
 	jsonBts, err := json.Marshal(v.Interface())
 	if err != nil {
 		return nil, err
@@ -1044,14 +2006,36 @@ func unmarshalJson(data json.RawMessage, v reflect.Value, quote bool) error {
 		return nil
 	}
 
-	if quote && quotable(v.Kind()) {
-		data = data[1 : len(data)-1]
-	}
-
 	for v.Kind() == reflect.Pointer {
 		v = v.Elem()
 	}
 
+	if isSqlNull(v.Type()) {
+		if bytes.Equal(data, NullJson) {
+			v.SetZero()
+			return nil
+		}
+		if err := unmarshalJson(data, v.Field(0), quote); err != nil {
+			return err
+		}
+		v.Field(1).SetBool(true)
+		return nil
+	}
+
+	if isTextUnmarshaler(v.Type()) {
+		if !v.CanAddr() {
+			return fmt.Errorf("unaddressable value")
+		}
+		return json.Unmarshal(data, v.Addr().Interface())
+	}
+
+	if quote && quotable(v.Kind()) {
+		var err error
+		if data, err = UnquoteMember(data, v.Kind().String()); err != nil {
+			return err
+		}
+	}
+
 	if !v.CanAddr() {
 		return fmt.Errorf("unaddressable value")
 	}
@@ -1088,11 +2072,13 @@ func unmarshalJson(data json.RawMessage, v reflect.Value, quote bool) error {
 		}
 		v.SetString(s)
 	case reflect.Struct, reflect.Array, reflect.Slice, reflect.Map:
-		var s = reflect.New(v.Type()).Interface()
-		if err := json.Unmarshal(data, &s); err != nil {
+		// nb unmarshal into the pointer directly, so that
+		// null reaches the type's UnmarshalJSON, if any
+		s := reflect.New(v.Type())
+		if err := json.Unmarshal(data, s.Interface()); err != nil {
 			return err
 		}
-		v.Set(reflect.ValueOf(s).Elem())
+		v.Set(s.Elem())
 	case reflect.Interface:
 		// if the interface has been initialised, unmarshal
 		// into the supplied value
@@ -1130,13 +2116,17 @@ func quotable(k reflect.Kind) bool {
 
 // isEmpty returns true iff the value is should be omitted
 // when the omitempty flag is set, ie if it is not valid,
-// zero, or an empty array, slice or map.
+// zero, an empty array, slice or map, or an sql nullable
+// that is not valid.
 // NB assumes that the input has been derefernced eg with
 // derefValue.
 func isEmpty(v reflect.Value) bool {
 	if !v.IsValid() || v.IsZero() {
 		return true
 	}
+	if isSqlNull(v.Type()) {
+		return !v.Field(1).Bool()
+	}
 
 	switch v.Kind() {
 	case reflect.Array, reflect.Slice, reflect.Map:
@@ -1146,6 +2136,53 @@ func isEmpty(v reflect.Value) bool {
 	}
 }
 
+// isZero returns true iff the value should be omitted when the
+// omitzero flag is set, ie if it is not valid, or it is zero,
+// as reported by its IsZero method if it has one. Unlike isEmpty,
+// non-nil empty slices and maps, and pointers to zero values, are
+// not zero.
+// NB expects the field's value, before dereferencing.
+func isZero(v reflect.Value) bool {
+	if !v.IsValid() {
+		return true
+	}
+	if v.Kind() == reflect.Pointer && v.IsNil() {
+		return true
+	}
+	if z, ok := v.Interface().(interface{ IsZero() bool }); ok {
+		return z.IsZero()
+	}
+	if v.CanAddr() {
+		if z, ok := v.Addr().Interface().(interface{ IsZero() bool }); ok {
+			return z.IsZero()
+		}
+	}
+	return v.IsZero()
+}
+
+// isEmptyCollection returns true iff the field of declared
+// type t is a map or a non-byte slice (or a pointer to one of
+// these), and the dereferenced value v is nil or has no elements.
+func isEmptyCollection(v reflect.Value, t reflect.Type) bool {
+	t = derefType(t)
+	switch {
+	case t.Kind() == reflect.Map:
+	case t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8:
+	default:
+		return false
+	}
+	return !v.IsValid() || v.Len() == 0
+}
+
+// emptyCollectionJson returns the empty json array or
+// object corresponding to the collection type t.
+func emptyCollectionJson(t reflect.Type) json.RawMessage {
+	if derefType(t).Kind() == reflect.Map {
+		return json.RawMessage("{}")
+	}
+	return json.RawMessage("[]")
+}
+
 // derefInput returns either:
 // - the underlying value of v, found by following all pointers, or
 // - an instance of type t, if one of the dereferenced values implements it.
@@ -1187,6 +2224,34 @@ func fieldByIndex(v reflect.Value, idxs []int) (reflect.Value, error) {
 	return v, nil
 }
 
+// isNullable returns true if t has a nil value.
+func isNullable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Interface:
+		return true
+	default:
+		return false
+	}
+}
+
+// zeroField sets the field found by following the nested struct
+// fields defined by idxs to its zero value, unless a nil pointer
+// is found on the path.
+func zeroField(v reflect.Value, idxs []int) error {
+	var err error
+	for _, idx := range idxs {
+		if v, err = derefValue(v); err != nil {
+			return err
+		}
+		if !v.IsValid() {
+			return nil
+		}
+		v = v.Field(idx)
+	}
+	v.Set(reflect.Zero(v.Type()))
+	return nil
+}
+
 // initFieldByIndex takes a value v and an array of indexes idxs, and
 // initialises the struct field found in v at index idxs[0], then the
 // struct field found at idxs[1] in the newly intialised struct, etc.
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -207,6 +208,77 @@ func TestUnmarshalResource_RscId_EmptyJson(t *testing.T) {
 	}
 }
 
+// resource with a local id, and relationships
+// to other resources by local id
+type rscLid struct {
+	Id       string   `jsonapi:"id,articles,omitempty"`
+	Lid      string   `jsonapi:"lid"`
+	Author   string   `jsonapi:"rel,author,people,lid"`
+	Comments []string `jsonapi:"rel,comments,comments,lid"`
+	Editor   *string  `jsonapi:"rel,editor,people,lid"`
+	Tags     []int    `jsonapi:"rel,tags,tags,string"`
+}
+
+var rscLidValue = rscLid{
+	Lid:      "local-1",
+	Author:   "local-2",
+	Comments: []string{"local-3", "local-4"},
+	Editor:   addrOf("local-5"),
+	Tags:     []int{6},
+}
+
+const rscLidJson = `
+{
+	"type": "articles",
+	"lid": "local-1",
+	"relationships": {
+		"author": {"data": {"type": "people", "lid": "local-2"}},
+		"comments": {"data": [{"type": "comments", "lid": "local-3"}, {"type": "comments", "lid": "local-4"}]},
+		"editor": {"data": {"type": "people", "lid": "local-5"}},
+		"tags": {"data": [{"type": "tags", "id": "6"}]}
+	}
+}`
+
+func TestMarshalResource_Lid(t *testing.T) {
+	got, err := MarshalResource(&rscLidValue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, fmtJson(t, []byte(rscLidJson)), fmtJson(t, got))
+}
+
+func TestUnmarshalResource_Lid(t *testing.T) {
+	got := rscLid{}
+	if err := UnmarshalResource([]byte(rscLidJson), &got); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, rscLidValue, got)
+
+	// related resources identified by id are
+	// not unmarshaled into lid relationships
+	got = rscLid{}
+	data := `{"type": "articles", "id": "1", "relationships": {"author": {"data": {"type": "people", "id": "2"}}}}`
+	if err := UnmarshalResource([]byte(data), &got); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, rscLid{Id: "1"}, got)
+}
+
+func TestMarshalResource_Lid_TagErr(t *testing.T) {
+	type lidInt struct {
+		Lid int `jsonapi:"lid"`
+	}
+	type relLidInt struct {
+		Rel []int `jsonapi:"rel,rel,type,lid"`
+	}
+
+	for _, in := range []any{&lidInt{}, &relLidInt{}} {
+		_, err := MarshalResource(in)
+		var tagErr *TagErr
+		assert.ErrorAs(t, err, &tagErr)
+	}
+}
+
 // attributes of all primitive types
 type attrsPrimitive struct {
 	Bool      bool    `jsonapi:"attr,bool"`
@@ -518,6 +590,140 @@ func TestMarshalResource_Attrs_OmitEmpty(t *testing.T) {
 	assert.Equal(t, fmtJson(t, []byte(want)), fmtJson(t, got))
 }
 
+type ptrZeroer struct{ N int }
+
+func (z *ptrZeroer) IsZero() bool { return z.N < 0 }
+
+func TestMarshalResource_OmitZero(t *testing.T) {
+	type tp struct {
+		Id         string            `jsonapi:"id,tps,omitzero"`
+		String     string            `jsonapi:"attr,string,omitzero"`
+		NilPtr     *string           `jsonapi:"attr,nilPtr,omitzero"`
+		ZeroPtr    *string           `jsonapi:"attr,zeroPtr,omitzero"`
+		Struct     simpleStruct      `jsonapi:"attr,struct,omitzero"`
+		Time       time.Time         `jsonapi:"attr,time,omitzero"`
+		PtrZeroer  ptrZeroer         `jsonapi:"attr,ptrZeroer,omitzero"`
+		EmptySlice []int             `jsonapi:"attr,emptySlice,omitzero"`
+		EmptyMap   map[string]string `jsonapi:"attr,emptyMap,omitzero"`
+		Rel        string            `jsonapi:"rel,rel,rels,omitzero"`
+		Meta       int               `jsonapi:"meta,meta,omitzero"`
+	}
+
+	in := &tp{
+		ZeroPtr:    addrOf(""),
+		Time:       time.Time{}.In(time.FixedZone("", 3600)),
+		PtrZeroer:  ptrZeroer{-1},
+		EmptySlice: []int{},
+		EmptyMap:   map[string]string{},
+	}
+
+	got, err := MarshalResource(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"type": "tps", "attributes": {"zeroPtr": "", "emptySlice": [], "emptyMap": {}}}`
+	assert.Equal(t, fmtJson(t, []byte(want)), fmtJson(t, got))
+
+	got, err = MarshalResource(in, WithAlwaysInclude("meta"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want = `{"type": "tps", "attributes": {"zeroPtr": "", "emptySlice": [], "emptyMap": {}}, "meta": {"meta": 0}}`
+	assert.Equal(t, fmtJson(t, []byte(want)), fmtJson(t, got))
+}
+
+func TestMarshalResource_Attrs_OmitNullAttributes(t *testing.T) {
+	type tp struct {
+		String    string            `jsonapi:"attr,string"`
+		IntPtr    *int              `jsonapi:"attr,intPtr"`
+		StructPtr *simpleStruct     `jsonapi:"attr,structPtr"`
+		Iface     any               `jsonapi:"attr,iface"`
+		Slice     []int             `jsonapi:"attr,slice"`
+		Map       map[string]string `jsonapi:"attr,map"`
+		Meta      *int              `jsonapi:"meta,meta"`
+	}
+
+	got, err := MarshalResource(&tp{}, WithOmitNullAttributes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// meta is not affected
+	want := `{"attributes": {"string": ""}, "meta": {"meta": null}}`
+
+	assert.Equal(t, fmtJson(t, []byte(want)), fmtJson(t, got))
+}
+
+func TestMarshalResource_Attrs_EmptyCollections(t *testing.T) {
+	type tp struct {
+		NilSlice   []int             `jsonapi:"attr,nilSlice"`
+		EmptySlice []int             `jsonapi:"attr,emptySlice"`
+		NilMap     map[string]int    `jsonapi:"attr,nilMap"`
+		EmptyMap   map[string]int    `jsonapi:"attr,emptyMap"`
+		NilPtr     *[]int            `jsonapi:"attr,nilPtr"`
+		Full       []int             `jsonapi:"attr,full"`
+		Bytes      []byte            `jsonapi:"attr,bytes"`
+		Null       []int             `jsonapi:"attr,null,empty=null"`
+		Collection map[string]string `jsonapi:"attr,collection,empty=collection"`
+		Omit       []int             `jsonapi:"attr,omit,empty=omit"`
+		Default    []int             `jsonapi:"attr,default,empty=default"`
+	}
+
+	in := &tp{
+		EmptySlice: []int{},
+		EmptyMap:   map[string]int{},
+		Full:       []int{1},
+		Null:       []int{},
+		Collection: map[string]string{},
+		Omit:       []int{},
+		Default:    []int{},
+	}
+
+	type testCase struct {
+		Opts []Option
+		Exp  string
+	}
+
+	testCases := []testCase{
+		{nil, `{"attributes": {
+			"nilSlice": null, "emptySlice": [], "nilMap": null, "emptyMap": {}, "nilPtr": null,
+			"full": [1], "bytes": null, "null": null, "collection": {}, "default": []}}`},
+		{[]Option{WithEmptyCollections(EmptyAsCollection)}, `{"attributes": {
+			"nilSlice": [], "emptySlice": [], "nilMap": {}, "emptyMap": {}, "nilPtr": [],
+			"full": [1], "bytes": null, "null": null, "collection": {}, "default": []}}`},
+		{[]Option{WithEmptyCollections(EmptyAsNull)}, `{"attributes": {
+			"nilSlice": null, "emptySlice": null, "nilMap": null, "emptyMap": null, "nilPtr": null,
+			"full": [1], "bytes": null, "null": null, "collection": {}, "default": []}}`},
+		{[]Option{WithEmptyCollections(EmptyOmit)}, `{"attributes": {
+			"full": [1], "bytes": null, "null": null, "collection": {}, "default": []}}`},
+		// explicit per-field null is kept
+		{[]Option{WithEmptyCollections(EmptyAsNull), WithOmitNullAttributes()}, `{"attributes": {
+			"full": [1], "null": null, "collection": {}, "default": []}}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run("", func(t *testing.T) {
+			got, err := MarshalResource(in, tc.Opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, fmtJson(t, []byte(tc.Exp)), fmtJson(t, got))
+		})
+	}
+}
+
+func TestMarshalResource_Attrs_EmptyCollections_TagErr(t *testing.T) {
+	type tp struct {
+		Slice []int `jsonapi:"attr,slice,empty=xxx"`
+	}
+
+	data, err := MarshalResource(&tp{})
+	assert.Empty(t, data)
+	assert.ErrorAs(t, err, addrOf(&TagErr{}))
+}
+
 func TestUnmarshalResource_Attrs_CompositePtr(t *testing.T) {
 	got := &attrsCompositePtr{}
 	if err := UnmarshalResource([]byte(attrsCompositeJson), got); err != nil {
@@ -1047,6 +1253,118 @@ func TestMarshalResource_ToManyRel_OmitEmpty(t *testing.T) {
 	assert.Equal(t, fmtJson(t, []byte(want)), fmtJson(t, got))
 }
 
+func TestMarshalResource_ToManyRel_NilPtr(t *testing.T) {
+	type tp struct {
+		PtrSliceString *[]string `jsonapi:"rel,ptr-[]string,rel-string"`
+	}
+
+	// a nil pointer to a slice is an empty to-many relationship
+	got, err := MarshalResource(&tp{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"relationships": {"ptr-[]string": {"data": []}}}`
+	assert.Equal(t, fmtJson(t, []byte(want)), fmtJson(t, got))
+}
+
+func TestUnmarshalResource_StringPtr(t *testing.T) {
+	type tp struct {
+		Id    *int `jsonapi:"id,type,string"`
+		Count *int `jsonapi:"attr,count,string"`
+	}
+
+	in := `{"type": "type", "id": "1", "attributes": {"count": "2"}}`
+
+	got := tp{}
+	if err := UnmarshalResource([]byte(in), &got); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, tp{Id: addrOf(1), Count: addrOf(2)}, got)
+}
+
+type countedIds struct {
+	n int
+}
+
+func (c *countedIds) Count() int {
+	return c.n
+}
+
+func TestMarshalResource_ToManyRel_CountOnly(t *testing.T) {
+	type tp struct {
+		Slice      []string       `jsonapi:"rel,slice,rel-string,countonly"`
+		NilSlice   []string       `jsonapi:"rel,nilSlice,rel-string,countonly"`
+		Array      [2]int         `jsonapi:"rel,array,rel-int,countonly"`
+		Map        map[string]int `jsonapi:"rel,map,rel-int,countonly"`
+		Counter    countedIds     `jsonapi:"rel,counter,rel-int,countonly"`
+		CounterPtr *countedIds    `jsonapi:"rel,counterPtr,rel-int,countonly"`
+		NilCounter *countedIds    `jsonapi:"rel,nilCounter,rel-int,countonly"`
+		OmitEmpty  []string       `jsonapi:"rel,omitEmpty,rel-string,countonly,omitempty"`
+		Linked     []string       `jsonapi:"rel,linked,rel-string"`
+	}
+
+	in := &tp{
+		Slice:      []string{"a", "b", "c"},
+		Map:        map[string]int{"a": 1},
+		Counter:    countedIds{5},
+		CounterPtr: &countedIds{1000000},
+		Linked:     []string{"a"},
+	}
+
+	got, err := MarshalResource(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `
+	{
+		"relationships": {
+			"slice": {"meta": {"count": 3}},
+			"nilSlice": {"meta": {"count": 0}},
+			"array": {"meta": {"count": 2}},
+			"map": {"meta": {"count": 1}},
+			"counter": {"meta": {"count": 5}},
+			"counterPtr": {"meta": {"count": 1000000}},
+			"nilCounter": {"meta": {"count": 0}},
+			"linked": {"data": [{"type": "rel-string", "id": "a"}]}
+		}
+	}`
+	assert.Equal(t, fmtJson(t, []byte(want)), fmtJson(t, got))
+}
+
+func TestMarshalResource_ToManyRel_CountOnly_Err(t *testing.T) {
+	type tp struct {
+		Int int `jsonapi:"rel,int,rel-int,countonly"`
+	}
+
+	_, err := MarshalResource(&tp{})
+	var marshalErr *MarshalErr
+	assert.ErrorAs(t, err, &marshalErr)
+}
+
+func TestUnmarshalResource_ToManyRel_CountOnly(t *testing.T) {
+	type tp struct {
+		Slice []string `jsonapi:"rel,slice,rel-string,countonly"`
+		Other []string `jsonapi:"rel,other,rel-string"`
+	}
+
+	in := `{"relationships": {"slice": {"meta": {"count": 3}}, "other": {"meta": {"count": 1}}}}`
+
+	got := tp{}
+	if err := UnmarshalResource([]byte(in), &got); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, tp{}, got)
+
+	r := Resource{}
+	if err := json.Unmarshal([]byte(in), &r); err != nil {
+		t.Fatal(err)
+	}
+	assert.Nil(t, r.ToManyRelationships["slice"].Data)
+	assert.Equal(t, json.RawMessage("3"), r.ToManyRelationships["slice"].Meta["count"])
+}
+
 func TestUnmarshalResource_ToManyRels_EmptyJson(t *testing.T) {
 	type testCase struct {
 		In       any
@@ -1417,6 +1735,56 @@ func TestMarshalResource_Meta_OmitEmpty(t *testing.T) {
 	assert.Equal(t, fmtJson(t, []byte(want)), fmtJson(t, got))
 }
 
+type relMetaStruct struct {
+	Id            string   `jsonapi:"id,articles"`
+	Author        string   `jsonapi:"rel,author,people"`
+	AuthorSince   int      `jsonapi:"meta,since,rel=author"`
+	Comments      []string `jsonapi:"rel,comments,comments"`
+	CommentsTotal int      `jsonapi:"meta,total,rel=comments,string"`
+	CommentsPages *int     `jsonapi:"meta,pages,rel=comments,omitempty"`
+	Tags          []string `jsonapi:"rel,tags,tags,omitempty"`
+	TagsTotal     int      `jsonapi:"meta,total,rel=tags"`
+	Views         int      `jsonapi:"meta,total"`
+}
+
+var relMetaValue = relMetaStruct{
+	Id:            "1",
+	Author:        "2",
+	AuthorSince:   2020,
+	Comments:      []string{"3"},
+	CommentsTotal: 40,
+	TagsTotal:     5,
+	Views:         100,
+}
+
+const relMetaJson = `
+{
+	"type": "articles",
+	"id": "1",
+	"relationships": {
+		"author": {"data": {"type": "people", "id": "2"}, "meta": {"since": 2020}},
+		"comments": {"data": [{"type": "comments", "id": "3"}], "meta": {"total": "40"}},
+		"tags": {"meta": {"total": 5}}
+	},
+	"meta": {"total": 100}
+}`
+
+func TestMarshalResource_RelMeta(t *testing.T) {
+	got, err := MarshalResource(relMetaValue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, fmtJson(t, []byte(relMetaJson)), fmtJson(t, got))
+}
+
+func TestUnmarshalResource_RelMeta(t *testing.T) {
+	got := relMetaStruct{}
+	if err := UnmarshalResource([]byte(relMetaJson), &got); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, relMetaValue, got)
+}
+
 type noJsonKey struct {
 	A1 int `jsonapi:"attr"`
 	A2 int `jsonapi:"attr,,omitempty"`
@@ -1670,7 +2038,6 @@ func TestUnmarshalResource_AnonymousUnexportedExportedField(t *testing.T) {
 }
 
 type PromotionPrecedence1 struct {
-	Id      string  `jsonapi:"id,type"`
 	Int     int     `jsonapi:"attr,int"`
 	String  string  `jsonapi:"attr"`
 	Float32 float32 `json:"flt32"`
@@ -1680,7 +2047,6 @@ type PromotionPrecedence1 struct {
 }
 
 type PromotionPrecedence2 struct {
-	Id      int     `jsonapi:"id,type"`
 	Int     int     `jsonapi:"attr,int"`
 	String  string  `jsonapi:"attr"`
 	Float32 float32 `json:"flt32"`
@@ -1696,7 +2062,6 @@ type promotionPrecedence struct {
 
 var promotionPrecedenceMarshalValue = promotionPrecedence{
 	PromotionPrecedence1: PromotionPrecedence1{
-		Id:      "id",
 		Int:     1,
 		String:  "2",
 		Float32: 3.1,
@@ -1705,7 +2070,6 @@ var promotionPrecedenceMarshalValue = promotionPrecedence{
 		Uint16:  6,
 	},
 	PromotionPrecedence2: PromotionPrecedence2{
-		Id:      7,
 		Int:     8,
 		String:  "9",
 		Float32: 10.1,
@@ -1748,6 +2112,47 @@ func TestUnmarshalResource_PromotionPrecedence(t *testing.T) {
 	assert.Equal(t, promotionPrecedenceUnmarshalValue, got)
 }
 
+type DuplicateId1 struct {
+	Id string `jsonapi:"id,type"`
+}
+
+type DuplicateId2 struct {
+	Key string `jsonapi:"id,type"`
+}
+
+func TestMarshalResource_DuplicateId(t *testing.T) {
+	type sameStruct struct {
+		Id  string `jsonapi:"id,type"`
+		Key string `jsonapi:"id,type"`
+	}
+	type sameDepthAnonymous struct {
+		DuplicateId1
+		DuplicateId2
+	}
+	type overridden struct {
+		DuplicateId1
+		Key string `jsonapi:"id,type"`
+	}
+
+	for _, in := range []any{sameStruct{}, sameDepthAnonymous{}} {
+		t.Run(fmt.Sprintf("%T", in), func(t *testing.T) {
+			_, err := MarshalResource(in)
+			assert.ErrorContains(t, err, "tag error on field 'Id': id tag also declared on field 'Key'")
+
+			err = UnmarshalResource([]byte(`{"type": "type", "id": "1"}`), &in)
+			var tagErr *TagErr
+			assert.ErrorAs(t, err, &tagErr)
+		})
+	}
+
+	// the anonymous field's id is overridden
+	got, err := MarshalResource(overridden{DuplicateId1{"1"}, "2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, fmtJson(t, []byte(`{"type": "type", "id": "2"}`)), fmtJson(t, got))
+}
+
 type AnonymousEliminationBase struct {
 	SimpleIface
 	Flt float64 `jsonapi:"attr,flt"`
@@ -2609,6 +3014,47 @@ func TestUnmarshalResource_StringTag(t *testing.T) {
 	assert.Equal(t, stringTagValue, got)
 }
 
+type typedModel struct {
+	kind string
+	Id   string `jsonapi:"id,models"`
+}
+
+func (m typedModel) JsonApiType() string {
+	return m.kind
+}
+
+type ptrTypedModel struct {
+	Id string `jsonapi:"id,models"`
+}
+
+func (*ptrTypedModel) JsonApiType() string {
+	return "ptr-models"
+}
+
+func TestMarshalResource_ResourceTyper(t *testing.T) {
+	type testCase struct {
+		In       any
+		Expected string
+	}
+
+	testCases := []testCase{
+		{typedModel{"widgets", "1"}, `{"type": "widgets", "id": "1"}`},
+		{&typedModel{"gadgets", "1"}, `{"type": "gadgets", "id": "1"}`},
+		{&ptrTypedModel{"1"}, `{"type": "ptr-models", "id": "1"}`},
+		{polyComment{"1", typedModel{"widgets", "2"}}, `{"type": "comments", "id": "1", "relationships": {"commentable": {"data": {"type": "widgets", "id": "2"}}}}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run("", func(t *testing.T) {
+			got, err := MarshalResource(tc.In)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, fmtJson(t, []byte(tc.Expected)), fmtJson(t, got))
+		})
+	}
+}
+
 func TestSplitTypeAndOpts(t *testing.T) {
 	type testType struct {
 		I int `jsonapi:"attr,name,omitempty"`
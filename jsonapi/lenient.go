@@ -0,0 +1,44 @@
+package jsonapi
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// ErrNotToMany is returned when unmarshaling a to-one
+// relationship's linkage into a to-many relationship field.
+var ErrNotToMany = fmt.Errorf("relationship linkage is not a collection")
+
+// WithSingleAsCollection makes unmarshaling accept a single resource
+// where a collection is expected, as sent by some legacy servers, and
+// treat it as a one-element collection. This applies to primary data
+// unmarshaled into slices, and to to-one linkage of to-many relationship
+// fields, whose null linkage is treated as empty. Without it, these
+// fail with ErrNotCollection and ErrNotToMany respectively.
+func WithSingleAsCollection() Option {
+	return func(o *options) {
+		o.singleAsCollection = true
+	}
+}
+
+// toManyLinkage returns the linkage of the to-many relationship
+// field f, converting to-one linkage if o allows it.
+func toManyLinkage(r *Resource, f field, o *options) (*ToManyResourceLinkage, bool, error) {
+	if rels, ok := r.ToManyRelationships[f.tag.name]; ok {
+		return rels, true, nil
+	}
+
+	rel, ok := r.ToOneRelationships[f.tag.name]
+	if !ok || len(rel.Data.Id) == 0 && rel.Data.Lid == "" {
+		return nil, false, nil
+	}
+	if !o.singleAsCollection {
+		return nil, false, &UnmarshalErr{Field: f.tag.name, Err: ErrNotToMany}
+	}
+
+	rels := &ToManyResourceLinkage{Links: rel.Links, Meta: rel.Meta, Data: []ResourceIdentifier{}}
+	if !bytes.Equal(rel.Data.Id, NullJson) {
+		rels.Data = append(rels.Data, rel.Data)
+	}
+	return rels, true, nil
+}
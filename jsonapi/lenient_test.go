@@ -0,0 +1,75 @@
+package jsonapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type lenientArticle struct {
+	Id   string   `jsonapi:"id,articles"`
+	Tags []string `jsonapi:"rel,tags,tags"`
+}
+
+func TestUnmarshalDocument_SingleAsCollection(t *testing.T) {
+	in := `{"data": {"type": "articles", "id": "1"}}`
+
+	got := []lenientArticle{}
+	err := UnmarshalDocument([]byte(in), &got)
+	assert.ErrorIs(t, err, ErrNotCollection)
+
+	if err := UnmarshalDocument([]byte(in), &got, WithSingleAsCollection()); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []lenientArticle{{Id: "1"}}, got)
+}
+
+func TestUnmarshalResource_SingleAsCollection(t *testing.T) {
+	type testCase struct {
+		Json     string
+		Expected lenientArticle
+	}
+
+	testCases := []testCase{
+		{`{"type": "tags", "id": "a"}`, lenientArticle{Id: "1", Tags: []string{"a"}}},
+		{`null`, lenientArticle{Id: "1"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Json, func(t *testing.T) {
+			in := `{"type": "articles", "id": "1", "relationships": {"tags": {"data": ` + tc.Json + `}}}`
+
+			err := UnmarshalResource([]byte(in), &lenientArticle{})
+			assert.ErrorIs(t, err, ErrNotToMany)
+			assert.ErrorAs(t, err, addrOf(&UnmarshalErr{}))
+
+			got := lenientArticle{}
+			if err := UnmarshalResource([]byte(in), &got, WithSingleAsCollection()); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tc.Expected, got)
+		})
+	}
+}
+
+func TestUnmarshalResource_SingleAsCollection_Apply(t *testing.T) {
+	got := lenientArticle{Id: "1", Tags: []string{"a", "b"}}
+	in := `{"data": {"type": "articles", "id": "1", "relationships": {"tags": {"data": null}}}}`
+	if err := ApplyDocument([]byte(in), &got, WithSingleAsCollection()); err != nil {
+		t.Fatal(err)
+	}
+	assert.Empty(t, got.Tags)
+}
+
+func TestUnmarshalRelationship_SingleAsCollection(t *testing.T) {
+	in := `{"data": {"type": "tags", "id": "a"}}`
+
+	got := lenientArticle{}
+	err := UnmarshalRelationship([]byte(in), &got, "tags")
+	assert.ErrorAs(t, err, addrOf(&UnmarshalErr{}))
+
+	if err := UnmarshalRelationship([]byte(in), &got, "tags", WithSingleAsCollection()); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []string{"a"}, got.Tags)
+}
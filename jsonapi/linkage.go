@@ -0,0 +1,75 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// MetaInto stores the linkage's meta in the value pointed to by a,
+// as though the meta object were unmarshaled into it by encoding/json.
+// Linkage without meta leaves a unchanged.
+func (l *ToOneResourceLinkage) MetaInto(a any) error {
+	return metaInto(l.Meta, a)
+}
+
+// Link returns the linkage's link called name,
+// and whether it has one.
+func (l *ToOneResourceLinkage) Link(name string) (*Link, bool) {
+	link, ok := l.Links[name]
+	return link, ok
+}
+
+// Ids returns the id of the related resource, as a one-element slice,
+// or an empty slice for null linkage, or linkage with only a lid.
+func (l *ToOneResourceLinkage) Ids() []string {
+	return identifierIds([]ResourceIdentifier{l.Data})
+}
+
+// MetaInto stores the linkage's meta in the value pointed to by a,
+// as though the meta object were unmarshaled into it by encoding/json.
+// Linkage without meta leaves a unchanged.
+func (l *ToManyResourceLinkage) MetaInto(a any) error {
+	return metaInto(l.Meta, a)
+}
+
+// Link returns the linkage's link called name,
+// and whether it has one.
+func (l *ToManyResourceLinkage) Link(name string) (*Link, bool) {
+	link, ok := l.Links[name]
+	return link, ok
+}
+
+// Ids returns the ids of the related resources, in order, skipping
+// those identified only by a lid.
+func (l *ToManyResourceLinkage) Ids() []string {
+	return identifierIds(l.Data)
+}
+
+// metaInto unmarshals each member of meta into the
+// corresponding field or element of the value pointed to by a.
+func metaInto(meta map[string]json.RawMessage, a any) error {
+	if len(meta) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return fmt.Errorf("jsonapi: marshaling meta: %w", err)
+	}
+	if err := json.Unmarshal(data, a); err != nil {
+		return fmt.Errorf("jsonapi: unmarshaling meta: %w", err)
+	}
+	return nil
+}
+
+// identifierIds returns the ids of the identifiers, as strings,
+// skipping null identifiers and those with only a lid.
+func identifierIds(ids []ResourceIdentifier) []string {
+	s := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if len(id.Id) == 0 || isNullIdentifier(id) {
+			continue
+		}
+		s = append(s, identifierNode(id).Id)
+	}
+	return s
+}
@@ -0,0 +1,83 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const linkageResourceJson = `
+{
+	"type": "articles",
+	"id": "1",
+	"relationships": {
+		"author": {
+			"data": {"type": "people", "id": "2"},
+			"meta": {"since": 2020, "role": "editor"},
+			"links": {"related": "/articles/1/author"}
+		},
+		"editor": {"data": null},
+		"tags": {
+			"data": [{"type": "tags", "id": "3"}, {"type": "tags", "id": 4}, {"type": "tags", "lid": "new"}],
+			"meta": {"total": 3},
+			"links": {"self": {"href": "/articles/1/relationships/tags"}}
+		}
+	}
+}`
+
+func TestResourceLinkage_Accessors(t *testing.T) {
+	r := Resource{}
+	if err := json.Unmarshal([]byte(linkageResourceJson), &r); err != nil {
+		t.Fatal(err)
+	}
+
+	author := r.ToOneRelationships["author"]
+	assert.Equal(t, []string{"2"}, author.Ids())
+
+	meta := struct {
+		Since int    `json:"since"`
+		Role  string `json:"role"`
+	}{}
+	if err := author.MetaInto(&meta); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 2020, meta.Since)
+	assert.Equal(t, "editor", meta.Role)
+
+	l, ok := author.Link("related")
+	assert.True(t, ok)
+	assert.Equal(t, "/articles/1/author", l.Href())
+	_, ok = author.Link("self")
+	assert.False(t, ok)
+
+	assert.Equal(t, []string{}, r.ToOneRelationships["editor"].Ids())
+
+	tags := r.ToManyRelationships["tags"]
+	assert.Equal(t, []string{"3", "4"}, tags.Ids())
+
+	total := map[string]int{}
+	if err := tags.MetaInto(&total); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, map[string]int{"total": 3}, total)
+
+	l, ok = tags.Link("self")
+	assert.True(t, ok)
+	assert.Equal(t, "/articles/1/relationships/tags", l.Href())
+}
+
+func TestResourceLinkage_MetaInto(t *testing.T) {
+	// linkage without meta leaves the value unchanged
+	meta := map[string]int{"total": 1}
+	if err := (&ToManyResourceLinkage{}).MetaInto(&meta); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, map[string]int{"total": 1}, meta)
+
+	l := &ToOneResourceLinkage{Meta: map[string]json.RawMessage{"since": json.RawMessage(`"2020"`)}}
+	err := l.MetaInto(&struct {
+		Since int `json:"since"`
+	}{})
+	assert.ErrorAs(t, err, addrOf(&json.UnmarshalTypeError{}))
+}
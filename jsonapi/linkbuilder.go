@@ -0,0 +1,115 @@
+package jsonapi
+
+import (
+	"net/url"
+	"strings"
+)
+
+// the link templates recommended by the specification
+const (
+	DefaultCollectionTemplate   = "/{type}"
+	DefaultResourceTemplate     = "/{type}/{id}"
+	DefaultRelationshipTemplate = "/{type}/{id}/relationships/{relationship}"
+	DefaultRelatedTemplate      = "/{type}/{id}/{relationship}"
+)
+
+// LinkBuilder builds the links of resources and relationships from
+// their types, ids and relationship names, so that links are consistent
+// across an API. Its templates are paths relative to Base, in which the
+// placeholders {type}, {id} and {relationship} are replaced by their
+// path-escaped values. Empty templates default to those recommended by
+// the specification. The zero value builds relative links.
+type LinkBuilder struct {
+	// Base is the URL the links are relative to,
+	// eg "https://example.com/api".
+	Base string
+	// Collection is the template of collections' links.
+	Collection string
+	// Resource is the template of resources' self links.
+	Resource string
+	// Relationship is the template of relationships' self links.
+	Relationship string
+	// Related is the template of relationships' related links.
+	Related string
+}
+
+// NewLinkBuilder returns a LinkBuilder of links relative
+// to base with the default templates.
+func NewLinkBuilder(base string) *LinkBuilder {
+	return &LinkBuilder{Base: base}
+}
+
+// CollectionLink returns the link of the collection of resources of type typ.
+func (b *LinkBuilder) CollectionLink(typ string) string {
+	return b.build(b.Collection, DefaultCollectionTemplate, typ, "", "")
+}
+
+// ResourceLink returns the self link of the resource of type typ with id.
+func (b *LinkBuilder) ResourceLink(typ, id string) string {
+	return b.build(b.Resource, DefaultResourceTemplate, typ, id, "")
+}
+
+// RelationshipLink returns the self link of the relationship
+// rel of the resource of type typ with id.
+func (b *LinkBuilder) RelationshipLink(typ, id, rel string) string {
+	return b.build(b.Relationship, DefaultRelationshipTemplate, typ, id, rel)
+}
+
+// RelatedLink returns the related link of the relationship
+// rel of the resource of type typ with id.
+func (b *LinkBuilder) RelatedLink(typ, id, rel string) string {
+	return b.build(b.Related, DefaultRelatedTemplate, typ, id, rel)
+}
+
+// Apply adds r's self link, and the self and related links of its
+// relationships, unless they already have them, eg from link fields or
+// templates. Resources without ids, eg those identified by local ids,
+// have no links.
+func (b *LinkBuilder) Apply(r *Resource) {
+	if len(r.Id) == 0 {
+		return
+	}
+	id := identifierNode(r.ResourceIdentifier).Id
+
+	if r.Links == nil {
+		r.Links = map[string]*Link{}
+	}
+	if _, ok := r.Links[TagValueSelf]; !ok {
+		r.Links[TagValueSelf] = &Link{LinkString: b.ResourceLink(r.Type, id)}
+	}
+
+	for _, name := range relationshipNames(r) {
+		links, _ := relMembers(r, name)
+		if *links == nil {
+			*links = map[string]*Link{}
+		}
+		if _, ok := (*links)[TagValueSelf]; !ok {
+			(*links)[TagValueSelf] = &Link{LinkString: b.RelationshipLink(r.Type, id, name)}
+		}
+		if _, ok := (*links)[TagValueRelated]; !ok {
+			(*links)[TagValueRelated] = &Link{LinkString: b.RelatedLink(r.Type, id, name)}
+		}
+	}
+}
+
+// build returns the link of template, or else def,
+// with its placeholders replaced, relative to the base.
+func (b *LinkBuilder) build(template, def, typ, id, rel string) string {
+	if template == "" {
+		template = def
+	}
+	repl := strings.NewReplacer(
+		"{type}", url.PathEscape(typ),
+		"{id}", url.PathEscape(id),
+		"{relationship}", url.PathEscape(rel),
+	)
+	return strings.TrimSuffix(b.Base, "/") + repl.Replace(template)
+}
+
+// WithLinkBuilder adds the links built by b to every resource formatted,
+// including included resources, as by LinkBuilder.Apply.
+func WithLinkBuilder(b *LinkBuilder) Option {
+	return func(o *options) {
+		o.linkBuilder = b
+	}
+}
@@ -0,0 +1,72 @@
+package jsonapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLinkBuilder(t *testing.T) {
+	b := NewLinkBuilder("https://example.com/api/")
+	assert.Equal(t, "https://example.com/api/articles", b.CollectionLink("articles"))
+	assert.Equal(t, "https://example.com/api/articles/1", b.ResourceLink("articles", "1"))
+	assert.Equal(t, "https://example.com/api/articles/1/relationships/tags", b.RelationshipLink("articles", "1", "tags"))
+	assert.Equal(t, "https://example.com/api/articles/1/tags", b.RelatedLink("articles", "1", "tags"))
+
+	// placeholders are path-escaped
+	assert.Equal(t, "https://example.com/api/articles/a%2Fb", b.ResourceLink("articles", "a/b"))
+
+	b = &LinkBuilder{
+		Resource:     "/v2/{type}/{id}",
+		Relationship: "/v2/{type}/{id}/links/{relationship}",
+	}
+	assert.Equal(t, "/v2/articles/1", b.ResourceLink("articles", "1"))
+	assert.Equal(t, "/v2/articles/1/links/tags", b.RelationshipLink("articles", "1", "tags"))
+	assert.Equal(t, "/articles/1/tags", b.RelatedLink("articles", "1", "tags"))
+}
+
+type builtArticle struct {
+	Id       int    `jsonapi:"id,articles,string,omitempty"`
+	Author   int    `jsonapi:"rel,author,people,string,related=/people/{id}"`
+	Comments []int  `jsonapi:"rel,comments,comments,string"`
+	Self     string `jsonapi:"link,self"`
+}
+
+func TestWithLinkBuilder(t *testing.T) {
+	data, err := MarshalDocument(builtArticle{Id: 1, Author: 2, Comments: []int{3}}, WithLinkBuilder(NewLinkBuilder("/api")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{
+		"data": {
+			"type": "articles",
+			"id": "1",
+			"links": {"self": "/api/articles/1"},
+			"relationships": {
+				"author": {
+					"data": {"type": "people", "id": "2"},
+					"links": {"self": "/api/articles/1/relationships/author", "related": "/people/1"}
+				},
+				"comments": {
+					"data": [{"type": "comments", "id": "3"}],
+					"links": {"self": "/api/articles/1/relationships/comments", "related": "/api/articles/1/comments"}
+				}
+			}
+		}
+	}`
+	assert.JSONEq(t, expected, string(data))
+
+	// existing links are kept, and resources without ids have none
+	r, err := FormatResource(builtArticle{Self: "/custom"}, WithLinkBuilder(NewLinkBuilder("/api")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, map[string]*Link{TagValueSelf: {LinkString: "/custom"}}, r.Links)
+	assert.Nil(t, r.ToManyRelationships["comments"].Links)
+
+	r, err = FormatResource(builtArticle{Id: 1, Self: "/custom"}, WithLinkBuilder(NewLinkBuilder("/api")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "/custom", r.Links[TagValueSelf].Href())
+}
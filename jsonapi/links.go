@@ -0,0 +1,180 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var (
+	linkType       = reflect.TypeFor[Link]()
+	linkObjectType = reflect.TypeFor[LinkObject]()
+)
+
+// parseLinkTag parses a link tag, eg `jsonapi:"link,self"`, which maps
+// a string, Link or LinkObject field to a member of the resource's links,
+// or, with the rel option, eg `jsonapi:"link,self,rel=author"`, to a
+// member of the links of one of its relationships.
+func parseLinkTag(f reflect.StructField, opts string) (tag, error) {
+	name, namePrec, opts := splitNameAndOpts(f, opts)
+
+	t := derefType(f.Type)
+	if t.Kind() != reflect.String && t != linkType && t != linkObjectType {
+		return tag{}, &TagErr{f.Name, fmt.Errorf("link must be a string, Link or LinkObject")}
+	}
+
+	tg := tag{
+		typ:      TagValueLink,
+		name:     name,
+		namePrec: namePrec,
+	}
+	if err := parseRelOpt(f, &tg, opts); err != nil {
+		return tag{}, err
+	}
+	return tg, nil
+}
+
+// marshalLink adds the link field f of v to r's links,
+// unless it is empty, ie a nil pointer or zero value.
+func marshalLink(v reflect.Value, r *Resource, f field) error {
+	v, err := fieldByIndex(v, f.idxs)
+	if err != nil {
+		return err
+	}
+	v, err = derefValue(v)
+	if err != nil {
+		return err
+	}
+
+	if !v.IsValid() || v.IsZero() {
+		return nil
+	}
+
+	if f.tag.rel != "" {
+		links, _ := relMembers(r, f.tag.rel)
+		if *links == nil {
+			*links = map[string]*Link{}
+		}
+		(*links)[f.tag.relMember] = toLink(v)
+		return nil
+	}
+
+	if r.Links == nil {
+		r.Links = map[string]*Link{}
+	}
+	r.Links[f.tag.name] = toLink(v)
+	return nil
+}
+
+// unmarshalLink sets the link field f of v from r's links.
+// Link objects are stored in string fields by their href,
+// and link strings in LinkObject fields as their href.
+func unmarshalLink(v reflect.Value, r *Resource, f field) error {
+	l := r.Links[f.tag.name]
+	if f.tag.rel != "" {
+		l = relLinks(r, f.tag.rel)[f.tag.relMember]
+	}
+	if l == nil {
+		return nil
+	}
+
+	v, err := initFieldByIndex(v, f.idxs)
+	if err != nil {
+		return err
+	}
+	v, err = derefValue(v)
+	if err != nil {
+		return err
+	}
+
+	setLink(v, l)
+	return nil
+}
+
+// toLink converts v, a string, Link or LinkObject, to a Link.
+func toLink(v reflect.Value) *Link {
+	switch v.Type() {
+	case linkType:
+		l := v.Interface().(Link)
+		return &l
+	case linkObjectType:
+		return &Link{LinkObject: v.Interface().(LinkObject)}
+	default:
+		return &Link{LinkString: v.String()}
+	}
+}
+
+// setLink sets v, a string, Link or LinkObject, to l.
+func setLink(v reflect.Value, l *Link) {
+	switch v.Type() {
+	case linkType:
+		v.Set(reflect.ValueOf(*l))
+	case linkObjectType:
+		lo := l.LinkObject
+		if l.LinkString != "" {
+			lo = LinkObject{Href: l.LinkString}
+		}
+		v.Set(reflect.ValueOf(lo))
+	default:
+		v.SetString(l.Href())
+	}
+}
+
+// relMembers returns pointers to the links and meta of r's relationship
+// called name. If r has no such relationship, one is added without
+// resource linkage.
+func relMembers(r *Resource, name string) (*map[string]*Link, *map[string]json.RawMessage) {
+	if rel, ok := r.ToOneRelationships[name]; ok {
+		return &rel.Links, &rel.Meta
+	}
+	rel, ok := r.ToManyRelationships[name]
+	if !ok {
+		if r.ToManyRelationships == nil {
+			r.ToManyRelationships = map[string]*ToManyResourceLinkage{}
+		}
+		rel = &ToManyResourceLinkage{}
+		r.ToManyRelationships[name] = rel
+	}
+	return &rel.Links, &rel.Meta
+}
+
+// relLinks returns the links of r's relationship called name, if any.
+func relLinks(r *Resource, name string) map[string]*Link {
+	if rel, ok := r.ToOneRelationships[name]; ok {
+		return rel.Links
+	}
+	if rel, ok := r.ToManyRelationships[name]; ok {
+		return rel.Links
+	}
+	return nil
+}
+
+// relMeta returns the meta of r's relationship called name, if any.
+func relMeta(r *Resource, name string) map[string]json.RawMessage {
+	if rel, ok := r.ToOneRelationships[name]; ok {
+		return rel.Meta
+	}
+	if rel, ok := r.ToManyRelationships[name]; ok {
+		return rel.Meta
+	}
+	return nil
+}
+
+// marshalRelLinkTemplates adds the links declared by the templates of
+// the relationship field f, eg `jsonapi:"rel,author,people,related=/articles/{id}/author"`,
+// to r's relationship, with {id} replaced by r's id, and {type} by its type.
+func marshalRelLinkTemplates(r *Resource, f field) {
+	if len(f.tag.links) == 0 {
+		return
+	}
+
+	repl := strings.NewReplacer("{id}", identifierNode(r.ResourceIdentifier).Id, "{type}", r.Type)
+	links, _ := relMembers(r, f.tag.name)
+	if *links == nil {
+		*links = map[string]*Link{}
+	}
+	for name, tmpl := range f.tag.links {
+		(*links)[name] = &Link{LinkString: repl.Replace(tmpl)}
+	}
+}
@@ -0,0 +1,236 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type linkedArticle struct {
+	Id      string      `jsonapi:"id,articles"`
+	Self    string      `jsonapi:"link,self"`
+	Related *Link       `jsonapi:"link,related"`
+	Next    *string     `jsonapi:"link,next"`
+	Author  LinkObject  `jsonapi:"link,author"`
+	Prev    *LinkObject `jsonapi:"link,prev"`
+}
+
+const linkedArticleJson = `
+{
+	"type": "articles",
+	"id": "1",
+	"links": {
+		"self": "/articles/1",
+		"related": {"href": "/articles/1/related", "title": "Related"},
+		"author": {"href": "/people/2", "meta": {"n": 1}}
+	}
+}`
+
+var linkedArticleValue = linkedArticle{
+	Id:      "1",
+	Self:    "/articles/1",
+	Related: &Link{LinkObject: LinkObject{Href: "/articles/1/related", Title: "Related"}},
+	Author:  LinkObject{Href: "/people/2", Meta: map[string]any{"n": float64(1)}},
+}
+
+func TestMarshalResource_Link(t *testing.T) {
+	got, err := MarshalResource(linkedArticleValue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, fmtJson(t, []byte(linkedArticleJson)), fmtJson(t, got))
+}
+
+func TestUnmarshalResource_Link(t *testing.T) {
+	got := linkedArticle{}
+	if err := UnmarshalResource([]byte(linkedArticleJson), &got); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, linkedArticleValue, got)
+}
+
+func TestLink_DescribedBy(t *testing.T) {
+	l := &Link{LinkObject: LinkObject{Href: "/articles/1", DescribedBy: &Link{LinkString: "/schemas/articles"}}}
+
+	data, err := json.Marshal(l)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.JSONEq(t, `{"href": "/articles/1", "describedby": "/schemas/articles"}`, string(data))
+
+	got := &Link{}
+	if err := json.Unmarshal(data, got); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, l, got)
+
+	// the member name used before 1.1 is not recognised
+	got = &Link{}
+	if err := json.Unmarshal([]byte(`{"href": "/articles/1", "described_by": "/schemas/articles"}`), got); err != nil {
+		t.Fatal(err)
+	}
+	assert.Nil(t, got.LinkObject.DescribedBy)
+}
+
+func TestUnmarshalResource_Link_Convert(t *testing.T) {
+	in := `
+	{
+		"type": "articles",
+		"id": "1",
+		"links": {
+			"self": {"href": "/articles/1"},
+			"next": {"href": "/articles/2"},
+			"author": "/people/2",
+			"prev": "/articles/0"
+		}
+	}`
+
+	got := linkedArticle{}
+	if err := UnmarshalResource([]byte(in), &got); err != nil {
+		t.Fatal(err)
+	}
+	want := linkedArticle{
+		Id:     "1",
+		Self:   "/articles/1",
+		Next:   addrOf("/articles/2"),
+		Author: LinkObject{Href: "/people/2"},
+		Prev:   &LinkObject{Href: "/articles/0"},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestMarshalResource_Link_TagErr(t *testing.T) {
+	_, err := MarshalResource(struct {
+		Id   string `jsonapi:"id,articles"`
+		Self int    `jsonapi:"link,self"`
+	}{})
+	assert.ErrorAs(t, err, addrOf(&TagErr{}))
+}
+
+type relLinkedArticle struct {
+	Id           string   `jsonapi:"id,articles"`
+	Author       string   `jsonapi:"rel,author,people,self=/articles/{id}/relationships/author,related=/articles/{id}/author"`
+	Tags         []string `jsonapi:"rel,tags,tags,omitempty"`
+	TagsSelf     string   `jsonapi:"link,self,rel=tags"`
+	TagsRelated  *Link    `jsonapi:"link,related,rel=tags"`
+	Self         string   `jsonapi:"link,self"`
+	AuthorSchema string   `jsonapi:"link,describedby,rel=author"`
+}
+
+const relLinkedArticleJson = `
+{
+	"type": "articles",
+	"id": "1",
+	"links": {"self": "/articles/1"},
+	"relationships": {
+		"author": {
+			"data": {"type": "people", "id": "2"},
+			"links": {
+				"self": "/articles/1/relationships/author",
+				"related": "/articles/1/author",
+				"describedby": "/schemas/author"
+			}
+		},
+		"tags": {
+			"links": {
+				"self": "/articles/1/relationships/tags",
+				"related": {"href": "/articles/1/tags"}
+			}
+		}
+	}
+}`
+
+var relLinkedArticleValue = relLinkedArticle{
+	Id:           "1",
+	Author:       "2",
+	TagsSelf:     "/articles/1/relationships/tags",
+	TagsRelated:  &Link{LinkObject: LinkObject{Href: "/articles/1/tags"}},
+	Self:         "/articles/1",
+	AuthorSchema: "/schemas/author",
+}
+
+func TestMarshalResource_RelLinks(t *testing.T) {
+	got, err := MarshalResource(relLinkedArticleValue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, fmtJson(t, []byte(relLinkedArticleJson)), fmtJson(t, got))
+}
+
+func TestUnmarshalResource_RelLinks(t *testing.T) {
+	got := relLinkedArticle{}
+	if err := UnmarshalResource([]byte(relLinkedArticleJson), &got); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, relLinkedArticleValue, got)
+}
+
+func TestLink_MarshalJSON(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		link *Link
+		json string
+	}{
+		{"string", &Link{LinkString: "/articles/1"}, `"/articles/1"`},
+		{"object", &Link{LinkObject: LinkObject{Href: "/articles/1", Title: "Article"}}, `{"href":"/articles/1","title":"Article"}`},
+		{"null", &Link{Null: true}, `null`},
+		{"zero", &Link{}, `null`},
+		{"nil", nil, `null`},
+	} {
+		got, err := json.Marshal(test.link)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, test.json, string(got), test.name)
+	}
+
+	_, err := json.Marshal(&Link{LinkObject: LinkObject{Title: "Article"}})
+	assert.ErrorIs(t, err, ErrMissingHref)
+}
+
+func TestLink_UnmarshalJSON(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		json string
+		link Link
+	}{
+		{"string", `"/articles/1"`, Link{LinkString: "/articles/1"}},
+		{"object", `{"href":"/articles/1","title":"Article"}`, Link{LinkObject: LinkObject{Href: "/articles/1", Title: "Article"}}},
+		{"null", `null`, Link{Null: true}},
+		{"hreflang", `{"href":"/articles/1","hreflang":["en","de"]}`, Link{LinkObject: LinkObject{Href: "/articles/1", HrefLang: []string{"en", "de"}}}},
+		{"hreflang string", `{"href":"/articles/1","hreflang":"en"}`, Link{LinkObject: LinkObject{Href: "/articles/1", HrefLang: []string{"en"}}}},
+	} {
+		l := Link{LinkString: "/previous"}
+		if err := l.UnmarshalJSON([]byte(test.json)); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, test.link, l, test.name)
+	}
+
+	l := Link{}
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"title":"Article"}`), &l), ErrMissingHref)
+}
+
+func TestLink_IsZero(t *testing.T) {
+	assert.True(t, (*Link)(nil).IsZero())
+	assert.True(t, (&Link{}).IsZero())
+	assert.False(t, (&Link{Null: true}).IsZero())
+	assert.False(t, (&Link{LinkString: "/articles/1"}).IsZero())
+	assert.False(t, (&Link{LinkObject: LinkObject{Title: "Article"}}).IsZero())
+}
+
+func TestLink_Href(t *testing.T) {
+	assert.Equal(t, "/articles/1", (&Link{LinkString: "/articles/1"}).Href())
+	assert.Equal(t, "/articles/1", (&Link{LinkObject: LinkObject{Href: "/articles/1"}}).Href())
+	assert.Equal(t, "", (&Link{Null: true}).Href())
+	assert.Equal(t, "", (*Link)(nil).Href())
+}
+
+func TestMarshalResource_NullLink(t *testing.T) {
+	got, err := MarshalResource(linkedArticle{Id: "1", Related: &Link{Null: true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, fmtJson(t, []byte(`{"type":"articles","id":"1","links":{"related":null}}`)), fmtJson(t, got))
+}
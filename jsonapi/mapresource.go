@@ -0,0 +1,183 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ErrInvalidLinkage is returned when marshaling a MapResource
+// relationship whose value is not resource linkage.
+var ErrInvalidLinkage = fmt.Errorf("invalid relationship linkage")
+
+// MapResource is a resource built from maps rather than a tagged struct,
+// eg by gateways and tools that handle resources of any type. It
+// implements ResourceMarshaler and ResourceUnmarshaler, and so can be
+// marshaled and unmarshaled like structs, including in collections and
+// as included resources.
+//
+// Attribute and meta values are marshaled with encoding/json. The values
+// of Relationships are their linkage: a ResourceIdentifier, or a
+// *ResourceIdentifier that is nil for null linkage, for to-one
+// relationships, and a []ResourceIdentifier for to-many relationships.
+// When unmarshaling, attributes and meta are decoded as by
+// encoding/json into an any, to-one relationships as
+// *ResourceIdentifiers and to-many relationships as
+// []ResourceIdentifiers.
+type MapResource struct {
+	Type string
+	// Id is marshaled as a JSON string,
+	// and omitted if empty.
+	Id            string
+	Lid           string
+	Attributes    map[string]any
+	Relationships map[string]any
+	Meta          map[string]any
+	Links         map[string]*Link
+}
+
+// NewMapResource returns a MapResource of type typ
+// with id and the supplied attributes and relationships.
+func NewMapResource(typ, id string, attrs, rels map[string]any) *MapResource {
+	return &MapResource{Type: typ, Id: id, Attributes: attrs, Relationships: rels}
+}
+
+// NewResourceIdentifier returns the identifier of the
+// resource of type typ with id, as a JSON string.
+func NewResourceIdentifier(typ, id string) ResourceIdentifier {
+	j, _ := json.Marshal(id)
+	return ResourceIdentifier{Type: typ, Id: j}
+}
+
+// Resource returns m as a Resource.
+func (m *MapResource) Resource() (*Resource, error) {
+	r := &Resource{
+		ResourceIdentifier: ResourceIdentifier{Type: m.Type, Lid: m.Lid},
+		Links:              m.Links,
+	}
+	if m.Id != "" {
+		r.Id = NewResourceIdentifier(m.Type, m.Id).Id
+	}
+
+	var err error
+	if r.Attributes, err = marshalMapMembers(m.Attributes, "/attributes/"); err != nil {
+		return nil, err
+	}
+	if r.Meta, err = marshalMapMembers(m.Meta, "/meta/"); err != nil {
+		return nil, err
+	}
+
+	for _, name := range sortedKeys(m.Relationships) {
+		switch l := m.Relationships[name].(type) {
+		case nil:
+			setToOne(r, name, ResourceIdentifier{Id: NullJson})
+		case *ResourceIdentifier:
+			if l == nil {
+				setToOne(r, name, ResourceIdentifier{Id: NullJson})
+			} else {
+				setToOne(r, name, *l)
+			}
+		case ResourceIdentifier:
+			setToOne(r, name, l)
+		case []ResourceIdentifier:
+			if r.ToManyRelationships == nil {
+				r.ToManyRelationships = map[string]*ToManyResourceLinkage{}
+			}
+			if l == nil {
+				l = []ResourceIdentifier{}
+			}
+			r.ToManyRelationships[name] = &ToManyResourceLinkage{Data: l}
+		default:
+			return nil, &MarshalErr{Field: name, Pointer: "/relationships/" + pointerToken(name), Err: fmt.Errorf("%w: %T", ErrInvalidLinkage, l)}
+		}
+	}
+	return r, nil
+}
+
+func setToOne(r *Resource, name string, id ResourceIdentifier) {
+	if r.ToOneRelationships == nil {
+		r.ToOneRelationships = map[string]*ToOneResourceLinkage{}
+	}
+	r.ToOneRelationships[name] = &ToOneResourceLinkage{Data: id}
+}
+
+// marshalMapMembers marshals the values of members,
+// whose pointers are prefixed by prefix.
+func marshalMapMembers(members map[string]any, prefix string) (map[string]json.RawMessage, error) {
+	if members == nil {
+		return nil, nil
+	}
+	raw := make(map[string]json.RawMessage, len(members))
+	for k, v := range members {
+		j, err := json.Marshal(v)
+		if err != nil {
+			return nil, &MarshalErr{Field: k, Pointer: prefix + pointerToken(k), Err: err}
+		}
+		raw[k] = j
+	}
+	return raw, nil
+}
+
+// SetResource replaces m's members with those of r.
+func (m *MapResource) SetResource(r *Resource) error {
+	*m = MapResource{Type: r.Type, Lid: r.Lid, Links: r.Links}
+	if len(r.Id) > 0 {
+		m.Id = identifierNode(ResourceIdentifier{Id: r.Id}).Id
+	}
+
+	var err error
+	if m.Attributes, err = unmarshalMapMembers(r.Attributes, "/attributes/"); err != nil {
+		return err
+	}
+	if m.Meta, err = unmarshalMapMembers(r.Meta, "/meta/"); err != nil {
+		return err
+	}
+
+	if len(r.ToOneRelationships)+len(r.ToManyRelationships) > 0 {
+		m.Relationships = map[string]any{}
+	}
+	for name, l := range r.ToOneRelationships {
+		if isNullIdentifier(l.Data) {
+			m.Relationships[name] = (*ResourceIdentifier)(nil)
+			continue
+		}
+		id := l.Data
+		m.Relationships[name] = &id
+	}
+	for name, l := range r.ToManyRelationships {
+		m.Relationships[name] = l.Data
+	}
+	return nil
+}
+
+// unmarshalMapMembers unmarshals the values of members,
+// whose pointers are prefixed by prefix.
+func unmarshalMapMembers(members map[string]json.RawMessage, prefix string) (map[string]any, error) {
+	if members == nil {
+		return nil, nil
+	}
+	m := make(map[string]any, len(members))
+	for k, raw := range members {
+		var v any
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return nil, &UnmarshalErr{Field: k, Pointer: prefix + pointerToken(k), Err: err}
+		}
+		m[k] = v
+	}
+	return m, nil
+}
+
+func (m *MapResource) MarshalJsonApiResource() ([]byte, error) {
+	r, err := m.Resource()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(r)
+}
+
+func (m *MapResource) UnmarshalJsonApiResource(data []byte) error {
+	r := &Resource{}
+	if err := json.Unmarshal(data, r); err != nil {
+		return err
+	}
+	return m.SetResource(r)
+}
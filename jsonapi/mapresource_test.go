@@ -0,0 +1,103 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapResource(t *testing.T) {
+	m := NewMapResource("articles", "1", map[string]any{"title": "Hello", "tags": []string{"go"}}, map[string]any{
+		"author":   NewResourceIdentifier("people", "2"),
+		"editor":   nil,
+		"comments": []ResourceIdentifier{NewResourceIdentifier("comments", "3")},
+	})
+	m.Meta = map[string]any{"views": 10}
+
+	data, err := MarshalDocument(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{
+		"data": {
+			"type": "articles",
+			"id": "1",
+			"attributes": {"title": "Hello", "tags": ["go"]},
+			"relationships": {
+				"author": {"data": {"type": "people", "id": "2"}},
+				"editor": {"data": {"id": null}},
+				"comments": {"data": [{"type": "comments", "id": "3"}]}
+			},
+			"meta": {"views": 10}
+		}
+	}`
+	assert.JSONEq(t, expected, string(data))
+
+	got := &MapResource{}
+	if err := UnmarshalDocument(data, got); err != nil {
+		t.Fatal(err)
+	}
+	author := NewResourceIdentifier("people", "2")
+	want := &MapResource{
+		Type:       "articles",
+		Id:         "1",
+		Attributes: map[string]any{"title": "Hello", "tags": []any{"go"}},
+		Relationships: map[string]any{
+			"author":   &author,
+			"editor":   (*ResourceIdentifier)(nil),
+			"comments": []ResourceIdentifier{NewResourceIdentifier("comments", "3")},
+		},
+		Meta: map[string]any{"views": float64(10)},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestMapResource_Collection(t *testing.T) {
+	data, err := MarshalDocument([]*MapResource{
+		NewMapResource("articles", "1", map[string]any{"title": "Hello"}, nil),
+		{Type: "articles", Lid: "new"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"data": [
+		{"type": "articles", "id": "1", "attributes": {"title": "Hello"}},
+		{"type": "articles", "lid": "new"}
+	]}`
+	assert.JSONEq(t, expected, string(data))
+
+	var got []*MapResource
+	if err := UnmarshalDocument(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []*MapResource{
+		{Type: "articles", Id: "1", Attributes: map[string]any{"title": "Hello"}},
+		{Type: "articles", Lid: "new"},
+	}, got)
+}
+
+func TestMapResource_InvalidLinkage(t *testing.T) {
+	_, err := MarshalDocument(NewMapResource("articles", "1", nil, map[string]any{"author/editor": "2"}))
+	assert.ErrorIs(t, err, ErrInvalidLinkage)
+
+	var me *MarshalErr
+	if assert.True(t, errors.As(err, &me)) {
+		assert.Equal(t, "/relationships/author~1editor", me.Pointer)
+	}
+}
+
+func TestMapResource_MemberErr(t *testing.T) {
+	_, err := MarshalDocument(NewMapResource("articles", "1", map[string]any{"a~b": make(chan int)}, nil))
+	var me *MarshalErr
+	if assert.True(t, errors.As(err, &me)) {
+		assert.Equal(t, "/attributes/a~0b", me.Pointer)
+	}
+
+	err = (&MapResource{}).SetResource(&Resource{Attributes: map[string]json.RawMessage{"a/b": json.RawMessage(`{`)}})
+	var ue *UnmarshalErr
+	if assert.True(t, errors.As(err, &ue)) {
+		assert.Equal(t, "/attributes/a~1b", ue.Pointer)
+	}
+}
@@ -0,0 +1,115 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// WithMapSchema formats maps with string keys, eg the map[string]any
+// rows of a dynamic query, as resources of type typ, using the struct
+// type registered for typ with RegisterType as their schema. This
+// applies to the primary data, collections of maps and values passed
+// to WithIncluded.
+//
+// Each map value is stored in the field of the schema whose member name,
+// after any MemberNamer, or Go field name matches its key, converting it
+// to the field's type, and the struct is then marshaled as usual, so that
+// its tags, and the options registered for typ, determine the resource's
+// members. Keys that match no field are ignored.
+func WithMapSchema(typ string) Option {
+	return func(o *options) {
+		o.mapSchema = typ
+	}
+}
+
+// formatMap converts the map v to a Resource, as described
+// by WithMapSchema.
+func formatMap(v reflect.Value, o *options) (*Resource, error) {
+	if v.Type().Key().Kind() != reflect.String {
+		return nil, fmt.Errorf("jsonapi: %w", ErrNotStruct)
+	}
+
+	t, ok := o.snapshot.Type(o.mapSchema)
+	if !ok || derefType(t).Kind() != reflect.Struct {
+		return nil, fmt.Errorf("jsonapi: map schema %s: %w", o.mapSchema, ErrUnregisteredType)
+	}
+
+	s := reflect.New(derefType(t)).Elem()
+	fields, err := parseTags(s)
+	if err != nil {
+		return nil, fmt.Errorf("jsonapi: parsing tags: %w", err)
+	}
+	fields = o.forType(resourceType(s, fields, o)).nameMembers(fields)
+
+	for _, f := range fields {
+		name := f.tag.name
+		if f.tag.typ == TagValueId || f.tag.typ == TagValueLid {
+			name = f.tag.typ
+		}
+		mv := v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key()))
+		if !mv.IsValid() {
+			mv = v.MapIndex(reflect.ValueOf(f.structField).Convert(v.Type().Key()))
+		}
+		if !mv.IsValid() || isNil(mv) {
+			continue
+		}
+
+		fv, err := initFieldByIndex(s, f.idxs)
+		if err != nil {
+			return nil, fmt.Errorf("jsonapi: marshaling field "+name+": %w", err)
+		}
+		if err := setMapValue(fv, mv); err != nil {
+			return nil, fmt.Errorf("jsonapi: marshaling field "+name+": %w", &MarshalErr{Field: name, Pointer: fieldPointer(f, f.tag.name), Err: err})
+		}
+	}
+
+	return formatStruct(s, o)
+}
+
+// setMapValue stores the map value mv in the field value fv, converting
+// numbers, and strings to and from byte slices, where this loses nothing.
+// Other values are converted by encoding them as JSON and decoding the
+// result into fv.
+func setMapValue(fv, mv reflect.Value) error {
+	for mv.Kind() == reflect.Interface {
+		mv = mv.Elem()
+	}
+
+	switch {
+	case mv.Type().AssignableTo(fv.Type()):
+		fv.Set(mv)
+		return nil
+	case fv.Kind() == reflect.Pointer:
+		if fv.IsNil() {
+			fv.Set(reflect.New(fv.Type().Elem()))
+		}
+		return setMapValue(fv.Elem(), mv)
+	case convertible(mv, fv.Type()):
+		fv.Set(mv.Convert(fv.Type()))
+		return nil
+	}
+
+	data, err := json.Marshal(mv.Interface())
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, fv.Addr().Interface())
+}
+
+// convertible returns whether v can be converted to type t
+// without losing anything, eg a float64 with an integral value
+// to an int, but not an int to a string, which Go converts to
+// the character with that code point.
+func convertible(v reflect.Value, t reflect.Type) bool {
+	if !v.Type().ConvertibleTo(t) {
+		return false
+	}
+	if t.Kind() == reflect.String || v.Kind() == reflect.String {
+		return t.Kind() == v.Kind() || t.Kind() == reflect.Slice || v.Kind() == reflect.Slice
+	}
+	if v.CanInt() || v.CanUint() || v.CanFloat() {
+		return v.Convert(t).Convert(v.Type()).Equal(v)
+	}
+	return true
+}
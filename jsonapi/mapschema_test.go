@@ -0,0 +1,101 @@
+package jsonapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mapArticle struct {
+	Id        int      `jsonapi:"id,articles,string"`
+	Title     string   `jsonapi:"attr,title"`
+	WordCount int      `jsonapi:"attr"`
+	Rating    *float64 `jsonapi:"attr,rating,omitempty"`
+	Author    string   `jsonapi:"rel,author,people"`
+	Tags      []int    `jsonapi:"rel,tags,tags,string"`
+	Views     int64    `jsonapi:"meta,views"`
+}
+
+func mapSchemaRegistry() *Registry {
+	reg := NewRegistry()
+	reg.RegisterType("articles", mapArticle{})
+	return reg
+}
+
+func TestWithMapSchema(t *testing.T) {
+	rows := []map[string]any{
+		// eg the rows of a database query
+		{"id": int64(1), "title": []byte("Hello"), "word_count": int64(300), "rating": 4.5, "author": "9", "tags": []any{1.0, 2.0}, "Views": int32(7)},
+		// eg decoded from JSON, with Go field names
+		{"Id": 2.0, "Title": "World", "WordCount": 10.0, "rating": nil, "other": "ignored"},
+	}
+
+	data, err := MarshalDocument(rows, WithRegistry(mapSchemaRegistry()), WithMapSchema("articles"), WithMemberNamer(MemberNameSnake))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{
+	"data": [{
+		"type": "articles",
+		"id": "1",
+		"attributes": {"title": "Hello", "word_count": 300, "rating": 4.5},
+		"relationships": {
+			"author": {"data": {"type": "people", "id": "9"}},
+			"tags": {"data": [{"type": "tags", "id": "1"}, {"type": "tags", "id": "2"}]}
+		},
+		"meta": {"views": 7}
+	}, {
+		"type": "articles",
+		"id": "2",
+		"attributes": {"title": "World", "word_count": 10},
+		"relationships": {
+			"author": {"data": {"type": "people", "id": ""}},
+			"tags": {"data": []}
+		},
+		"meta": {"views": 0}
+	}]
+}`
+	assert.Equal(t, fmtJson(t, []byte(want)), fmtJson(t, data))
+}
+
+func TestWithMapSchema_Included(t *testing.T) {
+	d, err := FormatDocument(
+		mapArticle{Id: 1, Title: "Hello"},
+		WithRegistry(mapSchemaRegistry()),
+		WithMapSchema("articles"),
+		WithIncluded(map[string]any{"id": 2, "title": "World"}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Len(t, d.Included, 1)
+	assert.Equal(t, "articles", d.Included[0].Type)
+	assert.Equal(t, `"2"`, string(d.Included[0].Id))
+	assert.Equal(t, `"World"`, string(d.Included[0].Attributes["title"]))
+}
+
+func TestWithMapSchema_Err(t *testing.T) {
+	type testCase struct {
+		Name     string
+		Value    any
+		Schema   string
+		Expected string
+	}
+
+	testCases := []testCase{
+		{"Unregistered", map[string]any{"id": 1}, "people", "jsonapi: map schema people: unregistered type"},
+		{"Lossy", map[string]any{"id": 1.5}, "articles", "jsonapi: marshaling field id: marshal error on field 'id': json: cannot unmarshal number 1.5 into Go value of type int"},
+		{"IntToString", map[string]any{"id": 1, "title": 65}, "articles", "jsonapi: marshaling field title: marshal error on field 'title': json: cannot unmarshal number into Go value of type string"},
+		{"Keys", map[int]any{1: 1}, "articles", "jsonapi: not a struct"},
+		{"NoSchema", map[string]any{"id": 1}, "", "jsonapi: not a struct"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			_, err := MarshalDocument(tc.Value, WithRegistry(mapSchemaRegistry()), WithMapSchema(tc.Schema))
+			assert.EqualError(t, err, tc.Expected)
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package jsonapi
+
+import (
+	"fmt"
+	"mime"
+	"slices"
+	"strings"
+)
+
+// ErrInvalidMediaType is returned by ParseMediaType for media types
+// other than the JSON:API media type, or with other parameters than
+// "ext" and "profile".
+var ErrInvalidMediaType = fmt.Errorf("invalid media type")
+
+// FormatMediaType returns the JSON:API media type, with "ext" and
+// "profile" parameters listing the URIs of the extensions and
+// profiles, if any, eg for a Content-Type or Accept header:
+//
+//	application/vnd.api+json; ext="https://jsonapi.org/ext/atomic"
+func FormatMediaType(ext, profiles []string) string {
+	params := map[string]string{}
+	if len(ext) > 0 {
+		params[MediaTypeParamExt] = strings.Join(ext, " ")
+	}
+	if len(profiles) > 0 {
+		params[MediaTypeParamProfile] = strings.Join(profiles, " ")
+	}
+	if len(params) == 0 {
+		return MediaType
+	}
+	return mime.FormatMediaType(MediaType, params)
+}
+
+// ParseMediaType returns the URIs of the extensions and profiles listed
+// by the "ext" and "profile" parameters of the JSON:API media type v,
+// eg a Content-Type header. Media types other than JSON:API's, and
+// those with other parameters, return an error wrapping
+// ErrInvalidMediaType.
+func ParseMediaType(v string) ([]string, []string, error) {
+	typ, params, err := mime.ParseMediaType(v)
+	if err != nil {
+		return nil, nil, fmt.Errorf("jsonapi: %w: %w", ErrInvalidMediaType, err)
+	}
+	if typ != MediaType {
+		return nil, nil, fmt.Errorf("jsonapi: %w: %s", ErrInvalidMediaType, typ)
+	}
+	for _, k := range sortedKeys(params) {
+		if k != MediaTypeParamExt && k != MediaTypeParamProfile {
+			return nil, nil, fmt.Errorf("jsonapi: %w: parameter %s", ErrInvalidMediaType, k)
+		}
+	}
+	return uriList(params[MediaTypeParamExt]), uriList(params[MediaTypeParamProfile]), nil
+}
+
+// uriList returns the URIs of the space-separated list
+// v, or nil if there are none.
+func uriList(v string) []string {
+	if uris := strings.Fields(v); len(uris) > 0 {
+		return uris
+	}
+	return nil
+}
+
+// WithExt declares the URIs of the extensions applied to documents
+// built by FormatDocument and MarshalDocument in the "ext" member
+// of their jsonapi objects, eg those negotiated with the client.
+func WithExt(uris ...string) Option {
+	return func(o *options) {
+		o.ext = append(o.ext, uris...)
+	}
+}
+
+// WithProfiles declares the URIs of the profiles applied to documents
+// built by FormatDocument and MarshalDocument in the "profile" member
+// of their jsonapi objects.
+func WithProfiles(uris ...string) Option {
+	return func(o *options) {
+		o.profiles = append(o.profiles, uris...)
+	}
+}
+
+// addAppliedMediaParams adds the extensions and profiles declared
+// with WithExt and WithProfiles to d's jsonapi object.
+func addAppliedMediaParams(d *Document, o *options) {
+	if len(o.ext) == 0 && len(o.profiles) == 0 {
+		return
+	}
+	if d.JsonApi == nil {
+		d.JsonApi = &JsonApiObject{}
+	}
+	d.JsonApi.Ext = slices.Clone(o.ext)
+	d.JsonApi.Profile = slices.Clone(o.profiles)
+}
@@ -0,0 +1,76 @@
+package jsonapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatMediaType(t *testing.T) {
+	assert.Equal(t, MediaType, FormatMediaType(nil, nil))
+	assert.Equal(t, MediaType+`; ext="https://example.com/a https://example.com/b"`,
+		FormatMediaType([]string{"https://example.com/a", "https://example.com/b"}, nil))
+	assert.Equal(t, MediaType+`; ext="https://example.com/a"; profile="https://example.com/p"`,
+		FormatMediaType([]string{"https://example.com/a"}, []string{"https://example.com/p"}))
+}
+
+func TestParseMediaType(t *testing.T) {
+	type testCase struct {
+		Name     string
+		In       string
+		Ext      []string
+		Profiles []string
+		Err      bool
+	}
+
+	for _, tc := range []testCase{
+		{Name: "plain", In: MediaType},
+		{
+			Name:     "ext and profile",
+			In:       MediaType + `; ext="https://example.com/a https://example.com/b"; profile="https://example.com/p"`,
+			Ext:      []string{"https://example.com/a", "https://example.com/b"},
+			Profiles: []string{"https://example.com/p"},
+		},
+		{Name: "round trip", In: FormatMediaType([]string{"https://example.com/a"}, nil), Ext: []string{"https://example.com/a"}},
+		{Name: "other type", In: "application/json", Err: true},
+		{Name: "other parameter", In: MediaType + "; charset=utf-8", Err: true},
+		{Name: "malformed", In: MediaType + "; ext", Err: true},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			ext, profiles, err := ParseMediaType(tc.In)
+			if tc.Err {
+				assert.ErrorIs(t, err, ErrInvalidMediaType)
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tc.Ext, ext)
+			assert.Equal(t, tc.Profiles, profiles)
+		})
+	}
+}
+
+func TestWithExt(t *testing.T) {
+	data, err := MarshalDocument(docArticleValue, WithExt("https://example.com/a"), WithProfiles("https://example.com/p"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{
+		"data": {
+			"type": "articles",
+			"id": "1",
+			"attributes": {"title": "Hello"},
+			"relationships": {"author": {"data": {"type": "people", "id": "2"}}}
+		},
+		"jsonapi": {"ext": ["https://example.com/a"], "profile": ["https://example.com/p"]}
+	}`
+	assert.JSONEq(t, expected, string(data))
+
+	d := Document{}
+	if err := d.UnmarshalJSON(data); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, &JsonApiObject{Ext: []string{"https://example.com/a"}, Profile: []string{"https://example.com/p"}}, d.JsonApi)
+	assert.Equal(t, d.JsonApi, d.JsonApi.Clone())
+}
@@ -0,0 +1,92 @@
+package jsonapi
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ErrInvalidMemberName is returned with WithStrictMemberNames for
+// member names that don't conform to the specification.
+var ErrInvalidMemberName = fmt.Errorf("invalid member name")
+
+// WithStrictMemberNames checks that the names of attributes,
+// relationships and meta members conform to the specification's
+// member name rules, as reported by ValidMemberName: those declared
+// by tags, or derived from field names, when marshaling or unmarshaling,
+// failing with a TagErr, and those of resources being unmarshaled,
+// failing with an UnmarshalErr. Both wrap ErrInvalidMemberName.
+func WithStrictMemberNames() Option {
+	return func(o *options) {
+		o.strictNames = true
+	}
+}
+
+// ValidMemberName returns whether name conforms to the specification's
+// member name rules: it is not empty, it only contains letters and digits
+// in the ASCII range, non-ASCII characters, and the hyphen, low line
+// and space, and it starts and ends with one of the former.
+func ValidMemberName(name string) bool {
+	if name == "" {
+		return false
+	}
+	rs := []rune(name)
+	for i, c := range rs {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c >= 0x80:
+		case c == '-' || c == '_' || c == ' ':
+			if i == 0 || i == len(rs)-1 {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// checkMemberNames returns a TagErr if one of the member
+// names of fields is invalid, as reported by ValidMemberName.
+func checkMemberNames(fields []field) error {
+	for _, f := range fields {
+		name := f.tag.name
+		switch {
+		case f.tag.typ != TagValueAttr && f.tag.typ != TagValueRel && f.tag.typ != TagValueMeta:
+			continue
+		case f.tag.rel != "":
+			name = f.tag.relMember
+		}
+		if !ValidMemberName(name) {
+			return &TagErr{f.structField, fmt.Errorf("%w: %q", ErrInvalidMemberName, name)}
+		}
+	}
+	return nil
+}
+
+// checkResourceMemberNames returns an UnmarshalErr for the first,
+// in name order, of r's attributes, relationships and meta members
+// whose name is invalid, as reported by ValidMemberName.
+func checkResourceMemberNames(r *Resource) error {
+	var invalid []string
+	pointers := map[string]string{}
+	for _, m := range []struct {
+		member string
+		keys   []string
+	}{
+		{"attributes", mapKeys(r.Attributes)},
+		{"relationships", mapKeys(r.ToOneRelationships)},
+		{"relationships", mapKeys(r.ToManyRelationships)},
+		{"meta", mapKeys(r.Meta)},
+	} {
+		for _, k := range m.keys {
+			if _, ok := pointers[k]; !ok && !ValidMemberName(k) {
+				invalid = append(invalid, k)
+				pointers[k] = "/" + m.member + "/" + pointerToken(k)
+			}
+		}
+	}
+	if len(invalid) == 0 {
+		return nil
+	}
+	sort.Strings(invalid)
+	return &UnmarshalErr{Field: invalid[0], Pointer: pointers[invalid[0]], Err: fmt.Errorf("%w: %q", ErrInvalidMemberName, invalid[0])}
+}
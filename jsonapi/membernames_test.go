@@ -0,0 +1,71 @@
+package jsonapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidMemberName(t *testing.T) {
+	for _, name := range []string{"a", "title", "created-at", "created_at", "created at", "X9", "naïve", "日本"} {
+		assert.True(t, ValidMemberName(name), name)
+	}
+	for _, name := range []string{"", "-a", "a-", "_a", "a_", " a", "a ", "a.b", "a+b", "@a", "a:b", "a/b"} {
+		assert.False(t, ValidMemberName(name), name)
+	}
+}
+
+type strictNamesArticle struct {
+	Id        string `jsonapi:"id,articles"`
+	Title     string `jsonapi:"attr,title"`
+	CreatedAt string `jsonapi:"attr"`
+	Author    string `jsonapi:"rel,author,people"`
+	Since     int    `jsonapi:"meta,since,rel=author"`
+}
+
+type strictNamesInvalid struct {
+	Id    string `jsonapi:"id,articles"`
+	Title string `jsonapi:"attr,_title"`
+}
+
+type strictNamesInvalidRelMeta struct {
+	Id     string `jsonapi:"id,articles"`
+	Author string `jsonapi:"rel,author,people"`
+	Since  int    `jsonapi:"meta,since-,rel=author"`
+}
+
+func TestWithStrictMemberNames_Marshal(t *testing.T) {
+	_, err := MarshalResource(strictNamesArticle{Id: "1"}, WithStrictMemberNames(), WithMemberNamer(MemberNameKebab))
+	assert.NoError(t, err)
+
+	_, err = MarshalResource(strictNamesInvalid{Id: "1"})
+	assert.NoError(t, err)
+
+	_, err = MarshalResource(strictNamesInvalid{Id: "1"}, WithStrictMemberNames())
+	assert.ErrorIs(t, err, ErrInvalidMemberName)
+	assert.EqualError(t, err, `jsonapi: parsing tags: tag error on field 'Title': invalid member name: "_title"`)
+
+	_, err = MarshalResource(strictNamesInvalidRelMeta{Id: "1"}, WithStrictMemberNames())
+	assert.EqualError(t, err, `jsonapi: parsing tags: tag error on field 'Since': invalid member name: "since-"`)
+}
+
+func TestWithStrictMemberNames_Unmarshal(t *testing.T) {
+	data := `{"type": "articles", "id": "1", "attributes": {"title": "Hello", "CreatedAt": "2020"}, "meta": {"$views": 1}}`
+	err := UnmarshalResource([]byte(data), &strictNamesArticle{})
+	assert.NoError(t, err)
+
+	err = UnmarshalResource([]byte(data), &strictNamesArticle{}, WithStrictMemberNames())
+	var uErr *UnmarshalErr
+	assert.ErrorAs(t, err, &uErr)
+	assert.ErrorIs(t, err, ErrInvalidMemberName)
+	assert.EqualError(t, err, `jsonapi: unmarshal error on field '$views': invalid member name: "$views"`)
+
+	data = `{"type": "articles", "id": "1", "relationships": {"author-": {"data": null}}}`
+	err = UnmarshalResource([]byte(data), &strictNamesArticle{}, WithStrictMemberNames())
+	assert.EqualError(t, err, `jsonapi: unmarshal error on field 'author-': invalid member name: "author-"`)
+
+	err = UnmarshalResource([]byte(`{"type": "articles", "id": "1"}`), &strictNamesInvalid{}, WithStrictMemberNames())
+	assert.ErrorIs(t, err, ErrInvalidMemberName)
+	var tErr *TagErr
+	assert.ErrorAs(t, err, &tErr)
+}
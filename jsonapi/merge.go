@@ -0,0 +1,152 @@
+package jsonapi
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+var (
+	ErrMergeConflict      = fmt.Errorf("conflicting member")
+	ErrIdentifierConflict = fmt.Errorf("resources have different identifiers")
+)
+
+// MergePolicy defines how MergeResources handles members
+// that both resources have.
+type MergePolicy int
+
+const (
+	// MergeOverwrite replaces the members of dst with those of src.
+	MergeOverwrite MergePolicy = iota
+	// MergeKeepExisting keeps the members of dst, only adding those
+	// it doesn't have, eg to fill in layered defaults.
+	MergeKeepExisting
+	// MergeError fails with ErrMergeConflict if a member of src
+	// differs from that of dst, leaving dst unchanged.
+	MergeError
+)
+
+// WithMergePolicy sets how MergeResources handles
+// members that both resources have.
+func WithMergePolicy(p MergePolicy) Option {
+	return func(o *options) {
+		o.mergePolicy = p
+	}
+}
+
+// MergeResources overlays the attributes, relationships, meta, links
+// and unknown members present in src onto dst, eg to build PATCH
+// semantics or layered defaults, handling the members that both have
+// according to the WithMergePolicy option, which defaults to
+// MergeOverwrite. Members are copied, so src is never shared with dst.
+// Relationships replace each other whole, including their links and
+// meta. If dst has no type, id or local id, it takes that of src, but
+// if both have one and they differ, it fails with ErrIdentifierConflict.
+// Values are compared as by DiffResources.
+func MergeResources(dst, src *Resource, opts ...Option) error {
+	if err := identifierConflict(dst, src); err != nil {
+		return err
+	}
+
+	o := newOptions(opts)
+	src = src.Clone()
+	switch o.mergePolicy {
+	case MergeKeepExisting:
+		dropExisting(dst, src)
+	case MergeError:
+		if p := mergeConflict(dst, src); p != "" {
+			return fmt.Errorf("jsonapi: %w: %s", ErrMergeConflict, p)
+		}
+	}
+	mergeIdentifier(dst, src)
+	mergeResource(dst, src)
+	return nil
+}
+
+// identifierConflict returns ErrIdentifierConflict if dst and src
+// both have a type, id or local id, and they differ.
+func identifierConflict(dst, src *Resource) error {
+	if dst.Type != "" && src.Type != "" && dst.Type != src.Type ||
+		len(dst.Id) > 0 && len(src.Id) > 0 && !bytes.Equal(dst.Id, src.Id) ||
+		dst.Lid != "" && src.Lid != "" && dst.Lid != src.Lid {
+		return fmt.Errorf("jsonapi: %w: %s and %s", ErrIdentifierConflict,
+			identifierNode(dst.ResourceIdentifier), identifierNode(src.ResourceIdentifier))
+	}
+	return nil
+}
+
+// mergeIdentifier sets the type, id and local
+// id of dst from src if dst doesn't have them.
+func mergeIdentifier(dst, src *Resource) {
+	if dst.Type == "" {
+		dst.Type = src.Type
+	}
+	if len(dst.Id) == 0 {
+		dst.Id = src.Id
+	}
+	if dst.Lid == "" {
+		dst.Lid = src.Lid
+	}
+}
+
+// dropExisting removes the members of src that dst has.
+func dropExisting(dst, src *Resource) {
+	for name := range src.Attributes {
+		if _, ok := dst.Attributes[name]; ok {
+			delete(src.Attributes, name)
+		}
+	}
+	for name := range src.Meta {
+		if _, ok := dst.Meta[name]; ok {
+			delete(src.Meta, name)
+		}
+	}
+	for name := range src.Links {
+		if _, ok := dst.Links[name]; ok {
+			delete(src.Links, name)
+		}
+	}
+	for name := range src.Unknown {
+		if _, ok := dst.Unknown[name]; ok {
+			delete(src.Unknown, name)
+		}
+	}
+	for _, name := range relationshipNames(src) {
+		if linkageKeys(dst, name) != nil {
+			delete(src.ToOneRelationships, name)
+			delete(src.ToManyRelationships, name)
+		}
+	}
+}
+
+// mergeConflict returns the JSON pointer of the first member of src
+// that dst also has, with a different value, or "" if there are none.
+func mergeConflict(dst, src *Resource) string {
+	for _, name := range sortedKeys(src.Attributes) {
+		if v, ok := dst.Attributes[name]; ok && !jsonEqual(v, src.Attributes[name]) {
+			return "/attributes/" + pointerToken(name)
+		}
+	}
+	for _, name := range relationshipNames(src) {
+		if keys := linkageKeys(dst, name); keys != nil && !slices.Equal(keys, linkageKeys(src, name)) {
+			return "/relationships/" + pointerToken(name)
+		}
+	}
+	for _, name := range sortedKeys(src.Meta) {
+		if v, ok := dst.Meta[name]; ok && !jsonEqual(v, src.Meta[name]) {
+			return "/meta/" + pointerToken(name)
+		}
+	}
+	for _, name := range sortedKeys(src.Links) {
+		if l, ok := dst.Links[name]; ok && !reflect.DeepEqual(l, src.Links[name]) {
+			return "/links/" + pointerToken(name)
+		}
+	}
+	for _, name := range sortedKeys(src.Unknown) {
+		if v, ok := dst.Unknown[name]; ok && !jsonEqual(v, src.Unknown[name]) {
+			return "/" + pointerToken(name)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,145 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const mergeDstJson = `{
+	"type": "articles",
+	"id": "1",
+	"attributes": {"title": "Hello", "body": "Hi"},
+	"relationships": {"author": {"data": {"type": "people", "id": "2"}}},
+	"links": {"self": "/articles/1"},
+	"meta": {"views": 10}
+}`
+
+const mergeSrcJson = `{
+	"type": "articles",
+	"id": "1",
+	"attributes": {"title": "Bye", "draft": true},
+	"relationships": {
+		"author": {"data": {"type": "people", "id": "3"}},
+		"tags": {"data": [{"type": "tags", "id": "4"}]}
+	},
+	"meta": {"views": 10, "rev": 2}
+}`
+
+func mergeResources(t *testing.T) (*Resource, *Resource) {
+	dst, src := &Resource{}, &Resource{}
+	if err := json.Unmarshal([]byte(mergeDstJson), dst); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(mergeSrcJson), src); err != nil {
+		t.Fatal(err)
+	}
+	return dst, src
+}
+
+func TestMergeResources(t *testing.T) {
+	type testCase struct {
+		name     string
+		policy   MergePolicy
+		expected string
+	}
+
+	testCases := []testCase{
+		{
+			name:   "overwrite",
+			policy: MergeOverwrite,
+			expected: `{
+				"type": "articles",
+				"id": "1",
+				"attributes": {"title": "Bye", "body": "Hi", "draft": true},
+				"relationships": {
+					"author": {"data": {"type": "people", "id": "3"}},
+					"tags": {"data": [{"type": "tags", "id": "4"}]}
+				},
+				"links": {"self": "/articles/1"},
+				"meta": {"views": 10, "rev": 2}
+			}`,
+		},
+		{
+			name:   "keep existing",
+			policy: MergeKeepExisting,
+			expected: `{
+				"type": "articles",
+				"id": "1",
+				"attributes": {"title": "Hello", "body": "Hi", "draft": true},
+				"relationships": {
+					"author": {"data": {"type": "people", "id": "2"}},
+					"tags": {"data": [{"type": "tags", "id": "4"}]}
+				},
+				"links": {"self": "/articles/1"},
+				"meta": {"views": 10, "rev": 2}
+			}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dst, src := mergeResources(t)
+			if err := MergeResources(dst, src, WithMergePolicy(tc.policy)); err != nil {
+				t.Fatal(err)
+			}
+			got, err := json.Marshal(dst)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, fmtJson(t, []byte(tc.expected)), fmtJson(t, got))
+
+			// src isn't shared with dst
+			dst.Attributes["draft"][0] = 'f'
+			assert.Equal(t, json.RawMessage(`true`), src.Attributes["draft"])
+		})
+	}
+}
+
+func TestMergeResources_Error(t *testing.T) {
+	dst, src := mergeResources(t)
+	err := MergeResources(dst, src, WithMergePolicy(MergeError))
+	assert.ErrorIs(t, err, ErrMergeConflict)
+	assert.EqualError(t, err, "jsonapi: conflicting member: /attributes/title")
+
+	// dst is unchanged
+	got, err := json.Marshal(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, fmtJson(t, []byte(mergeDstJson)), fmtJson(t, got))
+
+	// equal values don't conflict
+	src.Attributes["title"] = json.RawMessage(`"Hello"`)
+	delete(src.ToOneRelationships, "author")
+	if err := MergeResources(dst, src, WithMergePolicy(MergeError)); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, json.RawMessage(`2`), dst.Meta["rev"])
+
+	// a conflict leaves dst without the identifier of src
+	anon := &Resource{Attributes: map[string]json.RawMessage{"t": json.RawMessage(`1`)}}
+	src = &Resource{
+		ResourceIdentifier: ResourceIdentifier{Type: "x", Id: json.RawMessage(`"9"`)},
+		Attributes:         map[string]json.RawMessage{"t": json.RawMessage(`2`)},
+	}
+	err = MergeResources(anon, src, WithMergePolicy(MergeError))
+	assert.ErrorIs(t, err, ErrMergeConflict)
+	assert.Equal(t, &Resource{Attributes: map[string]json.RawMessage{"t": json.RawMessage(`1`)}}, anon)
+}
+
+func TestMergeResources_Identifier(t *testing.T) {
+	defaults := &Resource{Attributes: map[string]json.RawMessage{"draft": json.RawMessage(`true`)}}
+	_, src := mergeResources(t)
+	if err := MergeResources(defaults, src); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "articles", defaults.Type)
+	assert.Equal(t, json.RawMessage(`"1"`), defaults.Id)
+
+	other := &Resource{ResourceIdentifier: ResourceIdentifier{Type: "articles", Id: json.RawMessage(`"2"`)}}
+	err := MergeResources(other, src)
+	assert.ErrorIs(t, err, ErrIdentifierConflict)
+	assert.EqualError(t, err, "jsonapi: resources have different identifiers: articles/2 and articles/1")
+}
@@ -0,0 +1,15 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd)
+
+package jsonapi
+
+import "os"
+
+// MapFile reads the named file, on platforms where it can't
+// be mapped into memory. The returned function does nothing.
+func MapFile(name string) (data []byte, unmap func() error, err error) {
+	data, err = os.ReadFile(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, func() error { return nil }, nil
+}
@@ -0,0 +1,35 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd
+
+package jsonapi
+
+import (
+	"os"
+	"syscall"
+)
+
+// MapFile maps the named file into memory, read-only, for decoding
+// with DecodeDocumentAliased without reading it into the heap. The
+// returned function unmaps it, after which data must not be used,
+// nor any document aliasing it, unless detached. Writing to data
+// crashes the program.
+func MapFile(name string) (data []byte, unmap func() error, err error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, nil, err
+	}
+	if fi.Size() == 0 {
+		return []byte{}, func() error { return nil }, nil
+	}
+
+	data, err = syscall.Mmap(int(f.Fd()), 0, int(fi.Size()), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, nil, &os.PathError{Op: "mmap", Path: name, Err: err}
+	}
+	return data, func() error { return syscall.Munmap(data) }, nil
+}
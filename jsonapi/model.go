@@ -0,0 +1,23 @@
+package jsonapi
+
+// Model wraps the tagged struct T so that it implements json.Marshaler
+// and json.Unmarshaler by encoding it as a resource object, eg so that
+// code that only knows encoding/json can encode it:
+//
+//	b, err := json.Marshal(jsonapi.Model[Article]{V: article})
+//
+// Unlike embedding, wrapping needs no knowledge of
+// the memory layout of T.
+type Model[T any] struct {
+	V T
+}
+
+// MarshalJSON returns the resource object of m.V.
+func (m Model[T]) MarshalJSON() ([]byte, error) {
+	return MarshalResource(&m.V)
+}
+
+// UnmarshalJSON stores the resource object in data in m.V.
+func (m *Model[T]) UnmarshalJSON(data []byte) error {
+	return UnmarshalResource(data, &m.V)
+}
@@ -0,0 +1,53 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type modelArticle struct {
+	Id    string `jsonapi:"id,articles"`
+	Title string `jsonapi:"attr,title"`
+}
+
+const modelArticleJson = `{"type": "articles", "id": "1", "attributes": {"title": "Hello"}}`
+
+func TestModel_MarshalJSON(t *testing.T) {
+	got, err := json.Marshal(Model[modelArticle]{V: modelArticle{Id: "1", Title: "Hello"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, fmtJson(t, []byte(modelArticleJson)), fmtJson(t, got))
+
+	got, err = json.Marshal(map[string]any{"articles": []Model[modelArticle]{{V: modelArticle{Id: "1", Title: "Hello"}}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, fmtJson(t, []byte(`{"articles": [`+modelArticleJson+`]}`)), fmtJson(t, got))
+}
+
+func TestModel_UnmarshalJSON(t *testing.T) {
+	got := []Model[modelArticle]{}
+	if err := json.Unmarshal([]byte(`[`+modelArticleJson+`]`), &got); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []Model[modelArticle]{{V: modelArticle{Id: "1", Title: "Hello"}}}, got)
+
+	// a new Model is independent of any other
+	m := new(Model[modelArticle])
+	if err := json.Unmarshal([]byte(modelArticleJson), m); err != nil {
+		t.Fatal(err)
+	}
+	got2, err := json.Marshal(new(Model[modelArticle]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, fmtJson(t, []byte(`{"type": "articles", "id": "", "attributes": {"title": ""}}`)), fmtJson(t, got2))
+}
+
+func TestModel_NotStruct(t *testing.T) {
+	_, err := json.Marshal(Model[int]{V: 1})
+	assert.ErrorIs(t, err, ErrNotStruct)
+}
@@ -0,0 +1,141 @@
+package jsonapi
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// TypeNamer derives a resource type from the name of a struct
+// whose id tag doesn't declare one, eg `jsonapi:"id"`.
+type TypeNamer func(structName string) string
+
+var (
+	// TypeNameLower lowercases the struct name, eg BlogPost becomes blogpost.
+	TypeNameLower TypeNamer = strings.ToLower
+	// TypeNameSnake converts the struct name to snake case,
+	// eg BlogPost becomes blog_post.
+	TypeNameSnake TypeNamer = snakeCase
+	// TypeNamePlural converts the struct name to snake case and
+	// pluralises it using simple English rules, eg BlogPost becomes
+	// blog_posts and Category becomes categories. It is the default.
+	TypeNamePlural TypeNamer = func(name string) string {
+		return plural(snakeCase(name))
+	}
+)
+
+// WithTypeNamer sets how resource types are derived from
+// struct names when the id tag doesn't declare one.
+func WithTypeNamer(f TypeNamer) Option {
+	return func(o *options) {
+		o.typeNamer = f
+	}
+}
+
+// MemberNamer derives a member name from the name of a struct field
+// whose tag doesn't declare one, and that has no json tag name, eg
+// `jsonapi:"attr"`.
+type MemberNamer func(fieldName string) string
+
+var (
+	// MemberNameSnake converts the field name to snake case,
+	// eg CreatedAt becomes created_at.
+	MemberNameSnake MemberNamer = snakeCase
+	// MemberNameCamel converts the field name to camel case,
+	// eg CreatedAt becomes createdAt and UserID becomes userId.
+	MemberNameCamel MemberNamer = camelCase
+	// MemberNameKebab converts the field name to kebab case,
+	// eg CreatedAt becomes created-at.
+	MemberNameKebab MemberNamer = kebabCase
+)
+
+// WithMemberNamer sets how member names are derived from field
+// names when neither the jsonapi tag nor a json tag declares one.
+// By default, the field name is used as is.
+func WithMemberNamer(f MemberNamer) Option {
+	return func(o *options) {
+		o.memberNamer = f
+	}
+}
+
+// nameMembers returns fields with the names of those that
+// are derived from their field names set by o's member namer.
+func (o *options) nameMembers(fields []field) []field {
+	if o.memberNamer == nil {
+		return fields
+	}
+
+	named := slices.Clone(fields)
+	for i, f := range named {
+		if f.tag.namePrec != 1 {
+			continue
+		}
+		name := o.memberNamer(f.structField)
+		if f.tag.rel != "" {
+			named[i].tag.relMember = name
+			name = f.tag.rel + "." + name
+		}
+		named[i].tag.name = name
+	}
+	return named
+}
+
+// splitWords splits a Go identifier into its words, treating runs
+// of upper case letters as acronyms, eg HTTPServer becomes HTTP and
+// Server.
+func splitWords(s string) []string {
+	rs := []rune(s)
+	var words []string
+	start := 0
+	for i := 1; i < len(rs); i++ {
+		switch {
+		case unicode.IsUpper(rs[i]) && !unicode.IsUpper(rs[i-1]):
+		case unicode.IsUpper(rs[i]) && i+1 < len(rs) && unicode.IsLower(rs[i+1]):
+		default:
+			continue
+		}
+		words = append(words, string(rs[start:i]))
+		start = i
+	}
+	if start < len(rs) {
+		words = append(words, string(rs[start:]))
+	}
+	return words
+}
+
+func snakeCase(s string) string {
+	return strings.ToLower(strings.Join(splitWords(s), "_"))
+}
+
+func kebabCase(s string) string {
+	return strings.ToLower(strings.Join(splitWords(s), "-"))
+}
+
+func camelCase(s string) string {
+	words := splitWords(s)
+	for i, w := range words {
+		w = strings.ToLower(w)
+		if i > 0 && w != "" {
+			r, n := utf8.DecodeRuneInString(w)
+			w = string(unicode.ToUpper(r)) + w[n:]
+		}
+		words[i] = w
+	}
+	return strings.Join(words, "")
+}
+
+// plural returns the plural of the English noun s.
+func plural(s string) string {
+	switch {
+	case s == "":
+		return s
+	case strings.HasSuffix(s, "y") && len(s) > 1 && !strings.ContainsRune("aeiou", rune(s[len(s)-2])):
+		return s[:len(s)-1] + "ies"
+	case strings.HasSuffix(s, "s"), strings.HasSuffix(s, "x"), strings.HasSuffix(s, "z"),
+		strings.HasSuffix(s, "ch"), strings.HasSuffix(s, "sh"):
+		return s + "es"
+	default:
+		return s + "s"
+	}
+}
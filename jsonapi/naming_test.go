@@ -0,0 +1,155 @@
+package jsonapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type BlogPost struct {
+	Id    string `jsonapi:"id"`
+	Title string `jsonapi:"attr,title"`
+}
+
+func TestMarshalResource_DerivedType(t *testing.T) {
+	type testCase struct {
+		Name     string
+		Opts     []Option
+		Expected string
+	}
+
+	testCases := []testCase{
+		{"default", nil, "blog_posts"},
+		{"lower", []Option{WithTypeNamer(TypeNameLower)}, "blogpost"},
+		{"snake", []Option{WithTypeNamer(TypeNameSnake)}, "blog_post"},
+		{"plural", []Option{WithTypeNamer(TypeNamePlural)}, "blog_posts"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			got, err := MarshalResource(BlogPost{Id: "1", Title: "Hello"}, tc.Opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := `{"type": "` + tc.Expected + `", "id": "1", "attributes": {"title": "Hello"}}`
+			assert.Equal(t, fmtJson(t, []byte(want)), fmtJson(t, got))
+		})
+	}
+}
+
+func TestMarshalResource_DerivedType_Anonymous(t *testing.T) {
+	_, err := MarshalResource(struct {
+		Id string `jsonapi:"id"`
+	}{"1"})
+	assert.ErrorAs(t, err, addrOf(&TagErr{}))
+}
+
+func TestTypeNamers(t *testing.T) {
+	type testCase struct {
+		In     string
+		Lower  string
+		Snake  string
+		Plural string
+	}
+
+	testCases := []testCase{
+		{"Article", "article", "article", "articles"},
+		{"BlogPost", "blogpost", "blog_post", "blog_posts"},
+		{"HTTPServer", "httpserver", "http_server", "http_servers"},
+		{"UserID", "userid", "user_id", "user_ids"},
+		{"Category", "category", "category", "categories"},
+		{"Day", "day", "day", "days"},
+		{"Box", "box", "box", "boxes"},
+		{"Match", "match", "match", "matches"},
+		{"Status", "status", "status", "statuses"},
+		{"OAuth2Token", "oauth2token", "o_auth2_token", "o_auth2_tokens"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.In, func(t *testing.T) {
+			assert.Equal(t, tc.Lower, TypeNameLower(tc.In))
+			assert.Equal(t, tc.Snake, TypeNameSnake(tc.In))
+			assert.Equal(t, tc.Plural, TypeNamePlural(tc.In))
+		})
+	}
+}
+
+type namedArticle struct {
+	Id          string   `jsonapi:"id,articles"`
+	CreatedAt   string   `jsonapi:"attr"`
+	Title       string   `jsonapi:"attr,Title"`
+	Body        string   `jsonapi:"attr" json:"Body"`
+	MainAuthor  string   `jsonapi:"rel,,people"`
+	AuthorCount int      `jsonapi:"meta,,rel=main_author"`
+	RelatedTags []string `jsonapi:"rel,,tags"`
+}
+
+func TestMarshalResource_MemberNamer(t *testing.T) {
+	in := namedArticle{"1", "today", "Hello", "World", "2", 1, []string{"a"}}
+
+	got, err := MarshalResource(&in, WithMemberNamer(MemberNameSnake))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// declared names are kept
+	want := `
+	{
+		"type": "articles",
+		"id": "1",
+		"attributes": {"created_at": "today", "Title": "Hello", "Body": "World"},
+		"relationships": {
+			"main_author": {"data": {"type": "people", "id": "2"}, "meta": {"author_count": 1}},
+			"related_tags": {"data": [{"type": "tags", "id": "a"}]}
+		}
+	}`
+	assert.Equal(t, fmtJson(t, []byte(want)), fmtJson(t, got))
+
+	out := namedArticle{}
+	if err := UnmarshalResource(got, &out, WithMemberNamer(MemberNameSnake)); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, in, out)
+
+	// by default, the field names are used
+	got, err = MarshalResource(&namedArticle{Id: "1", CreatedAt: "today"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Contains(t, string(got), `"CreatedAt":"today"`)
+}
+
+func TestMarshalRelationship_MemberNamer(t *testing.T) {
+	in := namedArticle{Id: "1", MainAuthor: "2"}
+
+	d, err := FormatRelationship(&in, "main_author", WithMemberNamer(MemberNameSnake))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, `"2"`, string(d.Data.Identifier.Id))
+}
+
+func TestMemberNamers(t *testing.T) {
+	type testCase struct {
+		In    string
+		Snake string
+		Camel string
+		Kebab string
+	}
+
+	testCases := []testCase{
+		{"Title", "title", "title", "title"},
+		{"CreatedAt", "created_at", "createdAt", "created-at"},
+		{"HTTPServer", "http_server", "httpServer", "http-server"},
+		{"UserID", "user_id", "userId", "user-id"},
+		{"A1", "a1", "a1", "a1"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.In, func(t *testing.T) {
+			assert.Equal(t, tc.Snake, MemberNameSnake(tc.In))
+			assert.Equal(t, tc.Camel, MemberNameCamel(tc.In))
+			assert.Equal(t, tc.Kebab, MemberNameKebab(tc.In))
+		})
+	}
+}
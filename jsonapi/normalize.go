@@ -0,0 +1,90 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+)
+
+// WithNormalizedLinkageIds normalizes the ids of relationship linkage to
+// JSON strings, as required by the specification, before unmarshaling it,
+// so that numeric ids sent by non-conformant servers, eg {"id": 1}, are
+// stored in string fields rather than failing to unmarshal. Ids are then
+// unmarshaled into numeric fields as though they had the string option.
+func WithNormalizedLinkageIds() Option {
+	return func(o *options) {
+		o.normalizeIds = true
+	}
+}
+
+// NormalizeLinkageIds replaces the numeric ids of the relationship
+// linkage of every resource in d with the equivalent JSON strings,
+// eg 1 with "1", so that code reading ResourceIdentifiers needn't
+// handle both. Ids that are already strings, or null, are unchanged.
+func (d *Document) NormalizeLinkageIds() {
+	for _, r := range d.resources() {
+		for _, rel := range r.ToOneRelationships {
+			rel.Data.Id = normalizeId(rel.Data.Id)
+		}
+		for _, rel := range r.ToManyRelationships {
+			for i := range rel.Data {
+				rel.Data[i].Id = normalizeId(rel.Data[i].Id)
+			}
+		}
+	}
+}
+
+// normalizeLinkage returns r with the ids of its relationship linkage
+// normalized as by NormalizeLinkageIds. r is copied, rather than
+// modified, if any of its ids are numeric.
+func normalizeLinkage(r *Resource) *Resource {
+	c := *r
+	toOneCopied, toManyCopied := false, false
+	for name, rel := range r.ToOneRelationships {
+		if !isNumericId(rel.Data.Id) {
+			continue
+		}
+		if !toOneCopied {
+			c.ToOneRelationships, toOneCopied = maps.Clone(r.ToOneRelationships), true
+		}
+		l := *rel
+		l.Data.Id = normalizeId(l.Data.Id)
+		c.ToOneRelationships[name] = &l
+	}
+	for name, rel := range r.ToManyRelationships {
+		if !slices.ContainsFunc(rel.Data, func(id ResourceIdentifier) bool { return isNumericId(id.Id) }) {
+			continue
+		}
+		if !toManyCopied {
+			c.ToManyRelationships, toManyCopied = maps.Clone(r.ToManyRelationships), true
+		}
+		l := *rel
+		l.Data = slices.Clone(rel.Data)
+		for i := range l.Data {
+			l.Data[i].Id = normalizeId(l.Data[i].Id)
+		}
+		c.ToManyRelationships[name] = &l
+	}
+	return &c
+}
+
+// normalizeId returns the numeric raw id as a JSON string
+// of the same digits, and any other id unchanged.
+func normalizeId(id json.RawMessage) json.RawMessage {
+	if !isNumericId(id) {
+		return id
+	}
+	j, _ := json.Marshal(string(id))
+	return j
+}
+
+// isNumericId returns whether the raw id is a JSON number.
+func isNumericId(id json.RawMessage) bool {
+	return len(id) > 0 && (id[0] == '-' || id[0] >= '0' && id[0] <= '9')
+}
+
+// quoteRelId returns whether the id of the relationship field f
+// is unmarshaled as though it had the string option.
+func (o *options) quoteRelId(f field) bool {
+	return (f.tag.quote || o.normalizeIds) && !f.tag.lid
+}
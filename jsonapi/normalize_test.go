@@ -0,0 +1,79 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const normalizeResourceJson = `
+{
+	"type": "articles",
+	"id": "1",
+	"relationships": {
+		"author": {"data": {"type": "people", "id": 9}},
+		"editor": {"data": {"type": "people", "id": "10"}},
+		"reviewer": {"data": null},
+		"tags": {"data": [{"type": "tags", "id": 1}, {"type": "tags", "id": "2"}, {"type": "tags", "id": -3.5}]},
+		"comments": {"data": [{"type": "comments", "id": 4}]}
+	}
+}`
+
+type normalizeArticle struct {
+	Id       string   `jsonapi:"id,articles"`
+	Author   string   `jsonapi:"rel,author,people"`
+	Editor   int      `jsonapi:"rel,editor,people"`
+	Reviewer *string  `jsonapi:"rel,reviewer,people"`
+	Tags     []string `jsonapi:"rel,tags,tags"`
+	Comments []int    `jsonapi:"rel,comments,comments,string"`
+}
+
+func TestWithNormalizedLinkageIds(t *testing.T) {
+	a := normalizeArticle{Reviewer: addrOf("x")}
+	if err := UnmarshalResource([]byte(normalizeResourceJson), &a, WithNormalizedLinkageIds()); err != nil {
+		t.Fatal(err)
+	}
+	want := normalizeArticle{
+		Id:       "1",
+		Author:   "9",
+		Editor:   10,
+		Tags:     []string{"1", "2", "-3.5"},
+		Comments: []int{4},
+	}
+	assert.Equal(t, want, a)
+
+	err := UnmarshalResource([]byte(normalizeResourceJson), &normalizeArticle{})
+	assert.ErrorContains(t, err, "jsonapi: unmarshaling field author")
+}
+
+func TestWithNormalizedLinkageIds_Unchanged(t *testing.T) {
+	r := Resource{}
+	if err := json.Unmarshal([]byte(normalizeResourceJson), &r); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := DeformatResource(&r, &normalizeArticle{}, WithNormalizedLinkageIds()); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, `9`, string(r.ToOneRelationships["author"].Data.Id))
+	assert.Equal(t, `1`, string(r.ToManyRelationships["tags"].Data[0].Id))
+}
+
+func TestDocument_NormalizeLinkageIds(t *testing.T) {
+	d := Document{}
+	data := `{"data": ` + normalizeResourceJson + `, "included": [{"type": "people", "id": "9", "relationships": {"friend": {"data": {"type": "people", "id": 11}}}}]}`
+	if err := json.Unmarshal([]byte(data), &d); err != nil {
+		t.Fatal(err)
+	}
+
+	d.NormalizeLinkageIds()
+
+	r := d.Data.Resource
+	assert.Equal(t, []string{"9"}, r.ToOneRelationships["author"].Ids())
+	assert.Equal(t, `"9"`, string(r.ToOneRelationships["author"].Data.Id))
+	assert.Equal(t, `"10"`, string(r.ToOneRelationships["editor"].Data.Id))
+	assert.Equal(t, `null`, string(r.ToOneRelationships["reviewer"].Data.Id))
+	assert.Equal(t, []string{"1", "2", "-3.5"}, r.ToManyRelationships["tags"].Ids())
+	assert.Equal(t, `"11"`, string(d.Included[0].ToOneRelationships["friend"].Data.Id))
+}
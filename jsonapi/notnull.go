@@ -0,0 +1,31 @@
+package jsonapi
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// ErrNullMember is returned when unmarshaling an explicit null
+// into a field with the notnull option.
+var ErrNullMember = fmt.Errorf("member must not be null")
+
+// isNullMember returns whether the member of r mapped to the
+// attribute, meta or relationship field f is explicitly null, as
+// opposed to absent. Relationships are null if their linkage is.
+func isNullMember(r *Resource, f field) bool {
+	var data []byte
+	switch f.tag.typ {
+	case TagValueAttr:
+		data = r.Attributes[f.tag.name]
+	case TagValueMeta:
+		data = r.Meta[f.tag.name]
+		if f.tag.rel != "" {
+			data = relMeta(r, f.tag.rel)[f.tag.relMember]
+		}
+	case TagValueRel:
+		if rel, ok := r.ToOneRelationships[f.tag.name]; ok {
+			data = rel.Data.Id
+		}
+	}
+	return bytes.Equal(data, NullJson)
+}
@@ -0,0 +1,59 @@
+package jsonapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type notNullArticle struct {
+	Id     string  `jsonapi:"id,articles"`
+	Title  *string `jsonapi:"attr,title,notnull"`
+	Author *string `jsonapi:"rel,author,people,notnull"`
+	Views  *int    `jsonapi:"meta,views,notnull"`
+	Total  *int    `jsonapi:"meta,total,rel=author,notnull"`
+}
+
+func TestUnmarshalResource_NotNull(t *testing.T) {
+	type testCase struct {
+		Name string
+		Json string
+	}
+
+	testCases := []testCase{
+		{"title", `{"type": "articles", "attributes": {"title": null}}`},
+		{"author", `{"type": "articles", "relationships": {"author": {"data": null}}}`},
+		{"views", `{"type": "articles", "meta": {"views": null}}`},
+		{"author.total", `{"type": "articles", "relationships": {"author": {"meta": {"total": null}}}}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			err := UnmarshalResource([]byte(tc.Json), &notNullArticle{})
+			assert.ErrorIs(t, err, ErrNullMember)
+			want := &UnmarshalErr{}
+			if assert.ErrorAs(t, err, &want) {
+				assert.Equal(t, tc.Name, want.Field)
+			}
+		})
+	}
+}
+
+func TestUnmarshalResource_NotNull_Absent(t *testing.T) {
+	got := notNullArticle{}
+	if err := UnmarshalResource([]byte(`{"type": "articles", "id": "1"}`), &got); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, notNullArticle{Id: "1"}, got)
+
+	in := `{"type": "articles", "id": "1", "attributes": {"title": "Hello"}, "relationships": {"author": {"data": {"type": "people", "id": "2"}}}}`
+	if err := UnmarshalResource([]byte(in), &got); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, notNullArticle{Id: "1", Title: addrOf("Hello"), Author: addrOf("2")}, got)
+}
+
+func TestUnmarshalRelationship_NotNull(t *testing.T) {
+	err := UnmarshalRelationship([]byte(`{"data": null}`), &notNullArticle{}, "author")
+	assert.ErrorIs(t, err, ErrNullMember)
+}
@@ -0,0 +1,81 @@
+package jsonapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+)
+
+// Nullable is an attribute or meta field type that distinguishes a
+// member that is absent from one that is null and one that is set,
+// eg to tell which attributes a PATCH request updates or clears.
+// The zero value is absent, and is not marshaled.
+type Nullable[T any] struct {
+	value T
+	set   bool
+	null  bool
+}
+
+// NewNullable returns a Nullable set to v.
+func NewNullable[T any](v T) Nullable[T] {
+	return Nullable[T]{value: v, set: true}
+}
+
+// Null returns a Nullable that is null.
+func Null[T any]() Nullable[T] {
+	return Nullable[T]{set: true, null: true}
+}
+
+// IsSet returns true if the member is present, including if it is null.
+func (n Nullable[T]) IsSet() bool {
+	return n.set
+}
+
+// IsNull returns true if the member is null.
+func (n Nullable[T]) IsNull() bool {
+	return n.null
+}
+
+// Get returns the value, and true if the member is present and not null.
+func (n Nullable[T]) Get() (T, bool) {
+	return n.value, n.set && !n.null
+}
+
+func (n Nullable[T]) MarshalJSON() ([]byte, error) {
+	if !n.set || n.null {
+		return NullJson, nil
+	}
+	return json.Marshal(n.value)
+}
+
+func (n *Nullable[T]) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, NullJson) {
+		*n = Null[T]()
+		return nil
+	}
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*n = NewNullable(v)
+	return nil
+}
+
+// absent is implemented by Nullable, to
+// omit members that are not set.
+type absent interface {
+	absent() bool
+}
+
+func (n Nullable[T]) absent() bool {
+	return !n.set
+}
+
+// isAbsent returns true if v is a Nullable that is not set.
+func isAbsent(v reflect.Value) bool {
+	if !v.IsValid() || !v.CanInterface() {
+		return false
+	}
+	a, ok := v.Interface().(absent)
+	return ok && a.absent()
+}
@@ -0,0 +1,55 @@
+package jsonapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type nullableArticle struct {
+	Id     string             `jsonapi:"id,articles"`
+	Title  Nullable[string]   `jsonapi:"attr,title"`
+	Rating Nullable[float64]  `jsonapi:"attr,rating"`
+	Body   Nullable[string]   `jsonapi:"attr,body"`
+	Tags   Nullable[[]string] `jsonapi:"attr,tags"`
+	Views  Nullable[int]      `jsonapi:"meta,views"`
+}
+
+func TestMarshalResource_Nullable(t *testing.T) {
+	got, err := MarshalResource(nullableArticle{
+		Id:     "1",
+		Title:  NewNullable("Hello"),
+		Rating: Null[float64](),
+		Views:  NewNullable(0),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"type": "articles", "id": "1", "attributes": {"title": "Hello", "rating": null}, "meta": {"views": 0}}`
+	assert.Equal(t, fmtJson(t, []byte(want)), fmtJson(t, got))
+}
+
+func TestUnmarshalResource_Nullable(t *testing.T) {
+	in := `{"type": "articles", "id": "1", "attributes": {"title": "Hello", "rating": null, "tags": ["a"]}}`
+
+	got := nullableArticle{}
+	if err := UnmarshalResource([]byte(in), &got); err != nil {
+		t.Fatal(err)
+	}
+
+	title, ok := got.Title.Get()
+	assert.True(t, ok)
+	assert.Equal(t, "Hello", title)
+
+	assert.True(t, got.Rating.IsSet())
+	assert.True(t, got.Rating.IsNull())
+	_, ok = got.Rating.Get()
+	assert.False(t, ok)
+
+	assert.False(t, got.Body.IsSet())
+	assert.False(t, got.Views.IsSet())
+	assert.Equal(t, NewNullable([]string{"a"}), got.Tags)
+
+	err := UnmarshalResource([]byte(`{"type": "articles", "attributes": {"rating": "x"}}`), &got)
+	assert.ErrorAs(t, err, addrOf(&UnmarshalErr{}))
+}
@@ -0,0 +1,271 @@
+package jsonapi
+
+import (
+	"context"
+	"fmt"
+	"maps"
+)
+
+// Option configures the behaviour of the marshaling and
+// unmarshaling functions, which all accept a list of them, so
+// that new behaviour can be added without new functions.
+type Option func(*options)
+
+// options holds the configuration built from a list of Options.
+type options struct {
+	// omit attributes that marshal to null
+	omitNullAttrs bool
+	// the encoding of empty attribute collections
+	emptyCollections EmptyPolicy
+	// members that are never omitted
+	alwaysInclude map[string]bool
+	// the registry of per-type options
+	registry *Registry
+	// the registry snapshot used for the duration of a call
+	snapshot *RegistrySnapshot
+	// values to format as included resources
+	included []any
+	// omit the links of included resources
+	omitIncludedLinks bool
+	// sort included resources in dependency order
+	dependencyOrder bool
+	// the context checked while formatting collections
+	ctx context.Context
+	// called with the stats of each document
+	stats func(Stats)
+	// called with each instrumented call
+	instrument func(Call)
+	// called with the progress of encoding or decoding, which
+	// is tracked across the documents of Encoders and Decoders
+	progressFunc func(Progress) error
+	progress     *progress
+	// the handling of duplicate resources in collections
+	duplicates DuplicatePolicy
+	// the handling of members that both merged resources have
+	mergePolicy MergePolicy
+	// derives resource types from struct names
+	typeNamer TypeNamer
+	// derives member names from field names
+	memberNamer MemberNamer
+	// sparse fieldsets, by resource type
+	fieldsets map[string][]string
+	// the resource type whose registered struct is the schema of maps
+	mapSchema string
+	// the template of the top-level describedby link
+	describedBy string
+	// builds the links of formatted resources
+	linkBuilder *LinkBuilder
+	// the URIs of the extensions and profiles applied to documents
+	ext      []string
+	profiles []string
+	// check that member names conform to the specification
+	strictNames bool
+	// old member names, mapped to their new names
+	renames map[string]string
+	// reject members not mapped to fields
+	disallowUnknown bool
+	// report every field that fails to unmarshal
+	allErrors bool
+	// decode the numbers of interface values as json.Number
+	useNumber bool
+	// normalize the ids of relationship linkage to strings
+	normalizeIds bool
+	// require ids to be strings
+	stringIds bool
+	// accept single resources where collections are expected
+	singleAsCollection bool
+	// include predicates, by resource type and relationship name
+	includePredicates map[string]map[string]IncludePredicate
+	// identifier keys of resources linked by excluded relationships
+	excluded map[string]bool
+	// applying members to an existing value, as by ApplyResource
+	apply bool
+	// included resources by identifier key, for hydrating
+	// related structs, and those being hydrated
+	includedIndex map[string]*Resource
+	hydrating     map[string]bool
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.typeNamer == nil {
+		o.typeNamer = TypeNamePlural
+	}
+
+	if o.progressFunc != nil {
+		o.progress = &progress{}
+	}
+
+	reg := o.registry
+	if reg == nil {
+		reg = DefaultRegistry
+	}
+	o.snapshot = reg.Snapshot()
+
+	// include predicates can also be registered per type
+	if len(o.includePredicates) > 0 || len(o.snapshot.typeOpts) > 0 {
+		o.excluded = map[string]bool{}
+	}
+
+	return o
+}
+
+// forType returns the options that apply to resources of
+// type typ, ie o overridden by the options registered for typ.
+func (o *options) forType(typ string) *options {
+	typeOpts := o.snapshot.typeOpts[typ]
+	if len(typeOpts) == 0 {
+		return o
+	}
+
+	to := *o
+	to.alwaysInclude = maps.Clone(o.alwaysInclude)
+	for _, opt := range typeOpts {
+		opt(&to)
+	}
+	return &to
+}
+
+// ctxCheckInterval is the number of resources formatted
+// between checks of the context.
+const ctxCheckInterval = 64
+
+// ctxErr returns the wrapped error of the context, if it is done,
+// every ctxCheckInterval resources, where n is the number of
+// resources formatted so far.
+func (o *options) ctxErr(n int) error {
+	if o.ctx == nil || n%ctxCheckInterval != 0 {
+		return nil
+	}
+	if err := o.ctx.Err(); err != nil {
+		return fmt.Errorf("jsonapi: formatting document: %w", err)
+	}
+	return nil
+}
+
+// omitEmpty returns whether the field should be omitted when empty.
+func (o *options) omitEmpty(f field) bool {
+	return f.tag.omitempty && !o.alwaysInclude[f.tag.name]
+}
+
+// omitZero returns whether the field should be omitted when zero.
+func (o *options) omitZero(f field) bool {
+	return f.tag.omitzero && !o.alwaysInclude[f.tag.name]
+}
+
+// omitNull returns whether the attribute field should be omitted
+// when it marshals to null.
+func (o *options) omitNull(f field) bool {
+	return o.omitNullAttrs && !o.alwaysInclude[f.tag.name]
+}
+
+// WithOmitNullAttributes omits every attribute whose value marshals
+// to null, eg nil pointers, as though it were tagged with omitempty.
+func WithOmitNullAttributes() Option {
+	return func(o *options) {
+		o.omitNullAttrs = true
+	}
+}
+
+// WithEmptyCollections sets how empty or nil map and slice attributes are
+// encoded. It can be overridden per field with the "empty" tag option.
+func WithEmptyCollections(p EmptyPolicy) Option {
+	return func(o *options) {
+		o.emptyCollections = p
+	}
+}
+
+// WithAlwaysInclude ensures that the named members are always marshaled,
+// overriding the omitempty and omitzero tag options and any options that would otherwise
+// omit them.
+func WithAlwaysInclude(names ...string) Option {
+	return func(o *options) {
+		if o.alwaysInclude == nil {
+			o.alwaysInclude = map[string]bool{}
+		}
+		for _, name := range names {
+			o.alwaysInclude[name] = true
+		}
+	}
+}
+
+// WithRegistry sets the registry consulted for per-type
+// options, in place of DefaultRegistry.
+func WithRegistry(r *Registry) Option {
+	return func(o *options) {
+		o.registry = r
+	}
+}
+
+// EmptyPolicy defines how empty or nil map and slice attributes
+// are encoded.
+type EmptyPolicy int
+
+const (
+	// EmptyDefault follows encoding/json: nil collections
+	// are encoded as null and empty ones as {} or [].
+	EmptyDefault EmptyPolicy = iota
+	// EmptyAsCollection encodes nil and empty collections as {} or [].
+	EmptyAsCollection
+	// EmptyAsNull encodes nil and empty collections as null.
+	EmptyAsNull
+	// EmptyOmit omits nil and empty collections.
+	EmptyOmit
+
+	// emptyUnset is the policy of fields without an "empty"
+	// tag option, which follow WithEmptyCollections.
+	emptyUnset EmptyPolicy = -1
+)
+
+// parseEmptyPolicy parses the value of an "empty" tag
+// option, eg `jsonapi:"attr,name,empty=null"`.
+func parseEmptyPolicy(s string) (EmptyPolicy, error) {
+	switch s {
+	case "default":
+		return EmptyDefault, nil
+	case "collection":
+		return EmptyAsCollection, nil
+	case "null":
+		return EmptyAsNull, nil
+	case "omit":
+		return EmptyOmit, nil
+	default:
+		return EmptyDefault, fmt.Errorf("unknown empty policy: %s", s)
+	}
+}
+
+// WithIncluded adds the supplied values to the included resources of
+// documents built by FormatDocument and MarshalDocument. Each value
+// may be a struct or ResourceMarshaler, or a slice or array of these.
+func WithIncluded(a ...any) Option {
+	return func(o *options) {
+		o.included = append(o.included, a...)
+	}
+}
+
+// WithIncludedDependencyOrder sorts the included resources of documents
+// built by FormatDocument and MarshalDocument so that every resource
+// appears after the included resources that it references, allowing
+// them to be resolved in a single pass. Otherwise, the original order
+// is preserved. Resources whose references form a cycle cannot be
+// ordered, and are kept together in their original order, as described
+// by Document.SortIncluded.
+func WithIncludedDependencyOrder() Option {
+	return func(o *options) {
+		o.dependencyOrder = true
+	}
+}
+
+// WithContext sets a context that is checked periodically while
+// formatting large collections and included resources, so that
+// marshaling stops early, returning the context's error wrapped,
+// once it is cancelled.
+func WithContext(ctx context.Context) Option {
+	return func(o *options) {
+		o.ctx = ctx
+	}
+}
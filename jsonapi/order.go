@@ -0,0 +1,194 @@
+package jsonapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"strconv"
+)
+
+// Relationship is a relationship of a resource,
+// whose linkage is either to-one or to-many.
+type Relationship struct {
+	ToOne  *ToOneResourceLinkage
+	ToMany *ToManyResourceLinkage
+}
+
+// Attrs returns an iterator over r's attributes, in the order they
+// were unmarshaled, followed by any others in name order. Its type is
+// that of iter.Seq2[string, json.RawMessage], so it can be ranged
+// over, without requiring the iter package.
+func (r *Resource) Attrs() func(yield func(string, json.RawMessage) bool) {
+	return orderedMembers(r.attrOrder, r.Attributes)
+}
+
+// MetaMembers returns an iterator over the members of r's meta object,
+// in the order they were unmarshaled, followed by any others in name
+// order. Its type is that of iter.Seq2[string, json.RawMessage].
+func (r *Resource) MetaMembers() func(yield func(string, json.RawMessage) bool) {
+	return orderedMembers(r.metaOrder, r.Meta)
+}
+
+// Rels returns an iterator over r's relationships, in the order they
+// were unmarshaled, followed by any others in name order. Its type is
+// that of iter.Seq2[string, Relationship].
+func (r *Resource) Rels() func(yield func(string, Relationship) bool) {
+	return func(yield func(string, Relationship) bool) {
+		for _, name := range r.relNames() {
+			rel := Relationship{ToOne: r.ToOneRelationships[name], ToMany: r.ToManyRelationships[name]}
+			if !yield(name, rel) {
+				return
+			}
+		}
+	}
+}
+
+// MetaMembers returns an iterator over the members of d's meta object,
+// in the order they were unmarshaled, followed by any others in name
+// order. Its type is that of iter.Seq2[string, json.RawMessage].
+func (d *Document) MetaMembers() func(yield func(string, json.RawMessage) bool) {
+	return orderedMembers(d.metaOrder, d.Meta)
+}
+
+// Resources returns an iterator over the resources of d, those of the
+// primary data followed by the included ones, in order, with their
+// JSON pointers, eg "/data/0" or "/included/1". Its type is that of
+// iter.Seq2[string, *Resource].
+func (d *Document) Resources() func(yield func(string, *Resource) bool) {
+	return func(yield func(string, *Resource) bool) {
+		switch {
+		case d.Data == nil:
+		case d.Data.Collection:
+			for i, r := range d.Data.Resources {
+				if !yield("/data/"+strconv.Itoa(i), r) {
+					return
+				}
+			}
+		case d.Data.Resource != nil:
+			if !yield("/data", d.Data.Resource) {
+				return
+			}
+		}
+		for i, r := range d.Included {
+			if !yield("/included/"+strconv.Itoa(i), r) {
+				return
+			}
+		}
+	}
+}
+
+// orderedMembers returns an iterator over the members of m, in the
+// order returned by orderedKeys.
+func orderedMembers(order []string, m map[string]json.RawMessage) func(yield func(string, json.RawMessage) bool) {
+	return func(yield func(string, json.RawMessage) bool) {
+		for _, name := range orderedKeys(order, m) {
+			if !yield(name, m[name]) {
+				return
+			}
+		}
+	}
+}
+
+// relNames returns the names of r's relationships, in the order
+// they were unmarshaled, followed by any others in name order.
+func (r *Resource) relNames() []string {
+	present := make(map[string]bool, len(r.ToOneRelationships)+len(r.ToManyRelationships))
+	for name := range r.ToOneRelationships {
+		present[name] = true
+	}
+	for name := range r.ToManyRelationships {
+		present[name] = true
+	}
+	return orderedKeys(r.relOrder, present)
+}
+
+// orderedKeys returns the keys of m that are in order, in that order,
+// followed by the remaining keys, sorted.
+func orderedKeys[V any](order []string, m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	seen := make(map[string]bool, len(order))
+	for _, k := range order {
+		if _, ok := m[k]; ok && !seen[k] {
+			seen[k] = true
+			keys = append(keys, k)
+		}
+	}
+	n := len(keys)
+	for k := range m {
+		if !seen[k] {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys[n:])
+	return keys
+}
+
+// marshalOrdered returns the JSON object of the entries of m,
+// with keys in the order returned by orderedKeys.
+func marshalOrdered[V any](order []string, m map[string]V) (json.RawMessage, error) {
+	buf := bytes.Buffer{}
+	buf.WriteByte('{')
+	for i, k := range orderedKeys(order, m) {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(k)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(m[k])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// objectKeys returns the keys of the JSON object data, which
+// must be valid, in the order they appear.
+func objectKeys(data []byte) []string {
+	var keys []string
+	depth := 0
+	for i := 0; i < len(data); i++ {
+		switch data[i] {
+		case '{', '[':
+			depth++
+		case '}', ']':
+			depth--
+		case '"':
+			start := i
+			escaped := false
+			for i++; i < len(data); i++ {
+				if data[i] == '\\' {
+					escaped = true
+					i++
+					continue
+				}
+				if data[i] == '"' {
+					break
+				}
+			}
+			if depth != 1 || !isKey(data[i+1:]) {
+				continue
+			}
+			key := string(data[start+1 : i])
+			if escaped {
+				// nb data is valid, so this can't fail
+				_ = json.Unmarshal(data[start:i+1], &key)
+			}
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// isKey returns true if the string preceding rest
+// is an object key, ie is followed by a colon.
+func isKey(rest []byte) bool {
+	rest = bytes.TrimLeft(rest, " \t\r\n")
+	return len(rest) > 0 && rest[0] == ':'
+}
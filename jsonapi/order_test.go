@@ -0,0 +1,181 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const orderedResourceJson = `{"type":"articles","id":"1","meta":{"rev":3,"etag":"x"},"attributes":{"title":"Hello","body":"World","a\"b":1,"created":{"z":1,"a":2}},"relationships":{"tags":{"data":[]},"author":{"data":{"type":"people","id":"2"}}}}`
+
+func TestResource_Attrs(t *testing.T) {
+	r := Resource{}
+	if err := json.Unmarshal([]byte(orderedResourceJson), &r); err != nil {
+		t.Fatal(err)
+	}
+
+	names := []string{}
+	r.Attrs()(func(name string, value json.RawMessage) bool {
+		names = append(names, name)
+		assert.Equal(t, r.Attributes[name], value)
+		return true
+	})
+	assert.Equal(t, []string{"title", "body", `a"b`, "created"}, names)
+
+	// added members follow those unmarshaled, deleted ones are skipped
+	delete(r.Attributes, "body")
+	r.Attributes["b"] = json.RawMessage(`2`)
+	r.Attributes["a"] = json.RawMessage(`1`)
+	names = names[:0]
+	r.Attrs()(func(name string, _ json.RawMessage) bool {
+		names = append(names, name)
+		return name != "a"
+	})
+	assert.Equal(t, []string{"title", `a"b`, "created", "a"}, names)
+}
+
+func TestResource_Rels(t *testing.T) {
+	r := Resource{}
+	if err := json.Unmarshal([]byte(orderedResourceJson), &r); err != nil {
+		t.Fatal(err)
+	}
+
+	names := []string{}
+	r.Rels()(func(name string, rel Relationship) bool {
+		names = append(names, name)
+		switch name {
+		case "tags":
+			assert.Nil(t, rel.ToOne)
+			assert.Empty(t, rel.ToMany.Data)
+		case "author":
+			assert.Nil(t, rel.ToMany)
+			assert.Equal(t, json.RawMessage(`"2"`), rel.ToOne.Data.Id)
+		}
+		return true
+	})
+	assert.Equal(t, []string{"tags", "author"}, names)
+}
+
+func TestResource_MarshalOrdered(t *testing.T) {
+	r := Resource{}
+	if err := json.Unmarshal([]byte(orderedResourceJson), &r); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := json.Marshal(&r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, orderedResourceJson, string(got))
+
+	got, err = json.Marshal(r.Clone())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, orderedResourceJson, string(got))
+}
+
+func TestResource_MetaMembers(t *testing.T) {
+	r := Resource{}
+	if err := json.Unmarshal([]byte(orderedResourceJson), &r); err != nil {
+		t.Fatal(err)
+	}
+
+	names := []string{}
+	r.MetaMembers()(func(name string, value json.RawMessage) bool {
+		names = append(names, name)
+		assert.Equal(t, r.Meta[name], value)
+		return true
+	})
+	assert.Equal(t, []string{"rev", "etag"}, names)
+}
+
+const orderedDocumentJson = `{"data":[{"type":"articles","id":"1"},{"type":"articles","id":"2"}],"meta":{"total":2,"cursor":"b"},"included":[{"type":"people","id":"9"}]}`
+
+func TestDocument_MetaMembers(t *testing.T) {
+	d := Document{}
+	if err := json.Unmarshal([]byte(orderedDocumentJson), &d); err != nil {
+		t.Fatal(err)
+	}
+
+	names := []string{}
+	d.MetaMembers()(func(name string, _ json.RawMessage) bool {
+		names = append(names, name)
+		return true
+	})
+	assert.Equal(t, []string{"total", "cursor"}, names)
+
+	got, err := json.Marshal(&d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, orderedDocumentJson, string(got))
+
+	got, err = json.Marshal(d.Clone())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, orderedDocumentJson, string(got))
+}
+
+func TestDocument_Resources(t *testing.T) {
+	type testCase struct {
+		doc      string
+		pointers []string
+		ids      []string
+	}
+
+	testCases := []testCase{
+		{doc: orderedDocumentJson, pointers: []string{"/data/0", "/data/1", "/included/0"}, ids: []string{`"1"`, `"2"`, `"9"`}},
+		{doc: `{"data":{"type":"articles","id":"1"}}`, pointers: []string{"/data"}, ids: []string{`"1"`}},
+		{doc: `{"data":null}`, pointers: []string{}, ids: []string{}},
+		{doc: `{"meta":{}}`, pointers: []string{}, ids: []string{}},
+	}
+
+	for _, tc := range testCases {
+		d := Document{}
+		if err := json.Unmarshal([]byte(tc.doc), &d); err != nil {
+			t.Fatal(err)
+		}
+		pointers, ids := []string{}, []string{}
+		d.Resources()(func(pointer string, r *Resource) bool {
+			pointers = append(pointers, pointer)
+			ids = append(ids, string(r.Id))
+			return true
+		})
+		assert.Equal(t, tc.pointers, pointers, tc.doc)
+		assert.Equal(t, tc.ids, ids, tc.doc)
+	}
+
+	// iteration stops when yield returns false
+	d := Document{}
+	if err := json.Unmarshal([]byte(orderedDocumentJson), &d); err != nil {
+		t.Fatal(err)
+	}
+	n := 0
+	d.Resources()(func(string, *Resource) bool {
+		n++
+		return false
+	})
+	assert.Equal(t, 1, n)
+}
+
+func TestObjectKeys(t *testing.T) {
+	type testCase struct {
+		Json     string
+		Expected []string
+	}
+
+	testCases := []testCase{
+		{`{}`, nil},
+		{`{"a": 1, "b": [{"c": 2}], "d": "e:f"}`, []string{"a", "b", "d"}},
+		{` { "x\\" : "\"y\"" , "A" : null } `, []string{`x\`, "A"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Json, func(t *testing.T) {
+			assert.Equal(t, tc.Expected, objectKeys([]byte(tc.Json)))
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+const (
+	// pagination links
+	LinkFirst = "first"
+	LinkPrev  = "prev"
+	LinkNext  = "next"
+	LinkLast  = "last"
+	// pagination meta members
+	PageMetaTotal      = "total"
+	PageMetaTotalPages = "totalPages"
+)
+
+// PageLinks returns the first, prev, next and last links of the page p
+// of a collection of total resources, whose URL is base, to set as
+// the links of a collection document. The links keep the query
+// parameters of base other than the page parameters, eg its filters,
+// and have those of p's style, or else page[number] and page[size]
+// for unpaginated requests with a size, eg the default size set by
+// ParsePage. The prev and next links are null on the first and last
+// pages. Cursor pagination isn't supported, as its links depend on the
+// cursors of the collection rather than its size.
+func PageLinks(base string, p Page, total int) (map[string]*Link, error) {
+	u, err := url.Parse(base)
+	if err != nil {
+		return nil, fmt.Errorf("jsonapi: parsing url: %w", err)
+	}
+	if p.Style == PageStyleCursor {
+		return nil, fmt.Errorf("jsonapi: %w: cursor pagination links", ErrUnsupportedPaging)
+	}
+	if p.Size < 1 {
+		return nil, fmt.Errorf("jsonapi: %w: page size %d", ErrInvalidValue, p.Size)
+	}
+
+	q := u.Query()
+	for key := range q {
+		if _, ok := familyMember(key, QueryParamPage); ok {
+			q.Del(key)
+		}
+	}
+	link := func(first int) *Link {
+		values := url.Values{}
+		for key, vs := range q {
+			values[key] = vs
+		}
+		if p.Style == PageStyleOffset {
+			values.Set(QueryParamPage+"["+PageParamOffset+"]", strconv.Itoa(first))
+			values.Set(QueryParamPage+"["+PageParamLimit+"]", strconv.Itoa(p.Size))
+		} else {
+			values.Set(QueryParamPage+"["+PageParamNumber+"]", strconv.Itoa(first/p.Size+1))
+			values.Set(QueryParamPage+"["+PageParamSize+"]", strconv.Itoa(p.Size))
+		}
+		lu := *u
+		lu.RawQuery = values.Encode()
+		return &Link{LinkString: lu.String()}
+	}
+
+	// the links are built from the index of the first
+	// resource of their pages, which are aligned to the
+	// page size, except for offset pagination
+	skip := p.Skip()
+	last := max(totalPages(p, total)-1, 0) * p.Size
+	links := map[string]*Link{
+		LinkFirst: link(0),
+		LinkLast:  link(last),
+		LinkPrev:  {Null: true},
+		LinkNext:  {Null: true},
+	}
+	if skip > 0 {
+		links[LinkPrev] = link(min(max(skip-p.Size, 0), last))
+	}
+	if skip+p.Size < total {
+		links[LinkNext] = link(skip + p.Size)
+	}
+	return links, nil
+}
+
+// PageMeta returns the total number of resources of a paginated
+// collection, and its number of pages of p's size, if any, as the
+// total and totalPages meta members, to set as the meta of a
+// collection document alongside PageLinks.
+func PageMeta(p Page, total int) map[string]json.RawMessage {
+	meta := map[string]json.RawMessage{
+		PageMetaTotal: json.RawMessage(strconv.Itoa(total)),
+	}
+	if p.Size > 0 {
+		meta[PageMetaTotalPages] = json.RawMessage(strconv.Itoa(totalPages(p, total)))
+	}
+	return meta
+}
+
+// totalPages returns the number of pages of p's size of a collection
+// of total resources, which is at least one, so that empty collections
+// have a first and last page.
+func totalPages(p Page, total int) int {
+	return max((total+p.Size-1)/p.Size, 1)
+}
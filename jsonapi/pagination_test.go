@@ -0,0 +1,150 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPageLinks(t *testing.T) {
+	type testCase struct {
+		Name     string
+		Base     string
+		Page     Page
+		Total    int
+		Expected map[string]string
+	}
+
+	for _, tc := range []testCase{
+		{
+			Name:  "first page",
+			Base:  "/articles",
+			Page:  Page{Style: PageStyleNumber, Number: 1, Size: 10},
+			Total: 25,
+			Expected: map[string]string{
+				LinkFirst: "/articles?page%5Bnumber%5D=1&page%5Bsize%5D=10",
+				LinkLast:  "/articles?page%5Bnumber%5D=3&page%5Bsize%5D=10",
+				LinkNext:  "/articles?page%5Bnumber%5D=2&page%5Bsize%5D=10",
+			},
+		},
+		{
+			Name:  "last page",
+			Base:  "https://example.com/articles?filter%5Btitle%5D=go&page%5Bnumber%5D=3",
+			Page:  Page{Style: PageStyleNumber, Number: 3, Size: 10},
+			Total: 25,
+			Expected: map[string]string{
+				LinkFirst: "https://example.com/articles?filter%5Btitle%5D=go&page%5Bnumber%5D=1&page%5Bsize%5D=10",
+				LinkLast:  "https://example.com/articles?filter%5Btitle%5D=go&page%5Bnumber%5D=3&page%5Bsize%5D=10",
+				LinkPrev:  "https://example.com/articles?filter%5Btitle%5D=go&page%5Bnumber%5D=2&page%5Bsize%5D=10",
+			},
+		},
+		{
+			Name:  "beyond the last page",
+			Base:  "/articles",
+			Page:  Page{Style: PageStyleNumber, Number: 5, Size: 10},
+			Total: 25,
+			Expected: map[string]string{
+				LinkFirst: "/articles?page%5Bnumber%5D=1&page%5Bsize%5D=10",
+				LinkLast:  "/articles?page%5Bnumber%5D=3&page%5Bsize%5D=10",
+				LinkPrev:  "/articles?page%5Bnumber%5D=3&page%5Bsize%5D=10",
+			},
+		},
+		{
+			Name:  "offset",
+			Base:  "/articles",
+			Page:  Page{Style: PageStyleOffset, Offset: 5, Size: 10},
+			Total: 25,
+			Expected: map[string]string{
+				LinkFirst: "/articles?page%5Blimit%5D=10&page%5Boffset%5D=0",
+				LinkLast:  "/articles?page%5Blimit%5D=10&page%5Boffset%5D=20",
+				LinkPrev:  "/articles?page%5Blimit%5D=10&page%5Boffset%5D=0",
+				LinkNext:  "/articles?page%5Blimit%5D=10&page%5Boffset%5D=15",
+			},
+		},
+		{
+			Name:  "unpaginated",
+			Base:  "/articles",
+			Page:  Page{Size: 10},
+			Total: 0,
+			Expected: map[string]string{
+				LinkFirst: "/articles?page%5Bnumber%5D=1&page%5Bsize%5D=10",
+				LinkLast:  "/articles?page%5Bnumber%5D=1&page%5Bsize%5D=10",
+			},
+		},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			links, err := PageLinks(tc.Base, tc.Page, tc.Total)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := map[string]string{}
+			for name, l := range links {
+				if l.Null {
+					continue
+				}
+				got[name] = l.Href()
+			}
+			assert.Equal(t, tc.Expected, got)
+			// unavailable links are null
+			assert.Len(t, links, 4)
+		})
+	}
+}
+
+func TestPageLinks_Errors(t *testing.T) {
+	_, err := PageLinks("/articles", Page{Style: PageStyleCursor, Cursor: "abc", Size: 10}, 25)
+	assert.ErrorIs(t, err, ErrUnsupportedPaging)
+
+	_, err = PageLinks("/articles", Page{}, 25)
+	assert.ErrorIs(t, err, ErrInvalidValue)
+
+	_, err = PageLinks("%", Page{Size: 10}, 25)
+	assert.Error(t, err)
+}
+
+func TestPageMeta(t *testing.T) {
+	assert.Equal(t, map[string]json.RawMessage{
+		PageMetaTotal:      json.RawMessage("25"),
+		PageMetaTotalPages: json.RawMessage("3"),
+	}, PageMeta(Page{Style: PageStyleNumber, Number: 1, Size: 10}, 25))
+
+	assert.Equal(t, map[string]json.RawMessage{
+		PageMetaTotal: json.RawMessage("25"),
+	}, PageMeta(Page{Style: PageStyleCursor}, 25))
+}
+
+func TestPageLinks_Document(t *testing.T) {
+	p := Page{Style: PageStyleNumber, Number: 1, Size: 1}
+	links, err := PageLinks("/articles", p, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := FormatDocument(docArticlesValue[:1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Links = links
+	d.Meta = PageMeta(p, 2)
+
+	data, err := json.Marshal(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{
+		"data": [{
+			"type": "articles",
+			"id": "1",
+			"attributes": {"title": "Hello"},
+			"relationships": {"author": {"data": {"type": "people", "id": "2"}}}
+		}],
+		"links": {
+			"first": "/articles?page%5Bnumber%5D=1&page%5Bsize%5D=1",
+			"last": "/articles?page%5Bnumber%5D=2&page%5Bsize%5D=1",
+			"prev": null,
+			"next": "/articles?page%5Bnumber%5D=2&page%5Bsize%5D=1"
+		},
+		"meta": {"total": 2, "totalPages": 2}
+	}`
+	assert.JSONEq(t, expected, string(data))
+}
@@ -0,0 +1,98 @@
+package jsonapi
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+)
+
+// fieldPointer returns the JSON pointer, relative to the resource
+// object, of the member of f called name. This is f's own member,
+// except for catch-all fields, whose members are named by their
+// errors.
+func fieldPointer(f field, name string) string {
+	switch f.tag.typ {
+	case TagValueId, TagValueLid:
+		return "/" + f.tag.typ
+	case TagValueAttr, TagValueAttrMap:
+		return "/attributes/" + pointerToken(name)
+	case TagValueRel:
+		return "/relationships/" + pointerToken(name)
+	case TagValueMeta, TagValueMetaMap:
+		if f.tag.rel != "" {
+			return "/relationships/" + pointerToken(f.tag.rel) + "/meta/" + pointerToken(f.tag.relMember)
+		}
+		return "/meta/" + pointerToken(name)
+	case TagValueLink:
+		if f.tag.rel != "" {
+			return "/relationships/" + pointerToken(f.tag.rel) + "/links/" + pointerToken(f.tag.relMember)
+		}
+		return "/links/" + pointerToken(name)
+	}
+	return ""
+}
+
+// setPointer sets the pointer of the first UnmarshalErr or MarshalErr
+// in the chain of err, if it has none, to pointer, returning err.
+func setPointer(err error, pointer string) error {
+	if _, p := errPointer(err); p != nil && *p == "" {
+		*p = pointer
+	}
+	return err
+}
+
+// setFieldPointer sets the pointer of the first UnmarshalErr or
+// MarshalErr in the chain of err, if it has none, to that of the member
+// of f it names, returning err.
+func setFieldPointer(err error, f field) error {
+	if name, p := errPointer(err); p != nil && *p == "" {
+		*p = fieldPointer(f, name)
+	}
+	return err
+}
+
+// prefixPointers prefixes the pointer of the first UnmarshalErr or
+// MarshalErr in the chain of err, or of each of its joined errors, with
+// prefix, eg "/data/0", returning err.
+func prefixPointers(err error, prefix string) error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, err := range joined.Unwrap() {
+			prefixPointers(err, prefix)
+		}
+		return err
+	}
+	if _, p := errPointer(err); p != nil && *p != "" {
+		*p = prefix + *p
+	}
+	return err
+}
+
+// dataPointer returns the JSON pointer of the resource r in the
+// primary data rs. Resources that aren't in rs, as merged by
+// DuplicateMerge, are at the position of their first duplicate.
+func dataPointer(rs []*Resource, r *Resource) string {
+	i := slices.Index(rs, r)
+	if i < 0 {
+		key := identifierKey(r.ResourceIdentifier)
+		i = slices.IndexFunc(rs, func(d *Resource) bool {
+			return d != nil && identifierKey(d.ResourceIdentifier) == key
+		})
+	}
+	return "/data/" + strconv.Itoa(i)
+}
+
+// errPointer returns the field, and a pointer to the JSON pointer,
+// of the first UnmarshalErr or MarshalErr in the chain of err, or a
+// nil pointer if there is none.
+func errPointer(err error) (string, *string) {
+	for err != nil {
+		switch e := err.(type) {
+		case *UnmarshalErr:
+			return e.Field, &e.Pointer
+		case *MarshalErr:
+			return e.Field, &e.Pointer
+		}
+		err = errors.Unwrap(err)
+	}
+	return "", nil
+}
@@ -0,0 +1,151 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type pointerArticle struct {
+	Id       int            `jsonapi:"id,articles,string"`
+	Title    string         `jsonapi:"attr,title"`
+	Author   int            `jsonapi:"rel,author,people,string"`
+	Tags     []string       `jsonapi:"rel,tags,tags"`
+	Views    int            `jsonapi:"meta,views"`
+	Total    int            `jsonapi:"meta,total,rel=tags"`
+	Extra    map[string]int `jsonapi:"attr-map"`
+	Reviewer string         `jsonapi:"rel,a/b,people"`
+}
+
+func TestUnmarshalErr_Pointer(t *testing.T) {
+	type testCase struct {
+		name     string
+		resource string
+		pointer  string
+	}
+
+	testCases := []testCase{
+		{"id", `"id": "x"`, "/id"},
+		{"attribute", `"id": "1", "attributes": {"title": 1}`, "/attributes/title"},
+		{"catch-all", `"id": "1", "attributes": {"title": "Hello", "words": "many"}`, "/attributes/words"},
+		{"relationship", `"id": "1", "relationships": {"author": {"data": {"type": "people", "id": "x"}}}`, "/relationships/author"},
+		{"escaped", `"id": "1", "relationships": {"a/b": {"data": {"type": "people", "id": 1}}}`, "/relationships/a~1b"},
+		{"meta", `"id": "1", "meta": {"views": "many"}`, "/meta/views"},
+		{"relationship meta", `"id": "1", "relationships": {"tags": {"meta": {"total": "many"}}}`, "/relationships/tags/meta/total"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := `{"type": "articles", ` + tc.resource + `}`
+			err := UnmarshalResource([]byte(r), &pointerArticle{})
+			var ue *UnmarshalErr
+			if assert.ErrorAs(t, err, &ue) {
+				assert.Equal(t, tc.pointer, ue.Pointer)
+			}
+
+			err = UnmarshalDocument([]byte(`{"data": `+r+`}`), &pointerArticle{})
+			if assert.ErrorAs(t, err, &ue) {
+				assert.Equal(t, "/data"+tc.pointer, ue.Pointer)
+			}
+		})
+	}
+}
+
+func TestUnmarshalErr_PointerCollection(t *testing.T) {
+	data := `{"data": [
+		{"type": "articles", "id": "1"},
+		{"type": "articles", "id": "2", "attributes": {"title": 1}, "meta": {"views": "many"}}
+	]}`
+
+	var ue *UnmarshalErr
+	err := UnmarshalDocument([]byte(data), &[]pointerArticle{})
+	if assert.ErrorAs(t, err, &ue) {
+		assert.Equal(t, "/data/1/attributes/title", ue.Pointer)
+	}
+
+	err = UnmarshalDocument([]byte(data), &[]pointerArticle{}, WithAllErrors())
+	var pointers []string
+	for _, err := range err.(interface{ Unwrap() []error }).Unwrap() {
+		if assert.ErrorAs(t, err, &ue) {
+			pointers = append(pointers, ue.Pointer)
+		}
+	}
+	assert.Equal(t, []string{"/data/1/attributes/title", "/data/1/meta/views"}, pointers)
+
+	// merged duplicates are at the position of the first
+	data = `{"data": [
+		{"type": "articles", "id": "1"},
+		{"type": "articles", "id": "2"},
+		{"type": "articles", "id": "1", "attributes": {"title": 1}}
+	]}`
+	err = UnmarshalDocument([]byte(data), &[]pointerArticle{}, WithDuplicates(DuplicateMerge))
+	if assert.ErrorAs(t, err, &ue) {
+		assert.Equal(t, "/data/0/attributes/title", ue.Pointer)
+	}
+	err = UnmarshalDocument([]byte(data), &[]pointerArticle{}, WithDuplicates(DuplicateKeepLast))
+	if assert.ErrorAs(t, err, &ue) {
+		assert.Equal(t, "/data/2/attributes/title", ue.Pointer)
+	}
+
+	// single resources accepted as collections
+	err = UnmarshalDocument([]byte(`{"data": {"type": "articles", "id": "x"}}`), &[]pointerArticle{}, WithSingleAsCollection())
+	if assert.ErrorAs(t, err, &ue) {
+		assert.Equal(t, "/data/id", ue.Pointer)
+	}
+}
+
+func TestUnmarshalErr_PointerStringIds(t *testing.T) {
+	data := `{"type": "articles", "id": "1", "relationships": {"tags": {"data": [{"type": "tags", "id": "1"}, {"type": "tags", "id": 2}]}}}`
+	var ue *UnmarshalErr
+	err := UnmarshalResource([]byte(data), &pointerArticle{}, WithStringIds())
+	if assert.ErrorAs(t, err, &ue) {
+		assert.Equal(t, "/relationships/tags/data/1/id", ue.Pointer)
+	}
+
+	err = UnmarshalResource([]byte(`{"type": "articles", "id": "1", "meta": {"a b!": 1}}`), &docArticle{}, WithStrictMemberNames())
+	if assert.ErrorAs(t, err, &ue) {
+		assert.Equal(t, "/meta/a b!", ue.Pointer)
+	}
+}
+
+func TestUnmarshalErr_PointerRelationship(t *testing.T) {
+	var ue *UnmarshalErr
+	err := UnmarshalRelationship([]byte(`{"data": {"type": "people", "id": "x"}}`), &pointerArticle{}, "author")
+	if assert.ErrorAs(t, err, &ue) {
+		assert.Equal(t, "/data", ue.Pointer)
+	}
+
+	err = UnmarshalRelationship([]byte(`{"data": [{"type": "people", "id": "1"}]}`), &pointerArticle{}, "author")
+	if assert.ErrorAs(t, err, &ue) {
+		assert.Equal(t, "/data", ue.Pointer)
+	}
+}
+
+type pointerMarshaler struct{}
+
+func (pointerMarshaler) MarshalJSON() ([]byte, error) {
+	return nil, errors.New("failed")
+}
+
+func TestMarshalErr_Pointer(t *testing.T) {
+	type article struct {
+		Id    string           `jsonapi:"id,articles"`
+		Title pointerMarshaler `jsonapi:"attr,title"`
+	}
+
+	var me *MarshalErr
+	_, err := MarshalDocument(article{Id: "1"})
+	if assert.ErrorAs(t, err, &me) {
+		assert.Equal(t, "/attributes/title", me.Pointer)
+	}
+
+	_, err = MarshalResource(struct {
+		Id  json.RawMessage `jsonapi:"id,articles"`
+		Tag json.RawMessage `jsonapi:"rel,tag,tags"`
+	}{json.RawMessage(`"1"`), json.RawMessage(`{}`)}, WithStringIds())
+	if assert.ErrorAs(t, err, &me) {
+		assert.Equal(t, "/relationships/tag/data/id", me.Pointer)
+	}
+}
@@ -0,0 +1,137 @@
+package jsonapi
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type commentable interface {
+	commentable()
+}
+
+type polyArticle struct {
+	Id    int    `jsonapi:"id,articles,string"`
+	Title string `jsonapi:"attr,title"`
+}
+
+func (*polyArticle) commentable() {}
+
+type polyPhoto struct {
+	Id string `jsonapi:"id,photos"`
+}
+
+func (polyPhoto) commentable() {}
+
+func (typedModel) commentable() {}
+
+type polyVideoId string
+
+func (polyVideoId) commentable() {}
+
+type polyComment struct {
+	Id     string      `jsonapi:"id,comments"`
+	Target commentable `jsonapi:"rel,commentable"`
+}
+
+func newPolyRegistry() *Registry {
+	reg := NewRegistry()
+	reg.RegisterType("articles", &polyArticle{})
+	reg.RegisterType("photos", polyPhoto{})
+	reg.RegisterType("videos", polyVideoId(""))
+	return reg
+}
+
+func TestMarshalResource_PolymorphicRel(t *testing.T) {
+	type testCase struct {
+		Name     string
+		In       polyComment
+		Expected string
+	}
+
+	testCases := []testCase{
+		{"struct ptr", polyComment{"1", &polyArticle{Id: 2, Title: "Hello"}}, `{"type": "articles", "id": "2"}`},
+		{"struct", polyComment{"1", polyPhoto{"3"}}, `{"type": "photos", "id": "3"}`},
+		{"id", polyComment{"1", polyVideoId("4")}, `{"type": "videos", "id": "4"}`},
+		{"nil", polyComment{"1", nil}, `{"id": null}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			got, err := MarshalResource(&tc.In, WithRegistry(newPolyRegistry()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := `{"type": "comments", "id": "1", "relationships": {"commentable": {"data": ` + tc.Expected + `}}}`
+			assert.Equal(t, fmtJson(t, []byte(want)), fmtJson(t, got))
+		})
+	}
+}
+
+func TestMarshalResource_PolymorphicRel_Unregistered(t *testing.T) {
+	// structs default to their declared type
+	got, err := MarshalResource(&polyComment{"1", polyPhoto{"3"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"type": "comments", "id": "1", "relationships": {"commentable": {"data": {"type": "photos", "id": "3"}}}}`
+	assert.Equal(t, fmtJson(t, []byte(want)), fmtJson(t, got))
+
+	_, err = MarshalResource(&polyComment{"1", polyVideoId("4")})
+	assert.ErrorIs(t, err, ErrUnregisteredType)
+}
+
+func TestUnmarshalResource_PolymorphicRel(t *testing.T) {
+	type testCase struct {
+		Name     string
+		Linkage  string
+		Expected commentable
+	}
+
+	testCases := []testCase{
+		{"struct ptr", `{"type": "articles", "id": "2"}`, &polyArticle{Id: 2}},
+		{"struct", `{"type": "photos", "id": "3"}`, polyPhoto{"3"}},
+		{"id", `{"type": "videos", "id": "4"}`, polyVideoId("4")},
+		{"null", `null`, nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			in := `{"type": "comments", "id": "1", "relationships": {"commentable": {"data": ` + tc.Linkage + `}}}`
+
+			got := polyComment{}
+			if err := UnmarshalResource([]byte(in), &got, WithRegistry(newPolyRegistry())); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, polyComment{"1", tc.Expected}, got)
+		})
+	}
+}
+
+func TestUnmarshalResource_PolymorphicRel_Err(t *testing.T) {
+	in := `{"type": "comments", "id": "1", "relationships": {"commentable": {"data": {"type": "people", "id": "2"}}}}`
+	err := UnmarshalResource([]byte(in), &polyComment{}, WithRegistry(newPolyRegistry()))
+	assert.ErrorIs(t, err, ErrUnregisteredType)
+
+	// registered types must implement the field's interface
+	reg := NewRegistry()
+	reg.RegisterType("people", "")
+	err = UnmarshalResource([]byte(in), &polyComment{}, WithRegistry(reg))
+	var unmarshalErr *UnmarshalErr
+	assert.ErrorAs(t, err, &unmarshalErr)
+}
+
+func TestRegistry_RegisterType(t *testing.T) {
+	reg := NewRegistry()
+	reg.RegisterType("articles", &polyArticle{})
+	reg.RegisterType("posts", polyArticle{})
+
+	s := reg.Snapshot()
+	_, ok := s.Type("articles")
+	assert.False(t, ok)
+
+	typ, ok := s.TypeName(reflect.TypeFor[*polyArticle]())
+	assert.True(t, ok)
+	assert.Equal(t, "posts", typ)
+}
@@ -0,0 +1,70 @@
+package jsonapi
+
+import "sync"
+
+// maxPooledMembers is the largest number of members of a resource
+// that is returned to the pool, so that the pool doesn't hold on to
+// the large maps of occasional outsized resources.
+const maxPooledMembers = 256
+
+// resourcePool holds empty Resources, with their maps allocated,
+// for reuse by calls whose resources don't outlive them, eg
+// MarshalResource.
+var resourcePool = sync.Pool{
+	New: func() any {
+		r := newResource()
+		return &r
+	},
+}
+
+// acquireResource returns an empty Resource from the pool,
+// which can be returned with releaseResource once it is no
+// longer used.
+func acquireResource() *Resource {
+	r := resourcePool.Get().(*Resource)
+	r.pooled = true
+	return r
+}
+
+// releaseResource clears r and returns it to the pool, if it was
+// acquired from the pool and hasn't already been released. r must
+// not be used afterwards.
+func releaseResource(r *Resource) {
+	if r == nil || !r.pooled {
+		return
+	}
+	r.pooled = false
+
+	n := len(r.Meta) + len(r.Attributes) + len(r.ToOneRelationships) + len(r.ToManyRelationships)
+	if n > maxPooledMembers {
+		return
+	}
+
+	clear(r.Meta)
+	clear(r.Attributes)
+	clear(r.ToOneRelationships)
+	clear(r.ToManyRelationships)
+	*r = Resource{
+		ResourceIdentifier:  ResourceIdentifier{Meta: r.Meta},
+		Attributes:          r.Attributes,
+		ToOneRelationships:  r.ToOneRelationships,
+		ToManyRelationships: r.ToManyRelationships,
+	}
+	resourcePool.Put(r)
+}
+
+// releaseDocument returns the resources of d to the pool.
+// d must not be used afterwards.
+func releaseDocument(d *Document) {
+	for _, r := range d.resources() {
+		releaseResource(r)
+	}
+}
+
+// keepDocument marks the resources of d as owned by the caller,
+// so that they aren't returned to the pool.
+func keepDocument(d *Document) {
+	for _, r := range d.resources() {
+		r.pooled = false
+	}
+}
@@ -0,0 +1,78 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReleaseResource(t *testing.T) {
+	r := acquireResource()
+	r.Type = "articles"
+	r.Id = []byte(`"1"`)
+	r.Attributes["title"] = []byte(`"Hello"`)
+	r.Links = map[string]*Link{"self": {LinkString: "/articles/1"}}
+
+	attrs := r.Attributes
+	releaseResource(r)
+	assert.Equal(t, "", r.Type)
+	assert.Nil(t, r.Id)
+	assert.Nil(t, r.Links)
+	assert.Empty(t, attrs)
+	// so that it isn't released again
+	assert.False(t, r.pooled)
+}
+
+func TestReleaseResource_NotPooled(t *testing.T) {
+	r := &Resource{Attributes: map[string]json.RawMessage{"title": []byte(`"Hello"`)}}
+	releaseResource(r)
+	assert.Len(t, r.Attributes, 1)
+}
+
+func TestFormatResource_NotPooled(t *testing.T) {
+	r, err := FormatResource(docArticleValue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.False(t, r.pooled)
+
+	d, err := FormatDocument(docArticlesValue, WithIncluded(docArticleValue))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range d.resources() {
+		assert.False(t, r.pooled)
+	}
+}
+
+func TestMarshalResource_Pooled(t *testing.T) {
+	type sparseArticle struct {
+		Id    string `jsonapi:"id,articles"`
+		Title string `jsonapi:"attr,title,omitempty"`
+		Body  string `jsonapi:"attr,body,omitempty"`
+	}
+
+	// reused resources don't keep the members of previous calls
+	for _, test := range []struct {
+		article sparseArticle
+		json    string
+	}{
+		{sparseArticle{Id: "1", Title: "Hello", Body: "World"}, `{"type":"articles","id":"1","attributes":{"body":"World","title":"Hello"}}`},
+		{sparseArticle{Id: "2"}, `{"type":"articles","id":"2"}`},
+	} {
+		for range 3 {
+			got, err := MarshalResource(test.article)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.JSONEq(t, test.json, string(got))
+
+			got, err = MarshalDocument(test.article)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.JSONEq(t, `{"data":`+test.json+`}`, string(got))
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package jsonapi
+
+import (
+	"context"
+	"maps"
+	"reflect"
+)
+
+// IncludePredicate reports whether a relationship of parent, the
+// struct being marshaled, is included in documents, eg depending
+// on the permissions or feature flags found in ctx.
+type IncludePredicate func(ctx context.Context, parent any) bool
+
+// WithIncludePredicate omits the relationship rel of resources of
+// type typ when p returns false for them, along with its links and
+// meta, and the included resources that are only referenced through
+// it. The context is that of WithContext, if any.
+func WithIncludePredicate(typ, rel string, p IncludePredicate) Option {
+	return func(o *options) {
+		preds := maps.Clone(o.includePredicates)
+		if preds == nil {
+			preds = map[string]map[string]IncludePredicate{}
+		}
+		relPreds := maps.Clone(preds[typ])
+		if relPreds == nil {
+			relPreds = map[string]IncludePredicate{}
+		}
+		relPreds[rel] = p
+		preds[typ] = relPreds
+		o.includePredicates = preds
+	}
+}
+
+// excludedRels returns the names of the relationships of v, a
+// resource of type typ, whose include predicates return false.
+func (o *options) excludedRels(v reflect.Value, typ string) map[string]bool {
+	var excluded map[string]bool
+	for rel, p := range o.includePredicates[typ] {
+		ctx := o.ctx
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		if p(ctx, v.Interface()) {
+			continue
+		}
+		if excluded == nil {
+			excluded = map[string]bool{}
+		}
+		excluded[rel] = true
+	}
+	return excluded
+}
+
+// excludeRel records the resources linked by the relationship field f
+// of v as excluded, so that they can be pruned from the included
+// resources unless referenced elsewhere.
+func excludeRel(v reflect.Value, f field, o *options) error {
+	if o.excluded == nil {
+		return nil
+	}
+	r := acquireResource()
+	defer releaseResource(r)
+	if err := marshalRel(v, r, f, o); err != nil {
+		return err
+	}
+	for _, id := range references(r) {
+		o.excluded[identifierKey(id)] = true
+	}
+	return nil
+}
+
+// pruneExcluded returns d's included resources, without those that
+// were excluded and are not referenced by the primary data, directly
+// or through other included resources.
+func pruneExcluded(d *Document, excluded map[string]bool) []*Resource {
+	if len(excluded) == 0 {
+		return d.Included
+	}
+
+	byKey := make(map[string]*Resource, len(d.Included))
+	for _, r := range d.Included {
+		byKey[identifierKey(r.ResourceIdentifier)] = r
+	}
+
+	reachable := map[string]bool{}
+	var visit func(r *Resource)
+	visit = func(r *Resource) {
+		for _, id := range references(r) {
+			k := identifierKey(id)
+			if reachable[k] {
+				continue
+			}
+			reachable[k] = true
+			if inc, ok := byKey[k]; ok {
+				visit(inc)
+			}
+		}
+	}
+	if d.Data != nil {
+		if d.Data.Resource != nil {
+			visit(d.Data.Resource)
+		}
+		for _, r := range d.Data.Resources {
+			visit(r)
+		}
+	}
+
+	included := d.Included[:0:0]
+	for _, r := range d.Included {
+		k := identifierKey(r.ResourceIdentifier)
+		if !excluded[k] || reachable[k] {
+			included = append(included, r)
+		}
+	}
+	return included
+}
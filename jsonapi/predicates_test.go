@@ -0,0 +1,100 @@
+package jsonapi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type gatedArticle struct {
+	Id          string   `jsonapi:"id,articles"`
+	Author      string   `jsonapi:"rel,author,people"`
+	Reviewers   []string `jsonapi:"rel,reviewers,people"`
+	ReviewCount int      `jsonapi:"meta,count,rel=reviewers"`
+	Draft       bool     `jsonapi:"attr,draft"`
+}
+
+type gatedPerson struct {
+	Id string `jsonapi:"id,people"`
+}
+
+type ctxKey struct{}
+
+func TestMarshalDocument_IncludePredicate(t *testing.T) {
+	articles := []gatedArticle{
+		{Id: "1", Author: "2", Reviewers: []string{"3", "2"}, ReviewCount: 2, Draft: true},
+		{Id: "2", Author: "4", Reviewers: []string{"5"}, ReviewCount: 1},
+	}
+	people := []gatedPerson{{"2"}, {"3"}, {"4"}, {"5"}}
+
+	// reviewers are only visible to editors, or on drafts
+	pred := func(ctx context.Context, parent any) bool {
+		return ctx.Value(ctxKey{}) == "editor" || parent.(gatedArticle).Draft
+	}
+
+	got, err := MarshalDocument(articles,
+		WithIncluded(people),
+		WithIncludePredicate("articles", "reviewers", pred),
+		WithContext(context.WithValue(context.Background(), ctxKey{}, "reader")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `
+	{
+		"data": [
+			{
+				"type": "articles",
+				"id": "1",
+				"attributes": {"draft": true},
+				"relationships": {
+					"author": {"data": {"type": "people", "id": "2"}},
+					"reviewers": {"data": [{"type": "people", "id": "3"}, {"type": "people", "id": "2"}], "meta": {"count": 2}}
+				}
+			},
+			{
+				"type": "articles",
+				"id": "2",
+				"attributes": {"draft": false},
+				"relationships": {
+					"author": {"data": {"type": "people", "id": "4"}}
+				}
+			}
+		],
+		"included": [
+			{"type": "people", "id": "2"},
+			{"type": "people", "id": "3"},
+			{"type": "people", "id": "4"}
+		]
+	}`
+	assert.Equal(t, fmtJson(t, []byte(want)), fmtJson(t, got))
+
+	// editors see every relationship
+	got, err = MarshalDocument(articles,
+		WithIncluded(people),
+		WithIncludePredicate("articles", "reviewers", pred),
+		WithContext(context.WithValue(context.Background(), ctxKey{}, "editor")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := Document{}
+	if err := d.UnmarshalJSON(got); err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, d.Included, 4)
+	assert.Contains(t, d.Data.Resources[1].ToManyRelationships, "reviewers")
+}
+
+func TestMarshalResource_IncludePredicate_Registered(t *testing.T) {
+	reg := NewRegistry()
+	reg.RegisterTypeOptions("articles", WithIncludePredicate("articles", "author", func(context.Context, any) bool {
+		return false
+	}))
+
+	got, err := MarshalResource(gatedArticle{Id: "1", Author: "2"}, WithRegistry(reg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"type": "articles", "id": "1", "attributes": {"draft": false}, "relationships": {"reviewers": {"data": [], "meta": {"count": 0}}}}`
+	assert.Equal(t, fmtJson(t, []byte(want)), fmtJson(t, got))
+}
@@ -0,0 +1,83 @@
+package jsonapi
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// TagValuePresence declares a field of type Presence, or a pointer
+// to one, that records which members were present when unmarshaling.
+const TagValuePresence = "presence"
+
+var presenceType = reflect.TypeFor[Presence]()
+
+// Presence records the names of the members that were present in an
+// unmarshaled resource, so that servers can tell which attributes and
+// relationships a partial update sets. Members under old names accepted
+// by WithMemberRenames are recorded under their new names.
+type Presence struct {
+	Attributes    map[string]bool
+	Relationships map[string]bool
+	Meta          map[string]bool
+}
+
+// PresenceOf returns the Presence of the members of r.
+func PresenceOf(r *Resource) Presence {
+	p := Presence{
+		Attributes:    make(map[string]bool, len(r.Attributes)),
+		Relationships: make(map[string]bool, len(r.ToOneRelationships)+len(r.ToManyRelationships)),
+		Meta:          make(map[string]bool, len(r.Meta)),
+	}
+	for name := range r.Attributes {
+		p.Attributes[name] = true
+	}
+	for name := range r.ToOneRelationships {
+		p.Relationships[name] = true
+	}
+	for name := range r.ToManyRelationships {
+		p.Relationships[name] = true
+	}
+	for name := range r.Meta {
+		p.Meta[name] = true
+	}
+	return p
+}
+
+// HasAttribute returns true if the attribute called name was present.
+func (p Presence) HasAttribute(name string) bool {
+	return p.Attributes[name]
+}
+
+// HasRelationship returns true if the relationship called name was present.
+func (p Presence) HasRelationship(name string) bool {
+	return p.Relationships[name]
+}
+
+// HasMeta returns true if the meta member called name was present.
+func (p Presence) HasMeta(name string) bool {
+	return p.Meta[name]
+}
+
+// parsePresenceTag parses a presence tag, eg `jsonapi:"presence"`.
+func parsePresenceTag(f reflect.StructField) (tag, error) {
+	if derefType(f.Type) != presenceType {
+		return tag{}, &TagErr{f.Name, fmt.Errorf("presence must be a Presence")}
+	}
+	return tag{
+		typ:  TagValuePresence,
+		name: TagValuePresence,
+	}, nil
+}
+
+// unmarshalPresence stores the Presence of r in the presence field f of v.
+func unmarshalPresence(v reflect.Value, r *Resource, f field) error {
+	fv, err := initFieldByIndex(v, f.idxs)
+	if err != nil {
+		return err
+	}
+	if fv, err = derefValue(fv); err != nil {
+		return err
+	}
+	fv.Set(reflect.ValueOf(PresenceOf(r)))
+	return nil
+}
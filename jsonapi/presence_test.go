@@ -0,0 +1,69 @@
+package jsonapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type presenceArticle struct {
+	Id       string    `jsonapi:"id,articles"`
+	Title    string    `jsonapi:"attr,title"`
+	Subtitle string    `jsonapi:"attr,subtitle"`
+	Author   string    `jsonapi:"rel,author,people"`
+	Present  *Presence `jsonapi:"presence"`
+}
+
+func TestUnmarshalResource_Presence(t *testing.T) {
+	in := `
+	{
+		"type": "articles",
+		"id": "1",
+		"attributes": {"subtitle": "", "body": "x"},
+		"meta": {"rev": 2}
+	}`
+
+	got := presenceArticle{}
+	if err := UnmarshalResource([]byte(in), &got); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, &Presence{
+		Attributes:    map[string]bool{"subtitle": true, "body": true},
+		Relationships: map[string]bool{},
+		Meta:          map[string]bool{"rev": true},
+	}, got.Present)
+	assert.False(t, got.Present.HasAttribute("title"))
+	assert.True(t, got.Present.HasAttribute("subtitle"))
+	assert.False(t, got.Present.HasRelationship("author"))
+	assert.True(t, got.Present.HasMeta("rev"))
+}
+
+func TestUnmarshalResource_Presence_Renamed(t *testing.T) {
+	type tp struct {
+		Id      string   `jsonapi:"id,articles"`
+		Title   string   `jsonapi:"attr,title"`
+		Present Presence `jsonapi:"presence"`
+	}
+
+	got := tp{}
+	in := `{"type": "articles", "id": "1", "attributes": {"headline": "Hello"}}`
+	if err := UnmarshalResource([]byte(in), &got, WithMemberRenames(map[string]string{"headline": "title"})); err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, got.Present.HasAttribute("title"))
+	assert.False(t, got.Present.HasAttribute("headline"))
+}
+
+func TestMarshalResource_Presence(t *testing.T) {
+	got, err := MarshalResource(presenceArticle{Id: "1", Author: "2", Present: &Presence{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"type": "articles", "id": "1", "attributes": {"title": "", "subtitle": ""}, "relationships": {"author": {"data": {"type": "people", "id": "2"}}}}`
+	assert.Equal(t, fmtJson(t, []byte(want)), fmtJson(t, got))
+
+	_, err = MarshalResource(struct {
+		Present map[string]bool `jsonapi:"presence"`
+	}{})
+	assert.ErrorAs(t, err, addrOf(&TagErr{}))
+}
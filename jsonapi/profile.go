@@ -0,0 +1,93 @@
+package jsonapi
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+)
+
+// Profile is a JSON:API profile implemented outside of this package,
+// eg a draft profile for partial relationships or typed filters.
+// Profiles are registered with a Registry, and may implement any of
+// the hook interfaces QueryParser, DocumentEncoder and DocumentDecoder
+// to take part in parsing queries, and marshaling and unmarshaling
+// documents.
+//
+// Profile support is experimental: the Profile, QueryParser and
+// QueryParams interfaces may gain methods, and the order in which
+// hooks run may change, in minor releases. The Query.Profiles field
+// and the registration methods will not be removed.
+type Profile interface {
+	// URI uniquely identifies the profile, and is the value
+	// used in the "profile" media type parameter.
+	URI() string
+}
+
+// QueryParser is implemented by profiles that define query
+// parameters. It is called by ParseQuery, and the QueryParams
+// it returns, if not nil, are stored in Query.Profiles.
+type QueryParser interface {
+	ParseQuery(q url.Values) (QueryParams, error)
+}
+
+// QueryParams are the parsed query parameters of a profile.
+type QueryParams interface {
+	// Values returns the parameters in canonical form,
+	// which are added to those returned by Query.Values.
+	Values() url.Values
+}
+
+// RegisterProfile registers p, replacing any profile
+// previously registered with the same URI.
+func (r *Registry) RegisterProfile(p Profile) {
+	r.update(func(s *RegistrySnapshot) {
+		s.profiles = slices.DeleteFunc(slices.Clone(s.profiles), func(e Profile) bool {
+			return e.URI() == p.URI()
+		})
+		s.profiles = append(s.profiles, p)
+	})
+}
+
+// RegisterProfile registers p with DefaultRegistry.
+func RegisterProfile(p Profile) {
+	DefaultRegistry.RegisterProfile(p)
+}
+
+// Profiles returns the registered profiles,
+// in order of registration.
+func (s *RegistrySnapshot) Profiles() []Profile {
+	return slices.Clone(s.profiles)
+}
+
+// Profile returns the profile registered with the supplied URI.
+func (s *RegistrySnapshot) Profile(uri string) (Profile, bool) {
+	for _, p := range s.profiles {
+		if p.URI() == uri {
+			return p, true
+		}
+	}
+	return nil, false
+}
+
+// parseProfileQueries runs the QueryParser hooks of profiles.
+func parseProfileQueries(q url.Values, profiles []Profile) (map[string]QueryParams, error) {
+	var params map[string]QueryParams
+	for _, p := range profiles {
+		qp, ok := p.(QueryParser)
+		if !ok {
+			continue
+		}
+		v, err := qp.ParseQuery(q)
+		if err != nil {
+			return nil, fmt.Errorf("jsonapi: profile %s: %w", p.URI(), err)
+		}
+		if v == nil {
+			continue
+		}
+		if params == nil {
+			params = map[string]QueryParams{}
+		}
+		params[p.URI()] = v
+	}
+	return params, nil
+}
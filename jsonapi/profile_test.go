@@ -0,0 +1,109 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// partialProfile parses a "partial" parameter naming relationships
+// whose linkage is truncated, and records it in the document's meta.
+type partialProfile struct {
+	decoded bool
+}
+
+type partialParams struct {
+	Rels []string
+}
+
+func (p partialParams) Values() url.Values {
+	return url.Values{"partial": p.Rels}
+}
+
+func (*partialProfile) URI() string {
+	return "https://example.com/profiles/partial"
+}
+
+func (*partialProfile) ParseQuery(q url.Values) (QueryParams, error) {
+	rels, ok := q["partial"]
+	if !ok {
+		return nil, nil
+	}
+	for _, rel := range rels {
+		if rel == "" {
+			return nil, fmt.Errorf("empty relationship")
+		}
+	}
+	return partialParams{rels}, nil
+}
+
+func (*partialProfile) EncodeDocument(d *Document) error {
+	d.Meta = map[string]json.RawMessage{"partial": json.RawMessage(`true`)}
+	return nil
+}
+
+func (p *partialProfile) DecodeDocument(d *Document) error {
+	p.decoded = string(d.Meta["partial"]) == "true"
+	return nil
+}
+
+func TestRegistry_RegisterProfile(t *testing.T) {
+	p := &partialProfile{}
+	reg := NewRegistry()
+	reg.RegisterProfile(p)
+	reg.RegisterProfile(p)
+
+	s := reg.Snapshot()
+	assert.Equal(t, []Profile{p}, s.Profiles())
+	assert.Equal(t, `application/vnd.api+json; profile="https://example.com/profiles/partial"`, s.MediaType())
+
+	got, ok := s.Profile("https://example.com/profiles/partial")
+	assert.True(t, ok)
+	assert.Equal(t, p, got)
+
+	reg.RegisterExtension(otherExt{})
+	assert.Equal(t, `application/vnd.api+json; ext="https://example.com/ext/other"; profile="https://example.com/profiles/partial"`, reg.Snapshot().MediaType())
+}
+
+func TestParseQuery_Profiles(t *testing.T) {
+	cfg := QueryConfig{Profiles: []Profile{&partialProfile{}}}
+
+	q, err := ParseQuery(url.Values{"include": {"author"}, "partial": {"comments"}}, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, map[string]QueryParams{"https://example.com/profiles/partial": partialParams{[]string{"comments"}}}, q.Profiles)
+	assert.Equal(t, "include=author&partial=comments", q.Encode())
+
+	q, err = ParseQuery(url.Values{}, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Nil(t, q.Profiles)
+
+	_, err = ParseQuery(url.Values{"partial": {""}}, cfg)
+	assert.ErrorContains(t, err, "https://example.com/profiles/partial: empty relationship")
+}
+
+func TestProfile_Hooks(t *testing.T) {
+	p := &partialProfile{}
+	reg := NewRegistry()
+	reg.RegisterProfile(p)
+
+	got, err := MarshalDocument(docArticleValue, WithRegistry(reg))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"meta": {"partial": true}, ` + docArticleJson[3:]
+	assert.Equal(t, fmtJson(t, []byte(want)), fmtJson(t, got))
+
+	out := docArticle{}
+	if err := UnmarshalDocument(got, &out, WithRegistry(reg)); err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, p.decoded)
+}
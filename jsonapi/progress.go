@@ -0,0 +1,99 @@
+package jsonapi
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// Progress describes the work done so far by an Encoder or Decoder,
+// or by a single call of MarshalDocument or UnmarshalDocument, for
+// reporting the progress of long-running imports and exports.
+type Progress struct {
+	// Documents is the number of documents completed.
+	Documents int
+	// Resources is the number of primary and included resources
+	// encoded or decoded, including those of the current document.
+	Resources int
+	// Bytes is the size of the documents completed.
+	Bytes int64
+}
+
+// WithProgress sets a function that is called with the Progress of
+// an Encoder, Decoder, MarshalDocument or UnmarshalDocument every
+// ctxCheckInterval primary resources of a collection, and whenever
+// a document is completed. If it returns an error, encoding or
+// decoding stops, returning the error wrapped, so that the function
+// can also serve as a cancellation checkpoint. It's called on the
+// goroutine doing the encoding or decoding.
+func WithProgress(f func(Progress) error) Option {
+	return func(o *options) {
+		o.progressFunc = f
+	}
+}
+
+// progress accumulates the Progress of completed documents. It is
+// safe for concurrent use, so that it can be read, eg by a goroutine
+// reporting on a job, while it's updated.
+type progress struct {
+	documents atomic.Int64
+	resources atomic.Int64
+	bytes     atomic.Int64
+}
+
+func (p *progress) load() Progress {
+	return Progress{
+		Documents: int(p.documents.Load()),
+		Resources: int(p.resources.Load()),
+		Bytes:     p.bytes.Load(),
+	}
+}
+
+// progressCheckpoint calls the progress function, if any, every
+// ctxCheckInterval resources, with the progress of the completed
+// documents and the n resources of the current one done so far.
+func (o *options) progressCheckpoint(n int) error {
+	if o.progressFunc == nil || n%ctxCheckInterval != 0 {
+		return nil
+	}
+	p := o.progress.load()
+	p.Resources += n
+	return o.callProgress(p)
+}
+
+// reportProgress adds the completed document d, whose encoding
+// is n bytes, to the progress, and calls the progress function,
+// if any.
+func reportProgress(d *Document, n int, o *options) error {
+	if o.progress == nil {
+		return nil
+	}
+	s := documentStats(d, n)
+	o.progress.documents.Add(1)
+	o.progress.resources.Add(int64(s.Resources + s.Included))
+	o.progress.bytes.Add(int64(n))
+	if o.progressFunc == nil {
+		return nil
+	}
+	return o.callProgress(o.progress.load())
+}
+
+func (o *options) callProgress(p Progress) error {
+	if err := o.progressFunc(p); err != nil {
+		return fmt.Errorf("jsonapi: reporting progress: %w", err)
+	}
+	return nil
+}
+
+// Progress returns the progress of the encoder, across
+// all the documents it has encoded. It's safe to call
+// concurrently with Encode.
+func (e *Encoder) Progress() Progress {
+	return e.progress.load()
+}
+
+// Progress returns the progress of the decoder, across
+// all the documents it has decoded. It's safe to call
+// concurrently with Decode.
+func (d *Decoder) Progress() Progress {
+	return d.progress.load()
+}
@@ -0,0 +1,153 @@
+package jsonapi
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithProgress(t *testing.T) {
+	var got []Progress
+	collect := WithProgress(func(p Progress) error {
+		got = append(got, p)
+		return nil
+	})
+
+	data, err := MarshalDocument(docArticlesValue, collect, WithIncluded(&docArticleValue))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []Progress{{Documents: 1, Resources: 3, Bytes: int64(len(data))}}, got)
+
+	got = nil
+	if err := UnmarshalDocument([]byte(docArticleJson), &docArticle{}, collect); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []Progress{{Documents: 1, Resources: 1, Bytes: int64(len(docArticleJson))}}, got)
+}
+
+func TestWithProgress_Checkpoints(t *testing.T) {
+	articles := make([]docArticle, 2*ctxCheckInterval+1)
+	for i := range articles {
+		articles[i] = docArticle{Id: i + 1}
+	}
+
+	var got []Progress
+	collect := WithProgress(func(p Progress) error {
+		got = append(got, p)
+		return nil
+	})
+
+	data, err := MarshalDocument(articles, collect)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Progress{
+		{Resources: ctxCheckInterval},
+		{Resources: 2 * ctxCheckInterval},
+		{Documents: 1, Resources: len(articles), Bytes: int64(len(data))},
+	}
+	assert.Equal(t, want, got)
+
+	got = nil
+	if err := UnmarshalDocument(data, &[]docArticle{}, collect); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestWithProgress_Cancel(t *testing.T) {
+	errStop := errors.New("stop")
+	articles := make([]docArticle, 2*ctxCheckInterval)
+	for i := range articles {
+		articles[i] = docArticle{Id: i + 1}
+	}
+
+	calls := 0
+	stop := WithProgress(func(p Progress) error {
+		calls++
+		return errStop
+	})
+
+	_, err := MarshalDocument(articles, stop)
+	assert.ErrorIs(t, err, errStop)
+	assert.ErrorContains(t, err, "jsonapi: reporting progress: stop")
+	assert.Equal(t, 1, calls)
+
+	data, err := MarshalDocument(articles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	calls = 0
+	err = UnmarshalDocument(data, &[]docArticle{}, stop)
+	assert.ErrorIs(t, err, errStop)
+	assert.Equal(t, 1, calls)
+
+	// the final report can also fail the call
+	err = UnmarshalDocument([]byte(docArticleJson), &docArticle{}, stop)
+	assert.ErrorIs(t, err, errStop)
+}
+
+func TestEncoder_Progress(t *testing.T) {
+	var got []Progress
+	buf := bytes.Buffer{}
+	enc := NewEncoder(&buf, WithProgress(func(p Progress) error {
+		got = append(got, p)
+		return nil
+	}))
+	assert.Equal(t, Progress{}, enc.Progress())
+
+	if err := enc.Encode(docArticleValue); err != nil {
+		t.Fatal(err)
+	}
+	n := buf.Len()
+	if err := enc.Encode(docArticlesValue); err != nil {
+		t.Fatal(err)
+	}
+
+	want := Progress{Documents: 2, Resources: 3, Bytes: int64(buf.Len())}
+	assert.Equal(t, want, enc.Progress())
+	assert.Equal(t, []Progress{{Documents: 1, Resources: 1, Bytes: int64(n)}, want}, got)
+
+	// failed documents aren't counted
+	assert.Error(t, enc.Encode(1))
+	assert.Equal(t, want, enc.Progress())
+}
+
+func TestDecoder_Progress(t *testing.T) {
+	dec := NewDecoder(strings.NewReader(docArticleJson + docArticlesJson))
+	if err := dec.Decode(&docArticle{}); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, Progress{Documents: 1, Resources: 1, Bytes: int64(len(docArticleJson))}, dec.Progress())
+
+	if err := dec.Decode(&[]docArticle{}); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, Progress{Documents: 2, Resources: 3, Bytes: int64(len(docArticleJson) + len(docArticlesJson))}, dec.Progress())
+	assert.Equal(t, io.EOF, dec.Decode(&docArticle{}))
+}
+
+func TestDecoder_ProgressConcurrent(t *testing.T) {
+	data := strings.Repeat(strings.TrimSpace(docArticlesJson), 100)
+	dec := NewDecoder(strings.NewReader(data))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for dec.Progress().Documents < 100 {
+		}
+	}()
+
+	for dec.More() {
+		if err := dec.Decode(&[]docArticle{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	<-done
+	assert.Equal(t, Progress{Documents: 100, Resources: 200, Bytes: int64(len(data))}, dec.Progress())
+}
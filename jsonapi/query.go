@@ -0,0 +1,499 @@
+package jsonapi
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	// query parameters
+	QueryParamInclude = "include"
+	QueryParamSort    = "sort"
+	// query parameter families
+	QueryParamFields = "fields"
+	QueryParamPage   = "page"
+	// page parameters
+	PageParamNumber = "number"
+	PageParamSize   = "size"
+	PageParamOffset = "offset"
+	PageParamLimit  = "limit"
+	PageParamCursor = "cursor"
+)
+
+type QueryErr struct {
+	Param string
+	Err   error
+}
+
+func (e *QueryErr) Error() string {
+	return "query error on parameter '" + e.Param + "': " + e.Err.Error()
+}
+
+func (e *QueryErr) Unwrap() error {
+	return e.Err
+}
+
+var (
+	ErrMixedPageStyles   = fmt.Errorf("mixed pagination styles")
+	ErrUnsupportedPaging = fmt.Errorf("unsupported pagination style")
+	ErrUnknownParam      = fmt.Errorf("unknown parameter")
+	ErrInvalidValue      = fmt.Errorf("invalid value")
+)
+
+// PageStyle identifies the pagination strategy selected by a client.
+type PageStyle int
+
+const (
+	// PageStyleNone means no page parameters were supplied.
+	PageStyleNone PageStyle = iota
+	// PageStyleNumber is page[number] / page[size] pagination.
+	PageStyleNumber
+	// PageStyleOffset is page[offset] / page[limit] pagination.
+	PageStyleOffset
+	// PageStyleCursor is page[cursor] / page[size] pagination.
+	PageStyleCursor
+)
+
+// Page holds the parsed page parameters of a request.
+// Size is the number of resources per page, regardless of
+// whether it was supplied as page[size] or page[limit].
+type Page struct {
+	Style  PageStyle
+	Number int
+	Size   int
+	Offset int
+	Cursor string
+}
+
+// Skip returns the number of resources that precede the page,
+// or zero for cursor-based and unpaginated requests.
+func (p Page) Skip() int {
+	switch p.Style {
+	case PageStyleNumber:
+		return (p.Number - 1) * p.Size
+	case PageStyleOffset:
+		return p.Offset
+	default:
+		return 0
+	}
+}
+
+// PageConfig controls how page parameters are parsed.
+type PageConfig struct {
+	// DefaultSize is used when no page size or limit is supplied.
+	DefaultSize int
+	// MaxSize caps the page size or limit. Zero means no cap.
+	MaxSize int
+	// Styles lists the accepted pagination styles. If empty, all
+	// styles are accepted.
+	Styles []PageStyle
+}
+
+// ParsePage extracts the page[...] parameters from q.
+func ParsePage(q url.Values, cfg PageConfig) (Page, error) {
+	params := map[string]string{}
+	for key, values := range q {
+		name, ok := familyMember(key, QueryParamPage)
+		if !ok {
+			continue
+		}
+		switch name {
+		case PageParamNumber, PageParamSize, PageParamOffset, PageParamLimit, PageParamCursor:
+		default:
+			return Page{}, &QueryErr{key, ErrUnknownParam}
+		}
+		if len(values) > 0 {
+			params[name] = values[len(values)-1]
+		}
+	}
+
+	p := Page{Size: cfg.DefaultSize}
+
+	_, hasNumber := params[PageParamNumber]
+	_, hasSize := params[PageParamSize]
+	_, hasOffset := params[PageParamOffset]
+	_, hasLimit := params[PageParamLimit]
+	_, hasCursor := params[PageParamCursor]
+
+	switch {
+	case hasCursor:
+		if hasNumber || hasOffset || hasLimit {
+			return Page{}, &QueryErr{QueryParamPage, ErrMixedPageStyles}
+		}
+		p.Style = PageStyleCursor
+		p.Cursor = params[PageParamCursor]
+	case hasOffset || hasLimit:
+		if hasNumber || hasSize {
+			return Page{}, &QueryErr{QueryParamPage, ErrMixedPageStyles}
+		}
+		p.Style = PageStyleOffset
+	case hasNumber || hasSize:
+		p.Style = PageStyleNumber
+		p.Number = 1
+	default:
+		return p, nil
+	}
+
+	if len(cfg.Styles) > 0 && !slices.Contains(cfg.Styles, p.Style) {
+		return Page{}, &QueryErr{QueryParamPage, ErrUnsupportedPaging}
+	}
+
+	var err error
+	if hasNumber {
+		if p.Number, err = parsePageInt(PageParamNumber, params[PageParamNumber], 1); err != nil {
+			return Page{}, err
+		}
+	}
+	if hasOffset {
+		if p.Offset, err = parsePageInt(PageParamOffset, params[PageParamOffset], 0); err != nil {
+			return Page{}, err
+		}
+	}
+	if hasSize {
+		if p.Size, err = parsePageInt(PageParamSize, params[PageParamSize], 1); err != nil {
+			return Page{}, err
+		}
+	}
+	if hasLimit {
+		if p.Size, err = parsePageInt(PageParamLimit, params[PageParamLimit], 1); err != nil {
+			return Page{}, err
+		}
+	}
+
+	if cfg.MaxSize > 0 && p.Size > cfg.MaxSize {
+		p.Size = cfg.MaxSize
+	}
+
+	// the page must start at an offset that Skip can return
+	if p.Style == PageStyleNumber && p.Size > 0 && p.Number-1 > math.MaxInt/p.Size {
+		return Page{}, &QueryErr{QueryParamPage + "[" + PageParamNumber + "]", ErrInvalidValue}
+	}
+
+	return p, nil
+}
+
+// parsePageInt parses the value of the page parameter
+// name as an integer no smaller than lowest.
+func parsePageInt(name, value string, lowest int) (int, error) {
+	i, err := strconv.Atoi(value)
+	if err != nil || i < lowest {
+		return 0, &QueryErr{QueryParamPage + "[" + name + "]", ErrInvalidValue}
+	}
+	return i, nil
+}
+
+// familyMember returns the member name of a query parameter
+// belonging to the supplied family, eg familyMember("page[size]", "page")
+// returns ("size", true).
+func familyMember(key, family string) (string, bool) {
+	rest, ok := strings.CutPrefix(key, family+"[")
+	if !ok {
+		return "", false
+	}
+	return strings.CutSuffix(rest, "]")
+}
+
+// Query holds the parsed JSON:API query parameters of a request.
+type Query struct {
+	// Include lists the relationship paths to include.
+	Include []string
+	// Fields maps resource types to their sparse fieldsets.
+	Fields map[string][]string
+	// Sort lists the sort fields in order of precedence, with
+	// descending fields prefixed with "-".
+	Sort []string
+	// Page holds the pagination parameters.
+	Page Page
+	// Filter holds the parsed filter expression, if any.
+	Filter FilterExpr
+	// Profiles holds the query parameters parsed by
+	// profiles' QueryParser hooks, by profile URI.
+	Profiles map[string]QueryParams
+}
+
+// QueryConfig controls how query parameters are parsed.
+type QueryConfig struct {
+	// Page configures the page parameters.
+	Page PageConfig
+	// FilterOperators is the set of accepted filter operators.
+	// If nil, DefaultFilterOperators is used.
+	FilterOperators FilterOperators
+	// Profiles are the profiles whose QueryParser hooks are run.
+	// If nil, those registered with DefaultRegistry are used.
+	Profiles []Profile
+}
+
+// ParseQuery extracts the include, fields, sort, page and filter
+// parameters from q, and any parameters parsed by profiles. Other
+// parameters are ignored.
+func ParseQuery(q url.Values, cfg QueryConfig) (*Query, error) {
+	page, err := ParsePage(q, cfg.Page)
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err := ParseFilter(q, cfg.FilterOperators)
+	if err != nil {
+		return nil, err
+	}
+
+	profiles := cfg.Profiles
+	if profiles == nil {
+		profiles = DefaultRegistry.Snapshot().profiles
+	}
+	params, err := parseProfileQueries(q, profiles)
+	if err != nil {
+		return nil, err
+	}
+
+	query := &Query{
+		Include:  splitList(q[QueryParamInclude]),
+		Sort:     splitList(q[QueryParamSort]),
+		Page:     page,
+		Filter:   filter,
+		Profiles: params,
+	}
+
+	for key, values := range q {
+		typ, ok := familyMember(key, QueryParamFields)
+		if !ok {
+			continue
+		}
+		if typ == "" {
+			return nil, &QueryErr{key, ErrInvalidValue}
+		}
+		if query.Fields == nil {
+			query.Fields = map[string][]string{}
+		}
+		query.Fields[typ] = splitList(values)
+	}
+
+	return query, nil
+}
+
+// Values returns the canonical form of the query: includes and
+// fieldsets are deduplicated and sorted, repeated sort fields are
+// removed, and only the page parameters of the query's style are set.
+// Two queries that apply the same parameters have the same canonical form.
+func (q *Query) Values() url.Values {
+	values := url.Values{}
+
+	if include := sortedSet(q.Include); len(include) > 0 {
+		values.Set(QueryParamInclude, strings.Join(include, ","))
+	}
+
+	for typ, fields := range q.Fields {
+		values.Set(QueryParamFields+"["+typ+"]", strings.Join(sortedSet(fields), ","))
+	}
+
+	if len(q.Sort) > 0 {
+		sort := make([]string, 0, len(q.Sort))
+		seen := map[string]bool{}
+		for _, s := range q.Sort {
+			name := strings.TrimPrefix(s, "-")
+			if !seen[name] {
+				seen[name] = true
+				sort = append(sort, s)
+			}
+		}
+		values.Set(QueryParamSort, strings.Join(sort, ","))
+	}
+
+	setPage := func(name string, value string) {
+		values.Set(QueryParamPage+"["+name+"]", value)
+	}
+
+	switch q.Page.Style {
+	case PageStyleNumber:
+		setPage(PageParamNumber, strconv.Itoa(q.Page.Number))
+		setPage(PageParamSize, strconv.Itoa(q.Page.Size))
+	case PageStyleOffset:
+		setPage(PageParamOffset, strconv.Itoa(q.Page.Offset))
+		setPage(PageParamLimit, strconv.Itoa(q.Page.Size))
+	case PageStyleCursor:
+		setPage(PageParamCursor, q.Page.Cursor)
+		if q.Page.Size > 0 {
+			setPage(PageParamSize, strconv.Itoa(q.Page.Size))
+		}
+	}
+
+	if q.Filter != nil {
+		addFilterValues(values, q.Filter)
+	}
+
+	for _, params := range q.Profiles {
+		for name, vs := range params.Values() {
+			values[name] = vs
+		}
+	}
+
+	return values
+}
+
+// Encode returns the canonical URL-encoded form of the
+// query, sorted by parameter name.
+func (q *Query) Encode() string {
+	return q.Values().Encode()
+}
+
+// splitList splits comma-separated query parameter
+// values into a single list, dropping empty items.
+func splitList(values []string) []string {
+	var list []string
+	for _, v := range values {
+		for _, item := range strings.Split(v, ",") {
+			if item != "" {
+				list = append(list, item)
+			}
+		}
+	}
+	return list
+}
+
+// sortedSet returns a sorted copy of the input
+// with all duplicates removed.
+func sortedSet(in []string) []string {
+	out := slices.Clone(in)
+	slices.Sort(out)
+	return slices.Compact(out)
+}
+
+// QueryBuilder composes the query parameters of a request
+// to a JSON:API server. The zero value is ready to use.
+type QueryBuilder struct {
+	q   Query
+	err error
+}
+
+func NewQueryBuilder() *QueryBuilder {
+	return &QueryBuilder{}
+}
+
+// Include adds relationship paths to the include parameter.
+func (b *QueryBuilder) Include(paths ...string) *QueryBuilder {
+	b.q.Include = append(b.q.Include, paths...)
+	return b
+}
+
+// Fields adds fields to the sparse fieldset of the resource type typ.
+func (b *QueryBuilder) Fields(typ string, fields ...string) *QueryBuilder {
+	if b.q.Fields == nil {
+		b.q.Fields = map[string][]string{}
+	}
+	b.q.Fields[typ] = append(b.q.Fields[typ], fields...)
+	return b
+}
+
+// Sort adds ascending sort fields, or descending fields
+// if prefixed with "-".
+func (b *QueryBuilder) Sort(fields ...string) *QueryBuilder {
+	b.q.Sort = append(b.q.Sort, fields...)
+	return b
+}
+
+// SortDesc adds descending sort fields.
+func (b *QueryBuilder) SortDesc(fields ...string) *QueryBuilder {
+	for _, f := range fields {
+		b.q.Sort = append(b.q.Sort, "-"+f)
+	}
+	return b
+}
+
+// PageNumber requests page[number] / page[size] pagination.
+func (b *QueryBuilder) PageNumber(number, size int) *QueryBuilder {
+	b.q.Page = Page{Style: PageStyleNumber, Number: number, Size: size}
+	return b
+}
+
+// PageOffset requests page[offset] / page[limit] pagination.
+func (b *QueryBuilder) PageOffset(offset, limit int) *QueryBuilder {
+	b.q.Page = Page{Style: PageStyleOffset, Offset: offset, Size: limit}
+	return b
+}
+
+// PageCursor requests page[cursor] pagination, with an
+// optional page[size] if size is positive.
+func (b *QueryBuilder) PageCursor(cursor string, size int) *QueryBuilder {
+	b.q.Page = Page{Style: PageStyleCursor, Cursor: cursor, Size: size}
+	return b
+}
+
+// Filter adds a filter[field][op] condition. Its values are joined
+// with commas, so if there are several, or op is a multi-valued
+// operator of DefaultFilterOperators, eg "in", values containing
+// commas can't be told apart. Such conditions aren't added, and Err
+// returns a *QueryErr wrapping ErrInvalidValue.
+func (b *QueryBuilder) Filter(field, op string, values ...string) *QueryBuilder {
+	if len(values) > 1 || DefaultFilterOperators[op].Multi {
+		for _, v := range values {
+			if strings.Contains(v, ",") {
+				if b.err == nil {
+					b.err = &QueryErr{QueryParamFilter + "[" + field + "][" + op + "]", ErrInvalidValue}
+				}
+				return b
+			}
+		}
+	}
+
+	and, ok := b.q.Filter.(*FilterAnd)
+	if !ok {
+		and = &FilterAnd{}
+		if b.q.Filter != nil {
+			and.Exprs = append(and.Exprs, b.q.Filter)
+		}
+		b.q.Filter = and
+	}
+	and.Exprs = append(and.Exprs, &FilterCondition{
+		Field:    field,
+		Operator: op,
+		Values:   values,
+	})
+	return b
+}
+
+// Err returns the error of the first condition rejected by Filter, if any.
+func (b *QueryBuilder) Err() error {
+	return b.err
+}
+
+// Query returns a copy of the built query, which
+// isn't affected by later calls to the builder.
+func (b *QueryBuilder) Query() *Query {
+	q := b.q
+	q.Include = slices.Clone(q.Include)
+	q.Sort = slices.Clone(q.Sort)
+	if q.Fields != nil {
+		q.Fields = make(map[string][]string, len(b.q.Fields))
+		for typ, fields := range b.q.Fields {
+			q.Fields[typ] = slices.Clone(fields)
+		}
+	}
+	q.Filter = cloneFilter(q.Filter)
+	return &q
+}
+
+// cloneFilter returns a deep copy of the filter expression e.
+func cloneFilter(e FilterExpr) FilterExpr {
+	switch e := e.(type) {
+	case *FilterAnd:
+		and := &FilterAnd{Exprs: make([]FilterExpr, len(e.Exprs))}
+		for i, expr := range e.Exprs {
+			and.Exprs[i] = cloneFilter(expr)
+		}
+		return and
+	case *FilterCondition:
+		c := *e
+		c.Values = slices.Clone(e.Values)
+		return &c
+	}
+	return e
+}
+
+// Encode returns the URL-encoded query string.
+func (b *QueryBuilder) Encode() string {
+	return b.q.Encode()
+}
@@ -0,0 +1,299 @@
+package jsonapi
+
+import (
+	"math"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePage(t *testing.T) {
+	type testCase struct {
+		Query string
+		Cfg   PageConfig
+		Exp   Page
+	}
+
+	testCases := []testCase{
+		{"", PageConfig{DefaultSize: 10}, Page{Size: 10}},
+		{"page[number]=3&page[size]=5", PageConfig{}, Page{Style: PageStyleNumber, Number: 3, Size: 5}},
+		{"page[number]=2", PageConfig{DefaultSize: 10}, Page{Style: PageStyleNumber, Number: 2, Size: 10}},
+		{"page[size]=5", PageConfig{}, Page{Style: PageStyleNumber, Number: 1, Size: 5}},
+		{"page[offset]=20&page[limit]=10", PageConfig{}, Page{Style: PageStyleOffset, Offset: 20, Size: 10}},
+		{"page[limit]=10", PageConfig{}, Page{Style: PageStyleOffset, Size: 10}},
+		{"page[cursor]=abc&page[size]=5", PageConfig{}, Page{Style: PageStyleCursor, Cursor: "abc", Size: 5}},
+		// capped
+		{"page[size]=500", PageConfig{MaxSize: 100}, Page{Style: PageStyleNumber, Number: 1, Size: 100}},
+		{"page[limit]=500", PageConfig{MaxSize: 100}, Page{Style: PageStyleOffset, Size: 100}},
+		// other families are ignored
+		{"sort=title&fields[articles]=title", PageConfig{}, Page{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Query, func(t *testing.T) {
+			q, err := url.ParseQuery(tc.Query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := ParsePage(q, tc.Cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tc.Exp, got)
+		})
+	}
+}
+
+func TestParsePage_Err(t *testing.T) {
+	type testCase struct {
+		Query  string
+		Cfg    PageConfig
+		ExpErr error
+	}
+
+	testCases := []testCase{
+		{"page[number]=1&page[limit]=10", PageConfig{}, ErrMixedPageStyles},
+		{"page[cursor]=abc&page[offset]=10", PageConfig{}, ErrMixedPageStyles},
+		{"page[number]=0", PageConfig{}, ErrInvalidValue},
+		{"page[size]=x", PageConfig{}, ErrInvalidValue},
+		{"page[offset]=-1", PageConfig{}, ErrInvalidValue},
+		{"page[number]=" + strconv.Itoa(math.MaxInt/10+2) + "&page[size]=10", PageConfig{}, ErrInvalidValue},
+		{"page[number]=" + strconv.Itoa(math.MaxInt), PageConfig{DefaultSize: 2}, ErrInvalidValue},
+		{"page[foo]=1", PageConfig{}, ErrUnknownParam},
+		{"page[cursor]=abc", PageConfig{Styles: []PageStyle{PageStyleNumber}}, ErrUnsupportedPaging},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Query, func(t *testing.T) {
+			q, err := url.ParseQuery(tc.Query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = ParsePage(q, tc.Cfg)
+			assert.ErrorAs(t, err, addrOf(&QueryErr{}))
+			assert.ErrorIs(t, err, tc.ExpErr)
+		})
+	}
+}
+
+func TestPageSkip(t *testing.T) {
+	assert.Equal(t, 20, Page{Style: PageStyleNumber, Number: 3, Size: 10}.Skip())
+	assert.Equal(t, 7, Page{Style: PageStyleOffset, Offset: 7, Size: 10}.Skip())
+	assert.Equal(t, 0, Page{Style: PageStyleCursor, Cursor: "abc", Size: 10}.Skip())
+	assert.Equal(t, 0, Page{Size: 10}.Skip())
+
+	// the last page whose offset doesn't overflow
+	q := url.Values{"page[number]": {strconv.Itoa(math.MaxInt/10 + 1)}, "page[size]": {"10"}}
+	p, err := ParsePage(q, PageConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, math.MaxInt/10*10, p.Skip())
+}
+
+func TestParseQuery(t *testing.T) {
+	q, err := url.ParseQuery("include=author,comments.author&fields[articles]=title,body&fields[people]=&sort=-created,title&page[number]=2&page[size]=10&filter[x]=y&foo=bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ParseQuery(q, QueryConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := &Query{
+		Include: []string{"author", "comments.author"},
+		Fields: map[string][]string{
+			"articles": {"title", "body"},
+			"people":   nil,
+		},
+		Sort: []string{"-created", "title"},
+		Page: Page{Style: PageStyleNumber, Number: 2, Size: 10},
+		Filter: &FilterAnd{Exprs: []FilterExpr{
+			&FilterCondition{Field: "x", Operator: FilterOpEq, Values: []string{"y"}},
+		}},
+	}
+	assert.Equal(t, exp, got)
+}
+
+func TestParseQuery_Err(t *testing.T) {
+	for _, in := range []string{"fields[]=title", "page[number]=x", "filter[x][foo]=y"} {
+		t.Run(in, func(t *testing.T) {
+			q, err := url.ParseQuery(in)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = ParseQuery(q, QueryConfig{})
+			assert.ErrorAs(t, err, addrOf(&QueryErr{}))
+		})
+	}
+}
+
+func TestQueryEncode(t *testing.T) {
+	type testCase struct {
+		In  Query
+		Exp string
+	}
+
+	testCases := []testCase{
+		{Query{}, ""},
+		{
+			Query{Include: []string{"comments", "author", "comments"}},
+			"include=author%2Ccomments",
+		},
+		{
+			Query{Fields: map[string][]string{"people": {"name"}, "articles": {"title", "body", "title"}}},
+			"fields%5Barticles%5D=body%2Ctitle&fields%5Bpeople%5D=name",
+		},
+		{
+			Query{Sort: []string{"-created", "title", "created"}},
+			"sort=-created%2Ctitle",
+		},
+		{
+			Query{Page: Page{Style: PageStyleNumber, Number: 2, Size: 10, Offset: 5}},
+			"page%5Bnumber%5D=2&page%5Bsize%5D=10",
+		},
+		{
+			Query{Page: Page{Style: PageStyleOffset, Offset: 20, Size: 10}},
+			"page%5Blimit%5D=10&page%5Boffset%5D=20",
+		},
+		{
+			Query{Page: Page{Style: PageStyleCursor, Cursor: "abc"}},
+			"page%5Bcursor%5D=abc",
+		},
+		{
+			Query{Filter: &FilterAnd{Exprs: []FilterExpr{
+				&FilterCondition{Field: "age", Operator: FilterOpGt, Values: []string{"18"}},
+				&FilterCondition{Field: "name", Operator: FilterOpEq, Values: []string{"bob"}},
+				&FilterCondition{Field: "tag", Operator: FilterOpIn, Values: []string{"a", "b"}},
+			}}},
+			"filter%5Bage%5D%5Bgt%5D=18&filter%5Bname%5D=bob&filter%5Btag%5D%5Bin%5D=a%2Cb",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Exp, func(t *testing.T) {
+			assert.Equal(t, tc.Exp, tc.In.Encode())
+		})
+	}
+}
+
+func TestQueryEncode_RoundTrip(t *testing.T) {
+	q, err := url.ParseQuery("sort=title&include=b,a,b&fields[articles]=title,body&page[offset]=5&page[limit]=500")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	query, err := ParseQuery(q, QueryConfig{Page: PageConfig{MaxSize: 50}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the canonical form echoes the applied (capped) page size
+	got, err := url.ParseQuery(query.Encode())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := url.Values{
+		"sort":             {"title"},
+		"include":          {"a,b"},
+		"fields[articles]": {"body,title"},
+		"page[offset]":     {"5"},
+		"page[limit]":      {"50"},
+	}
+	assert.Equal(t, exp, got)
+}
+
+func TestQueryBuilder(t *testing.T) {
+	b := NewQueryBuilder().
+		Include("author", "comments.author").
+		Fields("articles", "title", "body").
+		Fields("people", "name").
+		Sort("title").
+		SortDesc("created").
+		PageNumber(2, 10).
+		Filter("author.age", FilterOpGe, "18").
+		Filter("tags", FilterOpIn, "go", "json")
+
+	got, err := url.ParseQuery(b.Encode())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := url.Values{
+		"include":                {"author,comments.author"},
+		"fields[articles]":       {"body,title"},
+		"fields[people]":         {"name"},
+		"sort":                   {"title,-created"},
+		"page[number]":           {"2"},
+		"page[size]":             {"10"},
+		"filter[author.age][ge]": {"18"},
+		"filter[tags][in]":       {"go,json"},
+	}
+	assert.Equal(t, exp, got)
+
+	// the rendered query is parsed back into an equivalent query
+	parsed, err := ParseQuery(got, QueryConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, b.Query().Encode(), parsed.Encode())
+}
+
+func TestQueryBuilder_Query(t *testing.T) {
+	b := NewQueryBuilder().
+		Include("author").
+		Fields("articles", "title").
+		Sort("title").
+		Filter("tags", FilterOpIn, "go")
+	q := b.Query()
+	want := &Query{
+		Include: []string{"author"},
+		Fields:  map[string][]string{"articles": {"title"}},
+		Sort:    []string{"title"},
+		Filter:  &FilterAnd{Exprs: []FilterExpr{&FilterCondition{Field: "tags", Operator: FilterOpIn, Values: []string{"go"}}}},
+	}
+	assert.Equal(t, want, q)
+
+	// later calls don't affect the returned query
+	b.Include("comments").Fields("articles", "body").Sort("-created").Filter("title", FilterOpEq, "Hello")
+	q.Filter.(*FilterAnd).Exprs[0].(*FilterCondition).Values[0] = "json"
+	assert.Equal(t, want.Include, q.Include)
+	assert.Equal(t, want.Fields, q.Fields)
+	assert.Equal(t, want.Sort, q.Sort)
+	assert.Len(t, q.Filter.(*FilterAnd).Exprs, 1)
+	assert.Equal(t, "fields%5Barticles%5D=body%2Ctitle&filter%5Btags%5D%5Bin%5D=go&filter%5Btitle%5D=Hello&include=author%2Ccomments&sort=title%2C-created", b.Encode())
+}
+
+func TestQueryBuilder_FilterComma(t *testing.T) {
+	b := NewQueryBuilder().Filter("title", FilterOpEq, "Hello, World")
+	assert.NoError(t, b.Err())
+	assert.Equal(t, "filter%5Btitle%5D=Hello%2C+World", b.Encode())
+
+	// the first rejected condition is reported
+	for param, b := range map[string]*QueryBuilder{
+		"filter[tags][in]":  NewQueryBuilder().Filter("tags", FilterOpIn, "go,json"),
+		"filter[title][eq]": NewQueryBuilder().Filter("title", FilterOpEq, "a", "b,c").Filter("tags", FilterOpIn, "go,json"),
+	} {
+		var qe *QueryErr
+		if assert.ErrorAs(t, b.Err(), &qe) {
+			assert.ErrorIs(t, qe, ErrInvalidValue)
+			assert.Equal(t, param, qe.Param)
+		}
+		assert.Equal(t, "", b.Encode())
+	}
+}
+
+func TestQueryBuilder_Page(t *testing.T) {
+	assert.Equal(t, "page%5Blimit%5D=10&page%5Boffset%5D=30", NewQueryBuilder().PageOffset(30, 10).Encode())
+	assert.Equal(t, "page%5Bcursor%5D=abc", NewQueryBuilder().PageCursor("abc", 0).Encode())
+	assert.Equal(t, "page%5Bcursor%5D=abc&page%5Bsize%5D=5", NewQueryBuilder().PageCursor("abc", 5).Encode())
+	assert.Equal(t, "", (&QueryBuilder{}).Encode())
+}
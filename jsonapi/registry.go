@@ -0,0 +1,136 @@
+package jsonapi
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultRegistry is the registry used unless
+// another is supplied with WithRegistry.
+var DefaultRegistry = NewRegistry()
+
+// Registry holds settings that are registered once per resource
+// type, and applied wherever resources of that type are marshaled
+// or unmarshaled. It is safe for concurrent use.
+//
+// Registrations are copy-on-write: each marshaling or unmarshaling
+// call reads from the snapshot current when it started, and so sees
+// a consistent set of registrations even if others are added while
+// it runs.
+type Registry struct {
+	// serialises writers
+	mu       sync.Mutex
+	snapshot atomic.Pointer[RegistrySnapshot]
+}
+
+// RegistrySnapshot is an immutable view of a Registry.
+type RegistrySnapshot struct {
+	typeOpts   map[string][]Option
+	extensions []Extension
+	profiles   []Profile
+	// converters of errors to error objects
+	errorMappers []ErrorMapper
+	// go types registered for resource types, and vice versa
+	types     map[string]reflect.Type
+	typeNames map[reflect.Type]string
+}
+
+func NewRegistry() *Registry {
+	r := &Registry{}
+	r.snapshot.Store(&RegistrySnapshot{
+		typeOpts:  map[string][]Option{},
+		types:     map[string]reflect.Type{},
+		typeNames: map[reflect.Type]string{},
+	})
+	return r
+}
+
+// Snapshot returns the registry's current registrations. The
+// snapshot is unaffected by subsequent registrations.
+func (r *Registry) Snapshot() *RegistrySnapshot {
+	return r.snapshot.Load()
+}
+
+// update replaces the current snapshot with a modified copy.
+func (r *Registry) update(f func(s *RegistrySnapshot)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	old := r.snapshot.Load()
+	s := &RegistrySnapshot{
+		typeOpts:     maps.Clone(old.typeOpts),
+		extensions:   old.extensions,
+		profiles:     old.profiles,
+		errorMappers: old.errorMappers,
+		types:        maps.Clone(old.types),
+		typeNames:    maps.Clone(old.typeNames),
+	}
+	f(s)
+	r.snapshot.Store(s)
+}
+
+// RegisterTypeOptions registers options that apply to every resource
+// of type typ, overriding those passed to the marshaling and unmarshaling
+// functions. Repeated calls append to the type's options.
+func (r *Registry) RegisterTypeOptions(typ string, opts ...Option) {
+	r.update(func(s *RegistrySnapshot) {
+		s.typeOpts[typ] = slices.Concat(s.typeOpts[typ], opts)
+	})
+}
+
+// TypeOptions returns the options currently registered for type typ.
+func (r *Registry) TypeOptions(typ string) []Option {
+	return r.Snapshot().TypeOptions(typ)
+}
+
+// TypeOptions returns the options registered for type typ.
+func (s *RegistrySnapshot) TypeOptions(typ string) []Option {
+	return slices.Clone(s.typeOpts[typ])
+}
+
+// RegisterTypeOptions registers options for type typ with DefaultRegistry.
+func RegisterTypeOptions(typ string, opts ...Option) {
+	DefaultRegistry.RegisterTypeOptions(typ, opts...)
+}
+
+// RegisterType registers the Go type of a as the type used for resources
+// of type typ in polymorphic relationships, ie relationships declared on
+// interface fields without a resource type. Values are unmarshaled as
+// pointers if a is a pointer. A type, after following pointers, may only
+// be registered for one resource type, and vice versa, so any previous
+// registrations of either are replaced.
+func (r *Registry) RegisterType(typ string, a any) {
+	t := reflect.TypeOf(a)
+	r.update(func(s *RegistrySnapshot) {
+		if old, ok := s.types[typ]; ok {
+			delete(s.typeNames, derefType(old))
+		}
+		if old, ok := s.typeNames[derefType(t)]; ok {
+			delete(s.types, old)
+		}
+		s.types[typ] = t
+		s.typeNames[derefType(t)] = typ
+	})
+}
+
+// RegisterType registers the Go type of a for resource type typ
+// with DefaultRegistry.
+func RegisterType(typ string, a any) {
+	DefaultRegistry.RegisterType(typ, a)
+}
+
+// Type returns the Go type registered for resource type typ.
+func (s *RegistrySnapshot) Type(typ string) (reflect.Type, bool) {
+	t, ok := s.types[typ]
+	return t, ok
+}
+
+// TypeName returns the resource type for which the Go type t,
+// after following pointers, is registered.
+func (s *RegistrySnapshot) TypeName(t reflect.Type) (string, bool) {
+	typ, ok := s.typeNames[derefType(t)]
+	return typ, ok
+}
@@ -0,0 +1,139 @@
+package jsonapi
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type registryArticle struct {
+	Id      string   `jsonapi:"id,articles"`
+	Title   *string  `jsonapi:"attr,title"`
+	Tags    []string `jsonapi:"attr,tags,omitempty"`
+	Summary string   `jsonapi:"attr,summary,omitempty"`
+}
+
+type registryPerson struct {
+	Id   string  `jsonapi:"id,people"`
+	Name *string `jsonapi:"attr,name"`
+}
+
+func TestRegistry_TypeOptions(t *testing.T) {
+	reg := NewRegistry()
+	reg.RegisterTypeOptions("articles", WithOmitNullAttributes())
+	reg.RegisterTypeOptions("articles", WithAlwaysInclude("tags"))
+
+	got, err := MarshalResource(&registryArticle{Id: "1"}, WithRegistry(reg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, fmtJson(t, []byte(`{"type": "articles", "id": "1", "attributes": {"tags": null}}`)), fmtJson(t, got))
+
+	// other types are unaffected
+	got, err = MarshalResource(&registryPerson{Id: "2"}, WithRegistry(reg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, fmtJson(t, []byte(`{"type": "people", "id": "2", "attributes": {"name": null}}`)), fmtJson(t, got))
+}
+
+func TestRegistry_TypeOptions_Document(t *testing.T) {
+	reg := NewRegistry()
+	reg.RegisterTypeOptions("articles", WithEmptyCollections(EmptyAsCollection), WithAlwaysInclude("tags", "summary"))
+
+	in := []registryArticle{{Id: "1", Title: addrOf("Hello")}}
+
+	got, err := MarshalDocument(in, WithRegistry(reg))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"data": [{"type": "articles", "id": "1", "attributes": {"title": "Hello", "tags": [], "summary": ""}}]}`
+	assert.Equal(t, fmtJson(t, []byte(want)), fmtJson(t, got))
+}
+
+func TestRegistry_TypeOptions_OverrideCallSite(t *testing.T) {
+	reg := NewRegistry()
+	reg.RegisterTypeOptions("articles", WithAlwaysInclude("title"))
+
+	got, err := MarshalResource(&registryArticle{Id: "1"}, WithRegistry(reg), WithOmitNullAttributes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, fmtJson(t, []byte(`{"type": "articles", "id": "1", "attributes": {"title": null}}`)), fmtJson(t, got))
+}
+
+func TestRegisterTypeOptions_DefaultRegistry(t *testing.T) {
+	type tp struct {
+		Id   string `jsonapi:"id,registry-default-test"`
+		Name *string
+	}
+
+	RegisterTypeOptions("registry-default-test", WithOmitNullAttributes())
+
+	got, err := MarshalResource(&tp{Id: "1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, fmtJson(t, []byte(`{"type": "registry-default-test", "id": "1"}`)), fmtJson(t, got))
+}
+
+func TestRegistry_Snapshot(t *testing.T) {
+	reg := NewRegistry()
+	reg.RegisterTypeOptions("articles", WithOmitNullAttributes())
+
+	snap := reg.Snapshot()
+	reg.RegisterTypeOptions("articles", WithAlwaysInclude("tags"))
+	reg.RegisterTypeOptions("people", WithOmitNullAttributes())
+
+	assert.Len(t, snap.TypeOptions("articles"), 1)
+	assert.Len(t, snap.TypeOptions("people"), 0)
+	assert.Len(t, reg.TypeOptions("articles"), 2)
+	assert.Len(t, reg.TypeOptions("people"), 1)
+}
+
+func TestRegistry_SnapshotPerCall(t *testing.T) {
+	reg := NewRegistry()
+
+	// the call's snapshot is taken before the
+	// articles options are registered
+	o := newOptions([]Option{WithRegistry(reg)})
+	reg.RegisterTypeOptions("articles", WithOmitNullAttributes())
+
+	r, err := formatStruct(reflect.ValueOf(registryArticle{Id: "1"}), o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Contains(t, r.Attributes, "title")
+
+	// subsequent calls see the registration
+	r, err = FormatResource(registryArticle{Id: "1"}, WithRegistry(reg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NotContains(t, r.Attributes, "title")
+}
+
+func TestRegistry_Concurrent(t *testing.T) {
+	reg := NewRegistry()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			reg.RegisterTypeOptions("articles", WithAlwaysInclude("tags"))
+		}()
+		go func() {
+			defer wg.Done()
+			if _, err := MarshalResource(&registryArticle{Id: "1"}, WithRegistry(reg)); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, reg.TypeOptions("articles"), 10)
+}
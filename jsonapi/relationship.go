@@ -0,0 +1,258 @@
+package jsonapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+var ErrUnknownRelationship = fmt.Errorf("unknown relationship")
+
+// RelationshipDocument is a top-level document whose primary data is
+// the resource linkage of a single relationship, as served and accepted
+// by relationship endpoints, eg /articles/1/relationships/tags.
+type RelationshipDocument struct {
+	Data    *ResourceLinkage           `json:"data,omitempty"`
+	Meta    map[string]json.RawMessage `json:"meta,omitempty"`
+	Links   map[string]*Link           `json:"links,omitempty"`
+	JsonApi *JsonApiObject             `json:"jsonapi,omitempty"`
+}
+
+// ResourceLinkage is the resource linkage of a relationship document:
+// either a single resource identifier (or null) for to-one
+// relationships, or an array of them for to-many relationships.
+type ResourceLinkage struct {
+	// Identifier is the to-one linkage, nil if null.
+	Identifier *ResourceIdentifier
+	// Identifiers is the to-many linkage.
+	Identifiers []ResourceIdentifier
+	// ToMany is true iff the linkage is an array.
+	ToMany bool
+}
+
+func (d *RelationshipDocument) UnmarshalJSON(data []byte) error {
+	type alias RelationshipDocument
+	a := struct {
+		*alias
+		Data json.RawMessage `json:"data"`
+	}{alias: (*alias)(d)}
+
+	if err := json.Unmarshal(data, &a); err != nil {
+		return err
+	}
+
+	// unlike encoding/json, null data is
+	// unmarshaled to a null linkage
+	d.Data = nil
+	if a.Data != nil {
+		d.Data = &ResourceLinkage{}
+		return d.Data.UnmarshalJSON(a.Data)
+	}
+	return nil
+}
+
+func (l *ResourceLinkage) MarshalJSON() ([]byte, error) {
+	if l.ToMany {
+		if l.Identifiers == nil {
+			return []byte("[]"), nil
+		}
+		return json.Marshal(l.Identifiers)
+	}
+	if l.Identifier == nil {
+		return NullJson, nil
+	}
+	return json.Marshal(l.Identifier)
+}
+
+func (l *ResourceLinkage) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	switch {
+	case bytes.Equal(data, NullJson):
+		*l = ResourceLinkage{}
+		return nil
+	case len(data) > 0 && data[0] == '[':
+		*l = ResourceLinkage{ToMany: true, Identifiers: []ResourceIdentifier{}}
+		return json.Unmarshal(data, &l.Identifiers)
+	case len(data) > 0 && data[0] == '{':
+		*l = ResourceLinkage{Identifier: &ResourceIdentifier{}}
+		return json.Unmarshal(data, l.Identifier)
+	default:
+		return fmt.Errorf("cannot unmarshal into resource linkage")
+	}
+}
+
+// FormatRelationship converts the relationship called name of a, which
+// must be a tagged struct, to a relationship document. Nil to-one
+// relationships are converted to null linkage, and nil or empty to-many
+// relationships to empty arrays, regardless of the omitempty tag option.
+func FormatRelationship(a any, name string, opts ...Option) (*RelationshipDocument, error) {
+	v, err := derefValue(reflect.ValueOf(a))
+	if err != nil {
+		return nil, fmt.Errorf("jsonapi: dereferencing input: %w", err)
+	}
+
+	if !v.IsValid() || v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("jsonapi: %w", ErrNotStruct)
+	}
+
+	fields, err := parseTags(v)
+	if err != nil {
+		return nil, fmt.Errorf("jsonapi: parsing tags: %w", err)
+	}
+
+	o := newOptions(opts)
+	o = o.forType(resourceType(v, fields, o))
+	fields = o.nameMembers(fields)
+
+	f, err := relField(fields, name)
+	if err != nil {
+		return nil, err
+	}
+	f.tag.omitempty = false
+
+	r := newResource()
+	if err := marshalRel(v, &r, f, o); err != nil {
+		return nil, fmt.Errorf("jsonapi: marshaling field "+f.tag.name+": %w", err)
+	}
+
+	if rel, ok := r.ToOneRelationships[name]; ok {
+		d := &RelationshipDocument{Data: &ResourceLinkage{}, Meta: rel.Meta, Links: rel.Links}
+		if !bytes.Equal(rel.Data.Id, NullJson) {
+			d.Data.Identifier = &rel.Data
+		}
+		return d, nil
+	}
+
+	rel := r.ToManyRelationships[name]
+	d := &RelationshipDocument{Meta: rel.Meta, Links: rel.Links}
+	if rel.Data != nil {
+		d.Data = &ResourceLinkage{ToMany: true, Identifiers: rel.Data}
+	}
+	return d, nil
+}
+
+// MarshalRelationship returns the relationship document encoding of the
+// relationship called name of a, as described by FormatRelationship.
+func MarshalRelationship(a any, name string, opts ...Option) ([]byte, error) {
+	d, err := FormatRelationship(a, name, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(d)
+	if err != nil {
+		return nil, fmt.Errorf("jsonapi: marshaling document: %w", err)
+	}
+	return data, nil
+}
+
+// DeformatRelationship stores the resource linkage of d in the relationship
+// called name of the struct pointed to by a. Null to-one linkage sets the
+// field to its zero value, and an empty array sets a to-many field to an
+// empty slice. If d has no data, a is unchanged. To-one linkage can only be
+// stored in a to-many field with the WithSingleAsCollection option.
+func DeformatRelationship(d *RelationshipDocument, a any, name string, opts ...Option) error {
+	v := reflect.ValueOf(a)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return ErrNotStructPtr
+	}
+
+	v, err := derefValue(v)
+	if err != nil {
+		return fmt.Errorf("jsonapi: dereferencing input: %w", err)
+	}
+
+	if v.Kind() != reflect.Struct {
+		return ErrNotStructPtr
+	}
+
+	fields, err := parseTags(v)
+	if err != nil {
+		return fmt.Errorf("jsonapi: parsing tags: %w", err)
+	}
+
+	o := newOptions(opts)
+	o = o.forType(resourceType(v, fields, o))
+	fields = o.nameMembers(fields)
+
+	f, err := relField(fields, name)
+	if err != nil {
+		return err
+	}
+
+	if d.Data == nil {
+		return nil
+	}
+
+	fv, err := initFieldByIndex(v, f.idxs[:len(f.idxs)-1])
+	if err != nil {
+		return err
+	}
+	if fv, err = derefValue(fv); err != nil {
+		return err
+	}
+	fv = fv.Field(f.idxs[len(f.idxs)-1])
+
+	data := *d.Data
+	toOne := isToOne(reflect.New(derefType(fv.Type())).Elem())
+	if !toOne && !data.ToMany && o.singleAsCollection {
+		data.ToMany = true
+		if data.Identifier != nil {
+			data.Identifiers = []ResourceIdentifier{*data.Identifier}
+		}
+	}
+	if toOne == data.ToMany {
+		return &UnmarshalErr{Field: f.tag.name, Pointer: "/data", Err: fmt.Errorf("cannot unmarshal linkage into %s", fv.Type())}
+	}
+
+	r := newResource()
+	switch {
+	case toOne && data.Identifier == nil && f.tag.notNull:
+		return fmt.Errorf("jsonapi: unmarshaling field "+f.tag.name+": %w", &UnmarshalErr{Field: f.tag.name, Pointer: "/data", Err: ErrNullMember})
+	case toOne && data.Identifier == nil:
+		fv.Set(reflect.Zero(fv.Type()))
+		return nil
+	case toOne:
+		r.ToOneRelationships[name] = &ToOneResourceLinkage{Data: *data.Identifier}
+	case len(data.Identifiers) == 0:
+		initValue(fv)
+		if fv, err = derefValue(fv); err != nil {
+			return err
+		}
+		if fv.Kind() == reflect.Slice {
+			fv.Set(reflect.MakeSlice(fv.Type(), 0, 0))
+		} else {
+			fv.Set(reflect.Zero(fv.Type()))
+		}
+		return nil
+	default:
+		r.ToManyRelationships[name] = &ToManyResourceLinkage{Data: data.Identifiers}
+	}
+
+	if err := unmarshalRel(v, &r, f, o); err != nil {
+		return fmt.Errorf("jsonapi: unmarshaling field "+f.tag.name+": %w", setPointer(err, "/data"))
+	}
+	return nil
+}
+
+// UnmarshalRelationship parses the relationship document data and stores
+// its resource linkage in the relationship called name of the struct pointed
+// to by a, as described by DeformatRelationship.
+func UnmarshalRelationship(data []byte, a any, name string, opts ...Option) error {
+	d := RelationshipDocument{}
+	if err := json.Unmarshal(data, &d); err != nil {
+		return fmt.Errorf("jsonapi: unmarshaling document: %w", err)
+	}
+	return DeformatRelationship(&d, a, name, opts...)
+}
+
+// relField returns the relationship field called name.
+func relField(fields []field, name string) (field, error) {
+	for _, f := range fields {
+		if f.tag.typ == TagValueRel && f.tag.name == name {
+			return f, nil
+		}
+	}
+	return field{}, fmt.Errorf("jsonapi: %w: %s", ErrUnknownRelationship, name)
+}
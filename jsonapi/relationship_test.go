@@ -0,0 +1,109 @@
+package jsonapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type relDocArticle struct {
+	Id     int     `jsonapi:"id,articles,string"`
+	Author *int    `jsonapi:"rel,author,people,string,omitempty"`
+	Tags   []int   `jsonapi:"rel,tags,tags,string,omitempty"`
+	Refs   *[]int  `jsonapi:"rel,refs,articles,string"`
+	Count  []int   `jsonapi:"rel,count,comments,countonly"`
+	Title  *string `jsonapi:"attr,title"`
+}
+
+func TestMarshalRelationship(t *testing.T) {
+	type testCase struct {
+		Name     string
+		In       relDocArticle
+		Rel      string
+		Expected string
+	}
+
+	testCases := []testCase{
+		{"to-one", relDocArticle{Author: addrOf(2)}, "author", `{"data": {"type": "people", "id": "2"}}`},
+		{"to-one null", relDocArticle{}, "author", `{"data": null}`},
+		{"to-many", relDocArticle{Tags: []int{1, 2}}, "tags", `{"data": [{"type": "tags", "id": "1"}, {"type": "tags", "id": "2"}]}`},
+		{"to-many nil", relDocArticle{}, "tags", `{"data": []}`},
+		{"to-many nil ptr", relDocArticle{}, "refs", `{"data": []}`},
+		{"count only", relDocArticle{Count: []int{1, 2}}, "count", `{"meta": {"count": 2}}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			got, err := MarshalRelationship(&tc.In, tc.Rel)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, fmtJson(t, []byte(tc.Expected)), fmtJson(t, got))
+		})
+	}
+}
+
+func TestMarshalRelationship_Err(t *testing.T) {
+	_, err := MarshalRelationship(&relDocArticle{}, "title")
+	assert.ErrorIs(t, err, ErrUnknownRelationship)
+
+	_, err = MarshalRelationship(0, "author")
+	assert.ErrorIs(t, err, ErrNotStruct)
+}
+
+func TestUnmarshalRelationship(t *testing.T) {
+	type testCase struct {
+		Name     string
+		Json     string
+		Rel      string
+		Expected relDocArticle
+	}
+
+	initial := relDocArticle{Id: 1, Author: addrOf(2), Tags: []int{3}, Refs: &[]int{4}}
+
+	testCases := []testCase{
+		{"to-one", `{"data": {"type": "people", "id": "5"}}`, "author", relDocArticle{Id: 1, Author: addrOf(5), Tags: []int{3}, Refs: &[]int{4}}},
+		{"to-one null", `{"data": null}`, "author", relDocArticle{Id: 1, Tags: []int{3}, Refs: &[]int{4}}},
+		{"to-many", `{"data": [{"type": "tags", "id": "5"}, {"type": "tags", "id": "6"}]}`, "tags", relDocArticle{Id: 1, Author: addrOf(2), Tags: []int{5, 6}, Refs: &[]int{4}}},
+		{"to-many empty", `{"data": []}`, "tags", relDocArticle{Id: 1, Author: addrOf(2), Tags: []int{}, Refs: &[]int{4}}},
+		{"to-many ptr empty", `{"data": []}`, "refs", relDocArticle{Id: 1, Author: addrOf(2), Tags: []int{3}, Refs: &[]int{}}},
+		{"no data", `{"meta": {"count": 1}}`, "tags", initial},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			got := initial
+			got.Author = addrOf(*initial.Author)
+			got.Tags = []int{3}
+			got.Refs = &[]int{4}
+
+			if err := UnmarshalRelationship([]byte(tc.Json), &got, tc.Rel); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tc.Expected, got)
+		})
+	}
+}
+
+func TestUnmarshalRelationship_Err(t *testing.T) {
+	type testCase struct {
+		Name string
+		Json string
+		Rel  string
+		In   any
+	}
+
+	testCases := []testCase{
+		{"array into to-one", `{"data": []}`, "author", &relDocArticle{}},
+		{"object into to-many", `{"data": {"type": "tags", "id": "1"}}`, "tags", &relDocArticle{}},
+		{"unknown relationship", `{"data": []}`, "title", &relDocArticle{}},
+		{"not a pointer", `{"data": []}`, "tags", relDocArticle{}},
+		{"invalid data", `{"data": 1}`, "tags", &relDocArticle{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			assert.Error(t, UnmarshalRelationship([]byte(tc.Json), tc.In, tc.Rel))
+		})
+	}
+}
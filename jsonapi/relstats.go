@@ -0,0 +1,104 @@
+package jsonapi
+
+import (
+	"cmp"
+	"slices"
+)
+
+// RelationshipStats summarises the resource linkage of a document's
+// relationships, eg for logging or enforcing quotas on include-heavy
+// requests.
+type RelationshipStats struct {
+	// Relationships holds the linkage counts of each relationship
+	// of each resource type, sorted by type and then name.
+	Relationships []RelationshipCount
+	// Missing lists the resources referenced by linkage that are
+	// neither primary data nor included, sorted.
+	Missing []GraphNode
+	// Types lists the distinct types of the referenced resources, sorted.
+	Types []string
+}
+
+// RelationshipCount counts the linkage of the relationship called
+// Name across the document's resources of type Type.
+type RelationshipCount struct {
+	Type string
+	Name string
+	// Resources is the number of resources with the relationship.
+	Resources int
+	// Linkage is the total number of resources referenced by the
+	// relationship's linkage, excluding null to-one linkage.
+	Linkage int
+	// Max is the largest number of resources referenced
+	// by a single resource's linkage.
+	Max int
+}
+
+// DocumentRelationshipStats returns the RelationshipStats of the
+// relationships of the resources in d's primary data and included
+// resources.
+func DocumentRelationshipStats(d *Document) *RelationshipStats {
+	rs := d.resources()
+
+	present := map[string]bool{}
+	for _, r := range rs {
+		present[identifierKey(r.ResourceIdentifier)] = true
+	}
+
+	s := &RelationshipStats{}
+	counts := map[[2]string]*RelationshipCount{}
+	missing := map[string]bool{}
+	types := map[string]bool{}
+
+	for _, r := range rs {
+		for _, name := range relationshipNames(r) {
+			var ids []ResourceIdentifier
+			if rel, ok := r.ToOneRelationships[name]; ok {
+				ids = append(ids, rel.Data)
+			}
+			if rel, ok := r.ToManyRelationships[name]; ok {
+				ids = append(ids, rel.Data...)
+			}
+
+			n := 0
+			for _, id := range ids {
+				if isNullIdentifier(id) {
+					continue
+				}
+				n++
+				types[id.Type] = true
+
+				key := identifierKey(id)
+				if !present[key] && !missing[key] {
+					missing[key] = true
+					s.Missing = append(s.Missing, identifierNode(id))
+				}
+			}
+
+			c := counts[[2]string{r.Type, name}]
+			if c == nil {
+				c = &RelationshipCount{Type: r.Type, Name: name}
+				counts[[2]string{r.Type, name}] = c
+			}
+			c.Resources++
+			c.Linkage += n
+			c.Max = max(c.Max, n)
+		}
+	}
+
+	for _, c := range counts {
+		s.Relationships = append(s.Relationships, *c)
+	}
+	slices.SortFunc(s.Relationships, func(a, b RelationshipCount) int {
+		return cmp.Or(cmp.Compare(a.Type, b.Type), cmp.Compare(a.Name, b.Name))
+	})
+	slices.SortFunc(s.Missing, func(a, b GraphNode) int {
+		return cmp.Or(cmp.Compare(a.Type, b.Type), cmp.Compare(a.Id, b.Id))
+	})
+	for typ := range types {
+		s.Types = append(s.Types, typ)
+	}
+	slices.Sort(s.Types)
+
+	return s
+}
@@ -0,0 +1,55 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const relStatsDocJson = `
+{
+	"data": [
+		{
+			"type": "articles", "id": "1",
+			"relationships": {
+				"author": {"data": {"type": "people", "id": "2"}},
+				"tags": {"data": [{"type": "tags", "id": "5"}, {"type": "tags", "id": "6"}]}
+			}
+		},
+		{
+			"type": "articles", "id": "2",
+			"relationships": {
+				"author": {"data": null},
+				"tags": {"data": [{"type": "tags", "id": "5"}]}
+			}
+		}
+	],
+	"included": [
+		{"type": "people", "id": "2", "relationships": {"articles": {"data": [{"type": "articles", "id": "1"}]}}},
+		{"type": "tags", "id": "5"}
+	]
+}`
+
+func TestDocumentRelationshipStats(t *testing.T) {
+	d := Document{}
+	if err := json.Unmarshal([]byte(relStatsDocJson), &d); err != nil {
+		t.Fatal(err)
+	}
+
+	s := DocumentRelationshipStats(&d)
+	assert.Equal(t, []RelationshipCount{
+		{Type: "articles", Name: "author", Resources: 2, Linkage: 1, Max: 1},
+		{Type: "articles", Name: "tags", Resources: 2, Linkage: 3, Max: 2},
+		{Type: "people", Name: "articles", Resources: 1, Linkage: 1, Max: 1},
+	}, s.Relationships)
+	assert.Equal(t, []GraphNode{{"tags", "6"}}, s.Missing)
+	assert.Equal(t, []string{"articles", "people", "tags"}, s.Types)
+}
+
+func TestDocumentRelationshipStats_Empty(t *testing.T) {
+	s := DocumentRelationshipStats(&Document{})
+	assert.Empty(t, s.Relationships)
+	assert.Empty(t, s.Missing)
+	assert.Empty(t, s.Types)
+}
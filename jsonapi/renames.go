@@ -0,0 +1,58 @@
+package jsonapi
+
+import (
+	"maps"
+)
+
+// WithMemberRenames accepts attributes and relationships under old names
+// when unmarshaling, eg during a deprecation window after a member has
+// been renamed, by mapping each old name in renames to its new name, as
+// declared by the struct's tags. If a resource has members under both
+// names, the new one is used.
+func WithMemberRenames(renames map[string]string) Option {
+	return func(o *options) {
+		if o.renames == nil {
+			o.renames = map[string]string{}
+		} else {
+			o.renames = maps.Clone(o.renames)
+		}
+		maps.Copy(o.renames, renames)
+	}
+}
+
+// renameMembers returns r with its attributes and relationships renamed
+// according to renames. r is copied, rather than modified, if any of
+// its members are renamed.
+func renameMembers(r *Resource, renames map[string]string) *Resource {
+	if len(renames) == 0 {
+		return r
+	}
+
+	c := *r
+	c.Attributes = renameKeys(r.Attributes, renames)
+	c.ToOneRelationships = renameKeys(r.ToOneRelationships, renames)
+	c.ToManyRelationships = renameKeys(r.ToManyRelationships, renames)
+	return &c
+}
+
+// renameKeys returns m with its keys renamed according to renames,
+// unless the new key is already present. m is copied, rather than
+// modified, if any of its keys are renamed.
+func renameKeys[V any](m map[string]V, renames map[string]string) map[string]V {
+	copied := false
+	for old, name := range renames {
+		v, ok := m[old]
+		if !ok {
+			continue
+		}
+		if _, ok := m[name]; ok {
+			continue
+		}
+		if !copied {
+			m, copied = maps.Clone(m), true
+		}
+		delete(m, old)
+		m[name] = v
+	}
+	return m
+}
@@ -0,0 +1,70 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnmarshalResource_MemberRenames(t *testing.T) {
+	type article struct {
+		Id      string   `jsonapi:"id,articles"`
+		Title   string   `jsonapi:"attr,title"`
+		Body    string   `jsonapi:"attr,body"`
+		Writer  string   `jsonapi:"rel,writer,people"`
+		Remarks []string `jsonapi:"rel,remarks,comments"`
+	}
+
+	renames := WithMemberRenames(map[string]string{
+		"headline": "title",
+		"text":     "body",
+		"author":   "writer",
+		"comments": "remarks",
+	})
+
+	in := `
+	{
+		"type": "articles",
+		"id": "1",
+		"attributes": {"headline": "Old", "text": "Hello", "body": "New"},
+		"relationships": {
+			"author": {"data": {"type": "people", "id": "2"}},
+			"comments": {"data": [{"type": "comments", "id": "3"}]}
+		}
+	}`
+
+	got := article{}
+	if err := UnmarshalResource([]byte(in), &got, renames); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, article{"1", "Old", "New", "2", []string{"3"}}, got)
+
+	// the resource itself is unchanged
+	r := Resource{}
+	if err := json.Unmarshal([]byte(in), &r); err != nil {
+		t.Fatal(err)
+	}
+	if err := DeformatResource(&r, &article{}, renames); err != nil {
+		t.Fatal(err)
+	}
+	assert.Contains(t, r.Attributes, "headline")
+	assert.Contains(t, r.ToOneRelationships, "author")
+}
+
+func TestUnmarshalResource_MemberRenames_PerType(t *testing.T) {
+	type article struct {
+		Id    string `jsonapi:"id,articles"`
+		Title string `jsonapi:"attr,title"`
+	}
+
+	reg := NewRegistry()
+	reg.RegisterTypeOptions("articles", WithMemberRenames(map[string]string{"headline": "title"}))
+
+	got := article{}
+	in := `{"type": "articles", "id": "1", "attributes": {"headline": "Old"}}`
+	if err := UnmarshalResource([]byte(in), &got, WithRegistry(reg)); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, article{"1", "Old"}, got)
+}
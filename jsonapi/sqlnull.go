@@ -0,0 +1,32 @@
+package jsonapi
+
+import (
+	"reflect"
+	"strings"
+)
+
+// isSqlNull returns true if t is one of the database/sql package's
+// nullable types, eg sql.NullString, sql.NullTime or sql.Null[T],
+// which are structs of a value and whether it is valid. They are
+// marshaled as their value, or null if not valid, rather than as
+// objects, so that database models can be tagged directly.
+func isSqlNull(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct &&
+		t.PkgPath() == "database/sql" &&
+		strings.HasPrefix(t.Name(), "Null") &&
+		t.NumField() == 2 &&
+		t.Field(1).Name == "Valid" &&
+		t.Field(1).Type.Kind() == reflect.Bool
+}
+
+// sqlNullValue returns the value of the sql nullable v, which is
+// invalid if v is not valid, and false if v isn't an sql nullable.
+func sqlNullValue(v reflect.Value) (reflect.Value, bool) {
+	if !isSqlNull(v.Type()) {
+		return v, false
+	}
+	if !v.Field(1).Bool() {
+		return reflect.Value{}, true
+	}
+	return v.Field(0), true
+}
@@ -0,0 +1,83 @@
+package jsonapi
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type sqlNullUser struct {
+	Id       sql.NullInt64    `jsonapi:"id,users,string"`
+	Name     sql.NullString   `jsonapi:"attr,name"`
+	Age      sql.NullInt32    `jsonapi:"attr,age"`
+	Score    sql.NullFloat64  `jsonapi:"attr,score,omitempty"`
+	Active   sql.NullBool     `jsonapi:"attr,active"`
+	Born     sql.NullTime     `jsonapi:"attr,born"`
+	Nickname sql.Null[string] `jsonapi:"attr,nickname"`
+	Version  sql.NullInt16    `jsonapi:"meta,version"`
+}
+
+func TestSqlNull(t *testing.T) {
+	born := time.Date(1990, 5, 1, 0, 0, 0, 0, time.UTC)
+	u := sqlNullUser{
+		Id:       sql.NullInt64{Int64: 7, Valid: true},
+		Name:     sql.NullString{String: "Ann", Valid: true},
+		Born:     sql.NullTime{Time: born, Valid: true},
+		Nickname: sql.Null[string]{V: "annie", Valid: true},
+		Version:  sql.NullInt16{Int16: 2, Valid: true},
+	}
+
+	data, err := MarshalResource(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{
+		"type": "users",
+		"id": "7",
+		"attributes": {
+			"name": "Ann",
+			"age": null,
+			"active": null,
+			"born": "1990-05-01T00:00:00Z",
+			"nickname": "annie"
+		},
+		"meta": {"version": 2}
+	}`
+	assert.Equal(t, fmtJson(t, []byte(expected)), fmtJson(t, data))
+
+	got := sqlNullUser{Age: sql.NullInt32{Int32: 30, Valid: true}}
+	if err := UnmarshalResource(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, u, got)
+}
+
+func TestSqlNull_Pointer(t *testing.T) {
+	type user struct {
+		Id   string          `jsonapi:"id,users"`
+		Name *sql.NullString `jsonapi:"attr,name"`
+	}
+
+	data := `{"type": "users", "id": "1", "attributes": {"name": "Ann"}}`
+	got := user{}
+	if err := UnmarshalResource([]byte(data), &got); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, &sql.NullString{String: "Ann", Valid: true}, got.Name)
+
+	data = `{"type": "users", "id": "1", "attributes": {"name": null}}`
+	if err := UnmarshalResource([]byte(data), &got); err != nil {
+		t.Fatal(err)
+	}
+	assert.Nil(t, got.Name)
+}
+
+func TestSqlNull_UnmarshalErr(t *testing.T) {
+	data := `{"type": "users", "id": "1", "attributes": {"age": "thirty"}}`
+	err := UnmarshalResource([]byte(data), &sqlNullUser{})
+	var ue *UnmarshalErr
+	assert.ErrorAs(t, err, &ue)
+	assert.Equal(t, "age", ue.Field)
+}
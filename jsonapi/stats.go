@@ -0,0 +1,44 @@
+package jsonapi
+
+// Stats describes the size of a document marshaled or unmarshaled by
+// a single call, for capacity planning of endpoints that serve large
+// compound documents.
+type Stats struct {
+	// Resources is the number of resources in the primary data.
+	Resources int
+	// Included is the number of included resources.
+	Included int
+	// Bytes is the size of the encoded document, which is the
+	// largest buffer held by the call.
+	Bytes int
+}
+
+// WithStats sets a function that is called with the Stats
+// of each document marshaled or unmarshaled successfully.
+func WithStats(f func(Stats)) Option {
+	return func(o *options) {
+		o.stats = f
+	}
+}
+
+// reportStats calls the stats function, if any,
+// with the stats of d, whose encoding is n bytes.
+func reportStats(d *Document, n int, o *options) {
+	if o.stats == nil {
+		return
+	}
+	o.stats(documentStats(d, n))
+}
+
+// documentStats returns the stats of d, whose encoding is n bytes.
+func documentStats(d *Document, n int) Stats {
+	s := Stats{Included: len(d.Included), Bytes: n}
+	switch {
+	case d.Data == nil:
+	case d.Data.Collection:
+		s.Resources = len(d.Data.Resources)
+	case d.Data.Resource != nil:
+		s.Resources = 1
+	}
+	return s
+}
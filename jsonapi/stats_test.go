@@ -0,0 +1,35 @@
+package jsonapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithStats(t *testing.T) {
+	var got []Stats
+	collect := WithStats(func(s Stats) { got = append(got, s) })
+
+	data, err := MarshalDocument(docArticlesValue, collect, WithIncluded(&docArticleValue))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []Stats{{Resources: 2, Included: 1, Bytes: len(data)}}, got)
+
+	got = nil
+	if err := UnmarshalDocument([]byte(docArticleJson), &docArticle{}, collect); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []Stats{{Resources: 1, Bytes: len(docArticleJson)}}, got)
+
+	got = nil
+	if _, err := MarshalDocument((*docArticle)(nil), collect); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []Stats{{Bytes: len(`{"data":null}`)}}, got)
+
+	// failed calls are not reported
+	got = nil
+	assert.Error(t, UnmarshalDocument([]byte(docArticlesJson), &docArticle{}, collect))
+	assert.Empty(t, got)
+}
@@ -0,0 +1,117 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// An Encoder writes JSON:API documents to an output stream,
+// with the options supplied to NewEncoder.
+type Encoder struct {
+	w              io.Writer
+	opts           []Option
+	prefix, indent string
+	progress       progress
+}
+
+// NewEncoder returns an Encoder that writes to w, applying opts
+// to every document it encodes.
+func NewEncoder(w io.Writer, opts ...Option) *Encoder {
+	return &Encoder{w: w, opts: opts}
+}
+
+// SetIndent indents each document written by the encoder,
+// as described by json.Encoder's SetIndent.
+func (e *Encoder) SetIndent(prefix, indent string) {
+	e.prefix, e.indent = prefix, indent
+}
+
+// Encode writes the JSON:API document encoding of a, as described
+// by FormatDocument, to the stream, followed by a newline.
+func (e *Encoder) Encode(a any) error {
+	o := newOptions(e.opts)
+	o.progress = &e.progress
+
+	d, err := formatDocument(a, o)
+	if err != nil {
+		return err
+	}
+
+	if err := encodeHooks(d, o); err != nil {
+		return err
+	}
+
+	cw := &countingWriter{w: e.w}
+	enc := json.NewEncoder(cw)
+	enc.SetIndent(e.prefix, e.indent)
+	if err := enc.Encode(d); err != nil {
+		return fmt.Errorf("jsonapi: encoding document: %w", err)
+	}
+
+	reportStats(d, cw.n, o)
+	err = reportProgress(d, cw.n, o)
+	releaseDocument(d)
+	return err
+}
+
+// A Decoder reads JSON:API documents from an input stream,
+// with the options supplied to NewDecoder.
+type Decoder struct {
+	dec      *json.Decoder
+	opts     []Option
+	progress progress
+}
+
+// NewDecoder returns a Decoder that reads from r, applying opts
+// to every document it decodes. The decoder may buffer data
+// read from r beyond the documents it decodes.
+func NewDecoder(r io.Reader, opts ...Option) *Decoder {
+	return &Decoder{dec: json.NewDecoder(r), opts: opts}
+}
+
+// Decode reads the next JSON:API document from the stream and stores
+// its primary data in the value pointed to by a, as described by
+// DeformatDocument. It returns io.EOF at the end of the stream.
+func (d *Decoder) Decode(a any) error {
+	o := newOptions(d.opts)
+	o.progress = &d.progress
+
+	start := d.dec.InputOffset()
+	doc := Document{}
+	if err := d.dec.Decode(&doc); err != nil {
+		if err == io.EOF {
+			return err
+		}
+		return fmt.Errorf("jsonapi: decoding document: %w", err)
+	}
+
+	if err := decodeHooks(&doc, o); err != nil {
+		return err
+	}
+
+	if err := deformatDocument(&doc, a, o); err != nil {
+		return err
+	}
+
+	n := int(d.dec.InputOffset() - start)
+	reportStats(&doc, n, o)
+	return reportProgress(&doc, n, o)
+}
+
+// More returns whether there is another document in the stream.
+func (d *Decoder) More() bool {
+	return d.dec.More()
+}
+
+// countingWriter counts the bytes written to w.
+type countingWriter struct {
+	w io.Writer
+	n int
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += n
+	return n, err
+}
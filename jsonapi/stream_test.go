@@ -0,0 +1,85 @@
+package jsonapi
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncoder(t *testing.T) {
+	buf := bytes.Buffer{}
+	enc := NewEncoder(&buf)
+	if err := enc.Encode(docArticleValue); err != nil {
+		t.Fatal(err)
+	}
+	if err := enc.Encode(docArticlesValue); err != nil {
+		t.Fatal(err)
+	}
+
+	docs := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if assert.Len(t, docs, 2) {
+		assert.Equal(t, fmtJson(t, []byte(docArticleJson)), fmtJson(t, []byte(docs[0])))
+		assert.Equal(t, fmtJson(t, []byte(docArticlesJson)), fmtJson(t, []byte(docs[1])))
+	}
+}
+
+func TestEncoder_Options(t *testing.T) {
+	var got []Stats
+	buf := bytes.Buffer{}
+	enc := NewEncoder(&buf, WithFields(map[string][]string{"articles": {"title"}}), WithStats(func(s Stats) { got = append(got, s) }))
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(docArticleValue); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "{\n  \"data\": {\n    \"type\": \"articles\",\n    \"id\": \"1\",\n    \"attributes\": {\n      \"title\": \"Hello\"\n    }\n  }\n}\n"
+	assert.Equal(t, expected, buf.String())
+	assert.Equal(t, []Stats{{Resources: 1, Bytes: len(expected)}}, got)
+}
+
+func TestEncoder_Error(t *testing.T) {
+	assert.ErrorIs(t, NewEncoder(io.Discard).Encode(1), ErrNotStruct)
+}
+
+func TestDecoder(t *testing.T) {
+	dec := NewDecoder(strings.NewReader(docArticleJson + docArticlesJson))
+
+	article := docArticle{}
+	if err := dec.Decode(&article); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, docArticleValue, article)
+
+	assert.True(t, dec.More())
+	articles := []docArticle{}
+	if err := dec.Decode(&articles); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, docArticlesValue, articles)
+
+	assert.False(t, dec.More())
+	assert.Equal(t, io.EOF, dec.Decode(&article))
+}
+
+func TestDecoder_Options(t *testing.T) {
+	var got []Stats
+	dec := NewDecoder(strings.NewReader(docArticleJson), WithSingleAsCollection(), WithStats(func(s Stats) { got = append(got, s) }))
+
+	articles := []docArticle{}
+	if err := dec.Decode(&articles); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []docArticle{docArticleValue}, articles)
+	assert.Equal(t, []Stats{{Resources: 1, Bytes: len(docArticleJson)}}, got)
+}
+
+func TestDecoder_Error(t *testing.T) {
+	err := NewDecoder(strings.NewReader(`{"data":`)).Decode(&docArticle{})
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+	err = NewDecoder(strings.NewReader(docArticlesJson)).Decode(&docArticle{})
+	assert.ErrorIs(t, err, ErrUnexpectedArray)
+}
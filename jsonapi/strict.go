@@ -0,0 +1,75 @@
+package jsonapi
+
+import (
+	"sort"
+	"strings"
+)
+
+// UnknownMembersErr is returned when unmarshaling with
+// WithDisallowUnknownMembers if a resource has members that are
+// not mapped to fields, eg "attributes.title" or "meta.views".
+type UnknownMembersErr struct {
+	Members []string
+}
+
+func (e *UnknownMembersErr) Error() string {
+	return "unknown members: " + strings.Join(e.Members, ", ")
+}
+
+// WithDisallowUnknownMembers makes unmarshaling fail with an
+// UnknownMembersErr if a resource has attributes, relationships or
+// meta members that are not mapped to fields, for APIs that reject
+// unexpected input. Catch-all fields map every member of their kind.
+func WithDisallowUnknownMembers() Option {
+	return func(o *options) {
+		o.disallowUnknown = true
+	}
+}
+
+// unknownMembers returns the sorted paths of the attributes,
+// relationships and meta members of r not mapped to fields.
+func unknownMembers(r *Resource, fields []field) []string {
+	attrs := claimedNames(fields, TagValueAttr)
+	meta := claimedNames(fields, TagValueMeta)
+	rels := claimedNames(fields, TagValueRel)
+	for _, f := range fields {
+		switch {
+		case f.tag.typ == TagValueAttrMap:
+			attrs = nil
+		case f.tag.typ == TagValueMetaMap:
+			meta = nil
+		case f.tag.rel != "":
+			rels[f.tag.rel] = true
+		}
+	}
+
+	var unknown []string
+	for _, m := range []struct {
+		name    string
+		claimed map[string]bool
+		keys    []string
+	}{
+		{"attributes", attrs, mapKeys(r.Attributes)},
+		{"relationships", rels, append(mapKeys(r.ToOneRelationships), mapKeys(r.ToManyRelationships)...)},
+		{"meta", meta, mapKeys(r.Meta)},
+	} {
+		if m.claimed == nil {
+			continue
+		}
+		for _, k := range m.keys {
+			if !m.claimed[k] {
+				unknown = append(unknown, m.name+"."+k)
+			}
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+func mapKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
@@ -0,0 +1,54 @@
+package jsonapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type strictArticle struct {
+	Id       string   `jsonapi:"id,articles"`
+	Title    string   `jsonapi:"attr,title"`
+	Author   string   `jsonapi:"rel,author,people"`
+	TagsSelf string   `jsonapi:"link,self,rel=tags"`
+	Views    int      `jsonapi:"meta,views"`
+	Total    int      `jsonapi:"meta,total,rel=author"`
+	Comments []string `jsonapi:"rel,comments,comments,omitempty"`
+}
+
+func TestUnmarshalResource_DisallowUnknownMembers(t *testing.T) {
+	in := `
+	{
+		"type": "articles",
+		"id": "1",
+		"attributes": {"title": "Hello", "body": "World", "rating": 4},
+		"relationships": {
+			"author": {"data": {"type": "people", "id": "2"}},
+			"tags": {"links": {"self": "/articles/1/relationships/tags"}},
+			"editor": {"data": {"type": "people", "id": "3"}}
+		},
+		"meta": {"views": 1, "total": 2}
+	}`
+
+	// ignored by default
+	if err := UnmarshalResource([]byte(in), &strictArticle{}); err != nil {
+		t.Fatal(err)
+	}
+
+	err := UnmarshalResource([]byte(in), &strictArticle{}, WithDisallowUnknownMembers())
+	want := &UnknownMembersErr{}
+	if assert.ErrorAs(t, err, &want) {
+		assert.Equal(t, []string{"attributes.body", "attributes.rating", "meta.total", "relationships.editor"}, want.Members)
+	}
+}
+
+func TestUnmarshalResource_DisallowUnknownMembers_CatchAll(t *testing.T) {
+	type tp struct {
+		Id    string         `jsonapi:"id,articles"`
+		Attrs map[string]any `jsonapi:"attr-map"`
+		Meta  map[string]any `jsonapi:"meta-map"`
+	}
+
+	in := `{"type": "articles", "id": "1", "attributes": {"title": "Hello"}, "meta": {"views": 1}}`
+	assert.NoError(t, UnmarshalResource([]byte(in), &tp{}, WithDisallowUnknownMembers()))
+}
@@ -0,0 +1,111 @@
+package jsonapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+)
+
+// ErrNonStringId is returned with WithStringIds for
+// ids that are neither strings nor numbers when marshaling,
+// and for ids that aren't strings when unmarshaling.
+var ErrNonStringId = fmt.Errorf("id is not a string")
+
+// WithStringIds enforces the specification's requirement that ids are
+// strings, for the ids of tagged structs and of their relationships'
+// linkage. When marshaling, numeric ids are encoded as strings, as
+// though their fields had the string option, and other ids that aren't
+// strings, eg objects, fail with a MarshalErr. When unmarshaling, ids
+// that aren't strings fail with an UnmarshalErr, and string ids are
+// decoded into numeric fields as though they had the string option.
+// Both errors wrap ErrNonStringId. Null linkage is unaffected.
+func WithStringIds() Option {
+	return func(o *options) {
+		o.stringIds = true
+	}
+}
+
+// marshalStringIds replaces the numeric ids of r, and of its
+// relationship linkage, with strings, returning a MarshalErr
+// if any other ids aren't strings.
+func marshalStringIds(r *Resource) error {
+	r.Id = normalizeId(r.Id)
+	if !isStringId(r.Id, false) {
+		return &MarshalErr{Field: TagValueId, Pointer: "/id", Err: fmt.Errorf("%w: %s", ErrNonStringId, r.Id)}
+	}
+
+	for name, rel := range r.ToOneRelationships {
+		rel.Data.Id = normalizeId(rel.Data.Id)
+		if !isStringId(rel.Data.Id, true) {
+			return &MarshalErr{Field: name, Pointer: linkagePointer(name, -1), Err: fmt.Errorf("%w: %s", ErrNonStringId, rel.Data.Id)}
+		}
+	}
+	for name, rel := range r.ToManyRelationships {
+		for i := range rel.Data {
+			rel.Data[i].Id = normalizeId(rel.Data[i].Id)
+			if !isStringId(rel.Data[i].Id, false) {
+				return &MarshalErr{Field: name, Pointer: linkagePointer(name, i), Err: fmt.Errorf("%w: %s", ErrNonStringId, rel.Data[i].Id)}
+			}
+		}
+	}
+	return nil
+}
+
+// checkStringIds returns an UnmarshalErr if the id of r,
+// or of its relationship linkage, isn't a string.
+func checkStringIds(r *Resource) error {
+	if !isStringId(r.Id, false) {
+		return &UnmarshalErr{Field: TagValueId, Pointer: "/id", Err: fmt.Errorf("%w: %s", ErrNonStringId, r.Id)}
+	}
+
+	for _, name := range relationshipNames(r) {
+		if rel, ok := r.ToOneRelationships[name]; ok && !isStringId(rel.Data.Id, true) {
+			return &UnmarshalErr{Field: name, Pointer: linkagePointer(name, -1), Err: fmt.Errorf("%w: %s", ErrNonStringId, rel.Data.Id)}
+		}
+		if rel, ok := r.ToManyRelationships[name]; ok {
+			for i, id := range rel.Data {
+				if !isStringId(id.Id, false) {
+					return &UnmarshalErr{Field: name, Pointer: linkagePointer(name, i), Err: fmt.Errorf("%w: %s", ErrNonStringId, id.Id)}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// isStringId returns whether the raw id is a JSON string, or absent,
+// eg for resources identified by their local ids, or, if null is
+// true, null, as for empty to-one relationships.
+func isStringId(id json.RawMessage, null bool) bool {
+	return len(id) == 0 || id[0] == '"' || null && bytes.Equal(id, NullJson)
+}
+
+// quoteIds returns fields with the string option set on the
+// id and relationship fields, copying fields if any are changed.
+func quoteIds(fields []field) []field {
+	copied := false
+	for i, f := range fields {
+		if f.tag.quote || f.tag.typ != TagValueId && f.tag.typ != TagValueRel {
+			continue
+		}
+		if !copied {
+			fields, copied = slices.Clone(fields), true
+		}
+		fields[i].tag.quote = true
+	}
+	return fields
+}
+
+// linkagePointer returns the JSON pointer of the id of the
+// resource identifier at index i of the linkage of the
+// relationship called name, or of its to-one linkage if i
+// is negative.
+func linkagePointer(name string, i int) string {
+	p := "/relationships/" + pointerToken(name) + "/data"
+	if i >= 0 {
+		p += "/" + strconv.Itoa(i)
+	}
+	return p + "/id"
+}
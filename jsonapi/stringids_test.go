@@ -0,0 +1,100 @@
+package jsonapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stringIdsArticle struct {
+	Id     int             `jsonapi:"id,articles"`
+	Title  string          `jsonapi:"attr,title"`
+	Author *int            `jsonapi:"rel,author,people"`
+	Tags   []float64       `jsonapi:"rel,tags,tags"`
+	Editor stringIdsPerson `jsonapi:"rel,editor,people"`
+}
+
+type stringIdsPerson struct {
+	Id uint `jsonapi:"id,people"`
+}
+
+type stringIdsObject struct {
+	Id struct{ A int } `jsonapi:"id,objects"`
+}
+
+func TestWithStringIds_Marshal(t *testing.T) {
+	a := stringIdsArticle{Id: 1, Title: "Hello", Tags: []float64{2.5}, Editor: stringIdsPerson{Id: 3}}
+
+	data, err := MarshalResource(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Contains(t, string(data), `"id":1`)
+
+	data, err = MarshalResource(a, WithStringIds())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{
+		"type": "articles",
+		"id": "1",
+		"attributes": {"title": "Hello"},
+		"relationships": {
+			"author": {"data": {"type": "people", "id": null}},
+			"tags": {"data": [{"type": "tags", "id": "2.5"}]},
+			"editor": {"data": {"type": "people", "id": "3"}}
+		}
+	}`
+	assert.Equal(t, fmtJson(t, []byte(want)), fmtJson(t, data))
+
+	_, err = MarshalResource(stringIdsObject{}, WithStringIds())
+	assert.ErrorIs(t, err, ErrNonStringId)
+	assert.EqualError(t, err, `jsonapi: marshal error on field 'id': id is not a string: {"A":0}`)
+}
+
+func TestWithStringIds_Unmarshal(t *testing.T) {
+	data := `{
+		"type": "articles",
+		"id": "1",
+		"relationships": {
+			"author": {"data": {"type": "people", "id": "2"}},
+			"tags": {"data": [{"type": "tags", "id": "2.5"}]}
+		}
+	}`
+	a := stringIdsArticle{}
+	if err := UnmarshalResource([]byte(data), &a, WithStringIds()); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, stringIdsArticle{Id: 1, Author: addrOf(2), Tags: []float64{2.5}}, a)
+
+	type testCase struct {
+		Name     string
+		Data     string
+		Expected string
+	}
+
+	testCases := []testCase{
+		{"Id", `{"type": "articles", "id": 1}`, "jsonapi: unmarshal error on field 'id': id is not a string: 1"},
+		{"ToOne", `{"type": "articles", "id": "1", "relationships": {"author": {"data": {"type": "people", "id": 2}}}}`, "jsonapi: unmarshal error on field 'author': id is not a string: 2"},
+		{"ToMany", `{"type": "articles", "id": "1", "relationships": {"tags": {"data": [{"type": "tags", "id": "1"}, {"type": "tags", "id": true}]}}}`, "jsonapi: unmarshal error on field 'tags': id is not a string: true"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			err := UnmarshalResource([]byte(tc.Data), &stringIdsArticle{}, WithStringIds())
+			assert.ErrorIs(t, err, ErrNonStringId)
+			assert.EqualError(t, err, tc.Expected)
+
+			err = UnmarshalResource([]byte(tc.Data), &stringIdsArticle{})
+			assert.NotErrorIs(t, err, ErrNonStringId)
+		})
+	}
+
+	// numeric linkage normalized to strings is accepted
+	data = `{"type": "articles", "id": "1", "relationships": {"author": {"data": {"type": "people", "id": 2}}}}`
+	a = stringIdsArticle{}
+	if err := UnmarshalResource([]byte(data), &a, WithStringIds(), WithNormalizedLinkageIds()); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, addrOf(2), a.Author)
+}
@@ -0,0 +1,56 @@
+package jsonapi
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+)
+
+var (
+	jsonMarshalerType   = reflect.TypeFor[json.Marshaler]()
+	jsonUnmarshalerType = reflect.TypeFor[json.Unmarshaler]()
+	textMarshalerType   = reflect.TypeFor[encoding.TextMarshaler]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// textMarshaler returns v as an encoding.TextMarshaler, eg a UUID id,
+// if it, or a pointer to it, implements it, and doesn't implement
+// json.Marshaler, which takes precedence as in encoding/json. Such
+// values are marshaled as JSON strings, even with the string option,
+// and even if the method has a pointer receiver and v isn't addressable.
+func textMarshaler(v reflect.Value) (encoding.TextMarshaler, bool) {
+	t := v.Type()
+	if t.Kind() == reflect.Pointer && v.IsNil() {
+		return nil, false
+	}
+	if t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType) {
+		return nil, false
+	}
+	if t.Implements(textMarshalerType) {
+		return v.Interface().(encoding.TextMarshaler), true
+	}
+	if reflect.PointerTo(t).Implements(textMarshalerType) {
+		p := reflect.New(t)
+		p.Elem().Set(v)
+		return p.Interface().(encoding.TextMarshaler), true
+	}
+	return nil, false
+}
+
+// isTextUnmarshaler returns whether pointers to t implement
+// encoding.TextUnmarshaler, but not json.Unmarshaler, and so
+// values of type t are unmarshaled from JSON strings, even if
+// they have numeric kinds.
+func isTextUnmarshaler(t reflect.Type) bool {
+	p := reflect.PointerTo(t)
+	return p.Implements(textUnmarshalerType) && !p.Implements(jsonUnmarshalerType)
+}
+
+// marshalText marshals the text of tm as a JSON string.
+func marshalText(tm encoding.TextMarshaler) (json.RawMessage, error) {
+	text, err := tm.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
@@ -0,0 +1,104 @@
+package jsonapi
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// textUuid is a UUID-like array type, which
+// would otherwise be marshaled as an array.
+type textUuid [4]byte
+
+func (u textUuid) MarshalText() ([]byte, error) {
+	return []byte(hex.EncodeToString(u[:])), nil
+}
+
+func (u *textUuid) UnmarshalText(text []byte) error {
+	b, err := hex.DecodeString(string(text))
+	if err != nil {
+		return err
+	}
+	if len(b) != len(u) {
+		return fmt.Errorf("invalid uuid %q", text)
+	}
+	copy(u[:], b)
+	return nil
+}
+
+// textOrderId is a numeric id type with a prefixed text
+// form, and a MarshalText method with a pointer receiver.
+type textOrderId int
+
+func (id *textOrderId) MarshalText() ([]byte, error) {
+	return []byte("ord-" + strconv.Itoa(int(*id))), nil
+}
+
+func (id *textOrderId) UnmarshalText(text []byte) error {
+	n, err := strconv.Atoi(strings.TrimPrefix(string(text), "ord-"))
+	*id = textOrderId(n)
+	return err
+}
+
+type textUser struct {
+	Id     textUuid      `jsonapi:"id,users"`
+	Orders []textOrderId `jsonapi:"rel,orders,orders,string"`
+	Best   *textOrderId  `jsonapi:"rel,best,orders"`
+}
+
+func TestTextMarshaler(t *testing.T) {
+	best := textOrderId(2)
+	u := textUser{
+		Id:     textUuid{0xde, 0xad, 0xbe, 0xef},
+		Orders: []textOrderId{1, 2},
+		Best:   &best,
+	}
+
+	data, err := MarshalResource(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{
+		"type": "users",
+		"id": "deadbeef",
+		"relationships": {
+			"orders": {"data": [{"type": "orders", "id": "ord-1"}, {"type": "orders", "id": "ord-2"}]},
+			"best": {"data": {"type": "orders", "id": "ord-2"}}
+		}
+	}`
+	assert.Equal(t, fmtJson(t, []byte(expected)), fmtJson(t, data))
+
+	got := textUser{}
+	if err := UnmarshalResource(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, u, got)
+}
+
+func TestTextMarshaler_UnmarshalErr(t *testing.T) {
+	type testCase struct {
+		name string
+		id   string
+		err  string
+	}
+
+	testCases := []testCase{
+		{"invalid", `"xyz"`, "encoding/hex: invalid byte"},
+		{"number", `12`, "cannot unmarshal number"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data := `{"type": "users", "id": ` + tc.id + `}`
+			err := UnmarshalResource([]byte(data), &textUser{})
+			var ue *UnmarshalErr
+			assert.ErrorAs(t, err, &ue)
+			assert.Equal(t, "/id", ue.Pointer)
+			assert.ErrorContains(t, err, tc.err)
+		})
+	}
+}
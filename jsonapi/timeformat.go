@@ -0,0 +1,181 @@
+package jsonapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// TagValueFormat is the option of attr and meta tags on time.Time
+// fields, or pointers to them, that sets the format of their values
+// when marshaling and unmarshaling, eg `jsonapi:"attr,published,format=date"`.
+// The formats are:
+//
+//   - rfc3339, a string as formatted by time.RFC3339, without
+//     fractional seconds
+//   - rfc3339nano, a string as formatted by time.RFC3339Nano
+//   - date, a string of the date alone, eg "2024-01-31"
+//   - unix and unixmilli, a number of seconds or milliseconds
+//     since the Unix epoch, unmarshaled as UTC times
+//   - any other value, a string as formatted by the value as a time
+//     package layout, which can't contain commas, eg "15:04"
+//
+// With the string option, unix and unixmilli numbers are quoted.
+//
+// It also sets the format of time.Duration fields, which is one of
+// string, the default, eg "1h30m0s", or seconds, milliseconds or
+// nanoseconds, a number of them, quoted with the string option.
+const TagValueFormat = "format"
+
+var timeType = reflect.TypeFor[time.Time]()
+
+// parseFormatOpt sets the time or duration format of the attribute
+// or meta tag tg from the format option, which is only valid for
+// time.Time and time.Duration fields.
+func parseFormatOpt(f reflect.StructField, tg *tag, opts string) error {
+	format, ok := optValue(opts, TagValueFormat)
+	t := derefType(f.Type)
+	switch {
+	case t == durationType:
+		if !ok {
+			format = "string"
+		}
+		if !slices.Contains(durationFormats, format) {
+			return &TagErr{f.Name, fmt.Errorf("unknown duration format %q", format)}
+		}
+		tg.durationFormat = format
+	case !ok:
+	case t != timeType:
+		return &TagErr{f.Name, fmt.Errorf("format requires a time.Time or time.Duration field, not %s", f.Type)}
+	case format == "":
+		return &TagErr{f.Name, fmt.Errorf("empty format")}
+	default:
+		tg.timeFormat = format
+	}
+	return nil
+}
+
+// marshalMemberValue marshals v, the value of an attribute or
+// meta member whose tag is tg, with its AttrMarshaler, if any, or
+// else in the tag's time or duration format, if any.
+func marshalMemberValue(v reflect.Value, tg tag) (json.RawMessage, error) {
+	if am, ok := attrMarshaler(v); ok {
+		return am.MarshalJsonApiAttr(tagOptions(tg))
+	}
+	switch {
+	case tg.timeFormat != "":
+		return marshalTime(v, tg.timeFormat, tg.quote)
+	case tg.durationFormat != "":
+		return marshalDuration(v, tg.durationFormat, tg.quote)
+	}
+	return marshalJson(v, tg.quote)
+}
+
+// unmarshalMemberValue unmarshals data into v, the value of an
+// attribute or meta member whose tag is tg, with its AttrUnmarshaler,
+// if any, or else in the tag's time or duration format, if any,
+// decoding the numbers of interface values as json.Number if
+// o.useNumber is set.
+func unmarshalMemberValue(data json.RawMessage, v reflect.Value, tg tag, o *options) error {
+	if au, ok := attrUnmarshaler(v); ok {
+		return au.UnmarshalJsonApiAttr(data, tagOptions(tg))
+	}
+	switch {
+	case tg.timeFormat != "":
+		return unmarshalTime(data, v, tg.timeFormat, tg.quote)
+	case tg.durationFormat != "":
+		return unmarshalDuration(data, v, tg.durationFormat, tg.quote)
+	case o.useNumber:
+		if ok, err := unmarshalNumbers(data, v); ok {
+			return err
+		}
+	}
+	return unmarshalJson(data, v, tg.quote)
+}
+
+// timeLayout returns the time package layout of the string format,
+// or false if format is numeric.
+func timeLayout(format string) (string, bool) {
+	switch format {
+	case "rfc3339":
+		return time.RFC3339, true
+	case "rfc3339nano":
+		return time.RFC3339Nano, true
+	case "date":
+		return time.DateOnly, true
+	case "unix", "unixmilli":
+		return "", false
+	}
+	return format, true
+}
+
+// marshalTime marshals the time.Time value v, if valid, in format,
+// quoting numeric formats if quote is true.
+func marshalTime(v reflect.Value, format string, quote bool) (json.RawMessage, error) {
+	if !v.IsValid() {
+		return NullJson, nil
+	}
+	t := v.Interface().(time.Time)
+
+	if layout, ok := timeLayout(format); ok {
+		return json.Marshal(t.Format(layout))
+	}
+
+	n := t.Unix()
+	if format == "unixmilli" {
+		n = t.UnixMilli()
+	}
+	j := strconv.AppendInt(nil, n, 10)
+	if quote {
+		j = strconv.AppendQuote(nil, string(j))
+	}
+	return j, nil
+}
+
+// unmarshalTime unmarshals data, in format, into the time.Time value
+// v, or the value it points to, expecting numeric formats to be quoted
+// if quote is true. Null is ignored.
+func unmarshalTime(data json.RawMessage, v reflect.Value, format string, quote bool) error {
+	if len(data) == 0 || bytes.Equal(data, NullJson) {
+		return nil
+	}
+	for v.Kind() == reflect.Pointer {
+		initValue(v)
+		v = v.Elem()
+	}
+
+	var t time.Time
+	if layout, ok := timeLayout(format); ok {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		var err error
+		if t, err = time.Parse(layout, s); err != nil {
+			return err
+		}
+	} else {
+		if quote {
+			if len(data) < 2 || data[0] != '"' {
+				return fmt.Errorf("cannot unmarshal %s into quoted %s time", data, format)
+			}
+			data = data[1 : len(data)-1]
+		}
+		n, err := strconv.ParseInt(string(data), 10, 64)
+		if err != nil {
+			return fmt.Errorf("cannot unmarshal %s into %s time", data, format)
+		}
+		if format == "unixmilli" {
+			t = time.UnixMilli(n).UTC()
+		} else {
+			t = time.Unix(n, 0).UTC()
+		}
+	}
+
+	v.Set(reflect.ValueOf(t))
+	return nil
+}
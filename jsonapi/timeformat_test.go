@@ -0,0 +1,120 @@
+package jsonapi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type timeFormatEvent struct {
+	Id        string     `jsonapi:"id,events"`
+	Default   time.Time  `jsonapi:"attr,default"`
+	Seconds   time.Time  `jsonapi:"attr,seconds,format=rfc3339"`
+	Nanos     time.Time  `jsonapi:"attr,nanos,format=rfc3339nano"`
+	Day       time.Time  `jsonapi:"attr,day,format=date"`
+	Unix      time.Time  `jsonapi:"attr,unix,format=unix"`
+	Millis    *time.Time `jsonapi:"attr,millis,format=unixmilli,string"`
+	Clock     time.Time  `jsonapi:"attr,clock,format=15:04"`
+	Missing   *time.Time `jsonapi:"attr,missing,format=unix"`
+	Generated time.Time  `jsonapi:"meta,generated,format=unix"`
+}
+
+func TestTimeFormat(t *testing.T) {
+	ts := time.Date(2024, 1, 31, 9, 30, 15, 500_000_000, time.UTC)
+	e := timeFormatEvent{
+		Id:        "1",
+		Default:   ts,
+		Seconds:   ts,
+		Nanos:     ts,
+		Day:       ts,
+		Unix:      ts,
+		Millis:    &ts,
+		Clock:     ts,
+		Generated: ts,
+	}
+
+	data, err := MarshalResource(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{
+		"type": "events",
+		"id": "1",
+		"attributes": {
+			"default": "2024-01-31T09:30:15.5Z",
+			"seconds": "2024-01-31T09:30:15Z",
+			"nanos": "2024-01-31T09:30:15.5Z",
+			"day": "2024-01-31",
+			"unix": 1706693415,
+			"millis": "1706693415500",
+			"clock": "09:30",
+			"missing": null
+		},
+		"meta": {"generated": 1706693415}
+	}`
+	assert.Equal(t, fmtJson(t, []byte(expected)), fmtJson(t, data))
+
+	got := timeFormatEvent{}
+	if err := UnmarshalResource(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	want := e
+	want.Seconds = ts.Truncate(time.Second)
+	want.Day = time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+	want.Unix = ts.Truncate(time.Second)
+	want.Clock = time.Date(0, 1, 1, 9, 30, 0, 0, time.UTC)
+	want.Generated = ts.Truncate(time.Second)
+	assert.Equal(t, want, got)
+}
+
+func TestTimeFormat_UnmarshalErr(t *testing.T) {
+	type testCase struct {
+		name string
+		json string
+		err  string
+	}
+
+	testCases := []testCase{
+		{"date", `{"day": "31/01/2024"}`, `parsing time "31/01/2024"`},
+		{"date number", `{"day": 1}`, "cannot unmarshal number"},
+		{"unix string", `{"unix": "1"}`, `cannot unmarshal "1" into unix time`},
+		{"unix float", `{"unix": 1.5}`, "cannot unmarshal 1.5 into unix time"},
+		{"unquoted", `{"millis": 1}`, "cannot unmarshal 1 into quoted unixmilli time"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data := `{"type": "events", "id": "1", "attributes": ` + tc.json + `}`
+			err := UnmarshalResource([]byte(data), &timeFormatEvent{})
+			var ue *UnmarshalErr
+			assert.ErrorAs(t, err, &ue)
+			assert.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestTimeFormat_Null(t *testing.T) {
+	ts := time.Unix(1, 0).UTC()
+	e := timeFormatEvent{Unix: ts, Missing: &ts}
+	data := `{"type": "events", "id": "1", "attributes": {"unix": null, "missing": null}}`
+	if err := UnmarshalResource([]byte(data), &e); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, ts, e.Unix)
+	assert.Nil(t, e.Missing)
+}
+
+func TestTimeFormat_TagErr(t *testing.T) {
+	_, err := MarshalResource(struct {
+		Id   string `jsonapi:"id,events"`
+		Time string `jsonapi:"attr,time,format=unix"`
+	}{})
+	assert.ErrorContains(t, err, "tag error on field 'Time': format requires a time.Time or time.Duration field, not string")
+
+	_, err = MarshalResource(struct {
+		Id   string    `jsonapi:"id,events"`
+		Time time.Time `jsonapi:"attr,time,format="`
+	}{})
+	assert.ErrorContains(t, err, "tag error on field 'Time': empty format")
+}
@@ -0,0 +1,36 @@
+package jsonapi
+
+// Marshal returns the JSON:API document encoding of v, as described by
+// FormatDocument. It is a typed form of MarshalDocument, for values
+// that are structs, pointers to structs, or slices of them.
+func Marshal[T any](v T, opts ...Option) ([]byte, error) {
+	o := newOptions(opts)
+	ic := o.startCall("Marshal", Marshaling, v)
+	data, err := marshalDocument(v, o, ic)
+	ic.end(len(data), err)
+	return data, err
+}
+
+// Unmarshal parses the JSON:API document data and returns its primary
+// data as a T, as described by DeformatDocument. It is a typed form of
+// UnmarshalDocument that allocates the target itself, and so cannot be
+// passed a non-pointer by mistake: T is a struct, a pointer to a struct,
+// or a slice of them, eg
+//
+//	a, err := jsonapi.Unmarshal[Article](data)
+//	as, err := jsonapi.Unmarshal[[]*Article](data)
+//
+// Unmarshaling a pointer type returns nil for null primary data. Other
+// types, eg an int, are still reported at run time.
+func Unmarshal[T any](data []byte, opts ...Option) (T, error) {
+	var v T
+	o := newOptions(opts)
+	ic := o.startCall("Unmarshal", Unmarshaling, &v)
+	err := unmarshalDocument(data, &v, o, ic)
+	ic.end(len(data), err)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return v, nil
+}
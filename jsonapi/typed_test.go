@@ -0,0 +1,67 @@
+package jsonapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshal(t *testing.T) {
+	data, err := Marshal(docArticleValue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.JSONEq(t, docArticleJson, string(data))
+
+	data, err = Marshal(docArticlesValue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.JSONEq(t, docArticlesJson, string(data))
+}
+
+func TestUnmarshal(t *testing.T) {
+	a, err := Unmarshal[docArticle]([]byte(docArticleJson))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, docArticleValue, a)
+
+	p, err := Unmarshal[*docArticle]([]byte(docArticleJson))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, &docArticleValue, p)
+
+	as, err := Unmarshal[[]docArticle]([]byte(docArticlesJson))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, docArticlesValue, as)
+
+	p, err = Unmarshal[*docArticle]([]byte(`{"data": null}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Nil(t, p)
+}
+
+func TestUnmarshal_Err(t *testing.T) {
+	a, err := Unmarshal[docArticle]([]byte(docArticlesJson))
+	assert.ErrorIs(t, err, ErrUnexpectedArray)
+	assert.Zero(t, a)
+
+	_, err = Unmarshal[int]([]byte(docArticleJson))
+	assert.ErrorIs(t, err, ErrNotStructPtr)
+}
+
+func TestUnmarshal_Instrumentation(t *testing.T) {
+	var calls []Call
+	if _, err := Unmarshal[docArticle]([]byte(docArticleJson), WithInstrumentation(func(c Call) { calls = append(calls, c) })); err != nil {
+		t.Fatal(err)
+	}
+	if assert.Len(t, calls, 1) {
+		assert.Equal(t, "Unmarshal", calls[0].Func)
+		assert.Equal(t, "jsonapi.docArticle", calls[0].Type)
+	}
+}
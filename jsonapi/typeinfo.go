@@ -0,0 +1,160 @@
+package jsonapi
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// TypeInfo describes how a tagged struct type is represented as a
+// JSON:API resource, eg for generating client types from it.
+type TypeInfo struct {
+	// GoType is the struct type.
+	GoType reflect.Type
+	// Type is the resource type.
+	Type string
+	// Id describes the id field, if any.
+	Id *MemberInfo
+	// Lid is true if the struct has a lid field.
+	Lid bool
+	// Attributes, Relationships and Meta describe the fields
+	// mapped to each kind of member, sorted by name.
+	Attributes    []MemberInfo
+	Relationships []RelationshipInfo
+	Meta          []MemberInfo
+	// Links holds the names of the resource's link fields, sorted.
+	Links []string
+}
+
+// MemberInfo describes a field mapped to the id, or
+// an attribute or meta member.
+type MemberInfo struct {
+	Name string
+	// GoType is the field's type.
+	GoType reflect.Type
+	// OmitEmpty is true if the field has the omitempty
+	// or omitzero option.
+	OmitEmpty bool
+	// String is true if the field has the string option,
+	// and so is encoded as a JSON string.
+	String bool
+	// Format is the time or duration format of a time.Time or
+	// time.Duration field, which is "string" by default for
+	// durations. See TagValueFormat.
+	Format string
+}
+
+// RelationshipInfo describes a field mapped to a relationship.
+type RelationshipInfo struct {
+	Name string
+	// Type is the related resource type, which is empty
+	// for polymorphic relationships.
+	Type string
+	// GoType is the field's type.
+	GoType reflect.Type
+	// ToMany is true for to-many relationships.
+	ToMany bool
+	// OmitEmpty, String, CountOnly and Lid are true
+	// if the field has the corresponding option, with
+	// omitzero also setting OmitEmpty.
+	OmitEmpty bool
+	String    bool
+	CountOnly bool
+	Lid       bool
+	// Meta describes the fields mapped to the
+	// relationship's meta, sorted by name.
+	Meta []MemberInfo
+}
+
+// DescribeType returns the TypeInfo of a, which must be a tagged
+// struct, a pointer to one, or the reflect.Type of either.
+func DescribeType(a any, opts ...Option) (*TypeInfo, error) {
+	t, ok := a.(reflect.Type)
+	if !ok {
+		t = reflect.TypeOf(a)
+	}
+	if t == nil || derefType(t).Kind() != reflect.Struct {
+		return nil, fmt.Errorf("jsonapi: %w", ErrNotStruct)
+	}
+	return describeType(derefType(t), newOptions(opts))
+}
+
+// DescribeRegistered returns the TypeInfo of every struct type registered
+// with RegisterType in the registry, sorted by resource type.
+func DescribeRegistered(opts ...Option) ([]*TypeInfo, error) {
+	o := newOptions(opts)
+
+	typs := make([]string, 0, len(o.snapshot.types))
+	for typ := range o.snapshot.types {
+		typs = append(typs, typ)
+	}
+	sort.Strings(typs)
+
+	var infos []*TypeInfo
+	for _, typ := range typs {
+		t := derefType(o.snapshot.types[typ])
+		if t.Kind() != reflect.Struct {
+			continue
+		}
+		info, err := describeType(t, o)
+		if err != nil {
+			return nil, err
+		}
+		info.Type = typ
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+func describeType(t reflect.Type, o *options) (*TypeInfo, error) {
+	v := reflect.New(t).Elem()
+	fields, err := parseTags(v)
+	if err != nil {
+		return nil, fmt.Errorf("jsonapi: parsing tags: %w", err)
+	}
+
+	info := &TypeInfo{GoType: t, Type: resourceType(v, fields, o)}
+	fields = o.forType(info.Type).nameMembers(fields)
+	relMeta := map[string][]MemberInfo{}
+	for _, f := range fields {
+		ft := t.FieldByIndex(f.idxs).Type
+		m := MemberInfo{Name: f.tag.name, GoType: ft, OmitEmpty: f.tag.omitempty || f.tag.omitzero, String: f.tag.quote}
+		m.Format = cmp.Or(f.tag.timeFormat, f.tag.durationFormat)
+		switch f.tag.typ {
+		case TagValueId:
+			m.Name = TagValueId
+			info.Id = &m
+		case TagValueLid:
+			info.Lid = true
+		case TagValueAttr:
+			info.Attributes = append(info.Attributes, m)
+		case TagValueMeta:
+			if f.tag.rel != "" {
+				m.Name = f.tag.relMember
+				relMeta[f.tag.rel] = append(relMeta[f.tag.rel], m)
+				continue
+			}
+			info.Meta = append(info.Meta, m)
+		case TagValueLink:
+			if f.tag.rel == "" {
+				info.Links = append(info.Links, f.tag.name)
+			}
+		case TagValueRel:
+			info.Relationships = append(info.Relationships, RelationshipInfo{
+				Name:      f.tag.name,
+				Type:      f.tag.rscType,
+				GoType:    ft,
+				ToMany:    !isToOne(reflect.Zero(derefType(ft))),
+				OmitEmpty: f.tag.omitempty || f.tag.omitzero,
+				String:    f.tag.quote,
+				CountOnly: f.tag.countOnly,
+				Lid:       f.tag.lid,
+			})
+		}
+	}
+	for i, r := range info.Relationships {
+		info.Relationships[i].Meta = relMeta[r.Name]
+	}
+	return info, nil
+}
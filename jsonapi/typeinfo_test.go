@@ -0,0 +1,73 @@
+package jsonapi
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type infoArticle struct {
+	Id       int           `jsonapi:"id,articles,string"`
+	Title    string        `jsonapi:"attr,title"`
+	Tags     []string      `jsonapi:"attr,tags,omitempty"`
+	Length   time.Duration `jsonapi:"attr,length"`
+	Created  time.Time     `jsonapi:"attr,created,format=unix"`
+	Author   *string       `jsonapi:"rel,author,people"`
+	Comments []int         `jsonapi:"rel,comments,comments,countonly"`
+	Subject  commentable   `jsonapi:"rel,subject"`
+	Views    int           `jsonapi:"meta,views"`
+	Self     string        `jsonapi:"link,self"`
+	CommSelf string        `jsonapi:"link,self,rel=comments"`
+	Total    int           `jsonapi:"meta,total,rel=comments"`
+}
+
+func TestDescribeType(t *testing.T) {
+	want := &TypeInfo{
+		GoType: reflect.TypeFor[infoArticle](),
+		Type:   "articles",
+		Id:     &MemberInfo{Name: "id", GoType: reflect.TypeFor[int](), String: true},
+		Attributes: []MemberInfo{
+			{Name: "created", GoType: reflect.TypeFor[time.Time](), Format: "unix"},
+			{Name: "length", GoType: reflect.TypeFor[time.Duration](), Format: "string"},
+			{Name: "tags", GoType: reflect.TypeFor[[]string](), OmitEmpty: true},
+			{Name: "title", GoType: reflect.TypeFor[string]()},
+		},
+		Relationships: []RelationshipInfo{
+			{Name: "author", Type: "people", GoType: reflect.TypeFor[*string]()},
+			{Name: "comments", Type: "comments", GoType: reflect.TypeFor[[]int](), ToMany: true, CountOnly: true,
+				Meta: []MemberInfo{{Name: "total", GoType: reflect.TypeFor[int]()}}},
+			{Name: "subject", GoType: reflect.TypeFor[commentable]()},
+		},
+		Meta:  []MemberInfo{{Name: "views", GoType: reflect.TypeFor[int]()}},
+		Links: []string{"self"},
+	}
+
+	for _, in := range []any{infoArticle{}, &infoArticle{}, reflect.TypeFor[infoArticle](), reflect.TypeFor[*infoArticle]()} {
+		got, err := DescribeType(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, want, got)
+	}
+
+	_, err := DescribeType(1)
+	assert.ErrorIs(t, err, ErrNotStruct)
+}
+
+func TestDescribeRegistered(t *testing.T) {
+	reg := NewRegistry()
+	reg.RegisterType("photos", polyPhoto{})
+	reg.RegisterType("articles", &polyArticle{})
+	reg.RegisterType("videos", polyVideoId(""))
+
+	got, err := DescribeRegistered(WithRegistry(reg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, got, 2)
+	assert.Equal(t, "articles", got[0].Type)
+	assert.Equal(t, reflect.TypeFor[polyArticle](), got[0].GoType)
+	assert.Equal(t, "photos", got[1].Type)
+}
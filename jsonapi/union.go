@@ -0,0 +1,105 @@
+package jsonapi
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// TagValueUnion tags the members of a tagged union struct, which holds
+// a resource of one of several types, eg
+//
+//	type SearchResult struct {
+//		Article *Article `jsonapi:"union,articles"`
+//		Person  *Person  `jsonapi:"union,people"`
+//	}
+//
+// Each member is a pointer, tagged with its resource type. A union is
+// marshaled as its single non-nil member, and unmarshaled by setting
+// the member tagged with the resource's type, so collections of unions
+// hold primary data of mixed types.
+const TagValueUnion = "union"
+
+var (
+	ErrUnionEmpty = fmt.Errorf("no union member set")
+	ErrUnionType  = fmt.Errorf("no union member for type")
+)
+
+// unionMember is a member of a tagged union struct.
+type unionMember struct {
+	idx     int
+	name    string
+	rscType string
+}
+
+// parseUnion returns the members of the struct type t if it is a tagged
+// union, ie if any of its fields are tagged as union members, or nil.
+func parseUnion(t reflect.Type) ([]unionMember, error) {
+	var members []unionMember
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		typ, opts, ok := splitTypeAndOpts(f)
+		if !ok || typ != TagValueUnion {
+			continue
+		}
+
+		rscType, _ := splitFirstAndOpts(opts)
+		if rscType == "" {
+			return nil, &TagErr{f.Name, fmt.Errorf("required: type")}
+		}
+		if f.Type.Kind() != reflect.Pointer {
+			return nil, &TagErr{f.Name, fmt.Errorf("union member must be a pointer")}
+		}
+		members = append(members, unionMember{i, f.Name, rscType})
+	}
+	return members, nil
+}
+
+// formatUnion converts the tagged union v, with the supplied
+// members, to a Resource of the type of its non-nil member.
+func formatUnion(v reflect.Value, members []unionMember, o *options) (*Resource, error) {
+	var set *unionMember
+	for i, m := range members {
+		if v.Field(m.idx).IsNil() {
+			continue
+		}
+		if set != nil {
+			return nil, fmt.Errorf("jsonapi: multiple union members set: %s and %s", set.name, m.name)
+		}
+		set = &members[i]
+	}
+	if set == nil {
+		return nil, fmt.Errorf("jsonapi: %w: %s", ErrUnionEmpty, v.Type())
+	}
+
+	r, err := formatValue(v.Field(set.idx), o)
+	if err != nil {
+		return nil, err
+	}
+	r.Type = set.rscType
+	return r, nil
+}
+
+// deformatUnion stores r in the member of the tagged union v, with
+// the supplied members, that is tagged with r's type, and clears the
+// other members.
+func deformatUnion(r *Resource, v reflect.Value, members []unionMember, o *options) error {
+	var set *unionMember
+	for i, m := range members {
+		if m.rscType == r.Type {
+			set = &members[i]
+			break
+		}
+	}
+	if set == nil {
+		return fmt.Errorf("jsonapi: %w: %s", ErrUnionType, r.Type)
+	}
+
+	for _, m := range members {
+		fv := v.Field(m.idx)
+		fv.Set(reflect.Zero(fv.Type()))
+	}
+
+	fv := v.Field(set.idx)
+	fv.Set(reflect.New(fv.Type().Elem()))
+	return deformatValue(r, fv, o)
+}
@@ -0,0 +1,75 @@
+package jsonapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type unionPerson struct {
+	Id   string `jsonapi:"id,people"`
+	Name string `jsonapi:"attr,name"`
+}
+
+type searchResult struct {
+	Article *docArticle  `jsonapi:"union,articles"`
+	Person  *unionPerson `jsonapi:"union,people"`
+}
+
+const searchResultsJson = `
+{
+	"data": [
+		{
+			"type": "articles",
+			"id": "1",
+			"attributes": {"title": "Hello"},
+			"relationships": {"author": {"data": {"type": "people", "id": "2"}}}
+		},
+		{"type": "people", "id": "2", "attributes": {"name": "Bob"}}
+	]
+}`
+
+var searchResultsValue = []searchResult{
+	{Article: &docArticle{Id: 1, Title: "Hello", Author: 2}},
+	{Person: &unionPerson{Id: "2", Name: "Bob"}},
+}
+
+func TestMarshalDocument_Union(t *testing.T) {
+	got, err := MarshalDocument(searchResultsValue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, fmtJson(t, []byte(searchResultsJson)), fmtJson(t, got))
+}
+
+func TestUnmarshalDocument_Union(t *testing.T) {
+	got := []searchResult{}
+	if err := UnmarshalDocument([]byte(searchResultsJson), &got); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, searchResultsValue, got)
+
+	// unmarshaling a single resource clears the other members
+	one := searchResult{Article: &docArticle{}}
+	if err := UnmarshalDocument([]byte(`{"data": {"type": "people", "id": "3"}}`), &one); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, searchResult{Person: &unionPerson{Id: "3"}}, one)
+}
+
+func TestUnion_Err(t *testing.T) {
+	_, err := MarshalDocument([]searchResult{{}})
+	assert.ErrorIs(t, err, ErrUnionEmpty)
+
+	_, err = MarshalDocument(searchResult{Article: &docArticle{}, Person: &unionPerson{}})
+	assert.Error(t, err)
+
+	err = UnmarshalDocument([]byte(`{"data": [{"type": "comments", "id": "5"}]}`), &[]searchResult{})
+	assert.ErrorIs(t, err, ErrUnionType)
+
+	type notPtr struct {
+		Person unionPerson `jsonapi:"union,people"`
+	}
+	_, err = MarshalDocument(notPtr{})
+	assert.ErrorAs(t, err, addrOf(&TagErr{}))
+}
@@ -0,0 +1,59 @@
+package jsonapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+)
+
+// WithUseNumber decodes the numbers of attributes and meta into
+// interface values, eg of any fields, or the elements of map[string]any
+// fields and catch-all maps, as json.Number rather than float64, so that
+// large integers, eg snowflake ids, keep their precision. Fields of
+// numeric types are unaffected.
+func WithUseNumber() Option {
+	return func(o *options) {
+		o.useNumber = true
+	}
+}
+
+// decodeJson unmarshals data into the value p points to,
+// decoding numbers as json.Number if useNumber is true.
+func decodeJson(data json.RawMessage, p any, useNumber bool) error {
+	if !useNumber {
+		return json.Unmarshal(data, p)
+	}
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	return d.Decode(p)
+}
+
+// unmarshalNumbers unmarshals data into v, or the value it points to,
+// decoding numbers as json.Number, if v's type can hold interface
+// values, eg nil any values or []any. It returns false if it can't.
+func unmarshalNumbers(data json.RawMessage, v reflect.Value) (bool, error) {
+	for v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Interface:
+		// initialised interfaces are unmarshaled
+		// into their values' types, as usual
+		if !v.IsNil() {
+			return false, nil
+		}
+	case reflect.Map, reflect.Slice, reflect.Array, reflect.Struct:
+	default:
+		return false, nil
+	}
+	if isTextUnmarshaler(v.Type()) || isSqlNull(v.Type()) {
+		return false, nil
+	}
+
+	p := reflect.New(v.Type())
+	if err := decodeJson(data, p.Interface(), true); err != nil {
+		return true, err
+	}
+	v.Set(p.Elem())
+	return true, nil
+}
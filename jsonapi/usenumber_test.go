@@ -0,0 +1,75 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type useNumberEvent struct {
+	Id      string         `jsonapi:"id,events"`
+	Payload any            `jsonapi:"attr,payload"`
+	Tags    map[string]any `jsonapi:"attr,tags"`
+	Count   int64          `jsonapi:"attr,count"`
+	TraceId any            `jsonapi:"meta,traceId"`
+	Extra   map[string]any `jsonapi:"meta-map"`
+}
+
+const useNumberJson = `{
+	"type": "events",
+	"id": "1",
+	"attributes": {
+		"payload": {"id": 1234567890123456789, "items": [1.5]},
+		"tags": {"n": 2},
+		"count": 9007199254740993
+	},
+	"meta": {"traceId": 1234567890123456789, "shard": 7}
+}`
+
+func TestWithUseNumber(t *testing.T) {
+	e := useNumberEvent{}
+	if err := UnmarshalResource([]byte(useNumberJson), &e, WithUseNumber()); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := useNumberEvent{
+		Id: "1",
+		Payload: map[string]any{
+			"id":    json.Number("1234567890123456789"),
+			"items": []any{json.Number("1.5")},
+		},
+		Tags:    map[string]any{"n": json.Number("2")},
+		Count:   9007199254740993,
+		TraceId: json.Number("1234567890123456789"),
+		Extra:   map[string]any{"shard": json.Number("7")},
+	}
+	assert.Equal(t, expected, e)
+}
+
+func TestWithUseNumber_Default(t *testing.T) {
+	e := useNumberEvent{}
+	if err := UnmarshalResource([]byte(useNumberJson), &e); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, float64(1234567890123456789), e.TraceId)
+	assert.Equal(t, map[string]any{"n": float64(2)}, e.Tags)
+	assert.Equal(t, map[string]any{"shard": float64(7)}, e.Extra)
+}
+
+func TestWithUseNumber_Initialised(t *testing.T) {
+	type point struct {
+		X float64 `json:"x"`
+	}
+	type shape struct {
+		Id     string `jsonapi:"id,shapes"`
+		Origin any    `jsonapi:"attr,origin"`
+	}
+
+	s := shape{Origin: &point{}}
+	data := `{"type": "shapes", "id": "1", "attributes": {"origin": {"x": 1.5}}}`
+	if err := UnmarshalResource([]byte(data), &s, WithUseNumber()); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, &point{X: 1.5}, s.Origin)
+}
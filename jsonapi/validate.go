@@ -0,0 +1,180 @@
+package jsonapi
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Violation describes a way in which a document, or a resource, breaks
+// the structural rules of the JSON:API specification.
+type Violation struct {
+	// Pointer is the JSON pointer of the offending member,
+	// relative to the validated resource or document, eg
+	// "/relationships/author".
+	Pointer string
+	Detail  string
+}
+
+func (v Violation) String() string {
+	if v.Pointer == "" {
+		return v.Detail
+	}
+	return v.Pointer + ": " + v.Detail
+}
+
+// Validate returns the ways in which r breaks the specification's rules
+// for resource objects, in the order of its members: it must have a
+// type, and an id or lid, its id must be a string, its relationships
+// must have links, data or meta, and their resource identifiers a type
+// and an id or lid, its attributes and relationships must not share
+// names, nor be named type or id, and its member names must be valid,
+// as reported by ValidMemberName.
+func (r *Resource) Validate() []Violation {
+	return r.validate("", true)
+}
+
+// Validate returns the ways in which d breaks the specification's
+// rules for documents: it must have data, errors or meta, but not
+// both data and errors, and only included resources if it has data.
+// Its resources are validated as by Resource.Validate, except that
+// single resource primary data, as in the creation requests of
+// resources without client-generated ids, needn't have an id, and
+// resources must not appear more than once.
+func (d *Document) Validate() []Violation {
+	var vs []Violation
+	if d.Data == nil && d.Errors == nil && d.Meta == nil {
+		vs = append(vs, Violation{"", "document must contain at least one of data, errors or meta"})
+	}
+	if d.Data != nil && d.Errors != nil {
+		vs = append(vs, Violation{"/errors", "document must not contain both data and errors"})
+	}
+	if d.Data == nil && d.Included != nil {
+		vs = append(vs, Violation{"/included", "document must not contain included without data"})
+	}
+
+	seen := map[string]bool{}
+	check := func(r *Resource, pointer string, requireId bool) {
+		if r == nil {
+			vs = append(vs, Violation{pointer, "resource must not be null"})
+			return
+		}
+		vs = append(vs, r.validate(pointer, requireId)...)
+		if len(r.Id) == 0 && r.Lid == "" {
+			return
+		}
+		key := identifierKey(r.ResourceIdentifier)
+		if seen[key] {
+			vs = append(vs, Violation{pointer, "duplicate resource " + identifierNode(r.ResourceIdentifier).String()})
+		}
+		seen[key] = true
+	}
+
+	if d.Data != nil {
+		if d.Data.Collection {
+			for i, r := range d.Data.Resources {
+				check(r, "/data/"+strconv.Itoa(i), true)
+			}
+		} else if d.Data.Resource != nil {
+			check(d.Data.Resource, "/data", false)
+		}
+	}
+	for i, r := range d.Included {
+		check(r, "/included/"+strconv.Itoa(i), true)
+	}
+	return vs
+}
+
+// validate returns the violations of r, as described by
+// Resource.Validate, with pointers prefixed by pointer. A
+// missing id is only reported if requireId is true.
+func (r *Resource) validate(pointer string, requireId bool) []Violation {
+	var vs []Violation
+	if r.Type == "" {
+		vs = append(vs, Violation{pointer + "/type", "resource must have a type"})
+	}
+	if requireId && len(r.Id) == 0 && r.Lid == "" {
+		vs = append(vs, Violation{pointer + "/id", "resource must have an id or lid"})
+	}
+	if !isStringId(r.Id, false) {
+		vs = append(vs, Violation{pointer + "/id", "id must be a string"})
+	}
+
+	for _, name := range sortedKeys(r.Attributes) {
+		p := pointer + "/attributes/" + pointerToken(name)
+		vs = appendNameViolation(vs, p, name)
+		if name == "type" || name == "id" {
+			vs = append(vs, Violation{p, "attribute must not be named " + name})
+		}
+		if _, ok := r.ToOneRelationships[name]; ok {
+			vs = append(vs, Violation{p, "attribute and relationship must not share a name"})
+		} else if _, ok := r.ToManyRelationships[name]; ok {
+			vs = append(vs, Violation{p, "attribute and relationship must not share a name"})
+		}
+	}
+
+	for _, name := range relationshipNames(r) {
+		p := pointer + "/relationships/" + pointerToken(name)
+		vs = appendNameViolation(vs, p, name)
+		if name == "type" || name == "id" {
+			vs = append(vs, Violation{p, "relationship must not be named " + name})
+		}
+		if rel, ok := r.ToOneRelationships[name]; ok {
+			vs = appendIdentifierViolations(vs, p+"/data", rel.Data)
+			continue
+		}
+		rel := r.ToManyRelationships[name]
+		if rel.Data == nil && len(rel.Links) == 0 && len(rel.Meta) == 0 {
+			vs = append(vs, Violation{p, "relationship must contain at least one of links, data or meta"})
+		}
+		for i, id := range rel.Data {
+			vs = appendIdentifierViolations(vs, p+"/data/"+strconv.Itoa(i), id)
+		}
+	}
+
+	for _, name := range sortedKeys(r.Meta) {
+		vs = appendNameViolation(vs, pointer+"/meta/"+pointerToken(name), name)
+	}
+	return vs
+}
+
+// sortedKeys returns the keys of m, sorted.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := mapKeys(m)
+	slices.Sort(keys)
+	return keys
+}
+
+// appendNameViolation appends a violation to vs if
+// the name of the member at pointer is invalid.
+func appendNameViolation(vs []Violation, pointer, name string) []Violation {
+	if ValidMemberName(name) {
+		return vs
+	}
+	return append(vs, Violation{pointer, "invalid member name"})
+}
+
+// appendIdentifierViolations appends the violations of the
+// resource identifier id, at pointer, to vs. Null linkage
+// is valid.
+func appendIdentifierViolations(vs []Violation, pointer string, id ResourceIdentifier) []Violation {
+	if isNullIdentifier(id) && id.Type == "" {
+		return vs
+	}
+	if id.Type == "" {
+		vs = append(vs, Violation{pointer + "/type", "resource identifier must have a type"})
+	}
+	if len(id.Id) == 0 && id.Lid == "" {
+		vs = append(vs, Violation{pointer + "/id", "resource identifier must have an id or lid"})
+	}
+	if !isStringId(id.Id, false) {
+		vs = append(vs, Violation{pointer + "/id", "id must be a string"})
+	}
+	return vs
+}
+
+// pointerToken escapes s for use as a JSON pointer
+// reference token, as described by RFC 6901.
+func pointerToken(s string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(s)
+}
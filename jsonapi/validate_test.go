@@ -0,0 +1,86 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResource_Validate(t *testing.T) {
+	r := Resource{}
+	data := `{
+		"id": 1,
+		"attributes": {"title": "Hello", "author": "Bob", "type": "article", "a/b~": 1},
+		"relationships": {
+			"author": {"data": {"type": "people", "id": "2"}},
+			"editor": {"data": null},
+			"tags": {"data": [{"type": "tags", "id": "1"}, {"id": "2"}, {"type": "tags"}]},
+			"comments": {"links": {"related": "/articles/1/comments"}},
+			"empty": {},
+			"_draft": {"meta": {"since": 1}}
+		},
+		"meta": {"views": 1, "-": 2}
+	}`
+	if err := json.Unmarshal([]byte(data), &r); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Violation{
+		{"/type", "resource must have a type"},
+		{"/id", "id must be a string"},
+		{"/attributes/a~1b~0", "invalid member name"},
+		{"/attributes/author", "attribute and relationship must not share a name"},
+		{"/attributes/type", "attribute must not be named type"},
+		{"/relationships/_draft", "invalid member name"},
+		{"/relationships/empty", "relationship must contain at least one of links, data or meta"},
+		{"/relationships/tags/data/1/type", "resource identifier must have a type"},
+		{"/relationships/tags/data/2/id", "resource identifier must have an id or lid"},
+		{"/meta/-", "invalid member name"},
+	}
+	assert.Equal(t, want, r.Validate())
+
+	valid := Resource{ResourceIdentifier: ResourceIdentifier{Type: "articles", Lid: "a"}}
+	assert.Empty(t, valid.Validate())
+
+	valid.Lid = ""
+	assert.Equal(t, []Violation{{"/id", "resource must have an id or lid"}}, valid.Validate())
+}
+
+func TestDocument_Validate(t *testing.T) {
+	type testCase struct {
+		Name     string
+		Data     string
+		Expected []string
+	}
+
+	testCases := []testCase{
+		{"Valid", docArticlesJson, nil},
+		{"Empty", `{}`, []string{"document must contain at least one of data, errors or meta"}},
+		{"DataAndErrors", `{"data": null, "errors": []}`, []string{"/errors: document must not contain both data and errors"}},
+		{"IncludedWithoutData", `{"meta": {}, "included": []}`, []string{"/included: document must not contain included without data"}},
+		{"NewResource", `{"data": {"type": "articles"}}`, nil},
+		{"Resources", `{"data": [{"type": "articles"}], "included": [{"id": "1"}]}`, []string{
+			"/data/0/id: resource must have an id or lid",
+			"/included/0/type: resource must have a type",
+		}},
+		{"Duplicates", `{"data": [{"type": "articles", "id": "1"}], "included": [{"type": "articles", "id": "1"}, {"type": "people", "lid": "a"}, {"type": "people", "lid": "a"}]}`, []string{
+			"/included/0: duplicate resource articles/1",
+			"/included/2: duplicate resource people/a",
+		}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			d := Document{}
+			if err := json.Unmarshal([]byte(tc.Data), &d); err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, v := range d.Validate() {
+				got = append(got, v.String())
+			}
+			assert.Equal(t, tc.Expected, got)
+		})
+	}
+}
@@ -0,0 +1,226 @@
+package jsonapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// ValidateDocument returns the ways in which the JSON payload data
+// breaks the specification's rules for documents, without decoding it
+// into a Go value, eg for gateways checking the requests they forward.
+// Each Violation's pointer is relative to the payload.
+//
+// First the payload's structure is checked: that it's valid JSON, that
+// the members of its document, resource, relationship, link and error
+// objects have the right kinds of value, and that those objects don't
+// have members the specification doesn't define, other than extension
+// members and @-members. If it has no structural violations, it's
+// decoded and the violations reported by Document.Validate returned.
+func ValidateDocument(data []byte) []Violation {
+	if !json.Valid(data) {
+		var v any
+		return []Violation{{"", "invalid JSON: " + json.Unmarshal(data, &v).Error()}}
+	}
+
+	data = bytes.TrimSpace(data)
+	if data[0] != '{' {
+		return []Violation{{"", "document must be an object"}}
+	}
+	if vs := rawDocument(nil, "", data); len(vs) > 0 {
+		return vs
+	}
+
+	d := &Document{}
+	if err := d.unmarshal(data, true); err != nil {
+		// eg links with empty hrefs
+		return []Violation{{"", err.Error()}}
+	}
+	return d.Validate()
+}
+
+// rawCheck appends the structural violations of the
+// valid JSON value data, at pointer, to vs.
+type rawCheck func(vs []Violation, pointer string, data []byte) []Violation
+
+var (
+	rawMeta  = rawMap(nil)
+	rawLinks = rawMap(rawLink)
+
+	rawIdentifier = rawObject(map[string]rawCheck{
+		"type": rawString,
+		"id":   nil,
+		"lid":  rawString,
+		"meta": rawMeta,
+	}, true)
+
+	rawRelationship = rawObject(map[string]rawCheck{
+		"data":  rawOneOrMany(rawIdentifier),
+		"links": rawLinks,
+		"meta":  rawMeta,
+	}, true)
+
+	rawResource = rawObject(map[string]rawCheck{
+		"type":          rawString,
+		"id":            nil,
+		"lid":           rawString,
+		"attributes":    rawMap(nil),
+		"relationships": rawMap(rawRelationship),
+		"links":         rawLinks,
+		"meta":          rawMeta,
+	}, true)
+
+	rawError = rawObject(map[string]rawCheck{
+		"id":     rawString,
+		"links":  rawLinks,
+		"status": rawString,
+		"code":   rawString,
+		"title":  rawString,
+		"detail": rawString,
+		"source": rawObject(map[string]rawCheck{
+			"pointer":   rawString,
+			"parameter": rawString,
+			"header":    rawString,
+		}, false),
+		"meta": rawMeta,
+	}, false)
+
+	rawLinkObject = rawObject(map[string]rawCheck{
+		"href":        rawString,
+		"rel":         rawString,
+		"describedby": nil,
+		"title":       rawString,
+		"type":        rawString,
+		"hreflang":    rawHrefLang,
+		"meta":        rawMeta,
+	}, false)
+
+	rawDocument = rawObject(map[string]rawCheck{
+		"data":   rawOneOrMany(rawResource),
+		"errors": rawArray(rawError),
+		"meta":   rawMeta,
+		"links":  rawLinks,
+		"jsonapi": rawObject(map[string]rawCheck{
+			"version": rawString,
+			"ext":     rawArray(rawString),
+			"profile": rawArray(rawString),
+			"meta":    rawMeta,
+		}, false),
+		"included": rawArray(rawResource),
+	}, true)
+)
+
+// rawObject returns a check that the value is an object whose members
+// are checked by the checks of the same name, where nil checks accept
+// any value. Other members are violations, except for @-members and,
+// if ext is true, extension members.
+func rawObject(members map[string]rawCheck, ext bool) rawCheck {
+	return func(vs []Violation, pointer string, data []byte) []Violation {
+		if data[0] != '{' {
+			return append(vs, Violation{pointer, "must be an object"})
+		}
+
+		seen := map[string]bool{}
+		eachMember(data, func(name string, value json.RawMessage) {
+			p := pointer + "/" + pointerToken(name)
+			if seen[name] {
+				vs = append(vs, Violation{p, "duplicate member"})
+			}
+			seen[name] = true
+
+			check, ok := members[name]
+			switch {
+			case ok && check != nil:
+				vs = check(vs, p, value)
+			case ok, strings.HasPrefix(name, "@"), ext && isExtMember(name):
+			default:
+				vs = append(vs, Violation{p, "member is not allowed"})
+			}
+		})
+		return vs
+	}
+}
+
+// rawMap returns a check that the value is an object, whose
+// members are checked by check, or accepted if check is nil.
+func rawMap(check rawCheck) rawCheck {
+	return func(vs []Violation, pointer string, data []byte) []Violation {
+		if data[0] != '{' {
+			return append(vs, Violation{pointer, "must be an object"})
+		}
+		if check == nil {
+			return vs
+		}
+		eachMember(data, func(name string, value json.RawMessage) {
+			vs = check(vs, pointer+"/"+pointerToken(name), value)
+		})
+		return vs
+	}
+}
+
+// rawArray returns a check that the value is an
+// array, whose elements are checked by check.
+func rawArray(check rawCheck) rawCheck {
+	return func(vs []Violation, pointer string, data []byte) []Violation {
+		if data[0] != '[' {
+			return append(vs, Violation{pointer, "must be an array"})
+		}
+		elems, _ := rawElements(data)
+		for i, elem := range elems {
+			vs = check(vs, pointer+"/"+strconv.Itoa(i), elem)
+		}
+		return vs
+	}
+}
+
+// rawOneOrMany returns a check that the value is null, an object
+// checked by check, or an array of these, as for primary data and
+// relationship linkage.
+func rawOneOrMany(check rawCheck) rawCheck {
+	many := rawArray(check)
+	return func(vs []Violation, pointer string, data []byte) []Violation {
+		switch data[0] {
+		case 'n':
+			return vs
+		case '{':
+			return check(vs, pointer, data)
+		case '[':
+			return many(vs, pointer, data)
+		default:
+			return append(vs, Violation{pointer, "must be an object, an array or null"})
+		}
+	}
+}
+
+func rawString(vs []Violation, pointer string, data []byte) []Violation {
+	if data[0] != '"' {
+		return append(vs, Violation{pointer, "must be a string"})
+	}
+	return vs
+}
+
+// rawLink checks that the value is a link: a string,
+// null, or a link object with an href.
+func rawLink(vs []Violation, pointer string, data []byte) []Violation {
+	switch data[0] {
+	case '"', 'n':
+		return vs
+	case '{':
+		if values, _ := rawMembers(data, true); values["href"] == nil {
+			vs = append(vs, Violation{pointer, "link object must have an href"})
+		}
+		return rawLinkObject(vs, pointer, data)
+	default:
+		return append(vs, Violation{pointer, "must be a string, an object or null"})
+	}
+}
+
+// rawHrefLang checks that the value is a language
+// tag or an array of them.
+func rawHrefLang(vs []Violation, pointer string, data []byte) []Violation {
+	if data[0] == '[' {
+		return rawArray(rawString)(vs, pointer, data)
+	}
+	return rawString(vs, pointer, data)
+}
@@ -0,0 +1,93 @@
+package jsonapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateDocument(t *testing.T) {
+	type testCase struct {
+		name string
+		data string
+		want []Violation
+	}
+
+	testCases := []testCase{{
+		name: "valid",
+		data: docArticlesJson,
+	}, {
+		name: "valid members",
+		data: `{
+			"data": {"type": "articles", "id": "1", "@ctx": 1, "ext:x": 2, "links": {"self": "/articles/1", "related": {"href": "/x", "hreflang": "en"}, "next": null}},
+			"jsonapi": {"version": "1.1", "ext": ["ext"]},
+			"meta": {"any": [1]}
+		}`,
+	}, {
+		name: "empty href",
+		data: `{"meta": {}, "links": {"self": {"href": ""}}}`,
+		want: []Violation{{"", ErrMissingHref.Error()}},
+	}, {
+		name: "invalid json",
+		data: `{"data": `,
+		want: []Violation{{"", "invalid JSON: unexpected end of JSON input"}},
+	}, {
+		name: "not an object",
+		data: ` [] `,
+		want: []Violation{{"", "document must be an object"}},
+	}, {
+		name: "structure",
+		data: `{
+			"data": [
+				{"type": 1, "id": "1", "attributes": [], "relationships": {"author": {"data": "9"}, "tags": {"data": [{"type": "tags", "lid": 2}]}}},
+				"x"
+			],
+			"included": {},
+			"meta": null,
+			"links": {"self": 1, "related": {"title": "x", "extra": true}},
+			"errors": [{"status": 404, "source": {"line": 1}}],
+			"jsonapi": {"version": "1.1", "ext:x": 1},
+			"foo": 1,
+			"meta": {}
+		}`,
+		want: []Violation{
+			{"/data/0/type", "must be a string"},
+			{"/data/0/attributes", "must be an object"},
+			{"/data/0/relationships/author/data", "must be an object, an array or null"},
+			{"/data/0/relationships/tags/data/0/lid", "must be a string"},
+			{"/data/1", "must be an object"},
+			{"/included", "must be an array"},
+			{"/meta", "must be an object"},
+			{"/links/self", "must be a string, an object or null"},
+			{"/links/related", "link object must have an href"},
+			{"/links/related/extra", "member is not allowed"},
+			{"/errors/0/status", "must be a string"},
+			{"/errors/0/source/line", "member is not allowed"},
+			{"/jsonapi/ext:x", "member is not allowed"},
+			{"/foo", "member is not allowed"},
+			{"/meta", "duplicate member"},
+		},
+	}, {
+		name: "escaped names",
+		data: `{"data": {"type": "articles", "attributes": {"a/b": 1}, "relationships": {"c~d": {"data": 1}}}}`,
+		want: []Violation{{"/data/relationships/c~0d/data", "must be an object, an array or null"}},
+	}, {
+		name: "document rules",
+		data: `{
+			"data": [{"type": "articles", "id": 1}, {"type": "articles", "id": 1}],
+			"errors": [{"title": "x"}]
+		}`,
+		want: []Violation{
+			{"/errors", "document must not contain both data and errors"},
+			{"/data/0/id", "id must be a string"},
+			{"/data/1/id", "id must be a string"},
+			{"/data/1", "duplicate resource articles/1"},
+		},
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, ValidateDocument([]byte(tc.data)))
+		})
+	}
+}
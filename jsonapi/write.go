@@ -0,0 +1,59 @@
+package jsonapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// AppendResource appends the resource object of a, as encoded by
+// MarshalResource, to dst and returns the extended slice, so that
+// callers that manage their own buffers avoid a copy.
+func AppendResource(dst []byte, a any, opts ...Option) ([]byte, error) {
+	v := reflect.ValueOf(a)
+
+	v, err := derefInput(v, resourceMarshalerType)
+	if err != nil {
+		return dst, fmt.Errorf("jsonapi: dereferencing input: %w", err)
+	}
+
+	if v.Type().Implements(resourceMarshalerType) {
+		data, err := v.Interface().(ResourceMarshaler).MarshalJsonApiResource()
+		if err != nil {
+			return dst, err
+		}
+		return append(dst, data...), nil
+	}
+
+	if v.Type().Kind() != reflect.Struct {
+		return dst, fmt.Errorf("jsonapi: %w", ErrNotStruct)
+	}
+
+	r, err := formatStruct(v, newOptions(opts))
+	if err != nil {
+		return dst, err
+	}
+
+	buf := bytes.NewBuffer(dst)
+	err = json.NewEncoder(buf).Encode(r)
+	releaseResource(r)
+	if err != nil {
+		return dst, fmt.Errorf("jsonapi: marshaling resource: %w", err)
+	}
+
+	// trim the encoder's trailing newline
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// WriteTo writes the JSON encoding of d to w,
+// implementing io.WriterTo.
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	data, err := d.MarshalJSON()
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(data)
+	return int64(n), err
+}
@@ -0,0 +1,49 @@
+package jsonapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppendResource(t *testing.T) {
+	a := struct {
+		Id    string `jsonapi:"id,articles"`
+		Title string `jsonapi:"attr,title"`
+	}{"1", "Hello"}
+
+	want, err := MarshalResource(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dst := make([]byte, 0, 256)
+	dst = append(dst, `{"data":`...)
+	got, err := AppendResource(dst, a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, `{"data":`+string(want), string(got))
+	// appended in place
+	assert.Same(t, &dst[:1][0], &got[0])
+
+	got, err = AppendResource(nil, 1)
+	assert.ErrorIs(t, err, ErrNotStruct)
+	assert.Nil(t, got)
+}
+
+func TestDocument_WriteTo(t *testing.T) {
+	d := &Document{Meta: map[string]json.RawMessage{"total": json.RawMessage("1")}}
+	var _ io.WriterTo = d
+
+	buf := bytes.Buffer{}
+	n, err := d.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, `{"meta":{"total":1}}`, buf.String())
+	assert.Equal(t, int64(buf.Len()), n)
+}
@@ -0,0 +1,107 @@
+// Package jsonapitest provides conformance test vectors for JSON:API
+// documents, and helpers that check they can be decoded and re-encoded
+// without loss, eg by downstream projects verifying their own documents.
+package jsonapitest
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/max-waters/jsonapi/jsonapi"
+)
+
+//go:embed vectors/*.json
+var vectors embed.FS
+
+// Vector is a JSON:API document used as a test vector.
+type Vector struct {
+	Name string
+	Data []byte
+}
+
+// Vectors returns the built-in test vectors, based on
+// the example documents in the JSON:API specification.
+func Vectors() []Vector {
+	vs, err := LoadVectors(vectors, "vectors/*.json")
+	if err != nil {
+		panic(err)
+	}
+	return vs
+}
+
+// LoadVectors loads the files in fsys matching the glob pattern as
+// test vectors, named after their file names without extensions.
+func LoadVectors(fsys fs.FS, pattern string) ([]Vector, error) {
+	names, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	vs := make([]Vector, len(names))
+	for i, name := range names {
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		vs[i] = Vector{
+			Name: strings.TrimSuffix(path.Base(name), path.Ext(name)),
+			Data: data,
+		}
+	}
+	return vs, nil
+}
+
+// RoundTrip decodes data as a jsonapi.Document, re-encodes it, and
+// returns an error if the result is not equivalent to data.
+func RoundTrip(data []byte) error {
+	d := jsonapi.Document{}
+	if err := json.Unmarshal(data, &d); err != nil {
+		return fmt.Errorf("decoding: %w", err)
+	}
+
+	got, err := json.Marshal(&d)
+	if err != nil {
+		return fmt.Errorf("encoding: %w", err)
+	}
+
+	var want, have any
+	if err := json.Unmarshal(data, &want); err != nil {
+		return fmt.Errorf("decoding: %w", err)
+	}
+	if err := json.Unmarshal(got, &have); err != nil {
+		return fmt.Errorf("decoding re-encoded document: %w", err)
+	}
+
+	if !reflect.DeepEqual(want, have) {
+		return fmt.Errorf("re-encoded document differs:\nwant: %s\ngot:  %s", compact(data), got)
+	}
+	return nil
+}
+
+// RunVectors runs a subtest for each vector,
+// checking that it survives a RoundTrip.
+func RunVectors(t *testing.T, vs []Vector) {
+	t.Helper()
+	for _, v := range vs {
+		t.Run(v.Name, func(t *testing.T) {
+			if err := RoundTrip(v.Data); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func compact(data []byte) []byte {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return data
+	}
+	c, _ := json.Marshal(v)
+	return c
+}
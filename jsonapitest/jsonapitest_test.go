@@ -0,0 +1,40 @@
+package jsonapitest
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVectors(t *testing.T) {
+	vs := Vectors()
+	assert.NotEmpty(t, vs)
+	RunVectors(t, vs)
+}
+
+func TestLoadVectors(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a/one.json": {Data: []byte(`{"data": null}`)},
+		"a/two.json": {Data: []byte(`{"data": []}`)},
+		"a/skip.txt": {Data: []byte(`skip`)},
+	}
+
+	vs, err := LoadVectors(fsys, "a/*.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, []Vector{
+		{"one", []byte(`{"data": null}`)},
+		{"two", []byte(`{"data": []}`)},
+	}, vs)
+}
+
+func TestRoundTrip_Lossy(t *testing.T) {
+	// unknown error object members are dropped
+	err := RoundTrip([]byte(`{"errors": [{"status": "400", "unknown": 1}]}`))
+	assert.ErrorContains(t, err, "re-encoded document differs")
+
+	assert.ErrorContains(t, RoundTrip([]byte(`{`)), "decoding")
+}
@@ -0,0 +1,15 @@
+// The jsonapivet command checks the jsonapi tags of the struct types
+// of Go packages. It can be run directly, or by go vet:
+//
+//	go vet -vettool=$(which jsonapivet) ./...
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/singlechecker"
+
+	"github.com/max-waters/jsonapi/jsonapivet"
+)
+
+func main() {
+	singlechecker.Main(jsonapivet.Analyzer)
+}
@@ -0,0 +1,208 @@
+// Package jsonapivet defines an Analyzer that checks the jsonapi tags
+// of struct types for the mistakes that the jsonapi package otherwise
+// only reports at runtime. Unlike the jsonapi command's vet subcommand,
+// it has the type information of the packages it checks, and so also
+// checks named field types, and relationships to the types of other
+// packages.
+//
+// It is a separate module, so that the jsonapi module itself doesn't
+// depend on golang.org/x/tools.
+package jsonapivet
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"reflect"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/max-waters/jsonapi/jsonapi"
+)
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "jsonapi",
+	Doc:      "check the jsonapi tags of struct types",
+	URL:      "https://pkg.go.dev/github.com/max-waters/jsonapi/jsonapivet",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	insp.Preorder([]ast.Node{(*ast.StructType)(nil)}, func(n ast.Node) {
+		st, ok := pass.TypesInfo.Types[n.(*ast.StructType)].Type.(*types.Struct)
+		if ok {
+			checkStruct(pass, n.(*ast.StructType), st)
+		}
+	})
+	return nil, nil
+}
+
+// checkStruct reports the problems with the jsonapi tags of the
+// struct type st, declared by expr, at the positions of its fields.
+func checkStruct(pass *analysis.Pass, expr *ast.StructType, st *types.Struct) {
+	// the fields of attributes and relationships, which share
+	// a namespace, and of meta, by member name
+	members := map[string]string{}
+	meta := map[string]string{}
+
+	for i := 0; i < st.NumFields(); i++ {
+		f := st.Field(i)
+		tags := reflect.StructTag(st.Tag(i))
+		value, ok := tags.Lookup(jsonapi.TagKeyJsonApi)
+		if !ok {
+			continue
+		}
+		typ, opts, _ := strings.Cut(value, ",")
+		pos := fieldPos(expr, f)
+
+		if !slices.Contains(jsonapi.TagTypes(), typ) {
+			pass.Reportf(pos, "field %s: unknown tag type %q", f.Name(), typ)
+			continue
+		}
+		if typ == jsonapi.TagValueIgnore {
+			continue
+		}
+		if k := unsupportedKind(f.Type()); k != "" {
+			pass.Reportf(pos, "field %s: unsupported %s type %s", f.Name(), typ, k)
+		}
+
+		name, rest, _ := strings.Cut(opts, ",")
+		switch typ {
+		case jsonapi.TagValueLid:
+			if !isString(f.Type()) {
+				pass.Reportf(pos, "field %s: lid must be a string", f.Name())
+			}
+		case jsonapi.TagValueRel:
+			if rscType, _, _ := strings.Cut(rest, ","); rscType == "" && !isTypedRel(f.Type()) {
+				pass.Reportf(pos, "field %s: rel tag requires a resource type", f.Name())
+			}
+		}
+
+		if typ != jsonapi.TagValueAttr && typ != jsonapi.TagValueRel && typ != jsonapi.TagValueMeta {
+			continue
+		}
+		if name == "" {
+			name, _, _ = strings.Cut(tags.Get(jsonapi.TagKeyJson), ",")
+		}
+		if name == "" {
+			name = f.Name()
+		}
+
+		seen := members
+		if typ == jsonapi.TagValueMeta {
+			seen = meta
+			if rel, ok := jsonapi.RelOption(rest); ok {
+				// relationship meta, which has
+				// a namespace per relationship
+				name = rel + "." + name
+			}
+		} else if name == "type" || name == "id" {
+			pass.Reportf(pos, "field %s: member name %q is reserved", f.Name(), name)
+		}
+		if other, ok := seen[name]; ok {
+			pass.Reportf(pos, "field %s: member name %q is also used by field %s", f.Name(), name, other)
+			continue
+		}
+		seen[name] = f.Name()
+	}
+}
+
+// fieldPos returns the position of the declaration of the
+// field f in expr, ie of its names, or of its embedded type.
+func fieldPos(expr *ast.StructType, f *types.Var) token.Pos {
+	for _, field := range expr.Fields.List {
+		for _, ident := range field.Names {
+			if ident.Pos() == f.Pos() {
+				return field.Pos()
+			}
+		}
+		if len(field.Names) == 0 && field.Type.Pos() <= f.Pos() && f.Pos() < field.Type.End() {
+			return field.Pos()
+		}
+	}
+	return f.Pos()
+}
+
+// deref returns the type t points to, if it's a pointer type.
+func deref(t types.Type) types.Type {
+	for {
+		p, ok := t.Underlying().(*types.Pointer)
+		if !ok {
+			return t
+		}
+		t = p.Elem()
+	}
+}
+
+// unsupportedKind returns the kind of the field type t, or of the
+// type it points to, if the jsonapi package can't marshal it, or "".
+func unsupportedKind(t types.Type) string {
+	switch u := deref(t).Underlying().(type) {
+	case *types.Signature:
+		return "func"
+	case *types.Chan:
+		return "chan"
+	case *types.Basic:
+		if u.Info()&types.IsComplex != 0 {
+			return u.Name()
+		}
+	}
+	return ""
+}
+
+// isString returns whether the field type t is
+// a string type, or a pointer to one.
+func isString(t types.Type) bool {
+	b, ok := deref(t).Underlying().(*types.Basic)
+	return ok && b.Info()&types.IsString != 0
+}
+
+// isTypedRel returns whether the field type t is an interface, or a
+// pointer to or slice of them, as polymorphic relationships are, or
+// a related struct whose id tag declares its type.
+func isTypedRel(t types.Type) bool {
+	t = deref(t)
+	if s, ok := t.Underlying().(*types.Slice); ok {
+		t = deref(s.Elem())
+	}
+
+	switch u := t.Underlying().(type) {
+	case *types.Interface:
+		return true
+	case *types.Struct:
+		return declaresType(u, map[*types.Struct]bool{})
+	}
+	return false
+}
+
+// declaresType returns whether the struct type st, or a struct it
+// embeds, has an id tag that declares a resource type. seen holds
+// the structs already checked, in case of embedded pointer cycles.
+func declaresType(st *types.Struct, seen map[*types.Struct]bool) bool {
+	if seen[st] {
+		return false
+	}
+	seen[st] = true
+
+	for i := 0; i < st.NumFields(); i++ {
+		value := reflect.StructTag(st.Tag(i)).Get(jsonapi.TagKeyJsonApi)
+		if typ, opts, _ := strings.Cut(value, ","); typ == jsonapi.TagValueId {
+			rscType, _, _ := strings.Cut(opts, ",")
+			return rscType != ""
+		}
+	}
+	for i := 0; i < st.NumFields(); i++ {
+		if f := st.Field(i); f.Embedded() {
+			if e, ok := deref(f.Type()).Underlying().(*types.Struct); ok && declaresType(e, seen) {
+				return true
+			}
+		}
+	}
+	return false
+}
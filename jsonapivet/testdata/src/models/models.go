@@ -0,0 +1,61 @@
+package models
+
+import "people"
+
+type Node interface {
+	ID() string
+}
+
+type Callback func()
+
+type LocalId string
+
+type Article struct {
+	ID       string         `jsonapi:"id,articles"`
+	Title    string         `jsonapi:"attr,title"`
+	Heading  string         `jsonapi:"attr,title"` // want `field Heading: member name "title" is also used by field Title`
+	Kind     string         `jsonapi:"attr,type"`  // want `field Kind: member name "type" is reserved`
+	Author   string         `jsonapi:"rel,author"` // want `field Author: rel tag requires a resource type`
+	Parent   Node           `jsonapi:"rel,parent"`
+	Children []any          `jsonapi:"rel,children"`
+	Editor   string         `jsonapi:"rel,title,people"`  // want `field Editor: member name "title" is also used by field Title`
+	OnSave   func()         `jsonapi:"attr,onsave"`       // want `field OnSave: unsupported attr type func`
+	OnLoad   *Callback      `jsonapi:"attr,onload"`       // want `field OnLoad: unsupported attr type func`
+	Updates  chan int       `jsonapi:"meta,updates"`      // want `field Updates: unsupported meta type chan`
+	Local    int            `jsonapi:"lid"`               // want `field Local: lid must be a string`
+	Summary  string         `jsonapi:"attribute,summary"` // want `field Summary: unknown tag type "attribute"`
+	Views    int            `jsonapi:"meta,views"`
+	Count    int            `jsonapi:"meta,views"` // want `field Count: member name "views" is also used by field Views`
+	Secret   string         `jsonapi:"-"`
+	Extra    map[string]any `jsonapi:"attr-map"`
+	Body     string         `json:"body" jsonapi:"attr"`
+	Text     string         `jsonapi:"attr,body"` // want `field Text: member name "body" is also used by field Body`
+	Ignored  string
+	Reviewer *people.Person   `jsonapi:"rel,reviewer"`
+	Owner    people.Untyped   `jsonapi:"rel,owner"` // want `field Owner: rel tag requires a resource type`
+	Readers  []*people.Person `jsonapi:"rel,readers"`
+}
+
+type Comment struct {
+	ID  string  `jsonapi:"id,comments"`
+	Lid LocalId `jsonapi:"lid"`
+}
+
+type Editor struct {
+	people.Person
+	Role string `jsonapi:"attr,role"`
+}
+
+type Post struct {
+	ID            string   `jsonapi:"id,posts"`
+	Comments      []string `jsonapi:"rel,comments,comments"`
+	CommentsTotal int      `jsonapi:"meta,total,rel=comments"`
+	Total         int      `jsonapi:"meta,total"`
+	Pages         int      `jsonapi:"meta,total,rel=comments"` // want `field Pages: member name "comments.total" is also used by field CommentsTotal`
+	Editors       []Editor `jsonapi:"rel,editors"`
+}
+
+type Result struct {
+	Article *Article `jsonapi:"union,articles"`
+	Person  *Comment `jsonapi:"union,comments"`
+}
@@ -0,0 +1,10 @@
+package people
+
+type Person struct {
+	ID   string `jsonapi:"id,people"`
+	Name string `jsonapi:"attr,name"`
+}
+
+type Untyped struct {
+	ID string `jsonapi:"id"`
+}
@@ -0,0 +1,189 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/max-waters/jsonapi/jsonapi"
+)
+
+// QueryParamPretty is the query parameter that
+// requests pretty-printed responses, eg ?pretty=true.
+const QueryParamPretty = "pretty"
+
+// EncoderConfig is the configuration for encoding the
+// response to a request, as derived by NewEncoderConfig.
+type EncoderConfig struct {
+	// Query holds the request's parsed query parameters,
+	// eg the relationship paths to include.
+	Query *jsonapi.Query
+	// Ext and Profiles list the URIs of the registered extensions
+	// and profiles requested by the Accept header, which are listed
+	// in the response's Content-Type.
+	Ext      []string
+	Profiles []string
+	// Locale is the most preferred language tag
+	// of the Accept-Language header, if any.
+	Locale string
+	// Pretty is true if the response should be indented.
+	Pretty bool
+	// Options are passed to jsonapi.FormatDocument, including
+	// WithFields for the query's sparse fieldsets.
+	Options []jsonapi.Option
+}
+
+// NewEncoderConfig derives the configuration for encoding the
+// response to r from its headers and query: the query parameters,
+// parsed with cfg, the extensions and profiles requested by the Accept
+// header, the Accept-Language locale, and the pretty parameter. opts
+// are added to the configuration's options, after WithFields, and the
+// extensions and profiles are those registered with DefaultRegistry.
+//
+// Invalid query parameters are reported as 400 Bad Request, and
+// unsupported extensions as 406 Not Acceptable, by errors that
+// can be passed to WriteError.
+func NewEncoderConfig(r *http.Request, cfg jsonapi.QueryConfig, opts ...jsonapi.Option) (*EncoderConfig, error) {
+	q, err := jsonapi.ParseQuery(r.URL.Query(), cfg)
+	if err != nil {
+		e := badRequest(err.Error(), "")
+		if qe := (*jsonapi.QueryErr)(nil); errors.As(err, &qe) {
+			e.Source = &jsonapi.ErrorSource{Parameter: qe.Param}
+		}
+		return nil, e
+	}
+
+	c := &EncoderConfig{
+		Query:  q,
+		Locale: locale(r.Header.Values("Accept-Language")),
+	}
+
+	if values, ok := r.URL.Query()[QueryParamPretty]; ok {
+		c.Pretty = values[0] == ""
+		if !c.Pretty {
+			if c.Pretty, err = strconv.ParseBool(values[0]); err != nil {
+				return nil, badRequest("pretty must be a boolean", "")
+			}
+		}
+	}
+
+	if q.Fields != nil {
+		c.Options = append(c.Options, jsonapi.WithFields(q.Fields))
+	}
+	c.Options = append(c.Options, opts...)
+
+	s := jsonapi.DefaultRegistry.Snapshot()
+	ext, profiles := acceptedParams(r.Header.Values("Accept"))
+	if err := s.SupportsExt(ext); err != nil {
+		return nil, &jsonapi.ErrorObject{
+			Status: strconv.Itoa(http.StatusNotAcceptable),
+			Title:  http.StatusText(http.StatusNotAcceptable),
+			Detail: err.Error(),
+			Source: &jsonapi.ErrorSource{Header: "Accept"},
+		}
+	}
+	c.Ext = strings.Fields(ext)
+	// unsupported profiles are ignored
+	for _, p := range strings.Fields(profiles) {
+		if _, ok := s.Profile(p); ok {
+			c.Profiles = append(c.Profiles, p)
+		}
+	}
+
+	return c, nil
+}
+
+// MediaType returns the response's media type, with ext
+// and profile parameters listing c's extensions and profiles.
+func (c *EncoderConfig) MediaType() string {
+	return jsonapi.FormatMediaType(c.Ext, c.Profiles)
+}
+
+// Encode marshals a as a document with c's options, as described by
+// jsonapi.MarshalDocument, declaring c's extensions and profiles in
+// its jsonapi object, and indenting it if c.Pretty is true.
+func (c *EncoderConfig) Encode(a any) ([]byte, error) {
+	opts := c.Options
+	if len(c.Ext) > 0 || len(c.Profiles) > 0 {
+		opts = append(slices.Clip(opts), jsonapi.WithExt(c.Ext...), jsonapi.WithProfiles(c.Profiles...))
+	}
+	data, err := jsonapi.MarshalDocument(a, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if !c.Pretty {
+		return data, nil
+	}
+
+	buf := bytes.Buffer{}
+	if err := json.Indent(&buf, data, "", "  "); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Write writes a document containing a, encoded by c, to w with
+// the supplied status code. If a cannot be marshaled, a 500 error
+// document is written and the marshaling error is returned.
+func (c *EncoderConfig) Write(w http.ResponseWriter, status int, a any) error {
+	data, err := c.Encode(a)
+	if err != nil {
+		writeInternalError(w)
+		return err
+	}
+
+	w.Header().Set("Content-Type", c.MediaType())
+	if c.Locale != "" {
+		w.Header().Set("Content-Language", c.Locale)
+	}
+	w.WriteHeader(status)
+	_, err = w.Write(data)
+	return err
+}
+
+// acceptedParams returns the ext and profile parameters of the
+// first JSON:API media range in the Accept header values with
+// no other parameters.
+func acceptedParams(accept []string) (string, string) {
+	for _, header := range accept {
+		for _, mediaRange := range splitHeader(header) {
+			typ, params, err := mime.ParseMediaType(mediaRange)
+			if err != nil || typ != jsonapi.MediaType {
+				continue
+			}
+			delete(params, "q")
+			if allowedParams(params) {
+				return params[jsonapi.MediaTypeParamExt], params[jsonapi.MediaTypeParamProfile]
+			}
+		}
+	}
+	return "", ""
+}
+
+// locale returns the language tag with the highest
+// quality in the Accept-Language header values.
+func locale(accept []string) string {
+	best, bestQ := "", 0.0
+	for _, header := range accept {
+		for _, lang := range splitHeader(header) {
+			tag, params, _ := strings.Cut(lang, ";")
+			tag = strings.TrimSpace(tag)
+			q := 1.0
+			if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+				var err error
+				if q, err = strconv.ParseFloat(v, 64); err != nil {
+					continue
+				}
+			}
+			if tag != "" && tag != "*" && q > bestQ {
+				best, bestQ = tag, q
+			}
+		}
+	}
+	return best
+}
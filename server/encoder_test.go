@@ -0,0 +1,139 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/max-waters/jsonapi/jsonapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewEncoderConfig(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/articles/1?include=author&fields[articles]=title&pretty", nil)
+	r.Header.Set("Accept", jsonapi.MediaType)
+	r.Header.Set("Accept-Language", "fr;q=0.5, en-GB, de;q=0.8")
+
+	c, err := NewEncoderConfig(r, jsonapi.QueryConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, []string{"author"}, c.Query.Include)
+	assert.Equal(t, map[string][]string{"articles": {"title"}}, c.Query.Fields)
+	assert.Equal(t, "en-GB", c.Locale)
+	assert.True(t, c.Pretty)
+	assert.Empty(t, c.Ext)
+	assert.Empty(t, c.Profiles)
+	assert.Len(t, c.Options, 1)
+}
+
+func TestNewEncoderConfig_Defaults(t *testing.T) {
+	c, err := NewEncoderConfig(httptest.NewRequest(http.MethodGet, "/articles", nil), jsonapi.QueryConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "", c.Locale)
+	assert.False(t, c.Pretty)
+	assert.Empty(t, c.Options)
+	assert.Equal(t, jsonapi.MediaType, c.MediaType())
+}
+
+func TestNewEncoderConfig_InvalidQuery(t *testing.T) {
+	for _, target := range []string{"/articles?fields[]=title", "/articles?pretty=maybe"} {
+		_, err := NewEncoderConfig(httptest.NewRequest(http.MethodGet, target, nil), jsonapi.QueryConfig{})
+		e := &jsonapi.ErrorObject{}
+		if assert.ErrorAs(t, err, &e, target) {
+			assert.Equal(t, "400", e.Status, target)
+		}
+	}
+
+	_, err := NewEncoderConfig(httptest.NewRequest(http.MethodGet, "/articles?fields[]=title", nil), jsonapi.QueryConfig{})
+	e := &jsonapi.ErrorObject{}
+	if assert.ErrorAs(t, err, &e) && assert.NotNil(t, e.Source) {
+		assert.Equal(t, "fields[]", e.Source.Parameter)
+	}
+}
+
+func TestNewEncoderConfig_UnsupportedExt(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/articles", nil)
+	r.Header.Set("Accept", jsonapi.MediaType+`; ext="https://example.com/unknown"`)
+
+	_, err := NewEncoderConfig(r, jsonapi.QueryConfig{})
+	e := &jsonapi.ErrorObject{}
+	if assert.ErrorAs(t, err, &e) {
+		assert.Equal(t, "406", e.Status)
+	}
+}
+
+func TestNewEncoderConfig_IgnoresUnknownProfiles(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/articles", nil)
+	r.Header.Set("Accept", jsonapi.MediaType+`; profile="https://example.com/unknown"`)
+
+	c, err := NewEncoderConfig(r, jsonapi.QueryConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Empty(t, c.Profiles)
+}
+
+func TestEncoderConfig_MediaType(t *testing.T) {
+	c := &EncoderConfig{Ext: []string{"https://example.com/a"}, Profiles: []string{"https://example.com/p"}}
+	assert.Equal(t, jsonapi.MediaType+`; ext="https://example.com/a"; profile="https://example.com/p"`, c.MediaType())
+}
+
+func TestEncoderConfig_Write(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/articles/1?fields[articles]=id&pretty=true", nil)
+	r.Header.Set("Accept-Language", "en")
+	c, err := NewEncoderConfig(r, jsonapi.QueryConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	if err := c.Write(w, http.StatusOK, &article{Id: 1, Title: "Hello"}); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, jsonapi.MediaType, w.Header().Get("Content-Type"))
+	assert.Equal(t, "en", w.Header().Get("Content-Language"))
+	assert.Equal(t, "{\n  \"data\": {\n    \"type\": \"articles\",\n    \"id\": \"1\"\n  }\n}", w.Body.String())
+}
+
+func TestEncoderConfig_Encode_Ext(t *testing.T) {
+	c := &EncoderConfig{Ext: []string{"https://example.com/a"}, Profiles: []string{"https://example.com/p"}}
+	data, err := c.Encode(&article{Id: 1, Title: "Hello"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{
+		"data": {"type": "articles", "id": "1", "attributes": {"title": "Hello"}},
+		"jsonapi": {"ext": ["https://example.com/a"], "profile": ["https://example.com/p"]}
+	}`
+	assert.JSONEq(t, expected, string(data))
+}
+
+func TestEncoderConfig_Encode_Hooks(t *testing.T) {
+	reg := jsonapi.NewRegistry()
+	reg.RegisterExtension(versionExt{})
+	c := &EncoderConfig{Pretty: true, Options: []jsonapi.Option{jsonapi.WithRegistry(reg)}}
+	data, err := c.Encode(&article{Id: 1, Title: "Hello"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{
+		"data": {"type": "articles", "id": "1", "attributes": {"title": "Hello"}},
+		"version:id": "42"
+	}`
+	assert.JSONEq(t, expected, string(data))
+	assert.Contains(t, string(data), "\n  \"data\": {")
+}
+
+func TestEncoderConfig_WriteError(t *testing.T) {
+	w := httptest.NewRecorder()
+	err := (&EncoderConfig{}).Write(w, http.StatusOK, 1)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
@@ -0,0 +1,138 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/max-waters/jsonapi/jsonapi"
+)
+
+// ErrorObjects converts err, and each of its joined errors, to error
+// objects for an errors document. Every *jsonapi.ErrorObject and
+// jsonapi.ErrorList found in err's tree is returned as it is, and other errors are converted by
+// the error mappers registered with jsonapi.DefaultRegistry, if any
+// apply. The jsonapi package's own client errors are reported as 400
+// Bad Request: a *jsonapi.UnmarshalErr with the pointer of its member
+// as its source, a *jsonapi.QueryErr with its parameter, and unknown
+// members, malformed JSON and unexpected primary data. Any other errors
+// are reported as generic internal server errors, so as not to leak
+// their details.
+func ErrorObjects(err error) []*jsonapi.ErrorObject {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		var errs []*jsonapi.ErrorObject
+		for _, e := range joined.Unwrap() {
+			errs = append(errs, ErrorObjects(e)...)
+		}
+		return errs
+	}
+
+	var list jsonapi.ErrorList
+	if errors.As(err, &list) {
+		return slices.Clone(list)
+	}
+	if obj := jsonapi.DefaultRegistry.Snapshot().MapError(err); obj != nil {
+		return []*jsonapi.ErrorObject{obj}
+	}
+
+	var (
+		ue      *jsonapi.UnmarshalErr
+		qe      *jsonapi.QueryErr
+		unknown *jsonapi.UnknownMembersErr
+		syntax  *json.SyntaxError
+	)
+	switch {
+	case errors.As(err, &ue):
+		return []*jsonapi.ErrorObject{badRequest(ue.Err.Error(), ue.Pointer)}
+	case errors.As(err, &qe):
+		e := badRequest(qe.Err.Error(), "")
+		e.Source = &jsonapi.ErrorSource{Parameter: qe.Param}
+		return []*jsonapi.ErrorObject{e}
+	case errors.As(err, &unknown):
+		return []*jsonapi.ErrorObject{badRequest(unknown.Error(), "")}
+	case errors.As(err, &syntax):
+		return []*jsonapi.ErrorObject{badRequest("malformed document: "+syntax.Error(), "")}
+	case errors.Is(err, jsonapi.ErrNotCollection), errors.Is(err, jsonapi.ErrUnexpectedArray),
+		errors.Is(err, jsonapi.ErrDuplicateResource):
+		return []*jsonapi.ErrorObject{badRequest(err.Error(), "/data")}
+	}
+	return []*jsonapi.ErrorObject{internalError()}
+}
+
+// ViolationErrorObjects converts the violations of a document, as
+// returned by jsonapi.ValidateDocument, to 400 Bad Request error
+// objects whose sources are the violations' pointers.
+func ViolationErrorObjects(vs []jsonapi.Violation) []*jsonapi.ErrorObject {
+	errs := make([]*jsonapi.ErrorObject, len(vs))
+	for i, v := range vs {
+		errs[i] = badRequest(v.Detail, v.Pointer)
+	}
+	return errs
+}
+
+// NewError returns an error object with status and its standard
+// title, eg "Not Found", and the supplied detail, if any.
+func NewError(status int, detail string) *jsonapi.ErrorObject {
+	return &jsonapi.ErrorObject{
+		Status: strconv.Itoa(status),
+		Title:  http.StatusText(status),
+		Detail: detail,
+	}
+}
+
+// NewNotFoundError returns a 404 Not Found error object, eg for
+// requests of resources that do not exist.
+func NewNotFoundError(detail string) *jsonapi.ErrorObject {
+	return NewError(http.StatusNotFound, detail)
+}
+
+// NewConflictError returns a 409 Conflict error object, eg for
+// requests that create resources with ids already in use.
+func NewConflictError(detail string) *jsonapi.ErrorObject {
+	return NewError(http.StatusConflict, detail)
+}
+
+// NewValidationError returns a 422 Unprocessable Content error object
+// whose source is the pointer of the invalid member, eg
+// "/data/attributes/title", for documents that are well-formed but
+// semantically invalid.
+func NewValidationError(pointer, detail string) *jsonapi.ErrorObject {
+	e := NewError(http.StatusUnprocessableEntity, detail)
+	if pointer != "" {
+		e.Source = &jsonapi.ErrorSource{Pointer: pointer}
+	}
+	return e
+}
+
+// Status returns the response status of an error document containing
+// errs: the status shared by all errors, or otherwise the most generally
+// applicable one, ie 400 Bad Request if they are all client errors, or
+// else 500 Internal Server Error.
+func Status(errs []*jsonapi.ErrorObject) int {
+	if len(errs) == 0 {
+		return http.StatusInternalServerError
+	}
+
+	code := errs[0].StatusCode()
+	clientErrs := true
+	for _, e := range errs {
+		c := e.StatusCode()
+		if c != code {
+			code = 0
+		}
+		if c < 400 || c >= 500 {
+			clientErrs = false
+		}
+	}
+
+	switch {
+	case code != 0:
+		return code
+	case clientErrs:
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
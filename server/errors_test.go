@@ -0,0 +1,136 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/max-waters/jsonapi/jsonapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorObjects(t *testing.T) {
+	notFound := &jsonapi.ErrorObject{Status: "404", Title: "Not Found"}
+	badRequest := func(detail, pointer string) *jsonapi.ErrorObject {
+		e := &jsonapi.ErrorObject{Status: "400", Title: "Bad Request", Detail: detail}
+		if pointer != "" {
+			e.Source = &jsonapi.ErrorSource{Pointer: pointer}
+		}
+		return e
+	}
+
+	unmarshal := func(data string, a any, opts ...jsonapi.Option) error {
+		err := jsonapi.UnmarshalDocument([]byte(data), a, opts...)
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+		return err
+	}
+
+	type testCase struct {
+		name string
+		err  error
+		want []*jsonapi.ErrorObject
+	}
+
+	testCases := []testCase{{
+		name: "error object",
+		err:  notFound,
+		want: []*jsonapi.ErrorObject{notFound},
+	}, {
+		name: "unmarshal",
+		err:  unmarshal(`{"data": {"type": "articles", "id": "1", "attributes": {"title": 1}}}`, &article{}),
+		want: []*jsonapi.ErrorObject{badRequest("json: cannot unmarshal number into Go value of type string", "/data/attributes/title")},
+	}, {
+		name: "all errors",
+		err:  unmarshal(`{"data": {"type": "articles", "id": "x", "attributes": {"title": 1}}}`, &article{}, jsonapi.WithAllErrors()),
+		want: []*jsonapi.ErrorObject{
+			badRequest("json: cannot unmarshal number into Go value of type string", "/data/attributes/title"),
+			badRequest("invalid character 'x' looking for beginning of value", "/data/id"),
+		},
+	}, {
+		name: "unknown members",
+		err:  unmarshal(`{"data": {"type": "articles", "id": "1", "attributes": {"body": ""}}}`, &article{}, jsonapi.WithDisallowUnknownMembers()),
+		want: []*jsonapi.ErrorObject{badRequest("unknown members: attributes.body", "")},
+	}, {
+		name: "malformed",
+		err:  unmarshal(`{"data": x}`, &article{}),
+		want: []*jsonapi.ErrorObject{badRequest("malformed document: invalid character 'x' looking for beginning of value", "")},
+	}, {
+		name: "collection",
+		err:  unmarshal(`{"data": {"type": "articles", "id": "1"}}`, &[]article{}),
+		want: []*jsonapi.ErrorObject{badRequest("jsonapi: primary data is not a collection", "/data")},
+	}, {
+		name: "query",
+		err:  fmt.Errorf("jsonapi: %w", &jsonapi.QueryErr{Param: "page[number]", Err: jsonapi.ErrMixedPageStyles}),
+		want: []*jsonapi.ErrorObject{{Status: "400", Title: "Bad Request", Detail: "mixed pagination styles", Source: &jsonapi.ErrorSource{Parameter: "page[number]"}}},
+	}, {
+		name: "error list",
+		err:  fmt.Errorf("saving: %w", jsonapi.ErrorList{notFound, badRequest("no title", "/data/attributes/title")}),
+		want: []*jsonapi.ErrorObject{notFound, badRequest("no title", "/data/attributes/title")},
+	}, {
+		name: "other",
+		err:  errors.Join(notFound, errors.New("database is down")),
+		want: []*jsonapi.ErrorObject{notFound, internalError()},
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, ErrorObjects(tc.err))
+		})
+	}
+}
+
+func TestViolationErrorObjects(t *testing.T) {
+	vs := jsonapi.ValidateDocument([]byte(`{"data": {"attributes": []}}`))
+	want := []*jsonapi.ErrorObject{{
+		Status: "400",
+		Title:  "Bad Request",
+		Detail: "must be an object",
+		Source: &jsonapi.ErrorSource{Pointer: "/data/attributes"},
+	}}
+	assert.Equal(t, want, ViolationErrorObjects(vs))
+
+	vs = jsonapi.ValidateDocument([]byte(`{}`))
+	want = []*jsonapi.ErrorObject{{
+		Status: "400",
+		Title:  "Bad Request",
+		Detail: "document must contain at least one of data, errors or meta",
+	}}
+	assert.Equal(t, want, ViolationErrorObjects(vs))
+}
+
+func TestNewError(t *testing.T) {
+	assert.Equal(t, &jsonapi.ErrorObject{Status: "404", Title: "Not Found", Detail: "no such article"},
+		NewNotFoundError("no such article"))
+	assert.Equal(t, &jsonapi.ErrorObject{Status: "409", Title: "Conflict", Detail: "id in use"},
+		NewConflictError("id in use"))
+	assert.Equal(t, &jsonapi.ErrorObject{
+		Status: "422",
+		Title:  "Unprocessable Entity",
+		Detail: "must not be empty",
+		Source: &jsonapi.ErrorSource{Pointer: "/data/attributes/title"},
+	}, NewValidationError("/data/attributes/title", "must not be empty"))
+	assert.Equal(t, &jsonapi.ErrorObject{Status: "403", Title: "Forbidden"}, NewError(403, ""))
+}
+
+func TestStatus(t *testing.T) {
+	type testCase struct {
+		Name string
+		In   []*jsonapi.ErrorObject
+		Exp  int
+	}
+
+	for _, tc := range []testCase{
+		{"none", nil, 500},
+		{"one", []*jsonapi.ErrorObject{NewNotFoundError("")}, 404},
+		{"shared", []*jsonapi.ErrorObject{NewValidationError("/data/id", ""), NewValidationError("/data/type", "")}, 422},
+		{"client errors", []*jsonapi.ErrorObject{NewValidationError("/data/id", ""), NewConflictError("")}, 400},
+		{"server error", []*jsonapi.ErrorObject{NewConflictError(""), internalError()}, 500},
+		{"no status", []*jsonapi.ErrorObject{NewConflictError(""), {Title: "Oops"}}, 500},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			assert.Equal(t, tc.Exp, Status(tc.In))
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/max-waters/jsonapi/jsonapi"
+)
+
+// ResourceHandler serves a resource or collection endpoint, eg
+// /articles/1 or /articles. Its capabilities are the optional
+// interfaces Creator, Updater and Deleter that it implements,
+// which determine the methods allowed by Handler.
+type ResourceHandler interface {
+	// Get returns the endpoint's primary data, as accepted
+	// by jsonapi.FormatDocument, eg a struct or a slice.
+	Get(r *http.Request) (any, error)
+}
+
+// Creator is implemented by ResourceHandlers that handle POST
+// requests. Create returns the created resource, or nil if the
+// resource was created as requested.
+type Creator interface {
+	Create(r *http.Request) (any, error)
+}
+
+// Updater is implemented by ResourceHandlers that handle PATCH
+// requests. Update returns the updated resource, or nil if the
+// resource was updated as requested.
+type Updater interface {
+	Update(r *http.Request) (any, error)
+}
+
+// Deleter is implemented by ResourceHandlers that handle DELETE requests.
+type Deleter interface {
+	Delete(r *http.Request) error
+}
+
+// AllowedMethods returns the HTTP methods allowed by h: GET, HEAD
+// and OPTIONS, and POST, PATCH and DELETE if h implements Creator,
+// Updater and Deleter respectively.
+func AllowedMethods(h ResourceHandler) []string {
+	methods := []string{http.MethodGet, http.MethodHead, http.MethodOptions}
+	if _, ok := h.(Creator); ok {
+		methods = append(methods, http.MethodPost)
+	}
+	if _, ok := h.(Updater); ok {
+		methods = append(methods, http.MethodPatch)
+	}
+	if _, ok := h.(Deleter); ok {
+		methods = append(methods, http.MethodDelete)
+	}
+	return methods
+}
+
+// Handler returns a handler that serves requests with h, marshaling
+// documents with jsonapi.MarshalDocument and opts, so that the hooks of
+// registered extensions and profiles run. Its responses to GET requests
+// have ETag and Content-Length headers, and are 304 Not Modified if the
+// ETag matches the request's If-None-Match header. HEAD requests are
+// answered with the same headers, without the body. OPTIONS requests,
+// and those with methods that h doesn't support, are answered with an
+// Allow header listing the methods it does, with statuses 204 No
+// Content and 405 Method Not Allowed respectively. Errors returned by h
+// are written with WriteError.
+func Handler(h ResourceHandler, opts ...jsonapi.Option) http.Handler {
+	allow := strings.Join(AllowedMethods(h), ", ")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var a any
+		var err error
+		status := http.StatusOK
+
+		c, u, d := capabilities(h)
+		switch {
+		case r.Method == http.MethodGet, r.Method == http.MethodHead:
+			if a, err = h.Get(r); err == nil {
+				err = writeCacheable(w, r, a, opts)
+			}
+			if err != nil {
+				_ = WriteError(w, err)
+			}
+			return
+		case r.Method == http.MethodOptions:
+			w.Header().Set("Allow", allow)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		case r.Method == http.MethodPost && c != nil:
+			a, err = c.Create(r)
+			status = http.StatusCreated
+		case r.Method == http.MethodPatch && u != nil:
+			a, err = u.Update(r)
+		case r.Method == http.MethodDelete && d != nil:
+			err = d.Delete(r)
+		default:
+			w.Header().Set("Allow", allow)
+			_ = WriteError(w, &jsonapi.ErrorObject{
+				Status: strconv.Itoa(http.StatusMethodNotAllowed),
+				Title:  http.StatusText(http.StatusMethodNotAllowed),
+				Detail: fmt.Sprintf("method %s is not allowed", r.Method),
+			})
+			return
+		}
+
+		switch {
+		case err != nil:
+			_ = WriteError(w, err)
+		case a == nil:
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			_ = writeData(w, status, a, opts)
+		}
+	})
+}
+
+// capabilities returns h as a Creator, Updater and
+// Deleter, each of which is nil if h doesn't implement it.
+func capabilities(h ResourceHandler) (Creator, Updater, Deleter) {
+	c, _ := h.(Creator)
+	u, _ := h.(Updater)
+	d, _ := h.(Deleter)
+	return c, u, d
+}
+
+// writeData writes a document with the primary data a,
+// marshaled by jsonapi.MarshalDocument with opts.
+func writeData(w http.ResponseWriter, status int, a any, opts []jsonapi.Option) error {
+	data, err := jsonapi.MarshalDocument(a, opts...)
+	if err != nil {
+		writeInternalError(w)
+		return err
+	}
+
+	w.Header().Set("Content-Type", jsonapi.MediaType)
+	w.WriteHeader(status)
+	_, err = w.Write(data)
+	return err
+}
+
+// writeCacheable writes a document with the primary data a in
+// response to the GET or HEAD request r, with ETag and
+// Content-Length headers, omitting the body for HEAD requests
+// and if the ETag matches r's If-None-Match header. As with
+// writeData, a is marshaled by jsonapi.MarshalDocument. It only
+// returns an error, having written nothing, if a can't be
+// marshaled.
+func writeCacheable(w http.ResponseWriter, r *http.Request, a any, opts []jsonapi.Option) error {
+	data, err := jsonapi.MarshalDocument(a, opts...)
+	if err != nil {
+		return err
+	}
+
+	etag := ETag(data)
+	w.Header().Set("ETag", etag)
+	if etagMatch(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return nil
+	}
+
+	w.Header().Set("Content-Type", jsonapi.MediaType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.WriteHeader(http.StatusOK)
+	if r.Method != http.MethodHead {
+		_, _ = w.Write(data)
+	}
+	return nil
+}
+
+// ETag returns a strong entity tag for the response body data.
+func ETag(data []byte) string {
+	sum := sha256.Sum256(data)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// etagMatch returns whether the If-None-Match header value
+// header matches etag, using weak comparison.
+func etagMatch(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+	for _, tag := range splitHeader(header) {
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,162 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/max-waters/jsonapi/jsonapi"
+	"github.com/stretchr/testify/assert"
+)
+
+// readOnlyHandler serves a single article.
+type readOnlyHandler struct{}
+
+func (readOnlyHandler) Get(r *http.Request) (any, error) {
+	if r.URL.Path == "/articles/2" {
+		return nil, &jsonapi.ErrorObject{Status: "404", Title: "Not Found"}
+	}
+	return &article{Id: 1, Title: "Hello"}, nil
+}
+
+// crudHandler also creates, updates and deletes articles.
+type crudHandler struct {
+	readOnlyHandler
+}
+
+func (crudHandler) Create(r *http.Request) (any, error) {
+	return &article{Id: 2, Title: "New"}, nil
+}
+
+func (crudHandler) Update(r *http.Request) (any, error) {
+	return nil, nil
+}
+
+func (crudHandler) Delete(r *http.Request) error {
+	return nil
+}
+
+const handlerArticleJson = `{"data":{"type":"articles","id":"1","attributes":{"title":"Hello"}}}`
+
+func TestAllowedMethods(t *testing.T) {
+	assert.Equal(t, []string{"GET", "HEAD", "OPTIONS"}, AllowedMethods(readOnlyHandler{}))
+	assert.Equal(t, []string{"GET", "HEAD", "OPTIONS", "POST", "PATCH", "DELETE"}, AllowedMethods(crudHandler{}))
+}
+
+func TestHandler_Get(t *testing.T) {
+	w := httptest.NewRecorder()
+	Handler(readOnlyHandler{}).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/articles/1", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, jsonapi.MediaType, w.Header().Get("Content-Type"))
+	assert.Equal(t, ETag([]byte(handlerArticleJson)), w.Header().Get("ETag"))
+	assert.Equal(t, strconv.Itoa(len(handlerArticleJson)), w.Header().Get("Content-Length"))
+	assert.Equal(t, handlerArticleJson, w.Body.String())
+}
+
+func TestHandler_Head(t *testing.T) {
+	w := httptest.NewRecorder()
+	Handler(readOnlyHandler{}).ServeHTTP(w, httptest.NewRequest(http.MethodHead, "/articles/1", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, ETag([]byte(handlerArticleJson)), w.Header().Get("ETag"))
+	assert.Equal(t, strconv.Itoa(len(handlerArticleJson)), w.Header().Get("Content-Length"))
+	assert.Empty(t, w.Body.String())
+}
+
+func TestHandler_NotModified(t *testing.T) {
+	etag := ETag([]byte(handlerArticleJson))
+	for _, header := range []string{etag, `"other", W/` + etag, "*"} {
+		t.Run(header, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/articles/1", nil)
+			r.Header.Set("If-None-Match", header)
+			w := httptest.NewRecorder()
+			Handler(readOnlyHandler{}).ServeHTTP(w, r)
+
+			assert.Equal(t, http.StatusNotModified, w.Code)
+			assert.Equal(t, etag, w.Header().Get("ETag"))
+			assert.Empty(t, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_Options(t *testing.T) {
+	w := httptest.NewRecorder()
+	Handler(crudHandler{}).ServeHTTP(w, httptest.NewRequest(http.MethodOptions, "/articles/1", nil))
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "GET, HEAD, OPTIONS, POST, PATCH, DELETE", w.Header().Get("Allow"))
+}
+
+func TestHandler_MethodNotAllowed(t *testing.T) {
+	w := httptest.NewRecorder()
+	Handler(readOnlyHandler{}).ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/articles/1", nil))
+
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	assert.Equal(t, "GET, HEAD, OPTIONS", w.Header().Get("Allow"))
+	assert.JSONEq(t, `{"errors": [{"status": "405", "title": "Method Not Allowed", "detail": "method DELETE is not allowed"}]}`, w.Body.String())
+}
+
+func TestHandler_Mutations(t *testing.T) {
+	type testCase struct {
+		Method   string
+		Status   int
+		Expected string
+	}
+
+	testCases := []testCase{
+		{http.MethodPost, http.StatusCreated, `{"data":{"type":"articles","id":"2","attributes":{"title":"New"}}}`},
+		{http.MethodPatch, http.StatusNoContent, ""},
+		{http.MethodDelete, http.StatusNoContent, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Method, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			Handler(crudHandler{}).ServeHTTP(w, httptest.NewRequest(tc.Method, "/articles/1", nil))
+			assert.Equal(t, tc.Status, w.Code)
+			assert.Equal(t, tc.Expected, w.Body.String())
+		})
+	}
+}
+
+// versionExt adds a version member to the documents it encodes.
+type versionExt struct{}
+
+func (versionExt) URI() string { return "https://example.com/ext/version" }
+
+func (versionExt) EncodeDocument(d *jsonapi.Document) error {
+	d.ExtMembers = map[string]json.RawMessage{"version:id": json.RawMessage(`"42"`)}
+	return nil
+}
+
+func TestHandler_EncodeHooks(t *testing.T) {
+	reg := jsonapi.NewRegistry()
+	reg.RegisterExtension(versionExt{})
+	var stats []jsonapi.Stats
+	h := Handler(crudHandler{}, jsonapi.WithRegistry(reg), jsonapi.WithStats(func(s jsonapi.Stats) { stats = append(stats, s) }))
+
+	const expected = `{"data":{"type":"articles","id":"1","attributes":{"title":"Hello"}},"version:id":"42"}`
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/articles/1", nil))
+	assert.JSONEq(t, expected, w.Body.String())
+	assert.Equal(t, ETag(w.Body.Bytes()), w.Header().Get("ETag"))
+	assert.Equal(t, strconv.Itoa(w.Body.Len()), w.Header().Get("Content-Length"))
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/articles", nil))
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"version:id":"42"`)
+	assert.Len(t, stats, 2)
+}
+
+func TestHandler_Err(t *testing.T) {
+	w := httptest.NewRecorder()
+	Handler(readOnlyHandler{}).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/articles/2", nil))
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Empty(t, w.Header().Get("ETag"))
+	assert.JSONEq(t, `{"errors": [{"status": "404", "title": "Not Found"}]}`, w.Body.String())
+}
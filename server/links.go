@@ -0,0 +1,128 @@
+package server
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// placeholders in URL layout patterns
+const (
+	placeholderType         = "{type}"
+	placeholderId           = "{id}"
+	placeholderRelationship = "{relationship}"
+)
+
+var ErrLayoutMismatch = fmt.Errorf("url does not match layout")
+
+// RelationshipTarget identifies a relationship of a resource,
+// eg the target of a relationship's self link.
+type RelationshipTarget struct {
+	Type         string
+	Id           string
+	Relationship string
+}
+
+// URLLayout maps relationship URLs to and from the relationships
+// they identify, with a path pattern whose segments are literals
+// or the placeholders {type}, {id} and {relationship}.
+type URLLayout struct {
+	segments []string
+}
+
+// DefaultLayout is the URL layout recommended by the
+// JSON:API specification, and used by ResolveRelationship.
+var DefaultLayout = MustURLLayout("/{type}/{id}/relationships/{relationship}")
+
+// NewURLLayout returns the URL layout with the supplied path pattern,
+// eg "/api/{type}/{id}/relationships/{relationship}", which must
+// contain each placeholder exactly once, as an entire segment.
+func NewURLLayout(pattern string) (*URLLayout, error) {
+	segments := strings.Split(strings.Trim(pattern, "/"), "/")
+	for _, p := range []string{placeholderType, placeholderId, placeholderRelationship} {
+		n := 0
+		for _, s := range segments {
+			if s == p {
+				n++
+			} else if strings.Contains(s, p) {
+				return nil, fmt.Errorf("jsonapi: url layout '%s': %s must be an entire segment", pattern, p)
+			}
+		}
+		if n != 1 {
+			return nil, fmt.Errorf("jsonapi: url layout '%s': %s must appear exactly once", pattern, p)
+		}
+	}
+	return &URLLayout{segments}, nil
+}
+
+// MustURLLayout is like NewURLLayout, but panics if
+// the pattern is invalid.
+func MustURLLayout(pattern string) *URLLayout {
+	l, err := NewURLLayout(pattern)
+	if err != nil {
+		panic(err)
+	}
+	return l
+}
+
+// Resolve returns the relationship identified by link, an absolute
+// or relative URL whose path matches the layout, eg the self link of
+// a relationship object, so that relationship endpoints can be routed
+// generically. Otherwise an error wrapping ErrLayoutMismatch is returned.
+func (l *URLLayout) Resolve(link string) (RelationshipTarget, error) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return RelationshipTarget{}, fmt.Errorf("jsonapi: parsing url: %w", err)
+	}
+
+	segments := strings.Split(strings.Trim(u.EscapedPath(), "/"), "/")
+	if len(segments) != len(l.segments) {
+		return RelationshipTarget{}, fmt.Errorf("jsonapi: %s: %w", link, ErrLayoutMismatch)
+	}
+
+	t := RelationshipTarget{}
+	for i, s := range segments {
+		if s, err = url.PathUnescape(s); err != nil {
+			return RelationshipTarget{}, fmt.Errorf("jsonapi: parsing url: %w", err)
+		}
+		switch p := l.segments[i]; {
+		case p == placeholderType:
+			t.Type = s
+		case p == placeholderId:
+			t.Id = s
+		case p == placeholderRelationship:
+			t.Relationship = s
+		case p != s:
+			return RelationshipTarget{}, fmt.Errorf("jsonapi: %s: %w", link, ErrLayoutMismatch)
+		}
+	}
+
+	if t.Type == "" || t.Id == "" || t.Relationship == "" {
+		return RelationshipTarget{}, fmt.Errorf("jsonapi: %s: %w", link, ErrLayoutMismatch)
+	}
+	return t, nil
+}
+
+// URL returns the path of the relationship t in the layout.
+func (l *URLLayout) URL(t RelationshipTarget) string {
+	segments := make([]string, len(l.segments))
+	for i, p := range l.segments {
+		switch p {
+		case placeholderType:
+			segments[i] = url.PathEscape(t.Type)
+		case placeholderId:
+			segments[i] = url.PathEscape(t.Id)
+		case placeholderRelationship:
+			segments[i] = url.PathEscape(t.Relationship)
+		default:
+			segments[i] = p
+		}
+	}
+	return "/" + strings.Join(segments, "/")
+}
+
+// ResolveRelationship returns the relationship identified by
+// link in DefaultLayout.
+func ResolveRelationship(link string) (RelationshipTarget, error) {
+	return DefaultLayout.Resolve(link)
+}
@@ -0,0 +1,64 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveRelationship(t *testing.T) {
+	type testCase struct {
+		link string
+		want RelationshipTarget
+		err  bool
+	}
+
+	for _, tc := range []testCase{
+		{link: "/articles/1/relationships/author", want: RelationshipTarget{"articles", "1", "author"}},
+		{link: "https://example.com/articles/1/relationships/author/", want: RelationshipTarget{"articles", "1", "author"}},
+		{link: "/articles/a%2Fb/relationships/tags?page=2", want: RelationshipTarget{"articles", "a/b", "tags"}},
+		{link: "/articles/1/author", err: true},
+		{link: "/articles/1/links/author", err: true},
+		{link: "/articles//relationships/author", err: true},
+	} {
+		got, err := ResolveRelationship(tc.link)
+		if tc.err {
+			assert.ErrorIs(t, err, ErrLayoutMismatch, tc.link)
+			continue
+		}
+		if assert.NoError(t, err, tc.link) {
+			assert.Equal(t, tc.want, got, tc.link)
+		}
+	}
+}
+
+func TestURLLayout(t *testing.T) {
+	l, err := NewURLLayout("/api/v1/{type}/{id}/rels/{relationship}")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	target := RelationshipTarget{"articles", "a/b", "author"}
+	link := l.URL(target)
+	assert.Equal(t, "/api/v1/articles/a%2Fb/rels/author", link)
+
+	got, err := l.Resolve(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, target, got)
+
+	_, err = l.Resolve("/api/v2/articles/1/rels/author")
+	assert.ErrorIs(t, err, ErrLayoutMismatch)
+}
+
+func TestNewURLLayout_Invalid(t *testing.T) {
+	for _, p := range []string{
+		"/{type}/{id}/relationships",
+		"/{type}/{id}/{id}/{relationship}",
+		"/{type}/id-{id}/{relationship}",
+	} {
+		_, err := NewURLLayout(p)
+		assert.Error(t, err, p)
+	}
+}
@@ -0,0 +1,99 @@
+package server
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/max-waters/jsonapi/jsonapi"
+)
+
+// ContentNegotiation returns middleware that enforces the JSON:API
+// content negotiation rules:
+//   - requests whose Content-Type is the JSON:API media type with
+//     parameters other than "ext" or "profile" are rejected with
+//     415 Unsupported Media Type
+//   - requests whose Accept header contains the JSON:API media type,
+//     but only with parameters other than "ext" or "profile", are
+//     rejected with 406 Not Acceptable
+//
+// Rejections are written as JSON:API error documents.
+func ContentNegotiation(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ct := r.Header.Get("Content-Type"); ct != "" {
+			typ, params, err := mime.ParseMediaType(ct)
+			if err == nil && typ == jsonapi.MediaType && !allowedParams(params) {
+				_ = WriteError(w, &jsonapi.ErrorObject{
+					Status: strconv.Itoa(http.StatusUnsupportedMediaType),
+					Title:  http.StatusText(http.StatusUnsupportedMediaType),
+					Detail: "Content-Type media type parameters are not supported",
+					Source: &jsonapi.ErrorSource{Header: "Content-Type"},
+				})
+				return
+			}
+		}
+
+		if !acceptable(r.Header.Values("Accept")) {
+			_ = WriteError(w, &jsonapi.ErrorObject{
+				Status: strconv.Itoa(http.StatusNotAcceptable),
+				Title:  http.StatusText(http.StatusNotAcceptable),
+				Detail: "Accept header media type parameters are not supported",
+				Source: &jsonapi.ErrorSource{Header: "Accept"},
+			})
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// acceptable returns false iff the Accept header values contain
+// the JSON:API media type, and every instance of it has parameters
+// other than "ext" and "profile".
+func acceptable(accept []string) bool {
+	found := false
+	for _, header := range accept {
+		for _, mediaRange := range splitHeader(header) {
+			typ, params, err := mime.ParseMediaType(mediaRange)
+			if err != nil || typ != jsonapi.MediaType {
+				continue
+			}
+			delete(params, "q")
+			if allowedParams(params) {
+				return true
+			}
+			found = true
+		}
+	}
+	return !found
+}
+
+// allowedParams returns true iff the only media
+// type parameters are "ext" and "profile".
+func allowedParams(params map[string]string) bool {
+	for k := range params {
+		if k != "ext" && k != "profile" {
+			return false
+		}
+	}
+	return true
+}
+
+// splitHeader splits a comma-separated header value,
+// ignoring commas within quoted strings.
+func splitHeader(header string) []string {
+	var parts []string
+	quoted := false
+	start := 0
+	for i, c := range header {
+		switch {
+		case c == '"':
+			quoted = !quoted
+		case c == ',' && !quoted:
+			parts = append(parts, strings.TrimSpace(header[start:i]))
+			start = i + 1
+		}
+	}
+	return append(parts, strings.TrimSpace(header[start:]))
+}
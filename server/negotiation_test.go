@@ -0,0 +1,72 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContentNegotiation(t *testing.T) {
+	type testCase struct {
+		Name        string
+		ContentType string
+		Accept      []string
+		ExpStatus   int
+	}
+
+	testCases := []testCase{
+		{"no headers", "", nil, http.StatusOK},
+		{"plain", "application/vnd.api+json", []string{"application/vnd.api+json"}, http.StatusOK},
+		{"ext and profile", `application/vnd.api+json; ext="https://jsonapi.org/ext/atomic"; profile="https://example.com/p"`, nil, http.StatusOK},
+		{"other content type", "application/json; charset=utf-8", nil, http.StatusOK},
+		{"content type params", "application/vnd.api+json; charset=utf-8", nil, http.StatusUnsupportedMediaType},
+		{"accept any", "", []string{"*/*"}, http.StatusOK},
+		{"accept with q", "", []string{"application/vnd.api+json;q=0.9"}, http.StatusOK},
+		{"accept params", "", []string{"application/vnd.api+json; charset=utf-8"}, http.StatusNotAcceptable},
+		{"accept one valid", "", []string{"application/vnd.api+json; charset=utf-8, application/vnd.api+json"}, http.StatusOK},
+		{"accept one valid, multiple headers", "", []string{"application/vnd.api+json; charset=utf-8", "application/vnd.api+json"}, http.StatusOK},
+		{"accept quoted comma", "", []string{`application/vnd.api+json; profile="https://example.com/a,b"`}, http.StatusOK},
+		{"accept other types", "", []string{"application/vnd.api+json; charset=utf-8, text/html"}, http.StatusNotAcceptable},
+	}
+
+	h := ContentNegotiation(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/articles", nil)
+			if tc.ContentType != "" {
+				r.Header.Set("Content-Type", tc.ContentType)
+			}
+			for _, a := range tc.Accept {
+				r.Header.Add("Accept", a)
+			}
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			assert.Equal(t, tc.ExpStatus, w.Code)
+		})
+	}
+}
+
+func TestContentNegotiation_ErrorDocument(t *testing.T) {
+	h := ContentNegotiation(http.NotFoundHandler())
+
+	r := httptest.NewRequest(http.MethodPost, "/articles", nil)
+	r.Header.Set("Content-Type", "application/vnd.api+json; version=1")
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+	assert.Equal(t, "application/vnd.api+json", w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"errors": [{
+		"status": "415",
+		"title": "Unsupported Media Type",
+		"detail": "Content-Type media type parameters are not supported",
+		"source": {"header": "Content-Type"}
+	}]}`, w.Body.String())
+}
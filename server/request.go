@@ -0,0 +1,90 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/max-waters/jsonapi/jsonapi"
+)
+
+// MaxBodySize is the maximum size, in bytes, of a
+// request body read by DecodeRequest.
+var MaxBodySize int64 = 1 << 20
+
+// TypeMismatchErr is returned by DecodeRequest when the type of
+// the request's primary resource is not the endpoint's type. It
+// unwraps to a 409 Conflict error object, so it can be passed
+// directly to WriteError.
+type TypeMismatchErr struct {
+	Expected string
+	Got      string
+}
+
+func (e *TypeMismatchErr) Error() string {
+	return fmt.Sprintf("resource type '%s' does not match expected type '%s'", e.Got, e.Expected)
+}
+
+func (e *TypeMismatchErr) Unwrap() error {
+	obj := NewConflictError(e.Error())
+	obj.Source = &jsonapi.ErrorSource{Pointer: "/data/type"}
+	return obj
+}
+
+// DecodeRequest reads a single-resource document from the body of r,
+// and stores its primary data in out, which must be a pointer to a
+// struct or a ResourceUnmarshaler. If expectedType is not empty and
+// the resource's type differs, a *TypeMismatchErr is returned. If the
+// body is larger than MaxBodySize, malformed, or does not contain a
+// single resource, a *jsonapi.ErrorObject with a 413 or 400 status is
+// returned, and if the resource can't be stored in out, the
+// jsonapi.ErrorList of its ErrorObjects. In all cases the error can be
+// passed to WriteError.
+func DecodeRequest(r *http.Request, expectedType string, out any, opts ...jsonapi.Option) error {
+	data, err := io.ReadAll(io.LimitReader(r.Body, MaxBodySize+1))
+	if err != nil {
+		return badRequest(fmt.Sprintf("reading request body: %s", err), "")
+	}
+	if int64(len(data)) > MaxBodySize {
+		return &jsonapi.ErrorObject{
+			Status: strconv.Itoa(http.StatusRequestEntityTooLarge),
+			Title:  http.StatusText(http.StatusRequestEntityTooLarge),
+			Detail: fmt.Sprintf("request body is larger than %d bytes", MaxBodySize),
+		}
+	}
+
+	d := jsonapi.Document{}
+	if err := json.Unmarshal(data, &d); err != nil {
+		return badRequest(fmt.Sprintf("malformed document: %s", err), "")
+	}
+	if d.Data == nil || d.Data.Resource == nil {
+		return badRequest("primary data must be a single resource", "/data")
+	}
+
+	if expectedType != "" && d.Data.Resource.Type != expectedType {
+		return &TypeMismatchErr{Expected: expectedType, Got: d.Data.Resource.Type}
+	}
+
+	err = jsonapi.DecodeDocument(&d, opts...)
+	if err == nil {
+		err = jsonapi.DeformatDocument(&d, out, opts...)
+	}
+	if err != nil {
+		if errors.Is(err, jsonapi.ErrNotStructPtr) {
+			return err
+		}
+		return jsonapi.ErrorList(ErrorObjects(err))
+	}
+	return nil
+}
+
+func badRequest(detail, pointer string) *jsonapi.ErrorObject {
+	e := NewError(http.StatusBadRequest, detail)
+	if pointer != "" {
+		e.Source = &jsonapi.ErrorSource{Pointer: pointer}
+	}
+	return e
+}
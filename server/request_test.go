@@ -0,0 +1,101 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/max-waters/jsonapi/jsonapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeRequest(t *testing.T) {
+	body := `{"data": {"type": "articles", "id": "1", "attributes": {"title": "Hello"}}}`
+	r := httptest.NewRequest(http.MethodPost, "/articles", strings.NewReader(body))
+
+	got := article{}
+	if err := DecodeRequest(r, "articles", &got); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, article{Id: 1, Title: "Hello"}, got)
+}
+
+func TestDecodeRequest_TypeMismatch(t *testing.T) {
+	body := `{"data": {"type": "people", "id": "1"}}`
+	r := httptest.NewRequest(http.MethodPost, "/articles", strings.NewReader(body))
+
+	got := article{}
+	err := DecodeRequest(r, "articles", &got)
+
+	var mismatch *TypeMismatchErr
+	if assert.ErrorAs(t, err, &mismatch) {
+		assert.Equal(t, &TypeMismatchErr{Expected: "articles", Got: "people"}, mismatch)
+	}
+	assert.Equal(t, article{}, got)
+
+	w := httptest.NewRecorder()
+	if err := WriteError(w, err); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.JSONEq(t, `{"errors": [{
+		"status": "409",
+		"title": "Conflict",
+		"detail": "resource type 'people' does not match expected type 'articles'",
+		"source": {"pointer": "/data/type"}
+	}]}`, w.Body.String())
+}
+
+func TestDecodeRequest_Err(t *testing.T) {
+	type testCase struct {
+		Name      string
+		Body      string
+		ExpStatus int
+	}
+
+	testCases := []testCase{
+		{"malformed", `{"data": `, http.StatusBadRequest},
+		{"no data", `{"meta": {}}`, http.StatusBadRequest},
+		{"null data", `{"data": null}`, http.StatusBadRequest},
+		{"collection", `{"data": [{"type": "articles", "id": "1"}]}`, http.StatusBadRequest},
+		{"bad attribute", `{"data": {"type": "articles", "id": "1", "attributes": {"title": 1}}}`, http.StatusBadRequest},
+		{"too large", `{"data": {"type": "articles", "id": "1", "attributes": {"title": "` + strings.Repeat("a", int(MaxBodySize)) + `"}}}`, http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/articles", strings.NewReader(tc.Body))
+			err := DecodeRequest(r, "articles", &article{})
+
+			var obj *jsonapi.ErrorObject
+			if assert.True(t, errors.As(err, &obj)) {
+				assert.Equal(t, tc.ExpStatus, obj.StatusCode())
+			}
+		})
+	}
+}
+
+func TestDecodeRequest_AllErrors(t *testing.T) {
+	body := `{"data": {"type": "articles", "id": "x", "attributes": {"title": 1}}}`
+	r := httptest.NewRequest(http.MethodPost, "/articles", strings.NewReader(body))
+	err := DecodeRequest(r, "articles", &article{}, jsonapi.WithAllErrors())
+
+	var list jsonapi.ErrorList
+	if assert.ErrorAs(t, err, &list) && assert.Len(t, list, 2) {
+		assert.Equal(t, "/data/attributes/title", list[0].Source.Pointer)
+		assert.Equal(t, "/data/id", list[1].Source.Pointer)
+	}
+}
+
+func TestDecodeRequest_Pointer(t *testing.T) {
+	body := `{"data": {"type": "articles", "id": "1", "attributes": {"title": 1}}}`
+	r := httptest.NewRequest(http.MethodPost, "/articles", strings.NewReader(body))
+	err := DecodeRequest(r, "articles", &article{})
+
+	var obj *jsonapi.ErrorObject
+	if assert.ErrorAs(t, err, &obj) && assert.NotNil(t, obj.Source) {
+		assert.Equal(t, "/data/attributes/title", obj.Source.Pointer)
+	}
+}
@@ -0,0 +1,111 @@
+// Package server provides helpers for serving JSON:API
+// documents from net/http handlers.
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/max-waters/jsonapi/jsonapi"
+)
+
+var (
+	ErrNotResource   = fmt.Errorf("primary data is not a single resource")
+	ErrNotCollection = fmt.Errorf("primary data is not a collection")
+)
+
+// WriteDocument writes d to w with the JSON:API
+// content type and the supplied status code.
+func WriteDocument(w http.ResponseWriter, status int, d *jsonapi.Document) error {
+	data, err := json.Marshal(d)
+	if err != nil {
+		writeInternalError(w)
+		return fmt.Errorf("jsonapi: marshaling document: %w", err)
+	}
+
+	w.Header().Set("Content-Type", jsonapi.MediaType)
+	w.WriteHeader(status)
+	_, err = w.Write(data)
+	return err
+}
+
+// WriteResource writes a single-resource document containing a, which
+// must be a struct, a ResourceMarshaler, or a nil pointer (null data).
+// If a cannot be marshaled, a 500 error document is written and the
+// marshaling error is returned.
+func WriteResource(w http.ResponseWriter, status int, a any, opts ...jsonapi.Option) error {
+	d, err := jsonapi.FormatDocument(a, opts...)
+	if err == nil && d.Data.Collection {
+		err = ErrNotResource
+	}
+	if err == nil {
+		err = jsonapi.EncodeDocument(d, opts...)
+	}
+	if err != nil {
+		writeInternalError(w)
+		return err
+	}
+	return WriteDocument(w, status, d)
+}
+
+// WriteCollection writes a collection document containing
+// the resources in a, which must be a slice or array.
+// If a cannot be marshaled, a 500 error document is written
+// and the marshaling error is returned.
+func WriteCollection(w http.ResponseWriter, status int, a any, opts ...jsonapi.Option) error {
+	d, err := jsonapi.FormatDocument(a, opts...)
+	if err == nil && !d.Data.Collection {
+		err = ErrNotCollection
+	}
+	if err == nil {
+		err = jsonapi.EncodeDocument(d, opts...)
+	}
+	if err != nil {
+		writeInternalError(w)
+		return err
+	}
+	return WriteDocument(w, status, d)
+}
+
+// WriteRelationship writes a relationship document containing the
+// resource linkage of the relationship called name of a, as served by
+// relationship endpoints, eg /articles/1/relationships/tags.
+// If it cannot be marshaled, a 500 error document is written
+// and the marshaling error is returned.
+func WriteRelationship(w http.ResponseWriter, status int, a any, name string, opts ...jsonapi.Option) error {
+	d, err := jsonapi.FormatRelationship(a, name, opts...)
+	if err != nil {
+		writeInternalError(w)
+		return err
+	}
+
+	data, err := json.Marshal(d)
+	if err != nil {
+		writeInternalError(w)
+		return fmt.Errorf("jsonapi: marshaling document: %w", err)
+	}
+
+	w.Header().Set("Content-Type", jsonapi.MediaType)
+	w.WriteHeader(status)
+	_, err = w.Write(data)
+	return err
+}
+
+// WriteError writes an error document describing err, with the
+// error objects returned by ErrorObjects, and the status returned
+// by Status.
+func WriteError(w http.ResponseWriter, err error) error {
+	errs := ErrorObjects(err)
+	return WriteDocument(w, Status(errs), &jsonapi.Document{Errors: errs})
+}
+
+func internalError() *jsonapi.ErrorObject {
+	return NewError(http.StatusInternalServerError, "")
+}
+
+func writeInternalError(w http.ResponseWriter) {
+	_ = WriteDocument(w, http.StatusInternalServerError, &jsonapi.Document{
+		Errors: []*jsonapi.ErrorObject{internalError()},
+	})
+}
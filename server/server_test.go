@@ -0,0 +1,134 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/max-waters/jsonapi/jsonapi"
+	"github.com/stretchr/testify/assert"
+)
+
+type article struct {
+	Id    int    `jsonapi:"id,articles,string"`
+	Title string `jsonapi:"attr,title"`
+}
+
+func TestWriteResource(t *testing.T) {
+	w := httptest.NewRecorder()
+	if err := WriteResource(w, http.StatusCreated, &article{Id: 1, Title: "Hello"}); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, jsonapi.MediaType, w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"data": {"type": "articles", "id": "1", "attributes": {"title": "Hello"}}}`, w.Body.String())
+}
+
+func TestWriteResource_Err(t *testing.T) {
+	for _, in := range []any{0, []article{}} {
+		t.Run(fmt.Sprintf("%T", in), func(t *testing.T) {
+			w := httptest.NewRecorder()
+			err := WriteResource(w, http.StatusOK, in)
+			assert.Error(t, err)
+			assert.Equal(t, http.StatusInternalServerError, w.Code)
+			assert.JSONEq(t, `{"errors": [{"status": "500", "title": "Internal Server Error"}]}`, w.Body.String())
+		})
+	}
+}
+
+func TestWriteCollection(t *testing.T) {
+	w := httptest.NewRecorder()
+	if err := WriteCollection(w, http.StatusOK, []article{{Id: 1, Title: "Hello"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, jsonapi.MediaType, w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"data": [{"type": "articles", "id": "1", "attributes": {"title": "Hello"}}]}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	err := WriteCollection(w, http.StatusOK, &article{})
+	assert.ErrorIs(t, err, ErrNotCollection)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestWriteResource_Hooks(t *testing.T) {
+	reg := jsonapi.NewRegistry()
+	reg.RegisterExtension(versionExt{})
+
+	w := httptest.NewRecorder()
+	if err := WriteResource(w, http.StatusOK, &article{Id: 1}, jsonapi.WithRegistry(reg)); err != nil {
+		t.Fatal(err)
+	}
+	assert.JSONEq(t, `{"data": {"type": "articles", "id": "1", "attributes": {"title": ""}}, "version:id": "42"}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	if err := WriteCollection(w, http.StatusOK, []article{}, jsonapi.WithRegistry(reg)); err != nil {
+		t.Fatal(err)
+	}
+	assert.JSONEq(t, `{"data": [], "version:id": "42"}`, w.Body.String())
+}
+
+func TestWriteRelationship(t *testing.T) {
+	type tagged struct {
+		Id   int   `jsonapi:"id,articles,string"`
+		Tags []int `jsonapi:"rel,tags,tags,string"`
+	}
+
+	w := httptest.NewRecorder()
+	if err := WriteRelationship(w, http.StatusOK, &tagged{Id: 1, Tags: []int{2}}, "tags"); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, jsonapi.MediaType, w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"data": [{"type": "tags", "id": "2"}]}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	err := WriteRelationship(w, http.StatusOK, &tagged{}, "author")
+	assert.ErrorIs(t, err, jsonapi.ErrUnknownRelationship)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestWriteError(t *testing.T) {
+	notFound := &jsonapi.ErrorObject{Status: "404", Title: "Not Found"}
+	badTitle := &jsonapi.ErrorObject{Status: "422", Title: "Invalid Attribute", Source: &jsonapi.ErrorSource{Pointer: "/data/attributes/title"}}
+	badId := &jsonapi.ErrorObject{Status: "400", Title: "Bad Request"}
+
+	type testCase struct {
+		Name      string
+		In        error
+		ExpStatus int
+		ExpErrs   []*jsonapi.ErrorObject
+	}
+
+	testCases := []testCase{
+		{"error object", notFound, 404, []*jsonapi.ErrorObject{notFound}},
+		{"wrapped", fmt.Errorf("finding article: %w", notFound), 404, []*jsonapi.ErrorObject{notFound}},
+		{"joined", errors.Join(badTitle, badId), 400, []*jsonapi.ErrorObject{badTitle, badId}},
+		{"other", errors.New("database is down"), 500, []*jsonapi.ErrorObject{internalError()}},
+		{"mixed", errors.Join(badTitle, errors.New("database is down")), 500, []*jsonapi.ErrorObject{badTitle, internalError()}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			if err := WriteError(w, tc.In); err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, tc.ExpStatus, w.Code)
+			assert.Equal(t, jsonapi.MediaType, w.Header().Get("Content-Type"))
+
+			got := jsonapi.Document{}
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tc.ExpErrs, got.Errors)
+		})
+	}
+}